@@ -0,0 +1,382 @@
+package api
+
+import (
+	"context"
+	"encoding/xml"
+	"github.com/inexio/thola/api/statistics"
+	"github.com/inexio/thola/internal/database"
+	"github.com/inexio/thola/internal/parser"
+	"github.com/inexio/thola/internal/request"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/xid"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// States of a job.
+const (
+	jobStatusPending   = "pending"
+	jobStatusRunning   = "running"
+	jobStatusFinished  = "finished"
+	jobStatusFailed    = "failed"
+	jobStatusCancelled = "cancelled"
+)
+
+// jobCancelInterval is the interval in which the running jobs of this api instance
+// are checked for cancel requests of other api instances.
+const jobCancelInterval = 5 * time.Second
+
+// jobs contains the jobs of this api instance. They are also stored in the database,
+// so that other instances can return them, but the database might be disabled.
+var jobs = struct {
+	sync.Mutex
+	m map[string]*job
+	// watching is true while watchCancelRequests is running.
+	watching bool
+}{m: make(map[string]*job)}
+
+var jobTTL = time.Hour
+
+type job struct {
+	sync.Mutex
+	state  database.Job
+	cancel context.CancelFunc
+}
+
+type jobCtxKey byte
+
+const jobKey jobCtxKey = iota + 1
+
+// JobResponse is the state of an asynchronously processed request.
+//
+// swagger:model
+type JobResponse struct {
+	XMLName xml.Name `json:"-" xml:"job"`
+
+	ID    string `json:"id" xml:"id"`
+	Route string `json:"route" xml:"route"`
+	// Status is one of "pending", "running", "finished", "failed" or "cancelled".
+	Status string `json:"status" xml:"status"`
+	// Progress describes the current step of the job.
+	Progress  string    `json:"progress" xml:"progress"`
+	CreatedAt time.Time `json:"created_at" xml:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" xml:"updated_at"`
+	// ExpiresAt is the time after which the job and its result are deleted.
+	ExpiresAt time.Time `json:"expires_at" xml:"expires_at"`
+	// StatusCode is the http status code of the response of the request.
+	StatusCode int `json:"status_code,omitempty" xml:"status_code,omitempty"`
+	// Result is the response of the request, or the error if the job failed.
+	Result *jobResult `json:"result,omitempty" xml:"result,omitempty"`
+}
+
+// jobResult is a response that is already encoded in the api format.
+type jobResult string
+
+func (r jobResult) MarshalJSON() ([]byte, error) {
+	return []byte(r), nil
+}
+
+func (r jobResult) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		Inner string `xml:",innerxml"`
+	}{string(r)}, start)
+}
+
+func newJobResponse(state database.Job) JobResponse {
+	res := JobResponse{
+		ID:         state.ID,
+		Route:      state.Route,
+		Status:     state.Status,
+		Progress:   state.Progress,
+		CreatedAt:  state.CreatedAt,
+		UpdatedAt:  state.UpdatedAt,
+		ExpiresAt:  state.ExpiresAt,
+		StatusCode: state.StatusCode,
+	}
+	if state.Result != "" {
+		result := jobResult(state.Result)
+		res.Result = &result
+	}
+	return res
+}
+
+func isDone(status string) bool {
+	return status == jobStatusFinished || status == jobStatusFailed || status == jobStatusCancelled
+}
+
+// startJob processes the request in the background and returns the new job.
+// The statistics of the request are recorded when the job is done.
+func startJob(ctx context.Context, echoCTX echo.Context, r request.Request, ip *string) error {
+	now := time.Now()
+	j := &job{state: database.Job{
+		ID:        xid.New().String(),
+		Route:     echoCTX.Path(),
+		Status:    jobStatusPending,
+		Progress:  "queued",
+		CreatedAt: now,
+	}}
+	if t, ok := tokenFromContext(echoCTX); ok {
+		j.state.Token = t.Name
+	}
+
+	logger := log.Ctx(ctx).With().Str("job", j.state.ID).Logger()
+	// ctx is not bound to the http request, so the job keeps the values of ctx, e.g. its statistics
+	ctx, j.cancel = context.WithCancel(context.WithValue(logger.WithContext(ctx), jobKey, j))
+
+	if err := j.save(ctx); err != nil {
+		j.cancel()
+		return handleError(echoCTX, err)
+	}
+
+	jobs.Lock()
+	for id, other := range jobs.m {
+		other.Lock()
+		if time.Now().After(other.state.ExpiresAt) {
+			delete(jobs.m, id)
+		}
+		other.Unlock()
+	}
+	jobs.m[j.state.ID] = j
+	if !jobs.watching {
+		jobs.watching = true
+		go watchCancelRequests()
+	}
+	jobs.Unlock()
+
+	log.Ctx(ctx).Debug().Msg("started job")
+	go j.run(ctx, statistics.Defer(ctx), r, ip)
+
+	j.Lock()
+	res := newJobResponse(j.state)
+	j.Unlock()
+	echoCTX.Response().Header().Set(echo.HeaderLocation, "/jobs/"+res.ID)
+	return returnInFormat(echoCTX, http.StatusAccepted, res)
+}
+
+func (j *job) run(ctx context.Context, recordStatistics func(statusCode int), r request.Request, ip *string) {
+	defer j.cancel()
+	j.update(ctx, func(state *database.Job) {
+		state.Status = jobStatusRunning
+	})
+
+	resp, err := processAPIRequest(ctx, r, ip)
+
+	statusCode := http.StatusOK
+	if err != nil {
+		statusCode, _ = errorResponse(err)
+	}
+	recordStatistics(statusCode)
+
+	j.update(ctx, func(state *database.Job) {
+		if state.Status == jobStatusCancelled {
+			return
+		}
+		var result interface{} = resp
+		state.Status = jobStatusFinished
+		state.StatusCode = http.StatusOK
+		if err != nil {
+			state.Status = jobStatusFailed
+			state.StatusCode, result = errorResponse(err)
+		}
+		state.Progress = "done"
+		b, encodeErr := parser.Parse(result, viper.GetString("api.format"))
+		if encodeErr != nil {
+			log.Ctx(ctx).Error().Err(encodeErr).Msg("failed to encode result of job")
+			state.Status = jobStatusFailed
+			state.StatusCode = http.StatusInternalServerError
+			return
+		}
+		state.Result = string(b)
+	})
+	log.Ctx(ctx).Debug().Msg("finished job")
+}
+
+// watchCancelRequests cancels the running jobs of this api instance which were cancelled on another api instance.
+// It returns as soon as there are no running jobs anymore.
+func watchCancelRequests() {
+	ctx := log.Logger.WithContext(context.Background())
+	ticker := time.NewTicker(jobCancelInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		running := runningJobs()
+		if len(running) == 0 {
+			return
+		}
+		db, err := database.GetDB(ctx)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to get db for checking job cancel requests")
+			continue
+		}
+		for _, j := range running {
+			logger := log.Ctx(ctx).With().Str("job", j.id()).Logger()
+			jobCtx := logger.WithContext(ctx)
+			cancelled, err := db.IsJobCancelRequested(jobCtx, j.id())
+			if err != nil {
+				log.Ctx(jobCtx).Error().Err(err).Msg("failed to check if job was cancelled")
+				continue
+			}
+			if cancelled {
+				j.cancelJob(jobCtx)
+			}
+		}
+	}
+}
+
+// runningJobs returns the jobs of this api instance which are not done yet.
+// If there are none, watchCancelRequests is marked as stopped.
+func runningJobs() []*job {
+	jobs.Lock()
+	defer jobs.Unlock()
+	var running []*job
+	for _, j := range jobs.m {
+		j.Lock()
+		if !isDone(j.state.Status) {
+			running = append(running, j)
+		}
+		j.Unlock()
+	}
+	if len(running) == 0 {
+		jobs.watching = false
+	}
+	return running
+}
+
+func (j *job) id() string {
+	j.Lock()
+	defer j.Unlock()
+	return j.state.ID
+}
+
+// cancelJob marks the job as cancelled and cancels its context.
+func (j *job) cancelJob(ctx context.Context) {
+	j.update(ctx, func(state *database.Job) {
+		if !isDone(state.Status) {
+			state.Status = jobStatusCancelled
+			state.Progress = "cancelled"
+		}
+	})
+	j.cancel()
+	log.Ctx(ctx).Debug().Msg("cancelled job")
+}
+
+// update changes the state of the job and stores it.
+func (j *job) update(ctx context.Context, f func(state *database.Job)) {
+	j.Lock()
+	f(&j.state)
+	j.Unlock()
+	if err := j.save(ctx); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to store job")
+	}
+}
+
+// save stores the state of the job in the database.
+// Cancel requests of other api instances are stored apart from the job, so they are not overwritten.
+func (j *job) save(ctx context.Context) error {
+	// the context of the job might be cancelled already
+	ctx = log.Ctx(ctx).WithContext(context.Background())
+	db, err := database.GetDB(ctx)
+	if err != nil {
+		return err
+	}
+
+	j.Lock()
+	j.state.UpdatedAt = time.Now()
+	j.state.ExpiresAt = j.state.UpdatedAt.Add(jobTTL)
+	state := j.state
+	j.Unlock()
+
+	return db.SetJob(ctx, state)
+}
+
+// setJobProgress sets the progress of the job of the context, if there is one.
+func setJobProgress(ctx context.Context, progress string) {
+	if j, ok := ctx.Value(jobKey).(*job); ok {
+		j.update(ctx, func(state *database.Job) {
+			if !isDone(state.Status) {
+				state.Progress = progress
+			}
+		})
+	}
+}
+
+// getJob returns the job of this instance or from the database.
+// Jobs which were started with another api token than the one of the request are not found.
+func getJob(c echo.Context) (*job, database.Job, error) {
+	id := c.Param("id")
+	jobs.Lock()
+	j, ok := jobs.m[id]
+	jobs.Unlock()
+
+	var state database.Job
+	if ok {
+		j.Lock()
+		state = j.state
+		j.Unlock()
+	} else {
+		ctx := log.Logger.WithContext(context.Background())
+		db, err := database.GetDB(ctx)
+		if err != nil {
+			return nil, database.Job{}, err
+		}
+		state, err = db.GetJob(ctx, id)
+		if err != nil {
+			return nil, database.Job{}, err
+		}
+	}
+
+	if time.Now().After(state.ExpiresAt) {
+		return nil, database.Job{}, tholaerr.NewNotFoundError("job expired")
+	}
+	if t, ok := tokenFromContext(c); ok && t.Name != state.Token {
+		return nil, database.Job{}, tholaerr.NewNotFoundError("job not found")
+	}
+	return j, state, nil
+}
+
+func jobNotFound(c echo.Context, err error) error {
+	if tholaerr.IsNotFoundError(err) {
+		return returnInFormat(c, http.StatusNotFound, tholaerr.OutputError{Error: "job not found"})
+	}
+	return handleError(c, err)
+}
+
+func getJobHandler(c echo.Context) error {
+	_, state, err := getJob(c)
+	if err != nil {
+		return jobNotFound(c, err)
+	}
+	return returnInFormat(c, http.StatusOK, newJobResponse(state))
+}
+
+func cancelJobHandler(c echo.Context) error {
+	j, state, err := getJob(c)
+	if err != nil {
+		return jobNotFound(c, err)
+	}
+	if isDone(state.Status) {
+		return returnInFormat(c, http.StatusConflict, tholaerr.OutputError{Error: "job is already " + state.Status})
+	}
+	ctx := log.Logger.WithContext(context.Background())
+
+	if j != nil {
+		j.cancelJob(ctx)
+		j.Lock()
+		state = j.state
+		j.Unlock()
+		return returnInFormat(c, http.StatusOK, newJobResponse(state))
+	}
+
+	// the job is processed by another api instance, which checks for cancel requests regularly
+	db, err := database.GetDB(ctx)
+	if err != nil {
+		return handleError(c, err)
+	}
+	if err := db.RequestJobCancel(ctx, state.ID, state.ExpiresAt); err != nil {
+		return handleError(c, err)
+	}
+	return returnInFormat(c, http.StatusAccepted, newJobResponse(state))
+}
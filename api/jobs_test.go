@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/inexio/thola/internal/devicelock"
+	"github.com/labstack/echo/v4"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJobs(t *testing.T) {
+	viper.Set("api.format", "json")
+	viper.Set("db.no-cache", true)
+	defer viper.Set("api.format", nil)
+	defer viper.Set("db.no-cache", nil)
+	deviceLocks = devicelock.NewMemoryProvider(devicelock.Config{})
+
+	e := echo.New()
+	e.POST("/check/thola-server", checkTholaServer)
+	e.POST("/check/snmp", checkSNMP)
+	e.GET("/jobs/:id", getJobHandler)
+	e.DELETE("/jobs/:id", cancelJobHandler)
+
+	send := func(method, path, body string) (int, JobResponse, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		var res JobResponse
+		_ = json.Unmarshal(rec.Body.Bytes(), &res)
+		return rec.Code, res, rec.Body.String()
+	}
+	waitFor := func(id string, done func(JobResponse) bool) JobResponse {
+		var res JobResponse
+		require.Eventually(t, func() bool {
+			_, res, _ = send(http.MethodGet, "/jobs/"+id, "")
+			return done(res)
+		}, 5*time.Second, 10*time.Millisecond)
+		return res
+	}
+
+	t.Run("finished", func(t *testing.T) {
+		code, job, body := send(http.MethodPost, "/check/thola-server?async=true", "{}")
+		require.Equal(t, http.StatusAccepted, code, body)
+		assert.Equal(t, "/check/thola-server", job.Route)
+
+		job = waitFor(job.ID, func(res JobResponse) bool { return isDone(res.Status) })
+		assert.Equal(t, jobStatusFinished, job.Status)
+		assert.Equal(t, http.StatusOK, job.StatusCode)
+
+		_, _, body = send(http.MethodGet, "/jobs/"+job.ID, "")
+		var res struct {
+			Result struct {
+				StatusCode int `json:"status_code"`
+			} `json:"result"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(body), &res), body)
+		assert.Equal(t, 0, res.Result.StatusCode)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		release, err := deviceLocks.Acquire(context.Background(), "192.0.2.1")
+		require.NoError(t, err)
+
+		code, job, body := send(http.MethodPost, "/check/snmp?async=true", `{"device_data": {"ip_address": "192.0.2.1"}}`)
+		require.Equal(t, http.StatusAccepted, code, body)
+		waitFor(job.ID, func(res JobResponse) bool { return res.Progress == "waiting for device lock" })
+
+		code, job, body = send(http.MethodDelete, "/jobs/"+job.ID, "")
+		assert.Equal(t, http.StatusOK, code, body)
+		assert.Equal(t, jobStatusCancelled, job.Status)
+		release()
+
+		code, _, _ = send(http.MethodDelete, "/jobs/"+job.ID, "")
+		assert.Equal(t, http.StatusConflict, code)
+
+		time.Sleep(50 * time.Millisecond)
+		_, job, _ = send(http.MethodGet, "/jobs/"+job.ID, "")
+		assert.Equal(t, jobStatusCancelled, job.Status)
+		assert.Nil(t, job.Result)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		code, _, _ := send(http.MethodGet, "/jobs/unknown", "")
+		assert.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("invalid async parameter", func(t *testing.T) {
+		code, _, _ := send(http.MethodPost, "/check/thola-server?async=maybe", "{}")
+		assert.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -30,6 +30,9 @@ func loggerMiddleware() echo.MiddlewareFunc {
 			evt.Str("uri", req.RequestURI)
 			evt.Str("user_agent", req.UserAgent())
 			evt.Int("status", res.Status)
+			if t, ok := tokenFromContext(c); ok {
+				evt.Str("token", t.Name)
+			}
 
 			if err != nil {
 				evt.Err(err)
@@ -0,0 +1,349 @@
+package api
+
+import (
+	"embed"
+	"encoding"
+	"encoding/json"
+	"github.com/inexio/thola/doc"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/labstack/echo/v4"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed swagger-ui.html
+var swaggerUI []byte
+
+// swaggerUIDist contains the scripts and styles of swagger-ui-dist 5.17.14, so the swagger ui works offline.
+//
+//go:embed swagger-ui-dist/swagger-ui.css swagger-ui-dist/swagger-ui-bundle.js
+var swaggerUIDist embed.FS
+
+// openAPIDocument is an OpenAPI 3 document, see https://spec.openapis.org/oas/v3.0.3.
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Tags       []openAPITag                            `json:"tags"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+	Security   []map[string][]string                   `json:"security"`
+}
+
+type openAPIInfo struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Version     string         `json:"version"`
+	License     openAPILicense `json:"license"`
+}
+
+type openAPILicense struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type openAPITag struct {
+	Name string `json:"name"`
+}
+
+type openAPIOperation struct {
+	Tags        []string                    `json:"tags"`
+	Summary     string                      `json:"summary"`
+	OperationID string                      `json:"operationId"`
+	Parameters  []openAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description"`
+	Required    bool           `json:"required"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema        `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+}
+
+var openAPI struct {
+	sync.Once
+	document []byte
+	err      error
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	pathParameterRegexp = regexp.MustCompile(`:([^/]+)`)
+)
+
+// newOpenAPIDocument generates the openapi document from the routes of the api and the types of their requests and responses.
+func newOpenAPIDocument(routes []route) *openAPIDocument {
+	g := schemaGenerator{schemas: make(map[string]*openAPISchema), types: make(map[string]reflect.Type)}
+	d := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:       "Thola",
+			Description: "REST API for Thola.\n\nFor more information look at our Github : https://github.com/inexio/thola",
+			Version:     doc.Version,
+			License:     openAPILicense{Name: "BSD", URL: "https://github.com/inexio/thola/blob/main/LICENSE"},
+		},
+		Paths: make(map[string]map[string]*openAPIOperation),
+		Components: openAPIComponents{
+			Schemas: g.schemas,
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"basicAuth":  {Type: "http", Scheme: "basic"},
+				"bearerAuth": {Type: "http", Scheme: "bearer"},
+			},
+		},
+		Security: []map[string][]string{{}, {"basicAuth": {}}, {"bearerAuth": {}}},
+	}
+
+	errorSchema := g.schema(reflect.TypeOf(tholaerr.OutputError{}))
+	tags := make(map[string]struct{})
+	for _, r := range routes {
+		path := pathParameterRegexp.ReplaceAllString(r.path, "{$1}")
+		if _, ok := d.Paths[path]; !ok {
+			d.Paths[path] = make(map[string]*openAPIOperation)
+		}
+		if _, ok := tags[r.tag]; !ok {
+			tags[r.tag] = struct{}{}
+			d.Tags = append(d.Tags, openAPITag{Name: r.tag})
+		}
+
+		produces := r.produces
+		if produces == nil {
+			produces = []string{echo.MIMEApplicationJSON, echo.MIMEApplicationXML}
+		}
+		op := &openAPIOperation{
+			Tags:        []string{r.tag},
+			Summary:     r.summary,
+			OperationID: operationID(r.method, r.path),
+			Responses: map[string]*openAPIResponse{
+				"200": {Description: "Returns the response.", Content: content(produces, g.schemaOf(r.response))},
+			},
+		}
+		for _, match := range pathParameterRegexp.FindAllStringSubmatch(r.path, -1) {
+			op.Parameters = append(op.Parameters, openAPIParameter{Name: match[1], In: "path", Description: "Path parameter '" + match[1] + "'.", Required: true, Schema: &openAPISchema{Type: "string"}})
+		}
+		if r.request != nil {
+			op.Parameters = append(op.Parameters, openAPIParameter{Name: "async", In: "query", Description: "Process the request asynchronously and return a job.", Schema: &openAPISchema{Type: "boolean"}})
+			op.RequestBody = &openAPIRequestBody{Required: true, Content: content([]string{echo.MIMEApplicationJSON, echo.MIMEApplicationXML}, g.schemaOf(r.request))}
+			op.Responses["202"] = &openAPIResponse{Description: "Returns the job which processes the request.", Content: content(produces, g.schemaOf(JobResponse{}))}
+			op.Responses["400"] = &openAPIResponse{Description: "Returns an error with more details in the body.", Content: content(produces, errorSchema)}
+		}
+		if strings.HasPrefix(r.path, "/jobs/") {
+			op.Responses["404"] = &openAPIResponse{Description: "Returns an error that the job was not found or expired.", Content: content(produces, errorSchema)}
+		}
+		if r.method == http.MethodDelete && strings.HasPrefix(r.path, "/jobs/") {
+			op.Responses["202"] = &openAPIResponse{Description: "Returns the job, which is cancelled by the api instance processing it.", Content: content(produces, g.schemaOf(JobResponse{}))}
+			op.Responses["409"] = &openAPIResponse{Description: "Returns an error that the job is already done.", Content: content(produces, errorSchema)}
+		}
+		d.Paths[path][strings.ToLower(r.method)] = op
+	}
+	return d
+}
+
+// operationID returns an id like "postReadInterfaces" for the route.
+func operationID(method, path string) string {
+	id := strings.ToLower(method)
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '-' || r == ':' || r == '.' }) {
+		id += strings.Title(part)
+	}
+	return id
+}
+
+func content(mimeTypes []string, schema *openAPISchema) map[string]openAPIMediaType {
+	c := make(map[string]openAPIMediaType, len(mimeTypes))
+	for _, m := range mimeTypes {
+		c[m] = openAPIMediaType{Schema: schema}
+	}
+	return c
+}
+
+// schemaGenerator generates schemas from go types by their json encoding. Named structs are added to the components.
+type schemaGenerator struct {
+	schemas map[string]*openAPISchema
+	types   map[string]reflect.Type
+}
+
+func (g *schemaGenerator) schemaOf(i interface{}) *openAPISchema {
+	if i == nil {
+		return nil
+	}
+	return g.schema(reflect.TypeOf(i))
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *openAPISchema {
+	if t == timeType {
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	}
+	if t.Kind() == reflect.Ptr {
+		s := g.schema(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		c := *s
+		c.Nullable = true
+		return &c
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		// the encoding of the type is custom, but most types with a custom encoding are strings or numbers in the underlying type
+		if t.Kind() == reflect.String {
+			return &openAPISchema{Type: "string"}
+		}
+		return &openAPISchema{}
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &openAPISchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &openAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &openAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &openAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &openAPISchema{Type: "string", Format: "byte"}
+		}
+		return &openAPISchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := g.name(t)
+		if _, ok := g.schemas[name]; !ok {
+			// the schema is added before its fields are generated because of recursive types
+			s := &openAPISchema{}
+			g.schemas[name] = s
+			*s = *g.structSchema(t)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + name}
+	default:
+		// interfaces can contain any value
+		return &openAPISchema{}
+	}
+}
+
+// name returns the name of the schema of the type, which is prefixed with its package if the name is not unique.
+func (g *schemaGenerator) name(t reflect.Type) string {
+	name := t.Name()
+	if other, ok := g.types[name]; ok && other != t {
+		name = strings.Title(t.PkgPath()[strings.LastIndex(t.PkgPath(), "/")+1:]) + name
+	}
+	g.types[name] = t
+	return name
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) *openAPISchema {
+	s := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	g.addFields(s, t)
+	return s
+}
+
+// addFields adds the fields of the struct like they are encoded by encoding/json, embedded structs are inlined.
+func (g *schemaGenerator) addFields(s *openAPISchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = g.schema(ft)
+	}
+}
+
+func getOpenAPIDocument(ctx echo.Context) error {
+	openAPI.Do(func() {
+		openAPI.document, openAPI.err = json.Marshal(newOpenAPIDocument(apiRoutes()))
+	})
+	if openAPI.err != nil {
+		return handleError(ctx, openAPI.err)
+	}
+	return ctx.Blob(http.StatusOK, echo.MIMEApplicationJSONCharsetUTF8, openAPI.document)
+}
+
+func getSwaggerUI(ctx echo.Context) error {
+	return ctx.HTMLBlob(http.StatusOK, swaggerUI)
+}
+
+func getSwaggerUIFile(ctx echo.Context) error {
+	file := ctx.Param("file")
+	contentTypes := map[string]string{
+		".css": "text/css; charset=utf-8",
+		".js":  echo.MIMEApplicationJavaScriptCharsetUTF8,
+	}
+	contentType, ok := contentTypes[path.Ext(file)]
+	b, err := swaggerUIDist.ReadFile("swagger-ui-dist/" + file)
+	if !ok || err != nil {
+		return echo.ErrNotFound
+	}
+	// the files only change with the version of thola
+	ctx.Response().Header().Set("Cache-Control", "public, max-age=86400")
+	return ctx.Blob(http.StatusOK, contentType, b)
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPIDocument_routes(t *testing.T) {
+	e := newServer(context.Background(), nil)
+	d := newOpenAPIDocument(apiRoutes())
+
+	for _, r := range e.Routes() {
+		path := pathParameterRegexp.ReplaceAllString(r.Path, "{$1}")
+		_, ok := d.Paths[path][strings.ToLower(r.Method)]
+		assert.True(t, ok, "route '%s %s' is missing in the openapi document", r.Method, r.Path)
+	}
+}
+
+func TestOpenAPIDocument_schemas(t *testing.T) {
+	d := newOpenAPIDocument(apiRoutes())
+
+	op := d.Paths["/read/interfaces"]["post"]
+	require.NotNil(t, op)
+	assert.Equal(t, "#/components/schemas/ReadInterfacesRequest", op.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/ReadInterfacesResponse", op.Responses["200"].Content["application/xml"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/JobResponse", op.Responses["202"].Content["application/json"].Schema.Ref)
+
+	// fields of embedded structs are inlined like they are encoded
+	require.Contains(t, d.Components.Schemas, "ReadInterfacesRequest")
+	assert.Contains(t, d.Components.Schemas["ReadInterfacesRequest"].Properties, "device_data")
+	assert.Equal(t, "date-time", d.Components.Schemas["JobResponse"].Properties["created_at"].Format)
+	assert.NotContains(t, d.Components.Schemas["JobResponse"].Properties, "XMLName")
+
+	params := d.Paths["/jobs/{id}"]["get"].Parameters
+	require.Len(t, params, 1)
+	assert.Equal(t, "path", params[0].In)
+}
+
+func TestGetOpenAPIDocument(t *testing.T) {
+	e := newServer(context.Background(), nil)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	var d map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &d))
+	assert.Equal(t, "3.0.3", d["openapi"])
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "openapi.json")
+	assert.NotContains(t, rec.Body.String(), "https://", "the swagger ui does not load files from the internet")
+
+	for file, contentType := range map[string]string{"swagger-ui.css": "text/css", "swagger-ui-bundle.js": echo.MIMEApplicationJavaScript} {
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs/"+file, nil))
+		require.Equal(t, http.StatusOK, rec.Code, file)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), contentType)
+		assert.NotEmpty(t, rec.Body.Bytes())
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs/LICENSE", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
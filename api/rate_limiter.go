@@ -16,23 +16,33 @@ var (
 	store         limiter.Store
 )
 
-func ipRateLimit() echo.MiddlewareFunc {
+// rateLimit limits the requests per ip address to the global rate limit. Requests with an api token
+// that has its own rate limit are limited per token instead.
+func rateLimit() echo.MiddlewareFunc {
+	if viper.GetString("api.ratelimit") != "" {
+		rate, err := limiter.NewRateFromFormatted(viper.GetString("api.ratelimit"))
+		if err != nil {
+			log.Error().Msg("Wrong format for ratelimit")
+			os.Exit(1)
+		}
 
-	rate, err := limiter.NewRateFromFormatted(viper.GetString("api.ratelimit"))
-	if err != nil {
-		log.Error().Msg("Wrong format for ratelimit")
-		os.Exit(1)
+		store = memory.NewStore()
+		ipRateLimiter = limiter.New(store, rate)
 	}
 
-	store = memory.NewStore()
-	ipRateLimiter = limiter.New(store, rate)
-
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) (err error) {
-			ip := c.RealIP()
-			limiterCtx, err := ipRateLimiter.Get(c.Request().Context(), ip)
+			rateLimiter, key := ipRateLimiter, c.RealIP()
+			if t, ok := tokenFromContext(c); ok && t.limiter != nil {
+				rateLimiter, key = t.limiter, "token:"+t.Name
+			}
+			if rateLimiter == nil {
+				return next(c)
+			}
+
+			limiterCtx, err := rateLimiter.Get(c.Request().Context(), key)
 			if err != nil {
-				log.Printf("ipRateLimit - ipRateLimiter.Get - err: %v, %s on %s", err, ip, c.Request().URL)
+				log.Printf("rateLimit - rateLimiter.Get - err: %v, %s on %s", err, key, c.Request().URL)
 				return handleError(c, err)
 			}
 
@@ -42,7 +52,7 @@ func ipRateLimit() echo.MiddlewareFunc {
 			h.Set("X-RateLimit-Reset", strconv.FormatInt(limiterCtx.Reset, 10))
 
 			if limiterCtx.Reached {
-				log.Printf("Too Many Requests from %s on %s", ip, c.Request().URL)
+				log.Printf("Too Many Requests from %s on %s", key, c.Request().URL)
 				return handleError(c, tholaerr.NewTooManyRequestsError("Too Many Requests on "+c.Request().URL.String()))
 			}
 
@@ -2,27 +2,51 @@ package api
 
 import (
 	"context"
-	"crypto/subtle"
 	"fmt"
 	"github.com/inexio/thola/api/statistics"
 	"github.com/inexio/thola/internal/database"
+	"github.com/inexio/thola/internal/devicelock"
 	"github.com/inexio/thola/internal/request"
 	"github.com/inexio/thola/internal/tholaerr"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 	"net/http"
 	"os"
 	"os/signal"
-	"sync"
+	"strconv"
 	"time"
 )
 
-var deviceLocks struct {
-	sync.RWMutex
+var deviceLocks devicelock.Provider
 
-	locks map[string]*sync.Mutex
+// newServer returns the echo server with all middlewares and routes of the api.
+func newServer(ctx context.Context, tokens []*Token) *echo.Echo {
+	e := echo.New()
+	e.HideBanner = true
+
+	if len(tokens) > 0 || (viper.GetString("api.username") != "" && viper.GetString("api.password") != "") {
+		log.Ctx(ctx).Debug().Int("tokens", len(tokens)).Msg("set authorization for api")
+		e.Use(authMiddleware(tokens, viper.GetString("api.username"), viper.GetString("api.password")))
+	}
+
+	rateLimitPerToken := false
+	for _, t := range tokens {
+		rateLimitPerToken = rateLimitPerToken || t.limiter != nil
+	}
+	if viper.GetString("api.ratelimit") != "" || rateLimitPerToken {
+		log.Ctx(ctx).Debug().Msg("set ratelimit for api")
+		e.Use(rateLimit())
+	}
+
+	e.Use(statistics.Middleware())
+
+	e.Use(requestIDMiddleware())
+
+	e.Use(loggerMiddleware())
+
+	registerRoutes(e)
+	return e
 }
 
 // StartAPI starts the API.
@@ -36,638 +60,41 @@ func StartAPI() {
 		log.Fatal().Err(err).Msg("starting the server failed")
 	}
 
-	deviceLocks.locks = make(map[string]*sync.Mutex)
-	e := echo.New()
+	deviceLocks, err = devicelock.New()
+	if err != nil {
+		log.Fatal().Err(err).Msg("starting the server failed")
+	}
 
-	e.HideBanner = true
 	fmt.Print(" ______   __  __     ______     __         ______   \n" +
 		"/\\__  _\\ /\\ \\_\\ \\   /\\  __ \\   /\\ \\       /\\  __ \\  \n" +
 		"\\/_/\\ \\/ \\ \\  __ \\  \\ \\ \\/\\ \\  \\ \\ \\____  \\ \\  __ \\ \n" +
 		"   \\ \\_\\  \\ \\_\\ \\_\\  \\ \\_____\\  \\ \\_____\\  \\ \\_\\ \\_\\\n" +
 		"    \\/_/   \\/_/\\/_/   \\/_____/   \\/_____/   \\/_/\\/_/\n\n")
 
-	if (viper.GetString("api.username") != "") && (viper.GetString("api.password") != "") {
-		log.Ctx(ctx).Debug().Msg("set authorization for api")
-		e.Use(middleware.BasicAuth(func(username, password string, c echo.Context) (bool, error) {
-			// Be careful to use constant time comparison to prevent timing attacks
-			if subtle.ConstantTimeCompare([]byte(username), []byte(viper.GetString("restapi.username"))) == 1 &&
-				subtle.ConstantTimeCompare([]byte(password), []byte(viper.GetString("restapi.password"))) == 1 {
-				return true, nil
-			}
-			return false, nil
-		}))
-	}
+	statistics.SetMaxDevices(viper.GetInt("api.statistics-max-devices"))
 
-	if viper.GetString("api.ratelimit") != "" {
-		log.Ctx(ctx).Debug().Msg("set ratelimit for api")
-		e.Use(ipRateLimit())
+	if ttl := viper.GetString("api.job-ttl"); ttl != "" {
+		jobTTL, err = time.ParseDuration(ttl)
+		if err != nil {
+			log.Fatal().Err(err).Msg("starting the server failed, invalid job ttl")
+		}
 	}
 
-	e.Use(statistics.Middleware())
-
-	e.Use(requestIDMiddleware())
-
-	e.Use(loggerMiddleware())
-
-	// swagger:operation POST /identify identify identify
-	// ---
-	// summary: Identifies a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json<
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/IdentifyRequest'
-	// responses:
-	//   200:
-	//     description: Returns the device which was found.
-	//     schema:
-	//       $ref: '#/definitions/IdentifyResponse'
-	//   400:
-	//     description: Returns a string that the request was formatted wrong.
-	//   404:
-	//     description: Returns a string that no device was found.
-	e.POST("/identify", identify)
-
-	// swagger:operation POST /check/identify check checkIdentify
-	// ---
-	// summary: Checks if identify matches the expectations.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/CheckIdentifyRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/CheckIdentifyResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/check/identify", checkIdentify)
-
-	// swagger:operation POST /check/snmp check checkSNMP
-	// ---
-	// summary: Checks SNMP availability.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/CheckSNMPRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/CheckSNMPResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/check/snmp", checkSNMP)
-
-	// swagger:operation POST /check/interface-metrics check checkInterfaceMetrics
-	// ---
-	// summary: Check to read out interface metrics.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/CheckInterfaceMetricsRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/CheckResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/check/interface-metrics", checkInterfaceMetrics)
-
-	// swagger:operation POST /check/thola-server check checkTholaServer
-	// ---
-	// summary: Check existence of thola servers.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/CheckTholaServerRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/CheckResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/check/thola-server", checkTholaServer)
-
-	// swagger:operation POST /check/ups check checkUPS
-	// ---
-	// summary: Checks whether a UPS device has its main voltage applied.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/CheckUPSRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/CheckResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/check/ups", checkUPS)
-
-	// swagger:operation POST /check/memory-usage check checkMemoryUsage
-	// ---
-	// summary: Check the memory usage of a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/CheckMemoryUsageRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/CheckResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/check/memory-usage", checkMemoryUsage)
-
-	// swagger:operation POST /check/cpu-load check checkCPULoad
-	// ---
-	// summary: Check the cpu load of a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/CheckCPULoadRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/CheckResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/check/cpu-load", checkCPULoad)
-
-	// swagger:operation POST /check/sbc check checkSBC
-	// ---
-	// summary: Check an sbc device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/CheckSBCRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/CheckResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/check/sbc", checkSBC)
-
-	// swagger:operation POST /check/server check checkServer
-	// ---
-	// summary: Check a linux server.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/CheckServerRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/CheckResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/check/server", checkServer)
-
-	// swagger:operation POST /check/disk check checkDisk
-	// ---
-	// summary: Check the disk of a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/CheckDiskRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/CheckResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/check/disk", checkDisk)
-
-	// swagger:operation POST /check/hardware-health check checkHardwareHealth
-	// ---
-	// summary: Check an hardware health of an device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/CheckHardwareHealthRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/CheckResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/check/hardware-health", checkHardwareHealth)
-
-	// swagger:operation POST /read/interfaces read readInterfaces
-	// ---
-	// summary: Reads out data of the interfaces of a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/ReadInterfacesRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/ReadInterfacesResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/read/interfaces", readInterfaces)
-
-	// swagger:operation POST /read/count-interfaces read readCountInterfaces
-	// ---
-	// summary: Counts the interfaces of a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/ReadCountInterfacesRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/ReadCountInterfacesResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/read/count-interfaces", readCountInterfaces)
-
-	// swagger:operation POST /read/cpu-load read readCPULoad
-	// ---
-	// summary: Read out the CPU load of a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/ReadCPULoadRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/ReadCPULoadResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/read/cpu-load", readCPULoad)
-
-	// swagger:operation POST /read/memory-usage read readMemoryUsage
-	// ---
-	// summary: Read out the memory usage of a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/ReadMemoryUsageRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/ReadMemoryUsageResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/read/memory-usage", readMemoryUsage)
-
-	// swagger:operation POST /read/ups read readUPS
-	// ---
-	// summary: Reads out UPS data of a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/ReadUPSRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/ReadUPSResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/read/ups", readUPS)
-
-	// swagger:operation POST /read/sbc read readSBC
-	// ---
-	// summary: Reads out SBC data of a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/ReadSBCRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/ReadSBCResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/read/sbc", readSBC)
-
-	// swagger:operation POST /read/server read readServer
-	// ---
-	// summary: Reads out server data of a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/ReadServerRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/ReadServerResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/read/server", readServer)
-
-	// swagger:operation POST /read/disk read readDisk
-	// ---
-	// summary: Reads out disk data of a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/ReadDiskRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/ReadDiskResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/read/disk", readDisk)
-
-	// swagger:operation POST /read/hardware-health read hardware-health
-	// ---
-	// summary: Reads out hardware health data of a device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/ReadHardwareHealthRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/ReadHardwareHealthResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/read/hardware-health", readHardwareHealth)
-
-	// swagger:operation POST /read/available-components read readAvailableComponents
-	// ---
-	// summary: Returns the available components for the device.
-	// consumes:
-	// - application/json
-	// - application/xml
-	// produces:
-	// - application/json
-	// - application/xml
-	// parameters:
-	// - name: body
-	//   in: body
-	//   description: Request to process.
-	//   required: true
-	//   schema:
-	//     $ref: '#/definitions/ReadAvailableComponentsRequest'
-	// responses:
-	//   200:
-	//     description: Returns the response.
-	//     schema:
-	//       $ref: '#/definitions/ReadAvailableComponentsResponse'
-	//   400:
-	//     description: Returns an error with more details in the body.
-	//     schema:
-	//       $ref: '#/definitions/OutputError'
-	e.POST("/read/available-components", readAvailableComponents)
+	tokens, err := loadTokens()
+	if err != nil {
+		log.Fatal().Err(err).Msg("starting the server failed")
+	}
+	e := newServer(ctx, tokens)
 
 	// Start server
 	go func() {
 		var err error
 		if viper.GetString("api.certfile") != "" && viper.GetString("api.keyfile") != "" {
-			err = e.StartTLS(":"+viper.GetString("api.port"), viper.GetString("api.certfile"), viper.GetString("api.keyfile"))
+			e.TLSServer.Addr = ":" + viper.GetString("api.port")
+			e.TLSServer.TLSConfig, err = tlsConfig(viper.GetString("api.certfile"), viper.GetString("api.keyfile"), viper.GetString("api.client-ca-file"))
+			if err == nil {
+				err = e.StartServer(e.TLSServer)
+			}
 		} else {
 			err = e.Start(":" + viper.GetString("api.port"))
 		}
@@ -704,11 +131,7 @@ func identify(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func checkIdentify(ctx echo.Context) error {
@@ -716,11 +139,7 @@ func checkIdentify(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func checkSNMP(ctx echo.Context) error {
@@ -728,11 +147,7 @@ func checkSNMP(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func checkInterfaceMetrics(ctx echo.Context) error {
@@ -740,11 +155,7 @@ func checkInterfaceMetrics(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func checkTholaServer(ctx echo.Context) error {
@@ -752,11 +163,7 @@ func checkTholaServer(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, nil)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, nil)
 }
 
 func checkUPS(ctx echo.Context) error {
@@ -764,11 +171,7 @@ func checkUPS(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func checkMemoryUsage(ctx echo.Context) error {
@@ -776,11 +179,7 @@ func checkMemoryUsage(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func checkCPULoad(ctx echo.Context) error {
@@ -788,11 +187,7 @@ func checkCPULoad(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func checkSBC(ctx echo.Context) error {
@@ -800,11 +195,7 @@ func checkSBC(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func checkServer(ctx echo.Context) error {
@@ -812,11 +203,7 @@ func checkServer(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func checkDisk(ctx echo.Context) error {
@@ -824,11 +211,7 @@ func checkDisk(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func checkHardwareHealth(ctx echo.Context) error {
@@ -836,11 +219,15 @@ func checkHardwareHealth(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
+}
+
+func checkSNMPValue(ctx echo.Context) error {
+	r := request.CheckSNMPValueRequest{}
+	if err := ctx.Bind(&r); err != nil {
+		return err
 	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func readInterfaces(ctx echo.Context) error {
@@ -848,11 +235,7 @@ func readInterfaces(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func readCountInterfaces(ctx echo.Context) error {
@@ -860,11 +243,7 @@ func readCountInterfaces(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func readCPULoad(ctx echo.Context) error {
@@ -872,11 +251,7 @@ func readCPULoad(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func readMemoryUsage(ctx echo.Context) error {
@@ -884,11 +259,7 @@ func readMemoryUsage(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func readUPS(ctx echo.Context) error {
@@ -896,11 +267,7 @@ func readUPS(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func readSBC(ctx echo.Context) error {
@@ -908,11 +275,7 @@ func readSBC(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func readServer(ctx echo.Context) error {
@@ -920,11 +283,7 @@ func readServer(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func readDisk(ctx echo.Context) error {
@@ -932,11 +291,7 @@ func readDisk(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
-	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func readHardwareHealth(ctx echo.Context) error {
@@ -944,11 +299,15 @@ func readHardwareHealth(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
-	if err != nil {
-		return handleError(ctx, err)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
+}
+
+func readSNMPValue(ctx echo.Context) error {
+	r := request.ReadSNMPValueRequest{}
+	if err := ctx.Bind(&r); err != nil {
+		return err
 	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
 }
 
 func readAvailableComponents(ctx echo.Context) error {
@@ -956,27 +315,57 @@ func readAvailableComponents(ctx echo.Context) error {
 	if err := ctx.Bind(&r); err != nil {
 		return err
 	}
-	resp, err := handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
+}
+
+func snmpGet(ctx echo.Context) error {
+	r := request.SNMPGetRequest{}
+	if err := ctx.Bind(&r); err != nil {
+		return err
+	}
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
+}
+
+func snmpWalk(ctx echo.Context) error {
+	r := request.SNMPWalkRequest{}
+	if err := ctx.Bind(&r); err != nil {
+		return err
+	}
+	return handleAPIRequest(ctx, &r, &r.BaseRequest.DeviceData.IPAddress)
+}
+
+func getStatistics(ctx echo.Context) error {
+	stats, err := statistics.GetStatistics()
 	if err != nil {
 		return handleError(ctx, err)
 	}
-	return returnInFormat(ctx, http.StatusOK, resp)
+	// the statistics contain maps, which cannot be encoded as xml
+	return ctx.JSON(http.StatusOK, stats)
 }
 
 func handleError(ctx echo.Context, err error) error {
+	statusCode, output := errorResponse(err)
+	return returnInFormat(ctx, statusCode, output)
+}
+
+// errorResponse returns the status code and the output of an error.
+func errorResponse(err error) (int, tholaerr.OutputError) {
 	if tholaerr.IsNetworkError(err) {
-		return returnInFormat(ctx, http.StatusBadRequest, tholaerr.OutputError{Error: "Network error: " + err.Error()})
+		return http.StatusBadRequest, tholaerr.OutputError{Error: "Network error: " + err.Error()}
 	}
 	if tholaerr.IsNotImplementedError(err) {
-		return returnInFormat(ctx, http.StatusInternalServerError, tholaerr.OutputError{Error: "Function not implemented: " + err.Error()})
+		return http.StatusInternalServerError, tholaerr.OutputError{Error: "Function not implemented: " + err.Error()}
 	}
 	if tholaerr.IsNotFoundError(err) {
-		return returnInFormat(ctx, http.StatusNotAcceptable, tholaerr.OutputError{Error: "Not found: " + err.Error()})
+		return http.StatusNotAcceptable, tholaerr.OutputError{Error: "Not found: " + err.Error()}
 	}
 	if tholaerr.IsTooManyRequestsError(err) {
-		return returnInFormat(ctx, http.StatusTooManyRequests, tholaerr.OutputError{Error: "Too many requests: " + err.Error()})
+		return http.StatusTooManyRequests, tholaerr.OutputError{Error: "Too many requests: " + err.Error()}
+	}
+	if tholaerr.IsForbiddenError(err) {
+		return http.StatusForbidden, tholaerr.OutputError{Error: "Forbidden: " + err.Error()}
 	}
-	return returnInFormat(ctx, http.StatusBadRequest, tholaerr.OutputError{Error: "Request failed: " + err.Error()})
+	return http.StatusBadRequest, tholaerr.OutputError{Error: "Request failed: " + err.Error()}
 }
 
 func returnInFormat(ctx echo.Context, statusCode int, resp interface{}) error {
@@ -988,35 +377,57 @@ func returnInFormat(ctx echo.Context, statusCode int, resp interface{}) error {
 	return ctx.String(http.StatusInternalServerError, "Invalid output format set")
 }
 
-func getDeviceLock(ip string) *sync.Mutex {
-	deviceLocks.RLock()
-	lock, ok := deviceLocks.locks[ip]
-	deviceLocks.RUnlock()
-	if !ok {
-		deviceLocks.Lock()
-		if lock, ok = deviceLocks.locks[ip]; !ok {
-			lock = &sync.Mutex{}
-			deviceLocks.locks[ip] = lock
+// handleAPIRequest processes the request and writes its response.
+// If the query parameter "async" is set, a job is started instead and returned immediately.
+func handleAPIRequest(echoCTX echo.Context, r request.Request, ip *string) error {
+	logger := log.With().Str("request_id", echoCTX.Request().Header.Get(echo.HeaderXRequestID)).Logger()
+	ctx := logger.WithContext(context.Background())
+
+	if ip != nil {
+		if err := authorizeDevice(echoCTX, *ip); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("request was rejected")
+			return handleError(echoCTX, err)
 		}
-		deviceLocks.Unlock()
 	}
-	return lock
-}
 
-func handleAPIRequest(echoCTX echo.Context, r request.Request, ip *string) (request.Response, error) {
-	logger := log.With().Str("request_id", echoCTX.Request().Header.Get(echo.HeaderXRequestID)).Logger()
-	ctx := logger.WithContext(context.Background())
+	ctx = statistics.NewContext(ctx, echoCTX)
+	if ip != nil {
+		statistics.SetDevice(ctx, *ip)
+	}
+
+	if echoCTX.QueryParam("async") != "" {
+		async, err := strconv.ParseBool(echoCTX.QueryParam("async"))
+		if err != nil {
+			return returnInFormat(echoCTX, http.StatusBadRequest, tholaerr.OutputError{Error: "invalid value for query parameter 'async'"})
+		}
+		if async {
+			return startJob(ctx, echoCTX, r, ip)
+		}
+	}
+
+	resp, err := processAPIRequest(ctx, r, ip)
+	if err != nil {
+		return handleError(echoCTX, err)
+	}
+	return returnInFormat(echoCTX, http.StatusOK, resp)
+}
 
+// processAPIRequest processes the request while holding the lock of the device.
+func processAPIRequest(ctx context.Context, r request.Request, ip *string) (request.Response, error) {
 	if ip != nil && !viper.GetBool("request.no-ip-lock") {
-		lock := getDeviceLock(*ip)
-		lock.Lock()
+		setJobProgress(ctx, "waiting for device lock")
+		release, err := deviceLocks.Acquire(ctx, *ip)
+		if err != nil {
+			return nil, err
+		}
 		defer func() {
-			lock.Unlock()
+			release()
 			log.Ctx(ctx).Debug().Msg("unlocked IP " + *ip)
 		}()
 
 		log.Ctx(ctx).Debug().Msg("locked IP " + *ip)
 	}
 
+	setJobProgress(ctx, "processing request")
 	return request.ProcessRequest(ctx, r)
 }
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"github.com/inexio/thola/api/statistics"
+	"github.com/inexio/thola/internal/request"
+	"github.com/labstack/echo/v4"
+	"net/http"
+)
+
+// route is an endpoint of the api. The routes are registered on the server
+// and the openapi document is generated from them, so they cannot drift apart.
+type route struct {
+	method  string
+	path    string
+	tag     string
+	summary string
+	handler echo.HandlerFunc
+
+	// request is the type of the request body. Routes with a request can be processed asynchronously as jobs.
+	request interface{}
+	// response is the type of a successful response, nil if the response is not structured.
+	response interface{}
+	// produces overrides the default content types of the response, which are json and xml.
+	produces []string
+}
+
+func apiRoutes() []route {
+	return []route{
+		{http.MethodPost, "/identify", "identify", "Identifies a device.", identify, request.IdentifyRequest{}, request.IdentifyResponse{}, nil},
+
+		{http.MethodPost, "/check/identify", "check", "Checks if identify matches the expectations.", checkIdentify, request.CheckIdentifyRequest{}, request.CheckIdentifyResponse{}, nil},
+		{http.MethodPost, "/check/snmp", "check", "Checks SNMP availability.", checkSNMP, request.CheckSNMPRequest{}, request.CheckSNMPResponse{}, nil},
+		{http.MethodPost, "/check/interface-metrics", "check", "Check to read out interface metrics.", checkInterfaceMetrics, request.CheckInterfaceMetricsRequest{}, request.CheckResponse{}, nil},
+		{http.MethodPost, "/check/thola-server", "check", "Check existence of thola servers.", checkTholaServer, request.CheckTholaServerRequest{}, request.CheckResponse{}, nil},
+		{http.MethodPost, "/check/ups", "check", "Checks whether a UPS device has its main voltage applied.", checkUPS, request.CheckUPSRequest{}, request.CheckResponse{}, nil},
+		{http.MethodPost, "/check/memory-usage", "check", "Check the memory usage of a device.", checkMemoryUsage, request.CheckMemoryUsageRequest{}, request.CheckResponse{}, nil},
+		{http.MethodPost, "/check/cpu-load", "check", "Check the cpu load of a device.", checkCPULoad, request.CheckCPULoadRequest{}, request.CheckResponse{}, nil},
+		{http.MethodPost, "/check/sbc", "check", "Check an sbc device.", checkSBC, request.CheckSBCRequest{}, request.CheckResponse{}, nil},
+		{http.MethodPost, "/check/server", "check", "Check a linux server.", checkServer, request.CheckServerRequest{}, request.CheckResponse{}, nil},
+		{http.MethodPost, "/check/disk", "check", "Check the disk of a device.", checkDisk, request.CheckDiskRequest{}, request.CheckResponse{}, nil},
+		{http.MethodPost, "/check/hardware-health", "check", "Check the hardware health of a device.", checkHardwareHealth, request.CheckHardwareHealthRequest{}, request.CheckResponse{}, nil},
+		{http.MethodPost, "/check/snmp-value", "check", "Checks custom snmp values of a device.", checkSNMPValue, request.CheckSNMPValueRequest{}, request.CheckResponse{}, nil},
+
+		{http.MethodPost, "/read/interfaces", "read", "Reads out data of the interfaces of a device.", readInterfaces, request.ReadInterfacesRequest{}, request.ReadInterfacesResponse{}, nil},
+		{http.MethodPost, "/read/count-interfaces", "read", "Counts the interfaces of a device.", readCountInterfaces, request.ReadCountInterfacesRequest{}, request.ReadCountInterfacesResponse{}, nil},
+		{http.MethodPost, "/read/cpu-load", "read", "Read out the CPU load of a device.", readCPULoad, request.ReadCPULoadRequest{}, request.ReadCPULoadResponse{}, nil},
+		{http.MethodPost, "/read/memory-usage", "read", "Read out the memory usage of a device.", readMemoryUsage, request.ReadMemoryUsageRequest{}, request.ReadMemoryUsageResponse{}, nil},
+		{http.MethodPost, "/read/ups", "read", "Reads out UPS data of a device.", readUPS, request.ReadUPSRequest{}, request.ReadUPSResponse{}, nil},
+		{http.MethodPost, "/read/sbc", "read", "Reads out SBC data of a device.", readSBC, request.ReadSBCRequest{}, request.ReadSBCResponse{}, nil},
+		{http.MethodPost, "/read/server", "read", "Reads out server data of a device.", readServer, request.ReadServerRequest{}, request.ReadServerResponse{}, nil},
+		{http.MethodPost, "/read/disk", "read", "Reads out disk data of a device.", readDisk, request.ReadDiskRequest{}, request.ReadDiskResponse{}, nil},
+		{http.MethodPost, "/read/hardware-health", "read", "Reads out hardware health data of a device.", readHardwareHealth, request.ReadHardwareHealthRequest{}, request.ReadHardwareHealthResponse{}, nil},
+		{http.MethodPost, "/read/snmp-value", "read", "Reads out custom snmp values of a device.", readSNMPValue, request.ReadSNMPValueRequest{}, request.ReadSNMPValueResponse{}, nil},
+		{http.MethodPost, "/read/available-components", "read", "Returns the available components for the device.", readAvailableComponents, request.ReadAvailableComponentsRequest{}, request.ReadAvailableComponentsResponse{}, nil},
+
+		{http.MethodPost, "/snmp/get", "snmp", "Sends a snmpget request to a device.", snmpGet, request.SNMPGetRequest{}, request.SNMPResponse{}, nil},
+		{http.MethodPost, "/snmp/walk", "snmp", "Sends a snmpwalk request to a device.", snmpWalk, request.SNMPWalkRequest{}, request.SNMPResponse{}, nil},
+
+		{http.MethodGet, "/jobs/:id", "jobs", "Returns the state of a job and the response of its request once it is done.", getJobHandler, nil, JobResponse{}, nil},
+		{http.MethodDelete, "/jobs/:id", "jobs", "Cancels a job which is not done yet.", cancelJobHandler, nil, JobResponse{}, nil},
+
+		// the statistics contain maps, which cannot be encoded as xml
+		{http.MethodGet, "/statistics", "statistics", "Returns statistics about the requests handled by the api.", getStatistics, nil, statistics.Statistics{}, []string{echo.MIMEApplicationJSON}},
+		{http.MethodGet, "/metrics", "statistics", "Returns the statistics in the prometheus exposition format.", echo.WrapHandler(statistics.PrometheusHandler()), nil, nil, []string{echo.MIMETextPlain}},
+
+		{http.MethodGet, "/openapi.json", "documentation", "Returns the openapi document of the api.", getOpenAPIDocument, nil, nil, []string{echo.MIMEApplicationJSON}},
+		{http.MethodGet, "/docs", "documentation", "Returns the swagger ui for the openapi document.", getSwaggerUI, nil, nil, []string{echo.MIMETextHTML}},
+		{http.MethodGet, "/docs/:file", "documentation", "Returns the scripts and styles of the swagger ui.", getSwaggerUIFile, nil, nil, []string{"text/css", echo.MIMEApplicationJavaScript}},
+	}
+}
+
+func registerRoutes(e *echo.Echo) {
+	for _, r := range apiRoutes() {
+		e.Add(r.method, r.path, r.handler)
+	}
+}
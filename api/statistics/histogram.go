@@ -0,0 +1,151 @@
+package statistics
+
+import (
+	"math"
+	"time"
+)
+
+// buckets are the upper bounds of the response time histograms in seconds.
+var buckets = [...]float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// histogram counts the response times per bucket, the last count belongs to the +Inf bucket.
+type histogram struct {
+	counts [len(buckets) + 1]uint64
+	count  uint64
+	sum    float64
+}
+
+func (h *histogram) observe(d time.Duration) {
+	seconds := d.Seconds()
+	i := 0
+	for i < len(buckets) && seconds > buckets[i] {
+		i++
+	}
+	h.counts[i]++
+	h.count++
+	h.sum += seconds
+}
+
+func (h *histogram) merge(o *histogram) {
+	for i := range h.counts {
+		h.counts[i] += o.counts[i]
+	}
+	h.count += o.count
+	h.sum += o.sum
+}
+
+// cumulative returns the cumulative counts of the finite buckets by their upper bound.
+func (h *histogram) cumulative() map[float64]uint64 {
+	res := make(map[float64]uint64, len(buckets))
+	var c uint64
+	for i, upper := range buckets {
+		c += h.counts[i]
+		res[upper] = c
+	}
+	return res
+}
+
+// quantile estimates the q-quantile by interpolating linearly within the bucket that contains it,
+// like the histogram_quantile function of Prometheus does.
+// If it is in the +Inf bucket, the highest finite upper bound is returned.
+func (h *histogram) quantile(q float64) float64 {
+	if h.count == 0 {
+		return 0
+	}
+	rank := q * float64(h.count)
+	var c uint64
+	lower := 0.0
+	for i, upper := range buckets {
+		if float64(c+h.counts[i]) >= rank {
+			if h.counts[i] == 0 {
+				return upper
+			}
+			return lower + (upper-lower)*(rank-float64(c))/float64(h.counts[i])
+		}
+		c += h.counts[i]
+		lower = upper
+	}
+	return lower
+}
+
+// requestStatistics contains the counters and the response time histogram of requests.
+type requestStatistics struct {
+	successfulCounter int
+	failedCounter     int
+	responseTimes     histogram
+}
+
+func (r *requestStatistics) add(d time.Duration, successful bool) {
+	if successful {
+		r.successfulCounter++
+	} else {
+		r.failedCounter++
+	}
+	r.responseTimes.observe(d)
+}
+
+func (r *requestStatistics) merge(o *requestStatistics) {
+	r.successfulCounter += o.successfulCounter
+	r.failedCounter += o.failedCounter
+	r.responseTimes.merge(&o.responseTimes)
+}
+
+func (r *requestStatistics) get() RequestStatistics {
+	s := RequestStatistics{
+		TotalCount:        r.successfulCounter + r.failedCounter,
+		SuccessfulCounter: r.successfulCounter,
+		FailedCounter:     r.failedCounter,
+	}
+	if s.TotalCount == 0 {
+		return s
+	}
+
+	s.ErrorRatio = roundMilli(float64(s.FailedCounter) / float64(s.TotalCount))
+	s.AverageResponseTime = roundMilli(r.responseTimes.sum / float64(s.TotalCount))
+	s.P50ResponseTime = roundMilli(r.responseTimes.quantile(0.5))
+	s.P90ResponseTime = roundMilli(r.responseTimes.quantile(0.9))
+	s.P95ResponseTime = roundMilli(r.responseTimes.quantile(0.95))
+	s.P99ResponseTime = roundMilli(r.responseTimes.quantile(0.99))
+	var c uint64
+	for i, upper := range buckets {
+		c += r.responseTimes.counts[i]
+		s.ResponseTimeHistogram = append(s.ResponseTimeHistogram, HistogramBucket{UpperBound: upper, Count: c})
+	}
+	return s
+}
+
+// windowSlots is the amount of minutes that are covered by the window.
+const windowSlots = 5
+
+// window contains the request statistics of the last minutes in one slot per minute.
+type window struct {
+	slots [windowSlots]struct {
+		minute int64
+		requestStatistics
+	}
+}
+
+func (w *window) add(now time.Time, d time.Duration, successful bool) {
+	minute := now.Unix() / 60
+	slot := &w.slots[minute%windowSlots]
+	if slot.minute != minute {
+		slot.minute = minute
+		slot.requestStatistics = requestStatistics{}
+	}
+	slot.add(d, successful)
+}
+
+func (w *window) get(now time.Time) requestStatistics {
+	minute := now.Unix() / 60
+	var res requestStatistics
+	for i := range w.slots {
+		if minute-w.slots[i].minute < windowSlots {
+			res.merge(&w.slots[i].requestStatistics)
+		}
+	}
+	return res
+}
+
+func roundMilli(f float64) float64 {
+	return math.Floor(f*1000) / 1000
+}
@@ -0,0 +1,86 @@
+package statistics
+
+import (
+	"github.com/inexio/thola/internal/database"
+	"github.com/inexio/thola/internal/network"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+var (
+	upSinceDesc = prometheus.NewDesc("thola_up_since_seconds",
+		"Start time of the api since unix epoch in seconds.", nil, nil)
+	routeRequestsDesc = prometheus.NewDesc("thola_api_requests_total",
+		"Number of handled api requests per route.", []string{"method", "route", "status"}, nil)
+	routeDurationDesc = prometheus.NewDesc("thola_api_request_duration_seconds",
+		"Response time of api requests per route.", []string{"method", "route"}, nil)
+	deviceClassRequestsDesc = prometheus.NewDesc("thola_device_class_requests_total",
+		"Number of handled api requests per device class.", []string{"device_class", "status"}, nil)
+	deviceClassDurationDesc = prometheus.NewDesc("thola_device_class_request_duration_seconds",
+		"Response time of api requests per device class.", []string{"device_class"}, nil)
+	deviceRequestsDesc = prometheus.NewDesc("thola_device_requests_total",
+		"Number of handled api requests per device.", []string{"device", "status"}, nil)
+	snmpRequestsDesc = prometheus.NewDesc("thola_snmp_requests_total",
+		"Number of snmp packets sent per device.", []string{"device"}, nil)
+	snmpTimeoutsDesc = prometheus.NewDesc("thola_snmp_timeouts_total",
+		"Number of snmp packets per device that were not answered in time.", []string{"device"}, nil)
+	cacheHitsDesc = prometheus.NewDesc("thola_cache_hits_total",
+		"Number of cache hits per cache.", []string{"cache"}, nil)
+	cacheMissesDesc = prometheus.NewDesc("thola_cache_misses_total",
+		"Number of cache misses per cache.", []string{"cache"}, nil)
+)
+
+// collector exports the statistics as prometheus metrics.
+type collector struct{}
+
+func (collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{upSinceDesc, routeRequestsDesc, routeDurationDesc, deviceClassRequestsDesc,
+		deviceClassDurationDesc, deviceRequestsDesc, snmpRequestsDesc, snmpTimeoutsDesc, cacheHitsDesc, cacheMissesDesc} {
+		ch <- desc
+	}
+}
+
+func (collector) Collect(ch chan<- prometheus.Metric) {
+	for device, s := range network.GetSNMPStatistics() {
+		ch <- prometheus.MustNewConstMetric(snmpRequestsDesc, prometheus.CounterValue, float64(s.Requests), device)
+		ch <- prometheus.MustNewConstMetric(snmpTimeoutsDesc, prometheus.CounterValue, float64(s.Timeouts), device)
+	}
+	caches := network.GetCacheStatistics()
+	caches["database"] = database.GetCacheStatistics()
+	for name, c := range caches {
+		ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(c.Hits), name)
+		ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(c.Misses), name)
+	}
+
+	stats.RLock()
+	defer stats.RUnlock()
+
+	if !stats.startTime.IsZero() {
+		ch <- prometheus.MustNewConstMetric(upSinceDesc, prometheus.GaugeValue, float64(stats.startTime.UnixNano())/1e9)
+	}
+	for r, rs := range stats.routes {
+		collectRequests(ch, routeRequestsDesc, rs, r.method, r.path)
+		ch <- prometheus.MustNewConstHistogram(routeDurationDesc, rs.responseTimes.count, rs.responseTimes.sum, rs.responseTimes.cumulative(), r.method, r.path)
+	}
+	for class, rs := range stats.deviceClasses {
+		collectRequests(ch, deviceClassRequestsDesc, rs, class)
+		ch <- prometheus.MustNewConstHistogram(deviceClassDurationDesc, rs.responseTimes.count, rs.responseTimes.sum, rs.responseTimes.cumulative(), class)
+	}
+	for device, ds := range stats.devices {
+		collectRequests(ch, deviceRequestsDesc, &ds.requestStatistics, device)
+	}
+}
+
+func collectRequests(ch chan<- prometheus.Metric, desc *prometheus.Desc, rs *requestStatistics, labels ...string) {
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(rs.successfulCounter), append(labels, "successful")...)
+	ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, float64(rs.failedCounter), append(labels, "failed")...)
+}
+
+// PrometheusHandler returns a http handler that serves the statistics
+// and the metrics of the go runtime in the prometheus exposition format.
+func PrometheusHandler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collector{}, prometheus.NewGoCollector(), prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}))
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
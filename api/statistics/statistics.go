@@ -1,91 +1,323 @@
 package statistics
 
 import (
+	"context"
+	"github.com/inexio/thola/internal/database"
+	"github.com/inexio/thola/internal/network"
 	"github.com/labstack/echo/v4"
-	"math"
-	"net/http"
 	"sync"
 	"time"
 )
 
-var stats statistics
+// DefaultMaxDevices is the default amount of devices for which statistics are kept.
+const DefaultMaxDevices = 1000
+
+var stats = statistics{maxDevices: DefaultMaxDevices}
 
 type statistics struct {
 	sync.Once
 	sync.RWMutex
 
-	startTime         time.Time
-	successfulCounter int
-	failedCounter     int
-	totalResponseTime time.Duration
+	startTime time.Time
+	total     requestStatistics
+	window    window
+
+	routes        map[route]*requestStatistics
+	deviceClasses map[string]*requestStatistics
+	devices       map[string]*deviceStatistics
+	// maxDevices limits the devices, and therefore the labels of the device metrics, the least recently requested device is dropped first.
+	maxDevices int
+}
+
+// deviceStatistics are the request statistics of a device and the time of its last request.
+type deviceStatistics struct {
+	requestStatistics
+	lastRequest time.Time
+}
+
+type route struct {
+	method string
+	path   string
 }
 
 // Statistics includes stats of all requests handled by the api.
+//
+// swagger:model
 type Statistics struct {
-	UpSince             time.Time
-	TotalCount          int
-	SuccessfulCounter   int
-	FailedCounter       int
-	AverageResponseTime float64
+	UpSince             time.Time `json:"up_since" xml:"up_since"`
+	TotalCount          int       `json:"total_count" xml:"total_count"`
+	SuccessfulCounter   int       `json:"successful_counter" xml:"successful_counter"`
+	FailedCounter       int       `json:"failed_counter" xml:"failed_counter"`
+	AverageResponseTime float64   `json:"average_response_time" xml:"average_response_time"`
+
+	// LastFiveMinutes contains the stats of all requests that finished in the last five minutes.
+	LastFiveMinutes RequestStatistics `json:"last_five_minutes" xml:"last_five_minutes"`
+	// Routes contains the stats per route, e.g. "POST /read/interfaces".
+	Routes map[string]RequestStatistics `json:"routes" xml:"-"`
+	// DeviceClasses contains the stats of all requests which used the device class.
+	DeviceClasses map[string]RequestStatistics `json:"device_classes" xml:"-"`
+	// Devices contains the stats per ip address of the requested device.
+	Devices map[string]DeviceStatistics `json:"devices" xml:"-"`
+	// Caches contains the hits and misses of the database and the request caches of the network clients.
+	Caches map[string]CacheStatistics `json:"caches" xml:"-"`
 }
 
-func handlerFunc(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		beginning := time.Now()
+// RequestStatistics includes stats of a subset of the requests.
+// All response times are in seconds, the percentiles are estimated from the histogram.
+type RequestStatistics struct {
+	TotalCount            int               `json:"total_count" xml:"total_count"`
+	SuccessfulCounter     int               `json:"successful_counter" xml:"successful_counter"`
+	FailedCounter         int               `json:"failed_counter" xml:"failed_counter"`
+	ErrorRatio            float64           `json:"error_ratio" xml:"error_ratio"`
+	AverageResponseTime   float64           `json:"average_response_time" xml:"average_response_time"`
+	P50ResponseTime       float64           `json:"p50_response_time" xml:"p50_response_time"`
+	P90ResponseTime       float64           `json:"p90_response_time" xml:"p90_response_time"`
+	P95ResponseTime       float64           `json:"p95_response_time" xml:"p95_response_time"`
+	P99ResponseTime       float64           `json:"p99_response_time" xml:"p99_response_time"`
+	ResponseTimeHistogram []HistogramBucket `json:"response_time_histogram,omitempty" xml:"response_time_histogram>bucket,omitempty"`
+}
 
-		h.ServeHTTP(w, r)
+// HistogramBucket contains the amount of requests with a response time less or equal to the upper bound.
+type HistogramBucket struct {
+	UpperBound float64 `json:"le" xml:"le"`
+	Count      uint64  `json:"count" xml:"count"`
+}
 
-		echoRes := w.(*echo.Response)
+// DeviceStatistics includes stats of all requests for a device and of the snmp packets sent to it.
+type DeviceStatistics struct {
+	RequestStatistics
+	SNMPRequests uint64 `json:"snmp_requests"`
+	SNMPTimeouts uint64 `json:"snmp_timeouts"`
+}
 
-		stats.add(beginning, echoRes.Status)
-	})
+// CacheStatistics includes the hits and misses of a cache.
+type CacheStatistics struct {
+	Hits     uint64  `json:"hits"`
+	Misses   uint64  `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// requestInfo contains information about a request that is only known while processing it.
+type requestInfo struct {
+	sync.Mutex
+
+	beginning   time.Time
+	route       route
+	device      string
+	deviceClass string
+	// deferred is set if the request is recorded when its processing finished, instead of when the response was sent.
+	deferred bool
 }
 
+const requestInfoKey = "statistics"
+
+type ctxKey byte
+
+const requestInfoCtxKey ctxKey = iota + 1
+
 // Middleware represents an API middleware.
 func Middleware() echo.MiddlewareFunc {
 	stats.Do(func() {
 		stats.init()
 	})
-	return echo.WrapMiddleware(handlerFunc)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			info := &requestInfo{beginning: time.Now()}
+			c.Set(requestInfoKey, info)
+
+			if err = next(c); err != nil {
+				c.Error(err)
+			}
+
+			info.Lock()
+			deferred := info.deferred
+			info.Unlock()
+			if !deferred {
+				stats.add(info.beginning, route{c.Request().Method, c.Path()}, info, c.Response().Status)
+			}
+			return
+		}
+	}
+}
+
+// SetMaxDevices sets the maximum amount of devices for which statistics are kept, 0 disables the statistics per device.
+// The limit also applies to the snmp statistics of the network clients.
+func SetMaxDevices(max int) {
+	stats.Lock()
+	defer stats.Unlock()
+	stats.maxDevices = max
+	for len(stats.devices) > max {
+		stats.evictDevice()
+	}
+	network.SetMaxSNMPStatisticsDevices(max)
+}
+
+// NewContext returns a new context that carries the request info of the echo context,
+// so that the device and device class of the request can be added while processing it.
+func NewContext(ctx context.Context, c echo.Context) context.Context {
+	info, ok := c.Get(requestInfoKey).(*requestInfo)
+	if !ok {
+		return ctx
+	}
+	info.Lock()
+	info.route = route{c.Request().Method, c.Path()}
+	info.Unlock()
+	return context.WithValue(ctx, requestInfoCtxKey, info)
+}
+
+// Defer defers the recording of the request of the context until the returned function is called
+// with the status code of its result, e.g. when an asynchronously processed request finished.
+func Defer(ctx context.Context) func(statusCode int) {
+	info, ok := ctx.Value(requestInfoCtxKey).(*requestInfo)
+	if !ok {
+		return func(int) {}
+	}
+	info.Lock()
+	info.deferred = true
+	info.Unlock()
+	return func(statusCode int) {
+		info.Lock()
+		beginning, r := info.beginning, info.route
+		info.Unlock()
+		stats.add(beginning, r, info, statusCode)
+	}
+}
+
+// SetDevice sets the device of the request in the context.
+func SetDevice(ctx context.Context, ip string) {
+	if info, ok := ctx.Value(requestInfoCtxKey).(*requestInfo); ok {
+		info.Lock()
+		info.device = ip
+		info.Unlock()
+	}
+}
+
+// SetDeviceClass sets the device class that is used for the request in the context.
+func SetDeviceClass(ctx context.Context, class string) {
+	if info, ok := ctx.Value(requestInfoCtxKey).(*requestInfo); ok {
+		info.Lock()
+		info.deviceClass = class
+		info.Unlock()
+	}
 }
 
 func (s *statistics) init() {
 	s.startTime = time.Now()
+	s.routes = make(map[route]*requestStatistics)
+	s.deviceClasses = make(map[string]*requestStatistics)
+	s.devices = make(map[string]*deviceStatistics)
 }
 
-func (s *statistics) add(startTime time.Time, statusCode int) {
+func (s *statistics) add(startTime time.Time, r route, info *requestInfo, statusCode int) {
+	now := time.Now()
+	d := now.Sub(startTime)
+	successful := statusCode >= 200 && statusCode <= 299
+
+	info.Lock()
+	device, deviceClass := info.device, info.deviceClass
+	info.Unlock()
+
 	s.Lock()
 	defer s.Unlock()
 
-	s.totalResponseTime += time.Since(startTime)
+	s.total.add(d, successful)
+	s.window.add(now, d, successful)
+	rs, ok := s.routes[r]
+	if !ok {
+		rs = &requestStatistics{}
+		s.routes[r] = rs
+	}
+	rs.add(d, successful)
+	if deviceClass != "" {
+		getOrCreate(s.deviceClasses, deviceClass).add(d, successful)
+	}
+	if device != "" {
+		s.addDevice(device, startTime, d, successful)
+	}
+}
 
-	if statusCode >= 200 && statusCode <= 299 {
-		s.successfulCounter++
-	} else {
-		s.failedCounter++
+func (s *statistics) addDevice(device string, requestTime time.Time, d time.Duration, successful bool) {
+	if s.maxDevices <= 0 {
+		return
 	}
+	ds, ok := s.devices[device]
+	if !ok {
+		for len(s.devices) >= s.maxDevices {
+			s.evictDevice()
+		}
+		ds = &deviceStatistics{}
+		s.devices[device] = ds
+	}
+	ds.add(d, successful)
+	ds.lastRequest = requestTime
+}
+
+// evictDevice removes the statistics of the least recently requested device.
+func (s *statistics) evictDevice() {
+	var oldest string
+	for device, ds := range s.devices {
+		if oldest == "" || ds.lastRequest.Before(s.devices[oldest].lastRequest) {
+			oldest = device
+		}
+	}
+	delete(s.devices, oldest)
+}
+
+func getOrCreate(m map[string]*requestStatistics, key string) *requestStatistics {
+	r, ok := m[key]
+	if !ok {
+		r = &requestStatistics{}
+		m[key] = r
+	}
+	return r
 }
 
 // GetStatistics returns the current statistics.
 func GetStatistics() (Statistics, error) {
+	snmpStats := network.GetSNMPStatistics()
+	cacheStats := network.GetCacheStatistics()
+	cacheStats["database"] = database.GetCacheStatistics()
+
 	stats.RLock()
 	defer stats.RUnlock()
 
+	total := stats.total.get()
+	window := stats.window.get(time.Now())
 	s := Statistics{
 		UpSince:             stats.startTime,
-		TotalCount:          stats.successfulCounter + stats.failedCounter,
-		SuccessfulCounter:   stats.successfulCounter,
-		FailedCounter:       stats.failedCounter,
-		AverageResponseTime: 0,
+		TotalCount:          total.TotalCount,
+		SuccessfulCounter:   total.SuccessfulCounter,
+		FailedCounter:       total.FailedCounter,
+		AverageResponseTime: total.AverageResponseTime,
+		LastFiveMinutes:     window.get(),
+		Routes:              make(map[string]RequestStatistics, len(stats.routes)),
+		DeviceClasses:       make(map[string]RequestStatistics, len(stats.deviceClasses)),
+		Devices:             make(map[string]DeviceStatistics, len(stats.devices)),
+		Caches:              make(map[string]CacheStatistics, len(cacheStats)),
 	}
 
-	if s.TotalCount == 0 {
-		return s, nil
+	for r, rs := range stats.routes {
+		s.Routes[r.method+" "+r.path] = rs.get()
+	}
+	for class, rs := range stats.deviceClasses {
+		s.DeviceClasses[class] = rs.get()
+	}
+	for device, ds := range stats.devices {
+		s.Devices[device] = DeviceStatistics{RequestStatistics: ds.get()}
+	}
+	for device, snmp := range snmpStats {
+		d := s.Devices[device]
+		d.SNMPRequests = snmp.Requests
+		d.SNMPTimeouts = snmp.Timeouts
+		s.Devices[device] = d
+	}
+	for name, c := range cacheStats {
+		cs := CacheStatistics{Hits: c.Hits, Misses: c.Misses}
+		if c.Hits+c.Misses > 0 {
+			cs.HitRatio = roundMilli(float64(c.Hits) / float64(c.Hits+c.Misses))
+		}
+		s.Caches[name] = cs
 	}
-
-	avgNs := int64(stats.totalResponseTime) / int64(s.TotalCount)
-	avgSec := float64(avgNs) / float64(time.Second)
-	s.AverageResponseTime = math.Floor(avgSec*1000) / 1000
 
 	return s, nil
 }
@@ -0,0 +1,125 @@
+package statistics
+
+import (
+	"context"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHistogram_quantile(t *testing.T) {
+	var h histogram
+	assert.Equal(t, 0.0, h.quantile(0.95))
+
+	for i := 0; i < 90; i++ {
+		h.observe(20 * time.Millisecond)
+	}
+	for i := 0; i < 10; i++ {
+		h.observe(2 * time.Second)
+	}
+	assert.InDelta(t, 0.025, h.quantile(0.5), 0.01)
+	assert.InDelta(t, 2.0, h.quantile(0.95), 0.5)
+
+	h.observe(time.Hour)
+	assert.Equal(t, 60.0, h.quantile(1))
+}
+
+func TestWindow(t *testing.T) {
+	var w window
+	now := time.Unix(600, 0)
+	w.add(now.Add(-10*time.Minute), time.Second, false)
+	w.add(now.Add(-time.Minute), time.Second, true)
+	w.add(now, time.Second, false)
+
+	s := w.get(now)
+	assert.Equal(t, 1, s.successfulCounter)
+	assert.Equal(t, 1, s.failedCounter)
+}
+
+func TestMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware())
+	e.POST("/read/:component", func(c echo.Context) error {
+		ctx := NewContext(context.Background(), c)
+		SetDevice(ctx, "192.0.2.1")
+		SetDeviceClass(ctx, "generic")
+		if c.Param("component") == "unknown" {
+			return c.String(http.StatusBadRequest, "unknown component")
+		}
+		return c.String(http.StatusOK, "ok")
+	})
+	e.GET("/metrics", echo.WrapHandler(PrometheusHandler()))
+
+	for _, path := range []string{"/read/interfaces", "/read/interfaces", "/read/unknown", "/not-found"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+	}
+
+	s, err := GetStatistics()
+	require.NoError(t, err)
+	assert.Equal(t, 4, s.TotalCount)
+	assert.Equal(t, 2, s.FailedCounter)
+	assert.Equal(t, 0.5, s.LastFiveMinutes.ErrorRatio)
+
+	r := s.Routes["POST /read/:component"]
+	assert.Equal(t, 3, r.TotalCount)
+	assert.Equal(t, 1, r.FailedCounter)
+	assert.Len(t, r.ResponseTimeHistogram, len(buckets))
+	assert.Equal(t, uint64(3), r.ResponseTimeHistogram[len(buckets)-1].Count)
+	assert.Equal(t, 3, s.DeviceClasses["generic"].TotalCount)
+	assert.Equal(t, 3, s.Devices["192.0.2.1"].TotalCount)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := ioutil.ReadAll(rec.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `thola_api_requests_total{method="POST",route="/read/:component",status="successful"} 2`)
+	assert.Contains(t, string(body), `thola_device_class_request_duration_seconds_count{device_class="generic"} 3`)
+	assert.Contains(t, string(body), `thola_device_requests_total{device="192.0.2.1",status="failed"} 1`)
+}
+
+func TestDefer(t *testing.T) {
+	e := echo.New()
+	e.Use(Middleware())
+	done := make(chan func(int), 1)
+	e.POST("/check/snmp", func(c echo.Context) error {
+		ctx := NewContext(context.Background(), c)
+		SetDevice(ctx, "192.0.2.10")
+		done <- Defer(ctx)
+		return c.String(http.StatusAccepted, "job started")
+	})
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/check/snmp", nil))
+	s, err := GetStatistics()
+	require.NoError(t, err)
+	assert.NotContains(t, s.Routes, "POST /check/snmp")
+
+	(<-done)(http.StatusBadGateway)
+	s, err = GetStatistics()
+	require.NoError(t, err)
+	assert.Equal(t, 1, s.Routes["POST /check/snmp"].FailedCounter)
+	assert.Equal(t, 1, s.Devices["192.0.2.10"].FailedCounter)
+}
+
+func TestStatistics_maxDevices(t *testing.T) {
+	s := statistics{maxDevices: 2}
+	s.init()
+	now := time.Unix(600, 0)
+	s.add(now, route{"POST", "/read/interfaces"}, &requestInfo{device: "192.0.2.1"}, http.StatusOK)
+	s.add(now.Add(time.Second), route{"POST", "/read/interfaces"}, &requestInfo{device: "192.0.2.2"}, http.StatusOK)
+	s.add(now.Add(2*time.Second), route{"POST", "/read/interfaces"}, &requestInfo{device: "192.0.2.1"}, http.StatusOK)
+	s.add(now.Add(3*time.Second), route{"POST", "/read/interfaces"}, &requestInfo{device: "192.0.2.3"}, http.StatusOK)
+
+	assert.Len(t, s.devices, 2)
+	assert.Equal(t, 2, s.devices["192.0.2.1"].successfulCounter)
+	assert.Contains(t, s.devices, "192.0.2.3")
+
+	s.maxDevices = 0
+	s.add(now.Add(4*time.Second), route{"POST", "/read/interfaces"}, &requestInfo{device: "192.0.2.4"}, http.StatusOK)
+	assert.NotContains(t, s.devices, "192.0.2.4")
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"crypto/tls"
+	"github.com/inexio/thola/internal/network"
+	"github.com/pkg/errors"
+)
+
+// tlsConfig returns the tls config of the api server.
+// If a client CA file is given, clients have to send a certificate that is signed by one of its CAs.
+func tlsConfig(certFile, keyFile, clientCAFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load server certificate")
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"h2"},
+	}
+	if clientCAFile != "" {
+		config.ClientCAs, err = network.ReadCertPool(clientCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read client CA file")
+		}
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/network/tlstest"
+	"github.com/labstack/echo/v4"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMutualTLS(t *testing.T) {
+	viper.Set("api.format", "json")
+	defer viper.Set("api.format", nil)
+
+	ca, err := tlstest.NewCA(t.TempDir())
+	require.NoError(t, err)
+	serverCert, err := ca.IssueServer("server")
+	require.NoError(t, err)
+	monitoringCert, err := ca.IssueClient("monitoring", "icinga.example.com")
+	require.NoError(t, err)
+	unknownCert, err := ca.IssueClient("unknown")
+	require.NoError(t, err)
+
+	otherCA, err := tlstest.NewCA(t.TempDir())
+	require.NoError(t, err)
+	otherCert, err := otherCA.IssueClient("monitoring")
+	require.NoError(t, err)
+
+	tokens := []*Token{
+		{Name: "monitoring", Identities: []string{"icinga.example.com"}, Scopes: []string{"check"}},
+		{Name: "admin", Token: "admin-token"},
+	}
+	for _, token := range tokens {
+		require.NoError(t, token.init())
+	}
+
+	e := echo.New()
+	e.Use(authMiddleware(tokens, "", ""))
+	handler := func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}
+	e.POST("/check/snmp", handler)
+	e.POST("/read/interfaces", handler)
+
+	server := httptest.NewUnstartedServer(e)
+	server.TLS, err = tlsConfig(serverCert.CertFile, serverCert.KeyFile, ca.CertFile)
+	require.NoError(t, err)
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		cert     tlstest.Certificate
+		path     string
+		header   map[string]string
+		expected int
+	}{
+		{"identity with scope", monitoringCert, "check/snmp", nil, http.StatusOK},
+		{"scope not allowed", monitoringCert, "read/interfaces", nil, http.StatusForbidden},
+		{"unknown identity", unknownCert, "check/snmp", nil, http.StatusUnauthorized},
+		{"unknown identity with token", unknownCert, "read/interfaces", map[string]string{"Authorization": "Bearer admin-token"}, http.StatusOK},
+		{"token overrides identity", monitoringCert, "read/interfaces", map[string]string{"Authorization": "Bearer admin-token"}, http.StatusOK},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := newTestHTTPClient(t, server.URL, ca.CertFile)
+			require.NoError(t, client.SetClientCertificate(test.cert.CertFile, test.cert.KeyFile))
+			res, err := client.Request(context.Background(), http.MethodPost, test.path, "", test.header, nil)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, res.StatusCode(), res.String())
+		})
+	}
+
+	t.Run("no client certificate", func(t *testing.T) {
+		client := newTestHTTPClient(t, server.URL, ca.CertFile)
+		_, err := client.Request(context.Background(), http.MethodPost, "check/snmp", "", nil, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("client certificate of other CA", func(t *testing.T) {
+		client := newTestHTTPClient(t, server.URL, ca.CertFile)
+		require.NoError(t, client.SetClientCertificate(otherCert.CertFile, otherCert.KeyFile))
+		_, err := client.Request(context.Background(), http.MethodPost, "check/snmp", "", nil, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown server CA", func(t *testing.T) {
+		client := newTestHTTPClient(t, server.URL, otherCA.CertFile)
+		require.NoError(t, client.SetClientCertificate(monitoringCert.CertFile, monitoringCert.KeyFile))
+		_, err := client.Request(context.Background(), http.MethodPost, "check/snmp", "", nil, nil)
+		assert.Error(t, err)
+	})
+}
+
+func newTestHTTPClient(t *testing.T, url, caFile string) *network.HTTPClient {
+	client, err := network.NewHTTPClient(url)
+	require.NoError(t, err)
+	require.NoError(t, client.SetRootCAs(caFile))
+	return client
+}
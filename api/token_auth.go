@@ -0,0 +1,302 @@
+package api
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"github.com/ulule/limiter/v3"
+	"github.com/ulule/limiter/v3/drivers/store/memory"
+	"gopkg.in/yaml.v2"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+)
+
+const tokenContextKey = "api_token"
+
+// Scopes of api tokens, which allow the requests of a group of endpoints.
+const (
+	scopeRead       = "read"
+	scopeCheck      = "check"
+	scopeStatistics = "statistics"
+)
+
+// Token is an api token or a set of client certificate identities with its scopes.
+// Empty scopes do not restrict the token.
+type Token struct {
+	// Name identifies the token in the logs, it is not secret.
+	Name  string `mapstructure:"name" yaml:"name"`
+	Token string `mapstructure:"token" yaml:"token"`
+	// Identities are the common names or SANs (DNS, email, URI or ip address) of client certificates
+	// which are authorized with this token if the api requires client certificates.
+	Identities []string `mapstructure:"identities" yaml:"identities"`
+	// Scopes are the allowed groups of endpoints: "read" (identify, read and snmp), "check" and "statistics".
+	Scopes []string `mapstructure:"scopes" yaml:"scopes"`
+	// Devices are the allowed ip addresses of devices in CIDR notation, e.g. "10.0.0.0/8".
+	Devices []string `mapstructure:"devices" yaml:"devices"`
+	// Requests are the allowed request types as patterns of the api path, e.g. "check/*" or "read/interfaces".
+	Requests []string `mapstructure:"requests" yaml:"requests"`
+	// RateLimit overrides the global rate limit for requests with this token, e.g. "1000-H".
+	RateLimit string `mapstructure:"ratelimit" yaml:"ratelimit"`
+
+	networks []*net.IPNet
+	limiter  *limiter.Limiter
+}
+
+// loadTokens loads the tokens of the config and the token file.
+func loadTokens() ([]*Token, error) {
+	var tokens []*Token
+	if err := viper.UnmarshalKey("api.tokens", &tokens); err != nil {
+		return nil, errors.Wrap(err, "failed to read api tokens from config")
+	}
+
+	if file := viper.GetString("api.token-file"); file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read token file")
+		}
+		var tokenFile struct {
+			Tokens []*Token `yaml:"tokens"`
+		}
+		if err := yaml.UnmarshalStrict(b, &tokenFile); err != nil {
+			return nil, errors.Wrap(err, "failed to parse token file")
+		}
+		tokens = append(tokens, tokenFile.Tokens...)
+	}
+
+	names := make(map[string]struct{})
+	for _, t := range tokens {
+		if err := t.init(); err != nil {
+			return nil, errors.Wrapf(err, "invalid api token '%s'", t.Name)
+		}
+		if _, ok := names[t.Name]; ok {
+			return nil, fmt.Errorf("api token name '%s' is not unique", t.Name)
+		}
+		names[t.Name] = struct{}{}
+	}
+	return tokens, nil
+}
+
+func (t *Token) init() error {
+	if t.Name == "" {
+		return errors.New("name is empty")
+	}
+	if t.Token == "" && len(t.Identities) == 0 {
+		return errors.New("neither token nor identities are set")
+	}
+	for _, scope := range t.Scopes {
+		if scope != scopeRead && scope != scopeCheck && scope != scopeStatistics {
+			return fmt.Errorf("unknown scope '%s'", scope)
+		}
+	}
+	for _, device := range t.Devices {
+		if !strings.Contains(device, "/") {
+			if ip := net.ParseIP(device); ip != nil && ip.To4() != nil {
+				device += "/32"
+			} else {
+				device += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(device)
+		if err != nil {
+			return errors.Wrapf(err, "invalid device '%s'", device)
+		}
+		t.networks = append(t.networks, network)
+	}
+	for _, pattern := range t.Requests {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.Wrapf(err, "invalid request pattern '%s'", pattern)
+		}
+	}
+	if t.RateLimit != "" {
+		rate, err := limiter.NewRateFromFormatted(t.RateLimit)
+		if err != nil {
+			return errors.Wrap(err, "invalid ratelimit")
+		}
+		t.limiter = limiter.New(memory.NewStore(), rate)
+	}
+	return nil
+}
+
+// routeScope returns the scope which is needed for the route.
+func routeScope(route string) string {
+	switch {
+	case strings.HasPrefix(route, "/check/"):
+		return scopeCheck
+	case route == "/statistics" || route == "/metrics":
+		return scopeStatistics
+	default:
+		return scopeRead
+	}
+}
+
+// allowsRoute checks if the scopes and request types of the token allow the route.
+func (t *Token) allowsRoute(route string) bool {
+	// the documentation is allowed for all tokens and jobs can only be accessed with the token that started them
+	if strings.HasPrefix(route, "/jobs/") || route == "/openapi.json" || route == "/docs" || route == "/docs/:file" {
+		return true
+	}
+	if len(t.Scopes) > 0 {
+		scope := routeScope(route)
+		allowed := false
+		for _, s := range t.Scopes {
+			if s == scope {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	if len(t.Requests) == 0 {
+		return true
+	}
+	for _, pattern := range t.Requests {
+		if ok, _ := path.Match(pattern, strings.TrimPrefix(route, "/")); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsDevice checks if the token is allowed to send requests to the device.
+func (t *Token) allowsDevice(ip string) bool {
+	if len(t.networks) == 0 {
+		return true
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		// the device is given by its hostname, which cannot be checked before the request is processed
+		return false
+	}
+	for _, network := range t.networks {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// findToken compares the token with all tokens in constant time.
+func findToken(tokens []*Token, token string) *Token {
+	var res *Token
+	for _, t := range tokens {
+		if t.Token != "" && subtle.ConstantTimeCompare([]byte(t.Token), []byte(token)) == 1 {
+			res = t
+		}
+	}
+	return res
+}
+
+// findIdentity returns the token of the first identity of the verified client certificate that is known.
+func findIdentity(tokens []*Token, state *tls.ConnectionState) (*Token, string) {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil, ""
+	}
+	for _, identity := range certificateIdentities(state.VerifiedChains[0][0]) {
+		for _, t := range tokens {
+			for _, i := range t.Identities {
+				if i == identity {
+					return t, identity
+				}
+			}
+		}
+	}
+	return nil, ""
+}
+
+// certificateIdentities returns the common name and all SANs of the certificate.
+func certificateIdentities(cert *x509.Certificate) []string {
+	var identities []string
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, cert.Subject.CommonName)
+	}
+	identities = append(identities, cert.DNSNames...)
+	identities = append(identities, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	for _, ip := range cert.IPAddresses {
+		identities = append(identities, ip.String())
+	}
+	return identities
+}
+
+// tokenFromContext returns the api token of the request if it was authorized by one.
+func tokenFromContext(c echo.Context) (*Token, bool) {
+	t, ok := c.Get(tokenContextKey).(*Token)
+	return t, ok
+}
+
+// authorizeDevice checks if the token of the request is allowed to send requests to the device.
+func authorizeDevice(c echo.Context, ip string) error {
+	t, ok := tokenFromContext(c)
+	if !ok || t.allowsDevice(ip) {
+		return nil
+	}
+	return tholaerr.NewForbiddenError(fmt.Sprintf("api token '%s' is not allowed to send requests to '%s'", t.Name, ip))
+}
+
+// authMiddleware authorizes requests with bearer tokens, identities of verified client certificates or basic auth.
+// If neither tokens nor basic auth are configured, all requests are allowed.
+func authMiddleware(tokens []*Token, username, password string) echo.MiddlewareFunc {
+	basicAuth := username != "" && password != ""
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			auth := c.Request().Header.Get(echo.HeaderAuthorization)
+
+			if strings.HasPrefix(auth, "Bearer ") && len(tokens) > 0 {
+				t := findToken(tokens, strings.TrimPrefix(auth, "Bearer "))
+				if t == nil {
+					log.Warn().Str("remote_ip", c.RealIP()).Msg("request with invalid api token")
+					return unauthorized(c, "Bearer", "Invalid api token")
+				}
+				return authorizeToken(c, t, next)
+			}
+
+			if t, identity := findIdentity(tokens, c.Request().TLS); t != nil {
+				log.Debug().Str("token", t.Name).Str("identity", identity).Msg("request authorized by client certificate")
+				return authorizeToken(c, t, next)
+			}
+
+			if basicAuth {
+				if u, p, ok := c.Request().BasicAuth(); ok &&
+					// Be careful to use constant time comparison to prevent timing attacks
+					subtle.ConstantTimeCompare([]byte(u), []byte(username)) == 1 &&
+					subtle.ConstantTimeCompare([]byte(p), []byte(password)) == 1 {
+					return next(c)
+				}
+				return unauthorized(c, "Basic", "Unauthorized")
+			}
+			if len(tokens) > 0 {
+				return unauthorized(c, "Bearer", "Unauthorized")
+			}
+			return next(c)
+		}
+	}
+}
+
+// authorizeToken checks if the token is allowed to access the route and stores it in the context.
+func authorizeToken(c echo.Context, t *Token, next echo.HandlerFunc) error {
+	if !t.allowsRoute(c.Path()) {
+		log.Warn().Str("token", t.Name).Str("uri", c.Request().RequestURI).Msg("api token is not allowed to access route")
+		return handleError(c, tholaerr.NewForbiddenError(fmt.Sprintf("api token '%s' is not allowed to access '%s'", t.Name, c.Path())))
+	}
+	c.Set(tokenContextKey, t)
+	return next(c)
+}
+
+func unauthorized(c echo.Context, scheme, msg string) error {
+	c.Response().Header().Set(echo.HeaderWWWAuthenticate, scheme+` realm="thola"`)
+	return returnInFormat(c, http.StatusUnauthorized, tholaerr.OutputError{Error: msg})
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestTokens(t *testing.T) []*Token {
+	tokens := []*Token{
+		{Name: "admin", Token: "admin-token"},
+		{Name: "monitoring", Token: "monitoring-token", Scopes: []string{"check"}, Devices: []string{"192.0.2.0/24", "2001:db8::1"}},
+		{Name: "interfaces", Token: "interfaces-token", Requests: []string{"read/interfaces", "check/interface-*"}, RateLimit: "1-M"},
+	}
+	for _, token := range tokens {
+		require.NoError(t, token.init())
+	}
+	return tokens
+}
+
+func TestToken_init(t *testing.T) {
+	assert.Error(t, (&Token{Name: "a"}).init())
+	assert.NoError(t, (&Token{Name: "a", Identities: []string{"client.example.com"}}).init())
+	assert.Error(t, (&Token{Name: "a", Token: "b", Scopes: []string{"write"}}).init())
+	assert.Error(t, (&Token{Name: "a", Token: "b", Devices: []string{"192.0.2.0/33"}}).init())
+	assert.Error(t, (&Token{Name: "a", Token: "b", Requests: []string{"read/["}}).init())
+	assert.Error(t, (&Token{Name: "a", Token: "b", RateLimit: "1-X"}).init())
+}
+
+func TestToken_allows(t *testing.T) {
+	tokens := newTestTokens(t)
+
+	assert.True(t, tokens[0].allowsRoute("/read/interfaces"))
+	assert.True(t, tokens[0].allowsDevice("example.com"))
+
+	assert.True(t, tokens[1].allowsRoute("/check/snmp"))
+	assert.False(t, tokens[1].allowsRoute("/identify"))
+	assert.False(t, tokens[1].allowsRoute("/metrics"))
+	assert.True(t, tokens[1].allowsDevice("192.0.2.10"))
+	assert.True(t, tokens[1].allowsDevice("2001:db8::1"))
+	assert.False(t, tokens[1].allowsDevice("198.51.100.1"))
+	assert.False(t, tokens[1].allowsDevice("example.com"))
+
+	assert.True(t, tokens[2].allowsRoute("/read/interfaces"))
+	assert.True(t, tokens[2].allowsRoute("/check/interface-metrics"))
+	assert.False(t, tokens[2].allowsRoute("/read/cpu-load"))
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	viper.Set("api.format", "json")
+	defer viper.Set("api.format", nil)
+
+	e := echo.New()
+	e.Use(authMiddleware(newTestTokens(t), "user", "password"), rateLimit())
+	handler := func(c echo.Context) error {
+		if err := authorizeDevice(c, c.QueryParam("ip")); err != nil {
+			return handleError(c, err)
+		}
+		return c.String(http.StatusOK, "ok")
+	}
+	e.POST("/read/interfaces", handler)
+	e.POST("/check/snmp", handler)
+
+	tests := []struct {
+		name     string
+		path     string
+		auth     func(r *http.Request)
+		expected int
+	}{
+		{"no auth", "/check/snmp?ip=192.0.2.1", func(r *http.Request) {}, http.StatusUnauthorized},
+		{"basic auth", "/check/snmp?ip=192.0.2.1", func(r *http.Request) { r.SetBasicAuth("user", "password") }, http.StatusOK},
+		{"wrong basic auth", "/check/snmp?ip=192.0.2.1", func(r *http.Request) { r.SetBasicAuth("user", "wrong") }, http.StatusUnauthorized},
+		{"invalid token", "/check/snmp?ip=192.0.2.1", bearer("wrong"), http.StatusUnauthorized},
+		{"admin token", "/read/interfaces?ip=198.51.100.1", bearer("admin-token"), http.StatusOK},
+		{"scoped token", "/check/snmp?ip=192.0.2.1", bearer("monitoring-token"), http.StatusOK},
+		{"scope not allowed", "/read/interfaces?ip=192.0.2.1", bearer("monitoring-token"), http.StatusForbidden},
+		{"device not allowed", "/check/snmp?ip=198.51.100.1", bearer("monitoring-token"), http.StatusForbidden},
+		{"request type", "/read/interfaces?ip=192.0.2.1", bearer("interfaces-token"), http.StatusOK},
+		{"rate limit of token", "/read/interfaces?ip=192.0.2.1", bearer("interfaces-token"), http.StatusTooManyRequests},
+		{"request type not allowed", "/check/snmp?ip=192.0.2.1", bearer("interfaces-token"), http.StatusForbidden},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, test.path, nil)
+			test.auth(req)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, test.expected, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func bearer(token string) func(r *http.Request) {
+	return func(r *http.Request) {
+		r.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	}
+}
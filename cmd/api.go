@@ -5,9 +5,11 @@ package cmd
 import (
 	"errors"
 	"github.com/inexio/thola/api"
+	"github.com/inexio/thola/api/statistics"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"time"
 )
 
 func init() {
@@ -15,12 +17,19 @@ func init() {
 
 	apiCMD.Flags().Int("port", 8237, "Port for the API")
 	apiCMD.Flags().Bool("no-ip-lock", false, "Allow multiple requests at a time for one IP")
+	apiCMD.Flags().String("device-lock-timeout", "", "Maximum duration a request waits for the lock of its device (e.g. \"10s\"), unbounded if not set")
+	apiCMD.Flags().Int("device-lock-concurrency", 1, "Amount of requests that are processed at the same time per device")
+	apiCMD.Flags().String("device-lock-lease", "30s", "Lease of device locks held in redis, after which locks of crashed instances expire")
 	apiCMD.Flags().String("api-format", "json", "API format ('json' or 'xml')")
 	apiCMD.Flags().String("username", "", "Username for authorization")
 	apiCMD.Flags().String("password", "", "Password for authorization")
 	apiCMD.Flags().String("certfile", "", "Cert file for SSL encryption")
 	apiCMD.Flags().String("keyfile", "", "Key file for SSL encryption")
+	apiCMD.Flags().String("client-ca-file", "", "CA bundle to verify client certificates against, requires client certificates if set")
 	apiCMD.Flags().String("ratelimit", "", "Ratelimit for the API (e.g. 1000 reqs/hour: \"1000-H\")")
+	apiCMD.Flags().String("token-file", "", "YAML file with API tokens and their scopes")
+	apiCMD.Flags().String("job-ttl", "1h", "Duration for which the results of asynchronous jobs are kept")
+	apiCMD.Flags().Int("statistics-max-devices", statistics.DefaultMaxDevices, "Maximum amount of devices with own statistics and metrics, the least recently requested devices are dropped first (0 disables them)")
 
 	err := viper.BindPFlag("api.port", apiCMD.Flags().Lookup("port"))
 	if err != nil {
@@ -36,6 +45,27 @@ func init() {
 			Msg("Can't bind flag no-ip-lock")
 		return
 	}
+	err = viper.BindPFlag("request.device-lock.timeout", apiCMD.Flags().Lookup("device-lock-timeout"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag device-lock-timeout")
+		return
+	}
+	err = viper.BindPFlag("request.device-lock.concurrency", apiCMD.Flags().Lookup("device-lock-concurrency"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag device-lock-concurrency")
+		return
+	}
+	err = viper.BindPFlag("request.device-lock.lease", apiCMD.Flags().Lookup("device-lock-lease"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag device-lock-lease")
+		return
+	}
 	err = viper.BindPFlag("api.format", apiCMD.Flags().Lookup("api-format"))
 	if err != nil {
 		log.Error().
@@ -71,6 +101,13 @@ func init() {
 			Msg("Can't bind flag keyfile")
 		return
 	}
+	err = viper.BindPFlag("api.client-ca-file", apiCMD.Flags().Lookup("client-ca-file"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag client-ca-file")
+		return
+	}
 	err = viper.BindPFlag("api.ratelimit", apiCMD.Flags().Lookup("ratelimit"))
 	if err != nil {
 		log.Error().
@@ -78,14 +115,41 @@ func init() {
 			Msg("Can't bind flag ratelimit")
 		return
 	}
+	err = viper.BindPFlag("api.token-file", apiCMD.Flags().Lookup("token-file"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag token-file")
+		return
+	}
+	err = viper.BindPFlag("api.job-ttl", apiCMD.Flags().Lookup("job-ttl"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag job-ttl")
+		return
+	}
+	err = viper.BindPFlag("api.statistics-max-devices", apiCMD.Flags().Lookup("statistics-max-devices"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag statistics-max-devices")
+		return
+	}
 }
 
 var apiCMD = &cobra.Command{
 	Use:   "api",
 	Short: "Start and configure the API of Thola",
 	Long: "Start and configure the API of Thola.\n\n" +
-		"You can set a port and authorization for the API. The authorization method is HTTP basic auth.\n" +
-		"If the username or password is empty, the API won't use any authorization.",
+		"You can set a port and authorization for the API. The authorization methods are HTTP basic auth\n" +
+		"and API tokens, which are sent as bearer tokens and can be restricted to scopes, devices and request types.\n" +
+		"API tokens are read from the 'api.tokens' key of the config file and from the token file.\n" +
+		"If a client CA file is set, the API requires client certificates signed by it. The identities (common name\n" +
+		"or SANs) of client certificates can be mapped onto the scopes of an API token with its 'identities' key.\n" +
+		"If neither username and password nor tokens are set, the API won't use any authorization.\n\n" +
+		"By default only one request per device is processed at a time. If redis is used as database, the device locks are\n" +
+		"shared between all API instances using the same redis.",
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		err := rootCMD.PersistentPreRunE(cmd, args)
 		if err != nil {
@@ -103,6 +167,26 @@ var apiCMD = &cobra.Command{
 		if viper.GetString("api.username") == "" && viper.GetString("api.password") != "" {
 			return errors.New("password but no username for api authorization set")
 		}
+		if _, err := time.ParseDuration(viper.GetString("api.job-ttl")); err != nil {
+			return errors.New("invalid job ttl set")
+		}
+		if t := viper.GetString("request.device-lock.timeout"); t != "" {
+			if _, err := time.ParseDuration(t); err != nil {
+				return errors.New("invalid device lock timeout set")
+			}
+		}
+		if viper.GetInt("request.device-lock.concurrency") < 1 {
+			return errors.New("device lock concurrency must be at least 1")
+		}
+		if _, err := time.ParseDuration(viper.GetString("request.device-lock.lease")); err != nil {
+			return errors.New("invalid device lock lease set")
+		}
+		if viper.GetInt("api.statistics-max-devices") < 0 {
+			return errors.New("statistics max devices must not be negative")
+		}
+		if viper.GetString("api.client-ca-file") != "" && (viper.GetString("api.certfile") == "" || viper.GetString("api.keyfile") == "") {
+			return errors.New("client CA file requires a cert file and a key file")
+		}
 		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -0,0 +1,348 @@
+// +build !client
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/inexio/thola/internal/database"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/parser"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+	"time"
+)
+
+func init() {
+	rootCMD.AddCommand(cacheCMD)
+	cacheCMD.AddCommand(cacheListCMD, cacheInspectCMD, cacheInvalidateCMD, cacheExportCMD)
+
+	cacheInvalidateCMD.Flags().Bool("all", false, "Invalidate the cache entries of all devices")
+	cacheExportCMD.Flags().String("out", "", "The file the entries are written to instead of stdout")
+	for _, c := range []*cobra.Command{cacheInspectCMD, cacheExportCMD} {
+		c.Flags().Bool("include-secrets", false, "Print the SNMP communities, SNMPv3 keys and passwords of the connection data")
+	}
+}
+
+var cacheCMD = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the cache entries of devices",
+	Long: "Manage the cache entries of devices.\n\n" +
+		"Thola caches the properties, the device class and the connection data of devices per IP address.\n" +
+		"These commands work on the database that is configured with the db flags.",
+}
+
+// cachedDevice is a device with cache entries.
+type cachedDevice struct {
+	IP      string   `json:"ip" xml:"ip"`
+	Class   string   `json:"class,omitempty" xml:"class,omitempty"`
+	Entries []string `json:"entries" xml:"entry"`
+	// Expires is the time the first of the entries expires.
+	Expires string `json:"expires" xml:"expires"`
+}
+
+type cachedDevices struct {
+	Devices []cachedDevice `json:"devices" xml:"device"`
+}
+
+var cacheListCMD = &cobra.Command{
+	Use:   "list",
+	Short: "List all devices with cache entries",
+	Long: "List all devices with cache entries.\n\n" +
+		"For every IP address the cached device class, the types of the cache entries and\n" +
+		"the time the first of them expires are printed.",
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runCacheCommand(func(ctx context.Context, db database.Database) error {
+			return listCache(ctx, db, os.Stdout)
+		})
+	},
+}
+
+var cacheInspectCMD = &cobra.Command{
+	Use:   "inspect [ip]",
+	Short: "Print all cache entries of a device",
+	Long: "Print all cache entries of a device.\n\n" +
+		"The entries are printed as JSON, together with their type and the time they expire.\n" +
+		"Secrets of the connection data are redacted unless --include-secrets is set.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		includeSecrets, err := cmd.Flags().GetBool("include-secrets")
+		if err != nil {
+			log.Fatal().Err(err).Msg("include-secrets needs to be a boolean")
+		}
+
+		runCacheCommand(func(ctx context.Context, db database.Database) error {
+			return inspectCache(ctx, db, os.Stdout, args[0], includeSecrets)
+		})
+	},
+}
+
+var cacheInvalidateCMD = &cobra.Command{
+	Use:   "invalidate [ip...]",
+	Short: "Invalidate the cache entries of devices",
+	Long: "Invalidate the cache entries of devices.\n\n" +
+		"All cache entries of the devices are deleted, so they are identified again on their next request.",
+	Run: func(cmd *cobra.Command, args []string) {
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			log.Fatal().Err(err).Msg("all needs to be a boolean")
+		}
+		if all == (len(args) != 0) {
+			log.Fatal().Msg("either ip addresses or the all flag need to be set")
+		}
+
+		runCacheCommand(func(ctx context.Context, db database.Database) error {
+			return invalidateCache(ctx, db, os.Stdout, args, all)
+		})
+	},
+}
+
+var cacheExportCMD = &cobra.Command{
+	Use:   "export [ip...]",
+	Short: "Export cache entries as JSON",
+	Long: "Export cache entries as JSON.\n\n" +
+		"The cache entries of the given devices, or of all devices if none are given,\n" +
+		"are written as JSON array together with their type and the time they expire.\n" +
+		"Secrets of the connection data are redacted unless --include-secrets is set.",
+	Run: func(cmd *cobra.Command, args []string) {
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			log.Fatal().Err(err).Msg("out needs to be a string")
+		}
+		includeSecrets, err := cmd.Flags().GetBool("include-secrets")
+		if err != nil {
+			log.Fatal().Err(err).Msg("include-secrets needs to be a boolean")
+		}
+
+		runCacheCommand(func(ctx context.Context, db database.Database) error {
+			if out == "" {
+				return exportCache(ctx, db, os.Stdout, args, includeSecrets)
+			}
+			var b bytes.Buffer
+			if err := exportCache(ctx, db, &b, args, includeSecrets); err != nil {
+				return err
+			}
+			return writeExportFile(out, b.Bytes())
+		})
+	},
+}
+
+// listCache writes the devices with cache entries to w.
+func listCache(ctx context.Context, db database.Database, w io.Writer) error {
+	ips, err := db.ListDevices(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list devices")
+	}
+	var res cachedDevices
+	for _, ip := range ips {
+		entries, err := db.GetEntries(ctx, ip)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get cache entries of '%s'", ip)
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		device := cachedDevice{IP: ip}
+		var expires time.Time
+		for _, entry := range entries {
+			device.Entries = append(device.Entries, entry.Type)
+			if expires.IsZero() || entry.ExpiresAt.Before(expires) {
+				expires = entry.ExpiresAt
+			}
+			if entry.Type == database.EntryDeviceClass || entry.Type == database.EntryDeviceProperties && device.Class == "" {
+				var class struct {
+					Class string `json:"class"`
+				}
+				if err := json.Unmarshal(entry.Data, &class); err == nil {
+					device.Class = class.Class
+				}
+			}
+		}
+		device.Expires = expires.Format(time.RFC3339)
+		res.Devices = append(res.Devices, device)
+	}
+	return printCacheResult(w, res)
+}
+
+// inspectCache writes all cache entries of the device to w.
+func inspectCache(ctx context.Context, db database.Database, w io.Writer, ip string, includeSecrets bool) error {
+	entries, err := db.GetEntries(ctx, ip)
+	if err != nil {
+		return errors.Wrap(err, "failed to get cache entries")
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("no cache entries found for '%s'", ip)
+	}
+	if !includeSecrets {
+		entries, err = redactEntries(entries)
+		if err != nil {
+			return err
+		}
+	}
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal cache entries")
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+// invalidateCache deletes the cache entries of the devices, or of all devices if all is set.
+func invalidateCache(ctx context.Context, db database.Database, w io.Writer, ips []string, all bool) error {
+	if all {
+		var err error
+		ips, err = db.ListDevices(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to list devices")
+		}
+	}
+	for _, ip := range ips {
+		err := db.DeleteEntries(ctx, ip)
+		if err != nil {
+			return errors.Wrapf(err, "failed to invalidate cache entries of '%s'", ip)
+		}
+		log.Ctx(ctx).Debug().Str("ip", ip).Msg("invalidated cache entries")
+	}
+	_, err := fmt.Fprintf(w, "invalidated cache entries of %d device(s)\n", len(ips))
+	return err
+}
+
+// exportCache writes the cache entries of the devices, or of all devices if none are given, as JSON to w.
+func exportCache(ctx context.Context, db database.Database, w io.Writer, ips []string, includeSecrets bool) error {
+	if len(ips) == 0 {
+		var err error
+		ips, err = db.ListDevices(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to list devices")
+		}
+	}
+	entries := []database.Entry{}
+	for _, ip := range ips {
+		deviceEntries, err := db.GetEntries(ctx, ip)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get cache entries of '%s'", ip)
+		}
+		entries = append(entries, deviceEntries...)
+	}
+	if !includeSecrets {
+		var err error
+		entries, err = redactEntries(entries)
+		if err != nil {
+			return err
+		}
+	}
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal cache entries")
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
+
+// writeExportFile writes the export to the file, which is only readable by the owner as it might contain secrets.
+func writeExportFile(file string, b []byte) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "failed to open export file")
+	}
+	// the mode of existing files is not changed by OpenFile
+	if err := f.Chmod(0600); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "failed to set mode of export file")
+	}
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return errors.Wrap(err, "failed to write export file")
+	}
+	return errors.Wrap(f.Close(), "failed to close export file")
+}
+
+// redactedSecret replaces the secrets of the connection data in printed cache entries.
+const redactedSecret = "<redacted>"
+
+// redactEntries returns the entries with the SNMP communities, SNMPv3 keys and passwords of the connection data redacted.
+func redactEntries(entries []database.Entry) ([]database.Entry, error) {
+	res := make([]database.Entry, len(entries))
+	for i, entry := range entries {
+		res[i] = entry
+		if entry.Type != database.EntryConnectionData {
+			continue
+		}
+		var data network.ConnectionData
+		if err := json.Unmarshal(entry.Data, &data); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal connection data of '%s'", entry.IP)
+		}
+		if data.SNMP != nil {
+			for j := range data.SNMP.Communities {
+				data.SNMP.Communities[j] = redactedSecret
+			}
+			redactSecret(&data.SNMP.V3Data.AuthKey)
+			redactSecret(&data.SNMP.V3Data.PrivKey)
+		}
+		if data.HTTP != nil {
+			redactSecret(&data.HTTP.AuthPassword)
+		}
+		if data.SSH != nil {
+			redactSecret(&data.SSH.Password)
+		}
+		if data.GNMI != nil {
+			redactSecret(&data.GNMI.Password)
+		}
+		if data.NETCONF != nil {
+			redactSecret(&data.NETCONF.Password)
+		}
+		b, err := json.Marshal(data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal connection data of '%s'", entry.IP)
+		}
+		res[i].Data = b
+	}
+	return res, nil
+}
+
+func redactSecret(secret **string) {
+	if *secret != nil && **secret != "" {
+		s := redactedSecret
+		*secret = &s
+	}
+}
+
+// runCacheCommand runs the function on the configured database and exits if it fails.
+func runCacheCommand(f func(ctx context.Context, db database.Database) error) {
+	ctx := log.Logger.WithContext(context.Background())
+
+	db, err := database.GetDB(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		os.Exit(3)
+	}
+
+	err = f(ctx, db)
+	if err != nil {
+		handleError(ctx, err)
+		_ = db.CloseConnection(ctx)
+		os.Exit(3)
+	}
+
+	err = db.CloseConnection(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		os.Exit(3)
+	}
+}
+
+func printCacheResult(w io.Writer, res interface{}) error {
+	b, err := parser.Parse(res, viper.GetString("format"))
+	if err != nil {
+		return errors.Wrap(err, "failed to parse result")
+	}
+	_, err = fmt.Fprintf(w, "%s\n", b)
+	return err
+}
@@ -0,0 +1,167 @@
+// +build !client
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/inexio/thola/internal/database"
+	"github.com/inexio/thola/internal/network"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cacheDB is a database which only contains the cache entries of devices.
+type cacheDB struct {
+	database.Database
+	entries map[string][]database.Entry
+}
+
+func (d *cacheDB) ListDevices(_ context.Context) ([]string, error) {
+	var ips []string
+	for ip := range d.entries {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+	return ips, nil
+}
+
+func (d *cacheDB) GetEntries(_ context.Context, ip string) ([]database.Entry, error) {
+	return d.entries[ip], nil
+}
+
+func (d *cacheDB) DeleteEntries(_ context.Context, ip string) error {
+	delete(d.entries, ip)
+	return nil
+}
+
+func newTestCacheDB() *cacheDB {
+	expires := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	return &cacheDB{entries: map[string][]database.Entry{
+		"192.0.2.1": {
+			{IP: "192.0.2.1", Type: database.EntryDeviceProperties, ExpiresAt: expires, Data: json.RawMessage(`{"class":"ios"}`)},
+			{IP: "192.0.2.1", Type: database.EntryDeviceClass, ExpiresAt: expires.Add(time.Hour), Data: json.RawMessage(`{"class":"ios/openconfig"}`)},
+		},
+		"192.0.2.2": {
+			{IP: "192.0.2.2", Type: database.EntryConnectionData, ExpiresAt: expires, Data: json.RawMessage(
+				`{"snmp":{"communities":["public"],"v3_data":{"user":"monitoring","auth_key":"authkey","priv_key":"privkey"}},"http":{"auth_username":"admin","auth_password":"httppassword"},"gnmi":{"username":"admin","password":"gnmipassword"}}`,
+			)},
+		},
+	}}
+}
+
+func TestCacheCommands(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("list", func(t *testing.T) {
+		viper.Set("format", "json")
+		defer viper.Set("format", nil)
+
+		var out bytes.Buffer
+		require.NoError(t, listCache(ctx, newTestCacheDB(), &out))
+		var res cachedDevices
+		require.NoError(t, json.Unmarshal(out.Bytes(), &res), out.String())
+		assert.Equal(t, []cachedDevice{
+			{IP: "192.0.2.1", Class: "ios/openconfig", Entries: []string{database.EntryDeviceProperties, database.EntryDeviceClass}, Expires: "2021-06-01T12:00:00Z"},
+			{IP: "192.0.2.2", Entries: []string{database.EntryConnectionData}, Expires: "2021-06-01T12:00:00Z"},
+		}, res.Devices)
+	})
+
+	t.Run("inspect", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, inspectCache(ctx, newTestCacheDB(), &out, "192.0.2.2", false))
+		var entries []database.Entry
+		require.NoError(t, json.Unmarshal(out.Bytes(), &entries), out.String())
+		require.Len(t, entries, 1)
+		assert.Equal(t, database.EntryConnectionData, entries[0].Type)
+
+		assert.Error(t, inspectCache(ctx, newTestCacheDB(), &out, "192.0.2.3", false))
+	})
+
+	t.Run("secrets", func(t *testing.T) {
+		secrets := []string{"public", "authkey", "privkey", "httppassword", "gnmipassword"}
+
+		var out bytes.Buffer
+		require.NoError(t, inspectCache(ctx, newTestCacheDB(), &out, "192.0.2.2", false))
+		for _, secret := range secrets {
+			assert.NotContains(t, out.String(), secret)
+		}
+		var entries []database.Entry
+		require.NoError(t, json.Unmarshal(out.Bytes(), &entries), out.String())
+		require.Len(t, entries, 1)
+		var data network.ConnectionData
+		require.NoError(t, json.Unmarshal(entries[0].Data, &data))
+		assert.Equal(t, []string{redactedSecret}, data.SNMP.Communities)
+		assert.Equal(t, redactedSecret, *data.SNMP.V3Data.AuthKey)
+		assert.Equal(t, "monitoring", *data.SNMP.V3Data.User, "other fields are kept")
+		assert.Equal(t, redactedSecret, *data.HTTP.AuthPassword)
+		assert.Equal(t, redactedSecret, *data.GNMI.Password)
+		assert.Nil(t, data.SSH, "missing connection data is not added")
+
+		out.Reset()
+		require.NoError(t, exportCache(ctx, newTestCacheDB(), &out, nil, false))
+		for _, secret := range secrets {
+			assert.NotContains(t, out.String(), secret)
+		}
+
+		out.Reset()
+		require.NoError(t, exportCache(ctx, newTestCacheDB(), &out, nil, true))
+		for _, secret := range secrets {
+			assert.Contains(t, out.String(), secret)
+		}
+	})
+
+	t.Run("invalidate", func(t *testing.T) {
+		db := newTestCacheDB()
+		var out bytes.Buffer
+		require.NoError(t, invalidateCache(ctx, db, &out, []string{"192.0.2.1"}, false))
+		assert.Equal(t, "invalidated cache entries of 1 device(s)\n", out.String())
+		assert.NotContains(t, db.entries, "192.0.2.1")
+		assert.Contains(t, db.entries, "192.0.2.2")
+
+		out.Reset()
+		require.NoError(t, invalidateCache(ctx, db, &out, nil, true))
+		assert.Equal(t, "invalidated cache entries of 1 device(s)\n", out.String())
+		assert.Empty(t, db.entries)
+	})
+
+	t.Run("export", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, exportCache(ctx, newTestCacheDB(), &out, nil, false))
+		var entries []database.Entry
+		require.NoError(t, json.Unmarshal(out.Bytes(), &entries), out.String())
+		assert.Len(t, entries, 3)
+
+		out.Reset()
+		require.NoError(t, exportCache(ctx, newTestCacheDB(), &out, []string{"192.0.2.1"}, false))
+		require.NoError(t, json.Unmarshal(out.Bytes(), &entries), out.String())
+		require.Len(t, entries, 2)
+		assert.Equal(t, "192.0.2.1", entries[0].IP)
+
+		// devices without entries are exported as empty array
+		out.Reset()
+		require.NoError(t, exportCache(ctx, &cacheDB{}, &out, nil, false))
+		assert.Equal(t, "[]\n", out.String())
+	})
+
+	t.Run("export file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "export.json")
+		require.NoError(t, os.WriteFile(file, []byte(strings.Repeat("old export", 10)), 0644))
+
+		require.NoError(t, writeExportFile(file, []byte("[]\n")))
+		b, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, "[]\n", string(b))
+		info, err := os.Stat(file)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm(), "existing files are made private too")
+	})
+}
@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"github.com/inexio/go-monitoringplugin"
 	"github.com/inexio/thola/internal/request"
+	"github.com/inexio/thola/internal/utility"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"strconv"
+	"strings"
 )
 
 func init() {
@@ -107,3 +111,54 @@ func generateCheckThresholds(cmd *cobra.Command, warningMin, warningMax, critica
 
 	return thresholds
 }
+
+// parseKeyValueFlag parses a flag value in the format 'key1=value1,key2=value2'.
+// Only the given keys are allowed.
+func parseKeyValueFlag(flag string, allowedKeys ...string) (map[string]string, error) {
+	values := make(map[string]string)
+	for _, pair := range strings.Split(flag, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, errors.New("expected 'key=value' pairs")
+		}
+		key := strings.TrimSpace(kv[0])
+		if !utility.StringSliceContains(allowedKeys, key) {
+			return nil, fmt.Errorf("unknown key '%s', allowed keys are: %s", key, strings.Join(allowedKeys, ", "))
+		}
+		values[key] = strings.TrimSpace(kv[1])
+	}
+	return values, nil
+}
+
+// generateKeyValueThresholds is the equivalent of generateCheckThresholds for values parsed by parseKeyValueFlag.
+func generateKeyValueThresholds(values map[string]string, warningMin, warningMax, criticalMin, criticalMax string, setMinToZeroIfEmpty bool) (monitoringplugin.Thresholds, error) {
+	var thresholds monitoringplugin.Thresholds
+	bounds := map[string]*interface{}{
+		warningMin:  &thresholds.WarningMin,
+		warningMax:  &thresholds.WarningMax,
+		criticalMin: &thresholds.CriticalMin,
+		criticalMax: &thresholds.CriticalMax,
+	}
+	for key, bound := range bounds {
+		v, ok := values[key]
+		if key == "" || !ok {
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return monitoringplugin.Thresholds{}, fmt.Errorf("value of '%s' is not a number", key)
+		}
+		*bound = f
+	}
+
+	if setMinToZeroIfEmpty {
+		if thresholds.HasWarning() && thresholds.WarningMin == nil {
+			thresholds.WarningMin = 0
+		}
+		if thresholds.HasCritical() && thresholds.CriticalMin == nil {
+			thresholds.CriticalMin = 0
+		}
+	}
+
+	return thresholds, nil
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/inexio/thola/internal/request"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -11,6 +12,10 @@ func init() {
 
 	checkCpuLoad.Flags().Float64("warning", 0, "warning threshold for cpu load")
 	checkCpuLoad.Flags().Float64("critical", 0, "critical threshold for cpu load")
+	checkCpuLoad.Flags().StringArray("cpu-threshold", []string{}, "Thresholds for CPUs in the format "+
+		"'label=<regex>,warning=<percent>,critical=<percent>' (can be given multiple times)")
+	checkCpuLoad.Flags().String("mode", "average", "Aggregation of the load of multiple CPUs which the thresholds are applied to (average or max)")
+	checkCpuLoad.Flags().String("window", "", "Check the 1, 5 or 15 minute load average instead of the current load")
 }
 
 var checkCpuLoad = &cobra.Command{
@@ -19,9 +24,40 @@ var checkCpuLoad = &cobra.Command{
 	Long: "Checks the cpu load of a device.\n\n" +
 		"The usage will be printed as performance data.",
 	Run: func(cmd *cobra.Command, args []string) {
+		cpuThresholdFlags, err := cmd.Flags().GetStringArray("cpu-threshold")
+		if err != nil {
+			log.Fatal().Err(err).Msg("cpu-threshold needs to be a string")
+		}
+		var cpuThresholds []request.CheckCPUThresholds
+		for _, flag := range cpuThresholdFlags {
+			values, err := parseKeyValueFlag(flag, "label", "warning", "critical")
+			if err != nil {
+				log.Fatal().Err(err).Msgf("invalid cpu-threshold '%s'", flag)
+			}
+			thresholds, err := generateKeyValueThresholds(values, "", "warning", "", "critical", true)
+			if err != nil {
+				log.Fatal().Err(err).Msgf("invalid cpu-threshold '%s'", flag)
+			}
+			cpuThresholds = append(cpuThresholds, request.CheckCPUThresholds{
+				LabelRegex:        values["label"],
+				CPULoadThresholds: thresholds,
+			})
+		}
+		mode, err := cmd.Flags().GetString("mode")
+		if err != nil {
+			log.Fatal().Err(err).Msg("mode needs to be a string")
+		}
+		window, err := cmd.Flags().GetString("window")
+		if err != nil {
+			log.Fatal().Err(err).Msg("window needs to be a string")
+		}
+
 		r := request.CheckCPULoadRequest{
 			CheckDeviceRequest: getCheckDeviceRequest(args[0]),
 			CPULoadThresholds:  generateCheckThresholds(cmd, "", "warning", "", "critical", true),
+			CPUThresholds:      cpuThresholds,
+			Mode:               mode,
+			Window:             window,
 		}
 		handleRequest(&r)
 	},
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/inexio/thola/internal/request"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -9,8 +10,16 @@ func init() {
 	addDeviceFlags(checkDiskCMD)
 	checkCMD.AddCommand(checkDiskCMD)
 
-	checkDiskCMD.Flags().Float64("warning", 0, "warning threshold for free disk space")
-	checkDiskCMD.Flags().Float64("critical", 0, "critical threshold for free disk space")
+	checkDiskCMD.Flags().Float64("warning", 0, "warning threshold for used disk space in percent")
+	checkDiskCMD.Flags().Float64("critical", 0, "critical threshold for used disk space in percent")
+	checkDiskCMD.Flags().StringArray("storage-threshold", []string{}, "Thresholds for specific storages in the format "+
+		"'description=<regex>,type=<type>,warning=<percent>,critical=<percent>,warning-bytes=<bytes>,critical-bytes=<bytes>' (can be given multiple times)")
+	checkDiskCMD.Flags().Float64("inodes-warning", 0, "warning threshold for used inodes in percent")
+	checkDiskCMD.Flags().Float64("inodes-critical", 0, "critical threshold for used inodes in percent")
+	checkDiskCMD.Flags().StringArray("exclude", []string{"^/(proc|sys)(/|$)"}, "Exclude storages whose description or path matches the given regex (can be given multiple times)")
+	checkDiskCMD.Flags().Bool("days-until-full", false, "Estimate the days until each storage is full based on previous checks")
+	checkDiskCMD.Flags().Float64("days-until-full-warning", 0, "warning threshold for the minimum days until a storage is full")
+	checkDiskCMD.Flags().Float64("days-until-full-critical", 0, "critical threshold for the minimum days until a storage is full")
 }
 
 var checkDiskCMD = &cobra.Command{
@@ -19,10 +28,61 @@ var checkDiskCMD = &cobra.Command{
 	Long: "Checks the disk of a device.\n\n" +
 		"The metrics will be printed as performance data.",
 	Run: func(cmd *cobra.Command, args []string) {
+		storageThresholdFlags, err := cmd.Flags().GetStringArray("storage-threshold")
+		if err != nil {
+			log.Fatal().Err(err).Msg("storage-threshold needs to be a string")
+		}
+		var storageThresholds []request.CheckDiskStorageThresholds
+		for _, flag := range storageThresholdFlags {
+			t, err := parseStorageThreshold(flag)
+			if err != nil {
+				log.Fatal().Err(err).Msgf("invalid storage-threshold '%s'", flag)
+			}
+			storageThresholds = append(storageThresholds, t)
+		}
+		exclude, err := cmd.Flags().GetStringArray("exclude")
+		if err != nil {
+			log.Fatal().Err(err).Msg("exclude needs to be a string")
+		}
+		daysUntilFull, err := cmd.Flags().GetBool("days-until-full")
+		if err != nil {
+			log.Fatal().Err(err).Msg("days-until-full needs to be a boolean")
+		}
+
 		r := request.CheckDiskRequest{
-			CheckDeviceRequest: getCheckDeviceRequest(args[0]),
-			DiskThresholds:     generateCheckThresholds(cmd, "", "warning", "", "critical", true),
+			CheckDeviceRequest:      getCheckDeviceRequest(args[0]),
+			DiskThresholds:          generateCheckThresholds(cmd, "", "warning", "", "critical", true),
+			StorageThresholds:       storageThresholds,
+			InodeThresholds:         generateCheckThresholds(cmd, "", "inodes-warning", "", "inodes-critical", true),
+			ExcludeStorages:         exclude,
+			DaysUntilFull:           daysUntilFull,
+			DaysUntilFullThresholds: generateCheckThresholds(cmd, "days-until-full-warning", "", "days-until-full-critical", "", false),
 		}
 		handleRequest(&r)
 	},
 }
+
+// parseStorageThreshold parses the value of a storage-threshold flag.
+func parseStorageThreshold(flag string) (request.CheckDiskStorageThresholds, error) {
+	values, err := parseKeyValueFlag(flag, "description", "type", "warning", "critical", "warning-bytes", "critical-bytes")
+	if err != nil {
+		return request.CheckDiskStorageThresholds{}, err
+	}
+
+	var t request.CheckDiskStorageThresholds
+	if description, ok := values["description"]; ok {
+		t.DescriptionRegex = &description
+	}
+	if storageType, ok := values["type"]; ok {
+		t.Type = &storageType
+	}
+	t.Thresholds, err = generateKeyValueThresholds(values, "", "warning", "", "critical", true)
+	if err != nil {
+		return request.CheckDiskStorageThresholds{}, err
+	}
+	t.AbsoluteThresholds, err = generateKeyValueThresholds(values, "", "warning-bytes", "", "critical-bytes", true)
+	if err != nil {
+		return request.CheckDiskStorageThresholds{}, err
+	}
+	return t, nil
+}
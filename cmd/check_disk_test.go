@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseStorageThreshold(t *testing.T) {
+	tests := []struct {
+		flag     string
+		expected request.CheckDiskStorageThresholds
+		err      bool
+	}{
+		{
+			flag: "description=^/$,warning=80,critical=90",
+			expected: request.CheckDiskStorageThresholds{
+				DescriptionRegex: stringPointer("^/$"),
+				Thresholds:       monitoringplugin.Thresholds{WarningMin: 0, WarningMax: float64(80), CriticalMin: 0, CriticalMax: float64(90)},
+			},
+		},
+		{
+			flag: " type = hrStorageVirtualMemory , critical-bytes = 1024 ",
+			expected: request.CheckDiskStorageThresholds{
+				Type:               stringPointer("hrStorageVirtualMemory"),
+				AbsoluteThresholds: monitoringplugin.Thresholds{CriticalMin: 0, CriticalMax: float64(1024)},
+			},
+		},
+		{
+			flag:     "description=a=b",
+			expected: request.CheckDiskStorageThresholds{DescriptionRegex: stringPointer("a=b")},
+		},
+		{flag: "warning", err: true},
+		{flag: "warning=high", err: true},
+		{flag: "usage=80", err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.flag, func(t *testing.T) {
+			res, err := parseStorageThreshold(test.flag)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, res)
+		})
+	}
+}
+
+func stringPointer(s string) *string {
+	return &s
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/inexio/thola/internal/request"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -11,6 +12,10 @@ func init() {
 
 	checkMemoryUsage.Flags().Float64("warning", 0, "warning threshold for memory usage")
 	checkMemoryUsage.Flags().Float64("critical", 0, "critical threshold for memory usage")
+	checkMemoryUsage.Flags().Float64("free-warning", 0, "warning threshold for the minimum free memory in bytes")
+	checkMemoryUsage.Flags().Float64("free-critical", 0, "critical threshold for the minimum free memory in bytes")
+	checkMemoryUsage.Flags().StringArray("pool-threshold", []string{}, "Thresholds for memory pools in the format "+
+		"'label=<regex>,warning=<percent>,critical=<percent>,free-warning=<bytes>,free-critical=<bytes>' (can be given multiple times)")
 }
 
 var checkMemoryUsage = &cobra.Command{
@@ -19,10 +24,46 @@ var checkMemoryUsage = &cobra.Command{
 	Long: "Checks the memory usage of a device.\n\n" +
 		"The usage will be printed as performance data.",
 	Run: func(cmd *cobra.Command, args []string) {
+		poolThresholdFlags, err := cmd.Flags().GetStringArray("pool-threshold")
+		if err != nil {
+			log.Fatal().Err(err).Msg("pool-threshold needs to be a string")
+		}
+		var poolThresholds []request.CheckMemoryPoolThresholds
+		for _, flag := range poolThresholdFlags {
+			t, err := parsePoolThreshold(flag)
+			if err != nil {
+				log.Fatal().Err(err).Msgf("invalid pool-threshold '%s'", flag)
+			}
+			poolThresholds = append(poolThresholds, t)
+		}
+
 		r := request.CheckMemoryUsageRequest{
 			CheckDeviceRequest:    getCheckDeviceRequest(args[0]),
 			MemoryUsageThresholds: generateCheckThresholds(cmd, "", "warning", "", "critical", true),
+			MemoryFreeThresholds:  generateCheckThresholds(cmd, "free-warning", "", "free-critical", "", false),
+			PoolThresholds:        poolThresholds,
 		}
 		handleRequest(&r)
 	},
 }
+
+// parsePoolThreshold parses the value of a pool-threshold flag.
+func parsePoolThreshold(flag string) (request.CheckMemoryPoolThresholds, error) {
+	values, err := parseKeyValueFlag(flag, "label", "warning", "critical", "free-warning", "free-critical")
+	if err != nil {
+		return request.CheckMemoryPoolThresholds{}, err
+	}
+
+	t := request.CheckMemoryPoolThresholds{
+		LabelRegex: values["label"],
+	}
+	t.MemoryUsageThresholds, err = generateKeyValueThresholds(values, "", "warning", "", "critical", true)
+	if err != nil {
+		return request.CheckMemoryPoolThresholds{}, err
+	}
+	t.MemoryFreeThresholds, err = generateKeyValueThresholds(values, "free-warning", "", "free-critical", "", false)
+	if err != nil {
+		return request.CheckMemoryPoolThresholds{}, err
+	}
+	return t, nil
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/inexio/thola/internal/request"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
 
@@ -13,18 +14,31 @@ func init() {
 	checkServerCMD.Flags().Float64("procs-critical", 0, "critical threshold for procs count")
 	checkServerCMD.Flags().Float64("users-warning", 0, "warning threshold for users count")
 	checkServerCMD.Flags().Float64("users-critical", 0, "critical threshold for users count")
+	checkServerCMD.Flags().StringSlice("process", []string{}, "Names of processes which should be running")
+	checkServerCMD.Flags().Float64("process-instances-warning-min", 0, "warning min threshold for the instance count of each process")
+	checkServerCMD.Flags().Float64("process-instances-warning-max", 0, "warning max threshold for the instance count of each process")
+	checkServerCMD.Flags().Float64("process-instances-critical-min", 0, "critical min threshold for the instance count of each process (default 1)")
+	checkServerCMD.Flags().Float64("process-instances-critical-max", 0, "critical max threshold for the instance count of each process")
 }
 
 var checkServerCMD = &cobra.Command{
 	Use:   "server",
 	Short: "Check the server specific metrics of a device",
 	Long: "Checks the server specific metrics of a device.\n\n" +
-		"The usage will be printed as performance data.",
+		"The usage will be printed as performance data.\n" +
+		"Processes given with --process are checked for their instance count.",
 	Run: func(cmd *cobra.Command, args []string) {
+		processes, err := cmd.Flags().GetStringSlice("process")
+		if err != nil {
+			log.Fatal().Err(err).Msg("process needs to be a string")
+		}
+
 		r := request.CheckServerRequest{
-			CheckDeviceRequest: getCheckDeviceRequest(args[0]),
-			UsersThreshold:     generateCheckThresholds(cmd, "", "users-warning", "", "users-critical", true),
-			ProcsThreshold:     generateCheckThresholds(cmd, "", "procs-warning", "", "procs-critical", true),
+			CheckDeviceRequest:         getCheckDeviceRequest(args[0]),
+			UsersThreshold:             generateCheckThresholds(cmd, "", "users-warning", "", "users-critical", true),
+			ProcsThreshold:             generateCheckThresholds(cmd, "", "procs-warning", "", "procs-critical", true),
+			ProcessNames:               processes,
+			ProcessInstancesThresholds: generateCheckThresholds(cmd, "process-instances-warning-min", "process-instances-warning-max", "process-instances-critical-min", "process-instances-critical-max", false),
 		}
 		handleRequest(&r)
 	},
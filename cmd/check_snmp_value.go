@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/inexio/thola/internal/request"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addDeviceFlags(checkSNMPValueCMD)
+	addSNMPValueFlags(checkSNMPValueCMD)
+	checkCMD.AddCommand(checkSNMPValueCMD)
+
+	checkSNMPValueCMD.Flags().String("name", "snmp_value", "Name of the performance data")
+	checkSNMPValueCMD.Flags().String("unit", "", "Unit of the performance data")
+	checkSNMPValueCMD.Flags().Float64("warning-min", 0, "Warning min threshold for the values")
+	checkSNMPValueCMD.Flags().Float64("warning-max", 0, "Warning max threshold for the values")
+	checkSNMPValueCMD.Flags().Float64("critical-min", 0, "Critical min threshold for the values")
+	checkSNMPValueCMD.Flags().Float64("critical-max", 0, "Critical max threshold for the values")
+}
+
+var checkSNMPValueCMD = &cobra.Command{
+	Use:   "snmp-value",
+	Short: "Check custom snmp values of a device",
+	Long: "Checks custom snmp values of a device.\n\n" +
+		"Either single oids or columns of a table can be read. The values can be processed with\n" +
+		"the same operators that are used in device classes, given as yaml or json.\n" +
+		"The values will be printed as performance data and the thresholds are applied to each of them.",
+	Run: func(cmd *cobra.Command, args []string) {
+		name, err := cmd.Flags().GetString("name")
+		if err != nil {
+			log.Fatal().Err(err).Msg("name needs to be a string")
+		}
+		unit, err := cmd.Flags().GetString("unit")
+		if err != nil {
+			log.Fatal().Err(err).Msg("unit needs to be a string")
+		}
+
+		r := request.CheckSNMPValueRequest{
+			CheckDeviceRequest: getCheckDeviceRequest(args[0]),
+			SNMPValueQuery:     getSNMPValueQuery(cmd),
+			Name:               name,
+			Unit:               unit,
+			Thresholds:         generateCheckThresholds(cmd, "warning-min", "warning-max", "critical-min", "critical-max", false),
+		}
+		handleRequest(&r)
+	},
+}
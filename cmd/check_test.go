@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseKeyValueFlag(t *testing.T) {
+	tests := []struct {
+		flag     string
+		expected map[string]string
+		err      bool
+	}{
+		{flag: "a=1", expected: map[string]string{"a": "1"}},
+		{flag: " a = 1 , b=x=y", expected: map[string]string{"a": "1", "b": "x=y"}},
+		{flag: "a=", expected: map[string]string{"a": ""}},
+		{flag: "", err: true},
+		{flag: "a=1,b", err: true},
+		{flag: "c=1", err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.flag, func(t *testing.T) {
+			res, err := parseKeyValueFlag(test.flag, "a", "b")
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, res)
+		})
+	}
+}
+
+func TestGenerateKeyValueThresholds(t *testing.T) {
+	tests := []struct {
+		name                string
+		values              map[string]string
+		setMinToZeroIfEmpty bool
+		expected            monitoringplugin.Thresholds
+		err                 bool
+	}{
+		{
+			name:     "empty",
+			values:   map[string]string{"other": "1"},
+			expected: monitoringplugin.Thresholds{},
+		},
+		{
+			name:     "all bounds",
+			values:   map[string]string{"wmin": "1", "wmax": "2", "cmin": "0.5", "cmax": "3"},
+			expected: monitoringplugin.Thresholds{WarningMin: float64(1), WarningMax: float64(2), CriticalMin: 0.5, CriticalMax: float64(3)},
+		},
+		{
+			name:                "max bounds with min set to zero",
+			values:              map[string]string{"wmax": "2", "cmax": "3"},
+			setMinToZeroIfEmpty: true,
+			expected:            monitoringplugin.Thresholds{WarningMin: 0, WarningMax: float64(2), CriticalMin: 0, CriticalMax: float64(3)},
+		},
+		{
+			name:   "no number",
+			values: map[string]string{"wmax": "two"},
+			err:    true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := generateKeyValueThresholds(test.values, "wmin", "wmax", "cmin", "cmax", test.setMinToZeroIfEmpty)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, res)
+		})
+	}
+}
+
+func TestParsePoolThreshold(t *testing.T) {
+	tests := []struct {
+		flag     string
+		expected request.CheckMemoryPoolThresholds
+		err      bool
+	}{
+		{
+			flag: "label=^Processor$,warning=80,critical=90",
+			expected: request.CheckMemoryPoolThresholds{
+				LabelRegex:            "^Processor$",
+				MemoryUsageThresholds: monitoringplugin.Thresholds{WarningMin: 0, WarningMax: float64(80), CriticalMin: 0, CriticalMax: float64(90)},
+			},
+		},
+		{
+			flag: "free-warning=1024,free-critical=512",
+			expected: request.CheckMemoryPoolThresholds{
+				MemoryFreeThresholds: monitoringplugin.Thresholds{WarningMin: float64(1024), CriticalMin: float64(512)},
+			},
+		},
+		{flag: "free-warning=much", err: true},
+		{flag: "description=a", err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.flag, func(t *testing.T) {
+			res, err := parsePoolThreshold(test.flag)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, res)
+		})
+	}
+}
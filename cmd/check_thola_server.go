@@ -9,16 +9,24 @@ import (
 
 func init() {
 	checkCMD.AddCommand(checkTholaServerCMD)
+
+	checkTholaServerCMD.Flags().Float64("p95-response-time-warning", 0, "warning threshold for the p95 response time in seconds of the last five minutes")
+	checkTholaServerCMD.Flags().Float64("p95-response-time-critical", 0, "critical threshold for the p95 response time in seconds of the last five minutes")
+	checkTholaServerCMD.Flags().Float64("error-ratio-warning", 0, "warning threshold for the ratio of failed requests (0-1) of the last five minutes")
+	checkTholaServerCMD.Flags().Float64("error-ratio-critical", 0, "critical threshold for the ratio of failed requests (0-1) of the last five minutes")
 }
 
 var checkTholaServerCMD = &cobra.Command{
 	Use:   "thola-server",
 	Short: "Check whether a Thola server is reachable",
 	Long: "Check whether a Thola server is reachable.\n\n" +
-		"Also prints statistics about how many requests the server handled.",
+		"Also prints statistics about how many requests the server handled. The p95 response time and\n" +
+		"the ratio of failed requests of the last five minutes can be checked against thresholds.",
 	Run: func(cmd *cobra.Command, args []string) {
 		r := request.CheckTholaServerRequest{
-			CheckRequest: getCheckRequest(),
+			CheckRequest:              getCheckRequest(),
+			P95ResponseTimeThresholds: generateCheckThresholds(cmd, "", "p95-response-time-warning", "", "p95-response-time-critical", false),
+			ErrorRatioThresholds:      generateCheckThresholds(cmd, "", "error-ratio-warning", "", "error-ratio-critical", false),
 		}
 		handleRequest(&r)
 	},
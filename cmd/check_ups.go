@@ -33,21 +33,26 @@ func init() {
 	checkUPSCMD.Flags().Float64("system-voltage-warning-max", 0, "Warning max threshold for system voltage")
 	checkUPSCMD.Flags().Float64("system-voltage-critical-min", 0, "Critical min threshold for system voltage")
 	checkUPSCMD.Flags().Float64("system-voltage-critical-max", 0, "Critical max threshold for system voltage")
+
+	checkUPSCMD.Flags().Float64("output-load-warning", 0, "Warning threshold for the load of each output phase in percent")
+	checkUPSCMD.Flags().Float64("output-load-critical", 0, "Critical threshold for the load of each output phase in percent")
 }
 
 var checkUPSCMD = &cobra.Command{
 	Use:   "ups",
 	Short: "Checks whether a UPS device has its main voltage applied",
 	Long: "Checks whether a UPS device has its main voltage applied.\n\n" +
+		"It also alerts if the UPS is running on battery or bypass, the battery status is low or the battery needs to be replaced.\n" +
 		"All UPS statistics will be printed as performance data.",
 	Run: func(cmd *cobra.Command, args []string) {
 		r := request.CheckUPSRequest{
 			CheckDeviceRequest:           getCheckDeviceRequest(args[0]),
 			BatteryCurrentThresholds:     generateCheckThresholds(cmd, "batt-current-warning-min", "batt-current-warning-max", "batt-current-critical-min", "batt-current-critical-max", false),
 			BatteryTemperatureThresholds: generateCheckThresholds(cmd, "batt-temperature-warning-min", "batt-temperature-warning-max", "batt-temperature-critical-min", "batt-temperature-critical-max", false),
-			CurrentLoadThresholds:        generateCheckThresholds(cmd, "current-load-warning-min", "current-load-warning-max", "current-load-warning-max", "current-load-warning-max", false),
+			CurrentLoadThresholds:        generateCheckThresholds(cmd, "current-load-warning-min", "current-load-warning-max", "current-load-critical-min", "current-load-critical-max", false),
 			RectifierCurrentThresholds:   generateCheckThresholds(cmd, "rectifier-current-warning-min", "rectifier-current-warning-max", "rectifier-current-critical-min", "rectifier-current-critical-max", false),
 			SystemVoltageThresholds:      generateCheckThresholds(cmd, "system-voltage-warning-min", "system-voltage-warning-max", "system-voltage-critical-min", "system-voltage-critical-max", false),
+			OutputLoadThresholds:         generateCheckThresholds(cmd, "", "output-load-warning", "", "output-load-critical", false),
 		}
 		handleRequest(&r)
 	},
@@ -51,6 +51,18 @@ func buildDeviceFlagSet() *flag.FlagSet {
 	fs.IntSlice("https-port", nil, "Ports for HTTPS to use")
 	fs.String("http-username", "", "Username for HTTP/HTTPS authorization")
 	fs.String("http-password", "", "Password for HTTP/HTTPS authorization")
+	fs.IntSlice("ssh-port", []int{22}, "Ports for SSH to use")
+	fs.String("ssh-username", "", "Username for the SSH login")
+	fs.String("ssh-password", "", "Password for the SSH login")
+	fs.IntSlice("gnmi-port", []int{57400}, "Ports for gNMI to use")
+	fs.String("gnmi-username", "", "Username for gNMI authorization")
+	fs.String("gnmi-password", "", "Password for gNMI authorization")
+	fs.Bool("gnmi-insecure", false, "Connect to gNMI without TLS")
+	fs.Bool("gnmi-insecure-skip-verify", false, "Accept the gNMI TLS certificate of the device without verification")
+	fs.String("gnmi-ca-file", "", "CA bundle to verify the gNMI TLS certificate of the device against")
+	fs.IntSlice("netconf-port", []int{830}, "Ports for NETCONF to use")
+	fs.String("netconf-username", "", "Username for the NETCONF login")
+	fs.String("netconf-password", "", "Password for the NETCONF login")
 
 	return fs
 }
@@ -233,5 +245,113 @@ func bindDeviceFlags(cmd *cobra.Command) error {
 			return err
 		}
 	}
+	if x := cmd.Flags().Lookup("ssh-port"); x != nil {
+		err := viper.BindPFlag("device.ssh-ports", x)
+		if err != nil {
+			log.Error().
+				AnErr("Error", err).
+				Msg("Can't bind flag ssh-port")
+			return err
+		}
+	}
+	if x := cmd.Flags().Lookup("ssh-username"); x != nil {
+		err := viper.BindPFlag("device.ssh-username", x)
+		if err != nil {
+			log.Error().
+				AnErr("Error", err).
+				Msg("Can't bind flag ssh-username")
+			return err
+		}
+	}
+	if x := cmd.Flags().Lookup("ssh-password"); x != nil {
+		err := viper.BindPFlag("device.ssh-password", x)
+		if err != nil {
+			log.Error().
+				AnErr("Error", err).
+				Msg("Can't bind flag ssh-password")
+			return err
+		}
+	}
+	if x := cmd.Flags().Lookup("gnmi-port"); x != nil {
+		err := viper.BindPFlag("device.gnmi-ports", x)
+		if err != nil {
+			log.Error().
+				AnErr("Error", err).
+				Msg("Can't bind flag gnmi-port")
+			return err
+		}
+	}
+	if x := cmd.Flags().Lookup("gnmi-username"); x != nil {
+		err := viper.BindPFlag("device.gnmi-username", x)
+		if err != nil {
+			log.Error().
+				AnErr("Error", err).
+				Msg("Can't bind flag gnmi-username")
+			return err
+		}
+	}
+	if x := cmd.Flags().Lookup("gnmi-password"); x != nil {
+		err := viper.BindPFlag("device.gnmi-password", x)
+		if err != nil {
+			log.Error().
+				AnErr("Error", err).
+				Msg("Can't bind flag gnmi-password")
+			return err
+		}
+	}
+	if x := cmd.Flags().Lookup("gnmi-insecure"); x != nil {
+		err := viper.BindPFlag("device.gnmi-insecure", x)
+		if err != nil {
+			log.Error().
+				AnErr("Error", err).
+				Msg("Can't bind flag gnmi-insecure")
+			return err
+		}
+	}
+	if x := cmd.Flags().Lookup("gnmi-insecure-skip-verify"); x != nil {
+		err := viper.BindPFlag("device.gnmi-insecure-skip-verify", x)
+		if err != nil {
+			log.Error().
+				AnErr("Error", err).
+				Msg("Can't bind flag gnmi-insecure-skip-verify")
+			return err
+		}
+	}
+	if x := cmd.Flags().Lookup("gnmi-ca-file"); x != nil {
+		err := viper.BindPFlag("device.gnmi-ca-file", x)
+		if err != nil {
+			log.Error().
+				AnErr("Error", err).
+				Msg("Can't bind flag gnmi-ca-file")
+			return err
+		}
+	}
+	if x := cmd.Flags().Lookup("netconf-port"); x != nil {
+		err := viper.BindPFlag("device.netconf-ports", x)
+		if err != nil {
+			log.Error().
+				AnErr("Error", err).
+				Msg("Can't bind flag netconf-port")
+			return err
+		}
+	}
+	if x := cmd.Flags().Lookup("netconf-username"); x != nil {
+		err := viper.BindPFlag("device.netconf-username", x)
+		if err != nil {
+			log.Error().
+				AnErr("Error", err).
+				Msg("Can't bind flag netconf-username")
+			return err
+		}
+	}
+	if x := cmd.Flags().Lookup("netconf-password"); x != nil {
+		err := viper.BindPFlag("device.netconf-password", x)
+		if err != nil {
+			log.Error().
+				AnErr("Error", err).
+				Msg("Can't bind flag netconf-password")
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/request"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	addDeviceFlags(readSNMPValueCMD)
+	addSNMPValueFlags(readSNMPValueCMD)
+	readCMD.AddCommand(readSNMPValueCMD)
+}
+
+var readSNMPValueCMD = &cobra.Command{
+	Use:   "snmp-value",
+	Short: "Read out custom snmp values of a device",
+	Long: "Read out custom snmp values of a device.\n\n" +
+		"Either single oids or columns of a table can be read. The values can be processed with\n" +
+		"the same operators that are used in device classes, given as yaml or json.",
+	Run: func(cmd *cobra.Command, args []string) {
+		request := request.ReadSNMPValueRequest{
+			ReadRequest:    getReadRequest(args[0]),
+			SNMPValueQuery: getSNMPValueQuery(cmd),
+		}
+		handleRequest(&request)
+	},
+}
+
+// addSNMPValueFlags adds the flags which describe the values of a snmp value request.
+func addSNMPValueFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("oid", []string{}, "OID which is read with snmpget (can be given multiple times)")
+	cmd.Flags().String("table", "", "OID of a table which is walked")
+	cmd.Flags().StringArray("column", []string{}, "Column of the table which is read (can be given multiple times)")
+	cmd.Flags().String("label-column", "", "Column of the table which is used as label instead of the index")
+	cmd.Flags().String("operators", "", "Operators which are applied to each value, given as yaml or json list "+
+		"in the same format as in device classes")
+}
+
+// getSNMPValueQuery returns the snmp value query described by the flags of the command.
+func getSNMPValueQuery(cmd *cobra.Command) request.SNMPValueQuery {
+	oids, err := cmd.Flags().GetStringArray("oid")
+	if err != nil {
+		log.Fatal().Err(err).Msg("oid needs to be a string")
+	}
+	table, err := cmd.Flags().GetString("table")
+	if err != nil {
+		log.Fatal().Err(err).Msg("table needs to be a string")
+	}
+	columns, err := cmd.Flags().GetStringArray("column")
+	if err != nil {
+		log.Fatal().Err(err).Msg("column needs to be a string")
+	}
+	labelColumn, err := cmd.Flags().GetString("label-column")
+	if err != nil {
+		log.Fatal().Err(err).Msg("label-column needs to be a string")
+	}
+	operators, err := cmd.Flags().GetString("operators")
+	if err != nil {
+		log.Fatal().Err(err).Msg("operators needs to be a string")
+	}
+
+	query := request.SNMPValueQuery{
+		Table:       network.OID(table),
+		Columns:     columns,
+		LabelColumn: labelColumn,
+		Operators:   operators,
+	}
+	for _, oid := range oids {
+		query.OIDs = append(query.OIDs, network.OID(oid))
+	}
+	return query
+}
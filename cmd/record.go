@@ -0,0 +1,147 @@
+// +build !client
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/inexio/thola/internal/database"
+	"github.com/inexio/thola/internal/devicetest"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/request"
+	"github.com/inexio/thola/internal/snmprec"
+	"github.com/pkg/errors"
+	"github.com/rs/xid"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	addDeviceFlags(recordCMD)
+	rootCMD.AddCommand(recordCMD)
+
+	recordCMD.Flags().String("out", "", "The directory where the recording is written to")
+	recordCMD.Flags().Bool("anonymize", false, "Anonymize serial numbers, ip addresses, community strings and the sysName")
+	recordCMD.Flags().String("name", "public", "The name of the recording, which is also the community of the snmprec file in snmpsim")
+
+	err := recordCMD.MarkFlagRequired("out")
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't mark flag out as required")
+	}
+}
+
+var recordCMD = &cobra.Command{
+	Use:   "record",
+	Short: "Record a device for the integration tests",
+	Long: "Record a device for the integration tests.\n\n" +
+		"All SNMP data that Thola reads from the device while identifying and checking it is recorded.\n" +
+		"The recording is written as snmprec file together with the testdata file containing the expected responses.",
+	Run: func(cmd *cobra.Command, args []string) {
+		out, err := cmd.Flags().GetString("out")
+		if err != nil {
+			log.Fatal().Err(err).Msg("out needs to be a string")
+		}
+		anonymize, err := cmd.Flags().GetBool("anonymize")
+		if err != nil {
+			log.Fatal().Err(err).Msg("anonymize needs to be a boolean")
+		}
+		name, err := cmd.Flags().GetString("name")
+		if err != nil {
+			log.Fatal().Err(err).Msg("name needs to be a string")
+		}
+
+		logger := log.With().Str("request_id", xid.New().String()).Logger()
+		ctx := logger.WithContext(context.Background())
+
+		err = recordDevice(ctx, getBaseRequest(args[0]), out, name, anonymize)
+		if err != nil {
+			handleError(ctx, err)
+			os.Exit(3)
+		}
+	},
+}
+
+func recordDevice(ctx context.Context, baseRequest request.BaseRequest, out, name string, anonymize bool) error {
+	recorder := network.NewSNMPRecorder()
+	ctx = network.NewContextWithSNMPRecorder(ctx, recorder)
+
+	db, err := database.GetDB(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get database")
+	}
+	testData, err := devicetest.Create(ctx, baseRequest, func(r request.Request) (request.Response, error) {
+		return request.ProcessRequest(ctx, r)
+	})
+	if dbErr := db.CloseConnection(ctx); dbErr != nil && err == nil {
+		err = dbErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to create testdata")
+	}
+
+	var records []snmprec.Record
+	for _, response := range recorder.GetResponses() {
+		tag, value, err := response.GetSNMPRecValue()
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Str("oid", string(response.GetOID())).Msg("skipping recorded snmp response")
+			continue
+		}
+		records = append(records, snmprec.Record{
+			OID:   strings.TrimPrefix(string(response.GetOID()), "."),
+			Type:  tag,
+			Value: value,
+		})
+	}
+	if len(records) == 0 {
+		return errors.New("no snmp data was recorded")
+	}
+
+	testDataJSON, err := json.MarshalIndent(testData, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal testdata")
+	}
+
+	if anonymize {
+		anonymizer := snmprec.NewAnonymizer()
+		if identify := testData.Expectations.Identify; identify != nil && identify.Properties.SerialNumber != nil {
+			anonymizer.AddSensitiveString(*identify.Properties.SerialNumber, "SERIAL0001")
+		}
+		for _, community := range viper.GetStringSlice("device.snmp-communities") {
+			anonymizer.AddSensitiveString(community, name)
+		}
+		anonymizer.AddSensitiveString(viper.GetString("device.snmp-v3-user"), "user")
+		anonymizer.AddSensitiveString(viper.GetString("device.snmp-v3-auth-key"), "authkey")
+		anonymizer.AddSensitiveString(viper.GetString("device.snmp-v3-priv-key"), "privkey")
+
+		records = anonymizer.Anonymize(records)
+		testDataJSON = []byte(anonymizer.AnonymizeString(string(testDataJSON)))
+	}
+
+	err = os.MkdirAll(out, 0755)
+	if err != nil {
+		return errors.Wrap(err, "failed to create output directory")
+	}
+
+	var snmpRec bytes.Buffer
+	err = snmprec.Write(&snmpRec, records)
+	if err != nil {
+		return errors.Wrap(err, "failed to write snmprec")
+	}
+	err = ioutil.WriteFile(filepath.Join(out, name+".snmprec"), snmpRec.Bytes(), 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to write snmprec file")
+	}
+	err = ioutil.WriteFile(filepath.Join(out, name+".testdata"), testDataJSON, 0644)
+	if err != nil {
+		return errors.Wrap(err, "failed to write testdata file")
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/inexio/thola/doc"
 	"github.com/inexio/thola/internal/database"
+	"github.com/inexio/thola/internal/network"
 	"github.com/inexio/thola/internal/parser"
 	"github.com/inexio/thola/internal/request"
 	"github.com/pkg/errors"
@@ -17,6 +18,7 @@ import (
 	"github.com/spf13/viper"
 	"os"
 	"strings"
+	"time"
 )
 
 var cfgFile string
@@ -29,17 +31,24 @@ func init() {
 	rootCMD.PersistentFlags().StringVarP(&cfgFile, "config", "c", "", "The location of the config file")
 	rootCMD.PersistentFlags().StringP("loglevel", "l", "error", "The loglevel")
 	rootCMD.PersistentFlags().StringP("format", "f", "pretty", "Output format ('json', 'xml' or 'pretty')")
-	rootCMD.PersistentFlags().String("db-drivername", "built-in", "Database type for caching ('built-in', 'mysql' or 'redis' supported)")
+	rootCMD.PersistentFlags().String("db-drivername", "built-in", "Database type for caching ('built-in', 'mysql', 'postgres', 'sqlite', 'redis' or 'etcd' supported)")
 	rootCMD.PersistentFlags().String("db-duration", "60m", "Duration in which the cache stays valid")
-	rootCMD.PersistentFlags().String("sql-datasourcename", "", "Data sourcename if using a sql driver")
+	rootCMD.PersistentFlags().String("device-class-ttl", "", "Duration in which a cached device class is trusted without matching it again, at most db-duration (default: db-duration)")
+	rootCMD.PersistentFlags().String("sql-datasourcename", "", "Data sourcename if using a sql driver (the database file for sqlite)")
 	rootCMD.PersistentFlags().String("redis-addr", "", "Database address if using the redis driver")
 	rootCMD.PersistentFlags().String("redis-pass", "", "Database password if using the redis driver")
 
 	rootCMD.PersistentFlags().Int("redis-db", 0, "Database to use if using the redis driver")
+	rootCMD.PersistentFlags().StringSlice("etcd-endpoints", []string{"http://localhost:2379"}, "Endpoints if using the etcd driver")
+	rootCMD.PersistentFlags().String("etcd-username", "", "Username if using the etcd driver")
+	rootCMD.PersistentFlags().String("etcd-password", "", "Password if using the etcd driver")
 
 	rootCMD.PersistentFlags().Bool("db-rebuild", false, "Rebuild the cache DB")
 	rootCMD.PersistentFlags().Bool("no-cache", false, "Don't use a database cache")
 	rootCMD.PersistentFlags().Bool("ignore-db-failure", false, "Ignore the cache if the database fails")
+	rootCMD.PersistentFlags().Int("snmp-max-concurrent-requests", 0, "Maximum amount of concurrent SNMP requests per device (0 => no limit), overridden by the device class")
+	rootCMD.PersistentFlags().String("snmp-min-request-interval", "", "Minimum delay between two SNMP packets per device (e.g. \"50ms\"), overridden by the device class")
+	rootCMD.PersistentFlags().Float64("snmp-max-requests-per-second", 0, "Maximum amount of SNMP packets per second per device (0 => no limit), overridden by the device class")
 	rootCMD.Flags().BoolP("version", "v", false, "Prints the version of Thola")
 
 	err := viper.BindPFlag("config", rootCMD.PersistentFlags().Lookup("config"))
@@ -82,6 +91,14 @@ func init() {
 		return
 	}
 
+	err = viper.BindPFlag("db.device-class-ttl", rootCMD.PersistentFlags().Lookup("device-class-ttl"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag device-class-ttl")
+		return
+	}
+
 	err = viper.BindPFlag("db.sql.datasourcename", rootCMD.PersistentFlags().Lookup("sql-datasourcename"))
 	if err != nil {
 		log.Error().
@@ -114,6 +131,30 @@ func init() {
 		return
 	}
 
+	err = viper.BindPFlag("db.etcd.endpoints", rootCMD.PersistentFlags().Lookup("etcd-endpoints"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag etcd-endpoints")
+		return
+	}
+
+	err = viper.BindPFlag("db.etcd.username", rootCMD.PersistentFlags().Lookup("etcd-username"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag etcd-username")
+		return
+	}
+
+	err = viper.BindPFlag("db.etcd.password", rootCMD.PersistentFlags().Lookup("etcd-password"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag etcd-password")
+		return
+	}
+
 	err = viper.BindPFlag("db.rebuild", rootCMD.PersistentFlags().Lookup("db-rebuild"))
 	if err != nil {
 		log.Error().
@@ -137,6 +178,30 @@ func init() {
 			Msg("Can't bind flag ignore-db-failure")
 		return
 	}
+
+	err = viper.BindPFlag("snmp.max-concurrent-requests", rootCMD.PersistentFlags().Lookup("snmp-max-concurrent-requests"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag snmp-max-concurrent-requests")
+		return
+	}
+
+	err = viper.BindPFlag("snmp.min-request-interval", rootCMD.PersistentFlags().Lookup("snmp-min-request-interval"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag snmp-min-request-interval")
+		return
+	}
+
+	err = viper.BindPFlag("snmp.max-requests-per-second", rootCMD.PersistentFlags().Lookup("snmp-max-requests-per-second"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag snmp-max-requests-per-second")
+		return
+	}
 }
 
 func initConfig() {
@@ -180,7 +245,7 @@ var rootCMD = &cobra.Command{
 			return errors.New("invalid loglevel set")
 		}
 		zerolog.SetGlobalLevel(loglevel)
-		return nil
+		return setSNMPSchedule()
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if cmd.Flags().Lookup("version").Changed {
@@ -191,6 +256,26 @@ var rootCMD = &cobra.Command{
 	},
 }
 
+// setSNMPSchedule sets the default limits of the snmp requests sent to a device.
+func setSNMPSchedule() error {
+	schedule := network.SNMPSchedule{
+		MaxConcurrentRequests: viper.GetInt("snmp.max-concurrent-requests"),
+		MaxRequestsPerSecond:  viper.GetFloat64("snmp.max-requests-per-second"),
+	}
+	if interval := viper.GetString("snmp.min-request-interval"); interval != "" {
+		var err error
+		schedule.MinRequestInterval, err = time.ParseDuration(interval)
+		if err != nil {
+			return errors.New("invalid snmp min request interval set")
+		}
+	}
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	network.SetDefaultSNMPSchedule(schedule)
+	return nil
+}
+
 // Execute is the entrypoint for the CLI interface.
 func Execute() {
 	if err := rootCMD.Execute(); err != nil {
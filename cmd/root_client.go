@@ -25,9 +25,13 @@ func init() {
 	rootCMD.PersistentFlags().StringP("target-api", "t", "", "The URL of the target API")
 	rootCMD.PersistentFlags().String("target-api-username", "", "The username for authorization on the target API")
 	rootCMD.PersistentFlags().String("target-api-password", "", "The password for authorization on the target API")
+	rootCMD.PersistentFlags().String("target-api-token", "", "The token for authorization on the target API")
 	rootCMD.PersistentFlags().String("target-api-format", "json", "The format of the target API ('json' or 'xml')")
 
 	rootCMD.PersistentFlags().Bool("insecure-ssl-cert", false, "Allow insecure SSL certificate of the target API")
+	rootCMD.PersistentFlags().String("target-api-cert", "", "The client certificate for authorization on the target API")
+	rootCMD.PersistentFlags().String("target-api-key", "", "The key of the client certificate")
+	rootCMD.PersistentFlags().String("target-api-ca", "", "The CA bundle to verify the certificate of the target API")
 
 	rootCMD.Flags().BoolP("version", "v", false, "Prints the version of Thola")
 
@@ -79,6 +83,14 @@ func init() {
 		return
 	}
 
+	err = viper.BindPFlag("target-api-token", rootCMD.PersistentFlags().Lookup("target-api-token"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag target-api-token")
+		return
+	}
+
 	err = viper.BindPFlag("target-api-format", rootCMD.PersistentFlags().Lookup("target-api-format"))
 	if err != nil {
 		log.Error().
@@ -94,6 +106,30 @@ func init() {
 			Msg("Can't bind flag insecure-ssl-cert")
 		return
 	}
+
+	err = viper.BindPFlag("target-api-cert", rootCMD.PersistentFlags().Lookup("target-api-cert"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag target-api-cert")
+		return
+	}
+
+	err = viper.BindPFlag("target-api-key", rootCMD.PersistentFlags().Lookup("target-api-key"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag target-api-key")
+		return
+	}
+
+	err = viper.BindPFlag("target-api-ca", rootCMD.PersistentFlags().Lookup("target-api-ca"))
+	if err != nil {
+		log.Error().
+			AnErr("Error", err).
+			Msg("Can't bind flag target-api-ca")
+		return
+	}
 }
 
 var rootCMD = &cobra.Command{
@@ -116,6 +152,9 @@ var rootCMD = &cobra.Command{
 		if !(viper.GetString("target-api-format") == "json" || viper.GetString("target-api-format") == "xml") {
 			return errors.New("invalid api format set")
 		}
+		if (viper.GetString("target-api-cert") == "") != (viper.GetString("target-api-key") == "") {
+			return errors.New("client certificate and key need to be set together")
+		}
 		loglevel, err := zerolog.ParseLevel(viper.GetString("loglevel"))
 		if err != nil {
 			return errors.New("invalid loglevel set")
@@ -15,6 +15,7 @@ func getBaseRequest(host string) request.BaseRequest {
 	var nullInt *int
 	var nullUInt32 *uint32
 	var nullString *string
+	var nullBool *bool
 	timeout := viper.GetInt("request.timeout")
 	maxRepetitions := viper.GetUint32("device.snmp-max-repetitions")
 	parallelRequests := viper.GetInt("device.snmp-discover-par-requests")
@@ -22,6 +23,15 @@ func getBaseRequest(host string) request.BaseRequest {
 	retries := viper.GetInt("device.snmp-discover-retries")
 	authUsername := viper.GetString("device.http-username")
 	authPassword := viper.GetString("device.http-password")
+	sshUsername := viper.GetString("device.ssh-username")
+	sshPassword := viper.GetString("device.ssh-password")
+	gnmiUsername := viper.GetString("device.gnmi-username")
+	gnmiPassword := viper.GetString("device.gnmi-password")
+	gnmiInsecure := viper.GetBool("device.gnmi-insecure")
+	gnmiInsecureSkipVerify := viper.GetBool("device.gnmi-insecure-skip-verify")
+	gnmiCAFile := viper.GetString("device.gnmi-ca-file")
+	netconfUsername := viper.GetString("device.netconf-username")
+	netconfPassword := viper.GetString("device.netconf-password")
 	v3Level := viper.GetString("device.snmp-v3-level")
 	v3ContextName := viper.GetString("device.snmp-v3-context")
 	v3User := viper.GetString("device.snmp-v3-user")
@@ -58,6 +68,24 @@ func getBaseRequest(host string) request.BaseRequest {
 					AuthUsername: utility.IfThenElse(deviceFlagSet.Changed("http-username"), &authUsername, nullString).(*string),
 					AuthPassword: utility.IfThenElse(deviceFlagSet.Changed("http-password"), &authPassword, nullString).(*string),
 				},
+				SSH: &network.SSHConnectionData{
+					Ports:    utility.IfThenElse(deviceFlagSet.Changed("ssh-port"), viper.GetIntSlice("device.ssh-ports"), []int{}).([]int),
+					Username: utility.IfThenElse(deviceFlagSet.Changed("ssh-username"), &sshUsername, nullString).(*string),
+					Password: utility.IfThenElse(deviceFlagSet.Changed("ssh-password"), &sshPassword, nullString).(*string),
+				},
+				GNMI: &network.GNMIConnectionData{
+					Ports:              utility.IfThenElse(deviceFlagSet.Changed("gnmi-port"), viper.GetIntSlice("device.gnmi-ports"), []int{}).([]int),
+					Username:           utility.IfThenElse(deviceFlagSet.Changed("gnmi-username"), &gnmiUsername, nullString).(*string),
+					Password:           utility.IfThenElse(deviceFlagSet.Changed("gnmi-password"), &gnmiPassword, nullString).(*string),
+					Insecure:           utility.IfThenElse(deviceFlagSet.Changed("gnmi-insecure"), &gnmiInsecure, nullBool).(*bool),
+					InsecureSkipVerify: utility.IfThenElse(deviceFlagSet.Changed("gnmi-insecure-skip-verify"), &gnmiInsecureSkipVerify, nullBool).(*bool),
+					CAFile:             utility.IfThenElse(deviceFlagSet.Changed("gnmi-ca-file"), &gnmiCAFile, nullString).(*string),
+				},
+				NETCONF: &network.NETCONFConnectionData{
+					Ports:    utility.IfThenElse(deviceFlagSet.Changed("netconf-port"), viper.GetIntSlice("device.netconf-ports"), []int{}).([]int),
+					Username: utility.IfThenElse(deviceFlagSet.Changed("netconf-username"), &netconfUsername, nullString).(*string),
+					Password: utility.IfThenElse(deviceFlagSet.Changed("netconf-password"), &netconfPassword, nullString).(*string),
+				},
 			},
 		},
 	}
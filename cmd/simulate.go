@@ -0,0 +1,68 @@
+// +build !client
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/inexio/thola/internal/simulator"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"os"
+	"os/signal"
+)
+
+func init() {
+	rootCMD.AddCommand(simulateCMD)
+
+	simulateCMD.Flags().String("dir", "test/testdata/devices", "The directory containing the snmprec files")
+	simulateCMD.Flags().String("address", "127.0.0.1:161", "The UDP address the simulator listens on")
+	simulateCMD.Flags().String("v3-user", "", "The username for SNMP v3 requests")
+	simulateCMD.Flags().String("v3-auth-proto", "", "The authentication protocol of the SNMP v3 user (e.g. 'MD5' or 'SHA')")
+	simulateCMD.Flags().String("v3-auth-key", "", "The authentication passphrase of the SNMP v3 user")
+	simulateCMD.Flags().String("v3-priv-proto", "", "The privacy protocol of the SNMP v3 user (e.g. 'DES' or 'AES')")
+	simulateCMD.Flags().String("v3-priv-key", "", "The privacy passphrase of the SNMP v3 user")
+}
+
+var simulateCMD = &cobra.Command{
+	Use:   "simulate",
+	Short: "Simulate devices from snmprec files",
+	Long: "Simulate devices from snmprec files.\n\n" +
+		"Starts an SNMP agent which answers requests with the data of the snmprec files in the directory.\n" +
+		"The snmprec file is selected by the community (SNMP v1/v2c) or the context name (SNMP v3),\n" +
+		"which is the path of the file relative to the directory without the file extension.",
+	Run: func(cmd *cobra.Command, args []string) {
+		config := simulator.Config{}
+		config.Dir, _ = cmd.Flags().GetString("dir")
+		config.Address, _ = cmd.Flags().GetString("address")
+
+		if user, _ := cmd.Flags().GetString("v3-user"); user != "" {
+			config.V3User = &simulator.V3User{Name: user}
+			config.V3User.AuthProtocol, _ = cmd.Flags().GetString("v3-auth-proto")
+			config.V3User.AuthKey, _ = cmd.Flags().GetString("v3-auth-key")
+			config.V3User.PrivProtocol, _ = cmd.Flags().GetString("v3-priv-proto")
+			config.V3User.PrivKey, _ = cmd.Flags().GetString("v3-priv-key")
+		}
+
+		ctx, cancel := context.WithCancel(log.Logger.WithContext(context.Background()))
+		defer cancel()
+
+		agent, err := simulator.NewAgent(config)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to start simulator")
+		}
+
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, os.Interrupt)
+		go func() {
+			<-quit
+			cancel()
+		}()
+
+		fmt.Printf("Simulating %d devices on %s\n", len(agent.Communities()), agent.Addr())
+		err = agent.Serve(ctx)
+		if err != nil {
+			log.Fatal().Err(err).Msg("simulator failed")
+		}
+	},
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/request"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	rootCMD.AddCommand(snmpCMD)
+
+	addDeviceFlags(snmpGetCMD)
+	snmpGetCMD.Use += " [oid...]"
+	snmpGetCMD.Args = cobra.MinimumNArgs(2)
+	snmpCMD.AddCommand(snmpGetCMD)
+
+	addDeviceFlags(snmpWalkCMD)
+	snmpWalkCMD.Use += " [oid]"
+	snmpWalkCMD.Args = cobra.ExactArgs(2)
+	snmpCMD.AddCommand(snmpWalkCMD)
+
+	addDeviceFlags(snmpBulkWalkCMD)
+	snmpBulkWalkCMD.Use += " [oid]"
+	snmpBulkWalkCMD.Args = cobra.ExactArgs(2)
+	snmpCMD.AddCommand(snmpBulkWalkCMD)
+}
+
+var snmpCMD = &cobra.Command{
+	Use:   "snmp",
+	Short: "Send raw snmp requests to a device",
+	Long: "Send raw snmp requests to a device.\n\n" +
+		"The connection data is discovered and cached in the same way as for all other requests.\n" +
+		"By default the output is in the snmprec format, which can be used as test data for snmpsim.",
+	DisableFlagsInUseLine: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		err := rootCMD.PersistentPreRunE(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		if !cmd.Flags().Changed("format") {
+			viper.Set("format", "snmprec")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(cmd.UsageString())
+	},
+}
+
+var snmpGetCMD = &cobra.Command{
+	Use:   "get",
+	Short: "Send a snmpget request to a device",
+	Long:  "Sends a snmpget request for one or more oids to a device.",
+	Run: func(cmd *cobra.Command, args []string) {
+		r := request.SNMPGetRequest{
+			BaseRequest: getBaseRequest(args[0]),
+		}
+		for _, oid := range args[1:] {
+			r.OIDs = append(r.OIDs, network.OID(oid))
+		}
+		handleRequest(&r)
+	},
+}
+
+var snmpWalkCMD = &cobra.Command{
+	Use:   "walk",
+	Short: "Send a snmpwalk request to a device",
+	Long:  "Walks the subtree of the oid with GETNEXT requests.",
+	Run: func(cmd *cobra.Command, args []string) {
+		r := request.SNMPWalkRequest{
+			BaseRequest: getBaseRequest(args[0]),
+			OID:         network.OID(args[1]),
+		}
+		handleRequest(&r)
+	},
+}
+
+var snmpBulkWalkCMD = &cobra.Command{
+	Use:   "bulkwalk",
+	Short: "Send a snmpbulkwalk request to a device",
+	Long:  "Walks the subtree of the oid with GETBULK requests.",
+	Run: func(cmd *cobra.Command, args []string) {
+		r := request.SNMPWalkRequest{
+			BaseRequest: getBaseRequest(args[0]),
+			OID:         network.OID(args[1]),
+			Bulk:        true,
+		}
+		handleRequest(&r)
+	},
+}
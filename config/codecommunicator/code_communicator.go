@@ -35,7 +35,7 @@ func GetCodeCommunicator(deviceClass communicator.Communicator, parentNetworkDev
 		return &poweronePCCCommunicator{base}, nil
 	case "ironware":
 		return &ironwareCommunicator{base}, nil
-	case "ios":
+	case "ios", "ios/openconfig":
 		return &iosCommunicator{base}, nil
 	case "ekinops":
 		return &ekinopsCommunicator{base}, nil
@@ -45,12 +45,14 @@ func GetCodeCommunicator(deviceClass communicator.Communicator, parentNetworkDev
 		return &timosSASCommunicator{base}, nil
 	case "timos":
 		return &timosCommunicator{base}, nil
-	case "junos":
+	case "junos", "junos/openconfig":
 		return &junosCommunicator{base}, nil
 	case "aviat":
 		return &aviatCommunicator{base}, nil
 	case "fortigate":
 		return &fortigateCommunicator{base}, nil
+	case "ups-mib":
+		return &upsMIBCommunicator{base}, nil
 	}
 	return nil, tholaerr.NewNotFoundError(fmt.Sprintf("no code communicator found for device class identifier '%s'", classIdentifier))
 }
@@ -99,10 +101,42 @@ func (c *codeCommunicator) GetServerComponentUsers(_ context.Context) (int, erro
 	return 0, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
 }
 
+func (c *codeCommunicator) GetServerComponentLoad1(_ context.Context) (float64, error) {
+	return 0, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
+func (c *codeCommunicator) GetServerComponentLoad5(_ context.Context) (float64, error) {
+	return 0, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
+func (c *codeCommunicator) GetServerComponentLoad15(_ context.Context) (float64, error) {
+	return 0, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
+func (c *codeCommunicator) GetServerComponentSwapTotal(_ context.Context) (uint64, error) {
+	return 0, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
+func (c *codeCommunicator) GetServerComponentSwapFree(_ context.Context) (uint64, error) {
+	return 0, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
+func (c *codeCommunicator) GetServerComponentUptime(_ context.Context) (uint64, error) {
+	return 0, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
+func (c *codeCommunicator) GetServerComponentProcesses(_ context.Context) ([]device.ServerComponentProcess, error) {
+	return nil, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
 func (c *codeCommunicator) GetDiskComponentStorages(_ context.Context) ([]device.DiskComponentStorage, error) {
 	return nil, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
 }
 
+func (c *codeCommunicator) GetDiskComponentInodes(_ context.Context) ([]device.DiskComponentInodes, error) {
+	return nil, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
 func (c *codeCommunicator) GetUPSComponentAlarmLowVoltageDisconnect(_ context.Context) (int, error) {
 	return 0, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
 }
@@ -155,6 +189,30 @@ func (c *codeCommunicator) GetUPSComponentSystemVoltage(_ context.Context) (floa
 	return 0, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
 }
 
+func (c *codeCommunicator) GetUPSComponentOutputFrequency(_ context.Context) (float64, error) {
+	return 0, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
+func (c *codeCommunicator) GetUPSComponentOutputSource(_ context.Context) (string, error) {
+	return "", tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
+func (c *codeCommunicator) GetUPSComponentBatteryStatus(_ context.Context) (string, error) {
+	return "", tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
+func (c *codeCommunicator) GetUPSComponentBatteryReplacementNeeded(_ context.Context) (bool, error) {
+	return false, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
+func (c *codeCommunicator) GetUPSComponentInputs(_ context.Context) ([]device.UPSComponentInput, error) {
+	return nil, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
+func (c *codeCommunicator) GetUPSComponentOutputs(_ context.Context) ([]device.UPSComponentOutput, error) {
+	return nil, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
+}
+
 func (c *codeCommunicator) GetSBCComponentGlobalCallPerSecond(_ context.Context) (int, error) {
 	return 0, tholaerr.NewNotImplementedError("function is not implemented for this communicator")
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/inexio/thola/internal/device"
 	"github.com/inexio/thola/internal/network"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
 	"github.com/shopspring/decimal"
 	"strconv"
 	"strings"
@@ -57,6 +58,27 @@ func (c *iosCommunicator) GetCPUComponentCPULoad(ctx context.Context) ([]device.
 		indices[cpuLoadResponseDeprecated.GetOID().GetIndex()] = len(cpus) - 1 //current entry
 	}
 
+	// the 5 minute value is used as cpu load, additionally read out the 1 minute value
+	for i := range cpus {
+		cpus[i].Load5 = cpus[i].Load
+	}
+	cpuLoad1min, err := con.SNMP.SnmpClient.SNMPWalk(ctx, "1.3.6.1.4.1.9.9.109.1.1.1.1.7")
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to read 1 minute cpu load, continuing without it")
+	} else {
+		for _, cpuLoadResponse := range cpuLoad1min {
+			cpuIndex, ok := indices[cpuLoadResponse.GetOID().GetIndex()]
+			if !ok {
+				continue
+			}
+			cpu, err := c.getCPUBySNMPResponse(cpuLoadResponse)
+			if err != nil {
+				return nil, err
+			}
+			cpus[cpuIndex].Load1 = cpu.Load
+		}
+	}
+
 	// read out physical indices for cpus
 	physicalIndicesResult, err := con.SNMP.SnmpClient.SNMPWalk(ctx, "1.3.6.1.4.1.9.9.109.1.1.1.1.2")
 	if err != nil {
@@ -190,9 +212,14 @@ func (c *iosCommunicator) getMemoryComponentMemoryUsage(ctx context.Context, poo
 			}
 		}
 
+		usedBytes, freeBytes, totalBytes := uint64(used.IntPart()), uint64(free.IntPart()), uint64(total.IntPart())
+
 		pools = append(pools, device.MemoryPool{
 			Label:                        &poolLabelString,
 			Usage:                        &usage,
+			Total:                        &totalBytes,
+			Used:                         &usedBytes,
+			Free:                         &freeBytes,
 			PerformanceDataPointModifier: performanceDataPointModifier,
 		})
 	}
@@ -28,6 +28,8 @@ func TestIosCommunicator_GetCPUComponentCPULoad(t *testing.T) {
 		Return([]network.SNMPResponse{
 			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.5.1", gosnmp.Gauge32, uint(10)),
 		}, nil).
+		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.7")).
+		Return(nil, errors.New("no such oid")).
 		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.2")).
 		Return(nil, errors.New("no such oid"))
 
@@ -38,6 +40,7 @@ func TestIosCommunicator_GetCPUComponentCPULoad(t *testing.T) {
 		{
 			Label: nil,
 			Load:  &load,
+			Load5: &load,
 		},
 	}
 
@@ -63,6 +66,8 @@ func TestIosCommunicator_GetCPUComponentCPULoad_onlyDepOID(t *testing.T) {
 		Return([]network.SNMPResponse{
 			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.5.1", gosnmp.Gauge32, uint(10)),
 		}, nil).
+		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.7")).
+		Return(nil, errors.New("no such oid")).
 		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.2")).
 		Return(nil, errors.New("no such oid"))
 
@@ -73,6 +78,7 @@ func TestIosCommunicator_GetCPUComponentCPULoad_onlyDepOID(t *testing.T) {
 		{
 			Label: nil,
 			Load:  &load,
+			Load5: &load,
 		},
 	}
 
@@ -98,6 +104,8 @@ func TestIosCommunicator_GetCPUComponentCPULoad_onlyRevOID(t *testing.T) {
 		}, nil).
 		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.5")).
 		Return(nil, errors.New("no such oid")).
+		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.7")).
+		Return(nil, errors.New("no such oid")).
 		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.2")).
 		Return(nil, errors.New("no such oid"))
 
@@ -108,6 +116,7 @@ func TestIosCommunicator_GetCPUComponentCPULoad_onlyRevOID(t *testing.T) {
 		{
 			Label: nil,
 			Load:  &load,
+			Load5: &load,
 		},
 	}
 
@@ -135,6 +144,8 @@ func TestIosCommunicator_GetCPUComponentCPULoad_withLabel(t *testing.T) {
 		Return([]network.SNMPResponse{
 			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.5.1", gosnmp.Gauge32, uint(10)),
 		}, nil).
+		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.7")).
+		Return(nil, errors.New("no such oid")).
 		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.2")).
 		Return([]network.SNMPResponse{
 			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.2.1", gosnmp.Integer, 1),
@@ -152,6 +163,7 @@ func TestIosCommunicator_GetCPUComponentCPULoad_withLabel(t *testing.T) {
 		{
 			Label: &cpu1,
 			Load:  &load,
+			Load5: &load,
 		},
 	}
 
@@ -183,6 +195,8 @@ func TestIosCommunicator_GetCPUComponentCPULoad_multipleCPUs(t *testing.T) {
 			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.5.2", gosnmp.Gauge32, uint(20)),
 			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.5.3", gosnmp.Gauge32, uint(30)),
 		}, nil).
+		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.7")).
+		Return(nil, errors.New("no such oid")).
 		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.2")).
 		Return(nil, errors.New("no such oid"))
 
@@ -195,14 +209,17 @@ func TestIosCommunicator_GetCPUComponentCPULoad_multipleCPUs(t *testing.T) {
 		{
 			Label: nil,
 			Load:  &load1,
+			Load5: &load1,
 		},
 		{
 			Label: nil,
 			Load:  &load2,
+			Load5: &load2,
 		},
 		{
 			Label: nil,
 			Load:  &load3,
+			Load5: &load3,
 		},
 	}
 
@@ -234,6 +251,8 @@ func TestIosCommunicator_GetCPUComponentCPULoad_multipleCPUsWithLabel(t *testing
 			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.5.2", gosnmp.Gauge32, uint(20)),
 			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.5.3", gosnmp.Gauge32, uint(30)),
 		}, nil).
+		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.7")).
+		Return(nil, errors.New("no such oid")).
 		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.2")).
 		Return([]network.SNMPResponse{
 			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.2.1", gosnmp.Integer, 3),
@@ -265,14 +284,17 @@ func TestIosCommunicator_GetCPUComponentCPULoad_multipleCPUsWithLabel(t *testing
 		{
 			Label: &cpu1,
 			Load:  &load1,
+			Load5: &load1,
 		},
 		{
 			Label: &cpu2,
 			Load:  &load2,
+			Load5: &load2,
 		},
 		{
 			Label: &cpu3,
 			Load:  &load3,
+			Load5: &load3,
 		},
 	}
 
@@ -302,6 +324,8 @@ func TestIosCommunicator_GetCPUComponentCPULoad_prioritiseRevOID(t *testing.T) {
 			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.5.1", gosnmp.Gauge32, uint(20)),
 			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.8.2", gosnmp.Gauge32, uint(20)),
 		}, nil).
+		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.7")).
+		Return(nil, errors.New("no such oid")).
 		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.2")).
 		Return(nil, errors.New("no such oid"))
 
@@ -314,14 +338,59 @@ func TestIosCommunicator_GetCPUComponentCPULoad_prioritiseRevOID(t *testing.T) {
 		{
 			Label: nil,
 			Load:  &load1,
+			Load5: &load1,
 		},
 		{
 			Label: nil,
 			Load:  &load2,
+			Load5: &load2,
 		},
 		{
 			Label: nil,
 			Load:  &load3,
+			Load5: &load3,
+		},
+	}
+
+	res, err := sut.GetCPUComponentCPULoad(ctx)
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected, res)
+	}
+}
+
+//TestIosCommunicator_GetCPUComponentCPULoad_with1minOID: 1 CPU with no label, 1 min load is read out additionally
+func TestIosCommunicator_GetCPUComponentCPULoad_with1minOID(t *testing.T) {
+	var snmpClient network.MockSNMPClient
+	ctx := network.NewContextWithDeviceConnection(context.Background(), &network.RequestDeviceConnection{
+		SNMP: &network.RequestDeviceConnectionSNMP{
+			SnmpClient: &snmpClient,
+		},
+	})
+
+	snmpClient.
+		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.8")).
+		Return([]network.SNMPResponse{
+			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.8.1", gosnmp.Gauge32, uint(10)),
+		}, nil).
+		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.5")).
+		Return(nil, errors.New("no such oid")).
+		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.7")).
+		Return([]network.SNMPResponse{
+			network.NewSNMPResponse(".1.3.6.1.4.1.9.9.109.1.1.1.1.7.1", gosnmp.Gauge32, uint(25)),
+		}, nil).
+		On("SNMPWalk", ctx, network.OID("1.3.6.1.4.1.9.9.109.1.1.1.1.2")).
+		Return(nil, errors.New("no such oid"))
+
+	sut := iosCommunicator{codeCommunicator{}}
+
+	load := 10.0
+	load1 := 25.0
+	expected := []device.CPU{
+		{
+			Label: nil,
+			Load:  &load,
+			Load1: &load1,
+			Load5: &load,
 		},
 	}
 
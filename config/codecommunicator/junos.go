@@ -276,10 +276,12 @@ func (c *junosCommunicator) GetCPUComponentCPULoad(ctx context.Context) ([]devic
 			return nil, errors.Wrap(err, "failed to parse cpu load")
 		}
 
-		cpus = append(cpus, device.CPU{
+		cpu := device.CPU{
 			Label: &indices[i].label,
 			Load:  &load,
-		})
+		}
+		c.addCPULoadAverages(ctx, &cpu, index.index)
+		cpus = append(cpus, cpu)
 	}
 
 	spuCpus, err := c.getSPUCPUs(ctx)
@@ -292,6 +294,47 @@ func (c *junosCommunicator) GetCPUComponentCPULoad(ctx context.Context) ([]devic
 	return cpus, nil
 }
 
+// addCPULoadAverages adds the 1, 5 and 15 minute cpu load averages of the routing engine with the given index to the cpu.
+// Older devices do not support the load averages, so errors are only logged.
+func (c *junosCommunicator) addCPULoadAverages(ctx context.Context, cpu *device.CPU, index string) {
+	con, ok := network.DeviceConnectionFromContext(ctx)
+	if !ok || con.SNMP == nil {
+		return
+	}
+
+	jnxOperating1MinLoadAvgOID := network.OID(".1.3.6.1.4.1.2636.3.1.13.1.20").AddIndex(index)
+	jnxOperating5MinLoadAvgOID := network.OID(".1.3.6.1.4.1.2636.3.1.13.1.21").AddIndex(index)
+	jnxOperating15MinLoadAvgOID := network.OID(".1.3.6.1.4.1.2636.3.1.13.1.22").AddIndex(index)
+	loadAvgs := map[network.OID]**float64{
+		jnxOperating1MinLoadAvgOID:  &cpu.Load1,
+		jnxOperating5MinLoadAvgOID:  &cpu.Load5,
+		jnxOperating15MinLoadAvgOID: &cpu.Load15,
+	}
+
+	response, err := con.SNMP.SnmpClient.SNMPGet(ctx, jnxOperating1MinLoadAvgOID, jnxOperating5MinLoadAvgOID, jnxOperating15MinLoadAvgOID)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to read out cpu load averages")
+		return
+	}
+
+	for _, res := range response {
+		load, ok := loadAvgs[res.GetOID()]
+		if !ok {
+			continue
+		}
+		val, err := res.GetValue()
+		if err != nil {
+			continue
+		}
+		f, err := val.Float64()
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Str("oid", res.GetOID().String()).Msg("failed to parse cpu load average")
+			continue
+		}
+		*load = &f
+	}
+}
+
 type indexAndLabel struct {
 	index string
 	label string
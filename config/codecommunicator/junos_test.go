@@ -28,6 +28,8 @@ func TestJunosCommunicator_GetCPUComponentCPULoad_WithSPU(t *testing.T) {
 		Return([]network.SNMPResponse{
 			network.NewSNMPResponse(".1.3.6.1.4.1.2636.3.1.13.1.8.2.1.0", gosnmp.OctetString, "26"),
 		}, nil).
+		On("SNMPGet", ctx, network.OID(".1.3.6.1.4.1.2636.3.1.13.1.20.2.1.0"), network.OID(".1.3.6.1.4.1.2636.3.1.13.1.21.2.1.0"), network.OID(".1.3.6.1.4.1.2636.3.1.13.1.22.2.1.0")).
+		Return(nil, errors.New("No Such Object available on this agent at this OID")).
 		On("SNMPWalk", ctx, network.OID(".1.3.6.1.4.1.2636.3.39.1.12.1.1.1.11")).
 		Return(nil, errors.New("No Such Object available on this agent at this OID"))
 
@@ -67,6 +69,12 @@ func TestJunosCommunicator_GetCPUComponentCPULoad(t *testing.T) {
 		Return([]network.SNMPResponse{
 			network.NewSNMPResponse(".1.3.6.1.4.1.2636.3.1.13.1.8.2.1.0", gosnmp.OctetString, "26"),
 		}, nil).
+		On("SNMPGet", ctx, network.OID(".1.3.6.1.4.1.2636.3.1.13.1.20.2.1.0"), network.OID(".1.3.6.1.4.1.2636.3.1.13.1.21.2.1.0"), network.OID(".1.3.6.1.4.1.2636.3.1.13.1.22.2.1.0")).
+		Return([]network.SNMPResponse{
+			network.NewSNMPResponse(".1.3.6.1.4.1.2636.3.1.13.1.20.2.1.0", gosnmp.Gauge32, uint(24)),
+			network.NewSNMPResponse(".1.3.6.1.4.1.2636.3.1.13.1.21.2.1.0", gosnmp.Gauge32, uint(21)),
+			network.NewSNMPResponse(".1.3.6.1.4.1.2636.3.1.13.1.22.2.1.0", gosnmp.Gauge32, uint(19)),
+		}, nil).
 		On("SNMPWalk", ctx, network.OID(".1.3.6.1.4.1.2636.3.39.1.12.1.1.1.11")).
 		Return([]network.SNMPResponse{
 			network.NewSNMPResponse(".1.3.6.1.4.1.2636.3.39.1.12.1.1.1.11.0", gosnmp.OctetString, "single"),
@@ -81,13 +89,19 @@ func TestJunosCommunicator_GetCPUComponentCPULoad(t *testing.T) {
 
 	cpuLabel := "Routing Engine 0"
 	cpuUtil := 26.0
+	cpuLoad1 := 24.0
+	cpuLoad5 := 21.0
+	cpuLoad15 := 19.0
 	cpuSPULabel := "spu_single"
 	cpuSPU1Util := 7.0
 
 	expected := []device.CPU{
 		{
-			Label: &cpuLabel,
-			Load:  &cpuUtil,
+			Label:  &cpuLabel,
+			Load:   &cpuUtil,
+			Load1:  &cpuLoad1,
+			Load5:  &cpuLoad5,
+			Load15: &cpuLoad15,
 		},
 		{
 			Label: &cpuSPULabel,
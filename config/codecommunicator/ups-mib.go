@@ -0,0 +1,57 @@
+package codecommunicator
+
+import (
+	"context"
+	"github.com/inexio/thola/internal/network"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+type upsMIBCommunicator struct {
+	codeCommunicator
+}
+
+// GetUPSComponentBatteryReplacementNeeded returns whether the upsAlarmTable contains the upsAlarmBatteryBad alarm.
+func (c *upsMIBCommunicator) GetUPSComponentBatteryReplacementNeeded(ctx context.Context) (bool, error) {
+	con, ok := network.DeviceConnectionFromContext(ctx)
+	if !ok || con.SNMP == nil {
+		return false, errors.New("no device connection available")
+	}
+
+	// upsAlarmsPresent
+	response, err := con.SNMP.SnmpClient.SNMPGet(ctx, ".1.3.6.1.2.1.33.1.6.1.0")
+	if err != nil {
+		return false, errors.Wrap(err, "snmpget failed")
+	}
+	if len(response) != 1 {
+		return false, errors.New("no or more than one snmp response available")
+	}
+	val, err := response[0].GetValue()
+	if err != nil {
+		return false, errors.Wrap(err, "couldn't get string value")
+	}
+	alarms, err := val.Int()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to parse snmp response")
+	}
+	if alarms == 0 {
+		return false, nil
+	}
+
+	// upsAlarmDescr
+	descriptions, err := con.SNMP.SnmpClient.SNMPWalk(ctx, ".1.3.6.1.2.1.33.1.6.2.1.2")
+	if err != nil {
+		return false, errors.Wrap(err, "snmpwalk failed")
+	}
+	for _, description := range descriptions {
+		val, err := description.GetValue()
+		if err != nil {
+			return false, errors.Wrap(err, "couldn't get string value")
+		}
+		// upsAlarmBatteryBad
+		if strings.TrimPrefix(val.String(), ".") == "1.3.6.1.2.1.33.1.6.3.1" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -3,19 +3,25 @@ module github.com/inexio/thola
 go 1.16
 
 require (
+	github.com/PaesslerAG/jsonpath v0.1.1
+	github.com/antchfx/xmlquery v1.3.3
+	github.com/antchfx/xpath v1.1.10
 	github.com/dgraph-io/badger/v2 v2.2007.2
 	github.com/go-resty/resty/v2 v2.3.0
 	github.com/go-sql-driver/mysql v1.5.0
 	github.com/gocarina/gocsv v0.0.0-20210516172204-ca9e8a8ddea8
 	github.com/gomodule/redigo v1.8.4
-	github.com/google/go-cmp v0.5.4
+	github.com/google/go-cmp v0.5.5
 	github.com/gosnmp/gosnmp v1.30.0
 	github.com/inexio/go-monitoringplugin v1.0.10
 	github.com/jmoiron/sqlx v1.2.0
 	github.com/labstack/echo/v4 v4.2.1
+	github.com/lib/pq v1.0.0
 	github.com/mitchellh/colorstring v0.0.0-20190213212951-d06e56a500db
 	github.com/mitchellh/mapstructure v1.3.3
+	github.com/openconfig/gnmi v0.0.0-20210226144353-8eae1937bf84
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.11.0
 	github.com/rs/xid v1.2.1
 	github.com/rs/zerolog v1.20.0
 	github.com/schollz/progressbar/v3 v3.5.1
@@ -25,6 +31,9 @@ require (
 	github.com/spf13/viper v1.7.1
 	github.com/stretchr/testify v1.7.0
 	github.com/ulule/limiter/v3 v3.5.0
-	golang.org/x/text v0.3.3
+	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2
+	golang.org/x/text v0.3.4
+	google.golang.org/grpc v1.36.0
 	gopkg.in/yaml.v2 v2.3.0
+	modernc.org/sqlite v1.17.3
 )
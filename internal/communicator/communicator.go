@@ -95,6 +95,9 @@ type availableDiskCommunicatorFunctions interface {
 
 	// GetDiskComponentStorages returns the storages of the device.
 	GetDiskComponentStorages(ctx context.Context) ([]device.DiskComponentStorage, error)
+
+	// GetDiskComponentInodes returns the inode usage of the file systems of the device.
+	GetDiskComponentInodes(ctx context.Context) ([]device.DiskComponentInodes, error)
 }
 
 type availableUPSCommunicatorFunctions interface {
@@ -131,6 +134,24 @@ type availableUPSCommunicatorFunctions interface {
 
 	// GetUPSComponentSystemVoltage returns the system voltage of the ups device.
 	GetUPSComponentSystemVoltage(ctx context.Context) (float64, error)
+
+	// GetUPSComponentOutputFrequency returns the output frequency of the ups device.
+	GetUPSComponentOutputFrequency(ctx context.Context) (float64, error)
+
+	// GetUPSComponentOutputSource returns the source of the output power of the ups device.
+	GetUPSComponentOutputSource(ctx context.Context) (string, error)
+
+	// GetUPSComponentBatteryStatus returns the battery status of the ups device.
+	GetUPSComponentBatteryStatus(ctx context.Context) (string, error)
+
+	// GetUPSComponentBatteryReplacementNeeded returns if the battery of the ups device needs to be replaced.
+	GetUPSComponentBatteryReplacementNeeded(ctx context.Context) (bool, error)
+
+	// GetUPSComponentInputs returns the input lines of the ups device.
+	GetUPSComponentInputs(ctx context.Context) ([]device.UPSComponentInput, error)
+
+	// GetUPSComponentOutputs returns the output lines of the ups device.
+	GetUPSComponentOutputs(ctx context.Context) ([]device.UPSComponentOutput, error)
 }
 
 type availableServerCommunicatorFunctions interface {
@@ -140,6 +161,27 @@ type availableServerCommunicatorFunctions interface {
 
 	// GetServerComponentUsers returns the user count of the device.
 	GetServerComponentUsers(ctx context.Context) (int, error)
+
+	// GetServerComponentLoad1 returns the 1 minute load average of the device.
+	GetServerComponentLoad1(ctx context.Context) (float64, error)
+
+	// GetServerComponentLoad5 returns the 5 minute load average of the device.
+	GetServerComponentLoad5(ctx context.Context) (float64, error)
+
+	// GetServerComponentLoad15 returns the 15 minute load average of the device.
+	GetServerComponentLoad15(ctx context.Context) (float64, error)
+
+	// GetServerComponentSwapTotal returns the total swap space of the device in bytes.
+	GetServerComponentSwapTotal(ctx context.Context) (uint64, error)
+
+	// GetServerComponentSwapFree returns the free swap space of the device in bytes.
+	GetServerComponentSwapFree(ctx context.Context) (uint64, error)
+
+	// GetServerComponentUptime returns the uptime of the device in seconds.
+	GetServerComponentUptime(ctx context.Context) (uint64, error)
+
+	// GetServerComponentProcesses returns the running processes of the device grouped by name.
+	GetServerComponentProcesses(ctx context.Context) ([]device.ServerComponentProcess, error)
 }
 
 type availableSBCCommunicatorFunctions interface {
@@ -121,6 +121,16 @@ func (c *networkDeviceCommunicator) GetDiskComponent(ctx context.Context) (devic
 		empty = false
 	}
 
+	inodes, err := c.GetDiskComponentInodes(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.DiskComponent{}, errors.Wrap(err, "error occurred during get disk component inodes")
+		}
+	} else {
+		disk.Inodes = inodes
+		empty = false
+	}
+
 	if empty {
 		return device.DiskComponent{}, tholaerr.NewNotFoundError("no disk data available")
 	}
@@ -246,6 +256,66 @@ func (c *networkDeviceCommunicator) GetUPSComponent(ctx context.Context) (device
 		empty = false
 	}
 
+	outputFrequency, err := c.GetUPSComponentOutputFrequency(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.UPSComponent{}, errors.Wrap(err, "error occurred during get output frequency")
+		}
+	} else {
+		ups.OutputFrequency = &outputFrequency
+		empty = false
+	}
+
+	outputSource, err := c.GetUPSComponentOutputSource(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.UPSComponent{}, errors.Wrap(err, "error occurred during get output source")
+		}
+	} else {
+		ups.OutputSource = &outputSource
+		empty = false
+	}
+
+	batteryStatus, err := c.GetUPSComponentBatteryStatus(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.UPSComponent{}, errors.Wrap(err, "error occurred during get battery status")
+		}
+	} else {
+		ups.BatteryStatus = &batteryStatus
+		empty = false
+	}
+
+	batteryReplacementNeeded, err := c.GetUPSComponentBatteryReplacementNeeded(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.UPSComponent{}, errors.Wrap(err, "error occurred during get battery replacement needed")
+		}
+	} else {
+		ups.BatteryReplacementNeeded = &batteryReplacementNeeded
+		empty = false
+	}
+
+	inputs, err := c.GetUPSComponentInputs(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.UPSComponent{}, errors.Wrap(err, "error occurred during get inputs")
+		}
+	} else {
+		ups.Inputs = inputs
+		empty = false
+	}
+
+	outputs, err := c.GetUPSComponentOutputs(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.UPSComponent{}, errors.Wrap(err, "error occurred during get outputs")
+		}
+	} else {
+		ups.Outputs = outputs
+		empty = false
+	}
+
 	if empty {
 		return device.UPSComponent{}, tholaerr.NewNotFoundError("no ups data available")
 	}
@@ -281,6 +351,76 @@ func (c *networkDeviceCommunicator) GetServerComponent(ctx context.Context) (dev
 		empty = false
 	}
 
+	load1, err := c.GetServerComponentLoad1(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component load 1")
+		}
+	} else {
+		server.Load1 = &load1
+		empty = false
+	}
+
+	load5, err := c.GetServerComponentLoad5(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component load 5")
+		}
+	} else {
+		server.Load5 = &load5
+		empty = false
+	}
+
+	load15, err := c.GetServerComponentLoad15(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component load 15")
+		}
+	} else {
+		server.Load15 = &load15
+		empty = false
+	}
+
+	swapTotal, err := c.GetServerComponentSwapTotal(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component swap total")
+		}
+	} else {
+		server.SwapTotal = &swapTotal
+		empty = false
+	}
+
+	swapFree, err := c.GetServerComponentSwapFree(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component swap free")
+		}
+	} else {
+		server.SwapFree = &swapFree
+		empty = false
+	}
+
+	uptime, err := c.GetServerComponentUptime(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component uptime")
+		}
+	} else {
+		server.Uptime = &uptime
+		empty = false
+	}
+
+	processes, err := c.GetServerComponentProcesses(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component processes")
+		}
+	} else {
+		server.Processes = processes
+		empty = false
+	}
+
 	if empty {
 		return device.ServerComponent{}, tholaerr.NewNotFoundError("no server data available")
 	}
@@ -640,6 +780,25 @@ func (c *networkDeviceCommunicator) GetDiskComponentStorages(ctx context.Context
 	return c.deviceClassCommunicator.GetDiskComponentStorages(ctx)
 }
 
+func (c *networkDeviceCommunicator) GetDiskComponentInodes(ctx context.Context) ([]device.DiskComponentInodes, error) {
+	if !c.HasComponent(component.Disk) {
+		return nil, tholaerr.NewComponentNotFoundError("no disk component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetDiskComponentInodes(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return nil, errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetDiskComponentInodes(ctx)
+}
+
 func (c *networkDeviceCommunicator) GetUPSComponentAlarmLowVoltageDisconnect(ctx context.Context) (int, error) {
 	if !c.HasComponent(component.UPS) {
 		return 0, tholaerr.NewComponentNotFoundError("no ups component available for this device")
@@ -849,6 +1008,120 @@ func (c *networkDeviceCommunicator) GetUPSComponentSystemVoltage(ctx context.Con
 	return c.deviceClassCommunicator.GetUPSComponentSystemVoltage(ctx)
 }
 
+func (c *networkDeviceCommunicator) GetUPSComponentOutputFrequency(ctx context.Context) (float64, error) {
+	if !c.HasComponent(component.UPS) {
+		return 0, tholaerr.NewComponentNotFoundError("no ups component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetUPSComponentOutputFrequency(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return 0, errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetUPSComponentOutputFrequency(ctx)
+}
+
+func (c *networkDeviceCommunicator) GetUPSComponentOutputSource(ctx context.Context) (string, error) {
+	if !c.HasComponent(component.UPS) {
+		return "", tholaerr.NewComponentNotFoundError("no ups component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetUPSComponentOutputSource(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return "", errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetUPSComponentOutputSource(ctx)
+}
+
+func (c *networkDeviceCommunicator) GetUPSComponentBatteryStatus(ctx context.Context) (string, error) {
+	if !c.HasComponent(component.UPS) {
+		return "", tholaerr.NewComponentNotFoundError("no ups component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetUPSComponentBatteryStatus(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return "", errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetUPSComponentBatteryStatus(ctx)
+}
+
+func (c *networkDeviceCommunicator) GetUPSComponentBatteryReplacementNeeded(ctx context.Context) (bool, error) {
+	if !c.HasComponent(component.UPS) {
+		return false, tholaerr.NewComponentNotFoundError("no ups component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetUPSComponentBatteryReplacementNeeded(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return false, errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetUPSComponentBatteryReplacementNeeded(ctx)
+}
+
+func (c *networkDeviceCommunicator) GetUPSComponentInputs(ctx context.Context) ([]device.UPSComponentInput, error) {
+	if !c.HasComponent(component.UPS) {
+		return nil, tholaerr.NewComponentNotFoundError("no ups component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetUPSComponentInputs(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return nil, errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetUPSComponentInputs(ctx)
+}
+
+func (c *networkDeviceCommunicator) GetUPSComponentOutputs(ctx context.Context) ([]device.UPSComponentOutput, error) {
+	if !c.HasComponent(component.UPS) {
+		return nil, tholaerr.NewComponentNotFoundError("no ups component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetUPSComponentOutputs(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return nil, errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetUPSComponentOutputs(ctx)
+}
+
 func (c *networkDeviceCommunicator) GetSBCComponentAgents(ctx context.Context) ([]device.SBCComponentAgent, error) {
 	if !c.HasComponent(component.SBC) {
 		return nil, tholaerr.NewComponentNotFoundError("no sbc component available for this device")
@@ -1058,6 +1331,139 @@ func (c *networkDeviceCommunicator) GetServerComponentUsers(ctx context.Context)
 	return c.deviceClassCommunicator.GetServerComponentUsers(ctx)
 }
 
+func (c *networkDeviceCommunicator) GetServerComponentLoad1(ctx context.Context) (float64, error) {
+	if !c.HasComponent(component.Server) {
+		return 0, tholaerr.NewComponentNotFoundError("no server component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetServerComponentLoad1(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return 0, errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetServerComponentLoad1(ctx)
+}
+
+func (c *networkDeviceCommunicator) GetServerComponentLoad5(ctx context.Context) (float64, error) {
+	if !c.HasComponent(component.Server) {
+		return 0, tholaerr.NewComponentNotFoundError("no server component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetServerComponentLoad5(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return 0, errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetServerComponentLoad5(ctx)
+}
+
+func (c *networkDeviceCommunicator) GetServerComponentLoad15(ctx context.Context) (float64, error) {
+	if !c.HasComponent(component.Server) {
+		return 0, tholaerr.NewComponentNotFoundError("no server component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetServerComponentLoad15(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return 0, errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetServerComponentLoad15(ctx)
+}
+
+func (c *networkDeviceCommunicator) GetServerComponentSwapTotal(ctx context.Context) (uint64, error) {
+	if !c.HasComponent(component.Server) {
+		return 0, tholaerr.NewComponentNotFoundError("no server component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetServerComponentSwapTotal(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return 0, errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetServerComponentSwapTotal(ctx)
+}
+
+func (c *networkDeviceCommunicator) GetServerComponentSwapFree(ctx context.Context) (uint64, error) {
+	if !c.HasComponent(component.Server) {
+		return 0, tholaerr.NewComponentNotFoundError("no server component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetServerComponentSwapFree(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return 0, errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetServerComponentSwapFree(ctx)
+}
+
+func (c *networkDeviceCommunicator) GetServerComponentUptime(ctx context.Context) (uint64, error) {
+	if !c.HasComponent(component.Server) {
+		return 0, tholaerr.NewComponentNotFoundError("no server component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetServerComponentUptime(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return 0, errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetServerComponentUptime(ctx)
+}
+
+func (c *networkDeviceCommunicator) GetServerComponentProcesses(ctx context.Context) ([]device.ServerComponentProcess, error) {
+	if !c.HasComponent(component.Server) {
+		return nil, tholaerr.NewComponentNotFoundError("no server component available for this device")
+	}
+
+	if c.codeCommunicator != nil {
+		res, err := c.codeCommunicator.GetServerComponentProcesses(ctx)
+		if err != nil {
+			if !tholaerr.IsNotImplementedError(err) {
+				return nil, errors.Wrap(err, "error in code communicator")
+			}
+		} else {
+			return res, nil
+		}
+	}
+
+	return c.deviceClassCommunicator.GetServerComponentProcesses(ctx)
+}
+
 func (c *networkDeviceCommunicator) GetHardwareHealthComponentEnvironmentMonitorState(ctx context.Context) (device.HardwareHealthComponentState, error) {
 	if !c.HasComponent(component.HardwareHealth) {
 		return "", tholaerr.NewComponentNotFoundError("no hardware health component available for this device")
@@ -10,6 +10,8 @@ import (
 	"github.com/inexio/thola/internal/tholaerr"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -113,6 +115,304 @@ func (d *badgerDatabase) GetConnectionData(_ context.Context, ip string) (networ
 	return data, nil
 }
 
+func (d *badgerDatabase) SetDiskUsageHistory(_ context.Context, ip string, data DiskUsageHistory) error {
+	txn := d.db.NewTransaction(true)
+	defer txn.Discard()
+
+	JSONData, err := parser.ToJSON(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshall disk usage history")
+	}
+	entry := badger.Entry{
+		Key:       []byte("DiskUsageHistory-" + ip),
+		Value:     JSONData,
+		ExpiresAt: uint64(time.Now().Add(diskUsageHistoryExpiration).Unix()),
+	}
+
+	err = txn.SetEntry(&entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to store disk usage history")
+	}
+
+	err = txn.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to store disk usage history")
+	}
+	return nil
+}
+
+func (d *badgerDatabase) GetDiskUsageHistory(_ context.Context, ip string) (DiskUsageHistory, error) {
+	txn := d.db.NewTransaction(false)
+	defer txn.Discard()
+
+	item, err := txn.Get([]byte("DiskUsageHistory-" + ip))
+	if err != nil {
+		return nil, tholaerr.NewNotFoundError("cannot find cache entry")
+	}
+
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get value from db item")
+	}
+
+	var data DiskUsageHistory
+	err = json.Unmarshal(value, &data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshall disk usage history")
+	}
+	return data, nil
+}
+
+func (d *badgerDatabase) SetSNMPv3EngineData(_ context.Context, ip string, data network.SNMPv3EngineData) error {
+	txn := d.db.NewTransaction(true)
+	defer txn.Discard()
+
+	JSONData, err := parser.ToJSON(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshall snmp v3 engine data")
+	}
+	entry := badger.Entry{
+		Key:       []byte("SNMPv3EngineData-" + ip),
+		Value:     JSONData,
+		ExpiresAt: uint64(time.Now().Add(cacheExpiration).Unix()),
+	}
+
+	err = txn.SetEntry(&entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to store snmp v3 engine data")
+	}
+
+	err = txn.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to store snmp v3 engine data")
+	}
+	return nil
+}
+
+func (d *badgerDatabase) GetSNMPv3EngineData(_ context.Context, ip string) (network.SNMPv3EngineData, error) {
+	txn := d.db.NewTransaction(false)
+	defer txn.Discard()
+
+	item, err := txn.Get([]byte("SNMPv3EngineData-" + ip))
+	if err != nil {
+		return network.SNMPv3EngineData{}, tholaerr.NewNotFoundError("cannot find cache entry")
+	}
+
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return network.SNMPv3EngineData{}, errors.Wrap(err, "failed to get value from db item")
+	}
+
+	var data network.SNMPv3EngineData
+	err = json.Unmarshal(value, &data)
+	if err != nil {
+		return network.SNMPv3EngineData{}, errors.Wrap(err, "failed to unmarshall snmp v3 engine data")
+	}
+	return data, nil
+}
+
+func (d *badgerDatabase) SetDeviceClass(_ context.Context, ip string, data DeviceClassData) error {
+	txn := d.db.NewTransaction(true)
+	defer txn.Discard()
+
+	JSONData, err := parser.ToJSON(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshall device class data")
+	}
+	entry := badger.Entry{
+		Key:       []byte(EntryDeviceClass + "-" + ip),
+		Value:     JSONData,
+		ExpiresAt: uint64(time.Now().Add(deviceClassExpiration).Unix()),
+	}
+
+	err = txn.SetEntry(&entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to store device class data")
+	}
+
+	err = txn.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to store device class data")
+	}
+	return nil
+}
+
+func (d *badgerDatabase) GetDeviceClass(_ context.Context, ip string) (DeviceClassData, error) {
+	txn := d.db.NewTransaction(false)
+	defer txn.Discard()
+
+	item, err := txn.Get([]byte(EntryDeviceClass + "-" + ip))
+	if err != nil {
+		return DeviceClassData{}, tholaerr.NewNotFoundError("cannot find cache entry")
+	}
+
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return DeviceClassData{}, errors.Wrap(err, "failed to get value from db item")
+	}
+
+	var data DeviceClassData
+	err = json.Unmarshal(value, &data)
+	if err != nil {
+		return DeviceClassData{}, errors.Wrap(err, "failed to unmarshall device class data")
+	}
+	return data, nil
+}
+
+func (d *badgerDatabase) SetJob(_ context.Context, data Job) error {
+	txn := d.db.NewTransaction(true)
+	defer txn.Discard()
+
+	JSONData, err := parser.ToJSON(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshall job")
+	}
+	entry := badger.Entry{
+		Key:       []byte("Job-" + data.ID),
+		Value:     JSONData,
+		ExpiresAt: uint64(data.ExpiresAt.Unix()),
+	}
+
+	err = txn.SetEntry(&entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to store job")
+	}
+
+	err = txn.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to store job")
+	}
+	return nil
+}
+
+func (d *badgerDatabase) GetJob(_ context.Context, id string) (Job, error) {
+	txn := d.db.NewTransaction(false)
+	defer txn.Discard()
+
+	item, err := txn.Get([]byte("Job-" + id))
+	if err != nil {
+		return Job{}, tholaerr.NewNotFoundError("cannot find job")
+	}
+
+	value, err := item.ValueCopy(nil)
+	if err != nil {
+		return Job{}, errors.Wrap(err, "failed to get value from db item")
+	}
+
+	var data Job
+	err = json.Unmarshal(value, &data)
+	if err != nil {
+		return Job{}, errors.Wrap(err, "failed to unmarshall job")
+	}
+	return data, nil
+}
+
+func (d *badgerDatabase) RequestJobCancel(_ context.Context, id string, expiresAt time.Time) error {
+	txn := d.db.NewTransaction(true)
+	defer txn.Discard()
+
+	entry := badger.Entry{
+		Key:       []byte("JobCancel-" + id),
+		Value:     []byte("true"),
+		ExpiresAt: uint64(expiresAt.Unix()),
+	}
+
+	err := txn.SetEntry(&entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to store job cancel request")
+	}
+
+	err = txn.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to store job cancel request")
+	}
+	return nil
+}
+
+func (d *badgerDatabase) IsJobCancelRequested(_ context.Context, id string) (bool, error) {
+	txn := d.db.NewTransaction(false)
+	defer txn.Discard()
+
+	_, err := txn.Get([]byte("JobCancel-" + id))
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to get job cancel request")
+	}
+	return true, nil
+}
+
+func (d *badgerDatabase) ListDevices(_ context.Context) ([]string, error) {
+	txn := d.db.NewTransaction(false)
+	defer txn.Discard()
+
+	options := badger.DefaultIteratorOptions
+	options.PrefetchValues = false
+	it := txn.NewIterator(options)
+	defer it.Close()
+
+	found := make(map[string]struct{})
+	var ips []string
+	for it.Rewind(); it.Valid(); it.Next() {
+		key := strings.SplitN(string(it.Item().Key()), "-", 2)
+		if len(key) != 2 || !isDeviceEntryType(key[0]) {
+			continue
+		}
+		if _, ok := found[key[1]]; !ok {
+			found[key[1]] = struct{}{}
+			ips = append(ips, key[1])
+		}
+	}
+	sort.Strings(ips)
+	return ips, nil
+}
+
+func (d *badgerDatabase) GetEntries(_ context.Context, ip string) ([]Entry, error) {
+	txn := d.db.NewTransaction(false)
+	defer txn.Discard()
+
+	var entries []Entry
+	for _, entryType := range deviceEntryTypes {
+		item, err := txn.Get([]byte(entryType + "-" + ip))
+		if err != nil {
+			if err == badger.ErrKeyNotFound {
+				continue
+			}
+			return nil, errors.Wrap(err, "failed to get cache entry")
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get value from db item")
+		}
+		entries = append(entries, Entry{
+			IP:        ip,
+			Type:      entryType,
+			ExpiresAt: time.Unix(int64(item.ExpiresAt()), 0),
+			Data:      value,
+		})
+	}
+	return entries, nil
+}
+
+func (d *badgerDatabase) DeleteEntries(_ context.Context, ip string) error {
+	txn := d.db.NewTransaction(true)
+	defer txn.Discard()
+
+	for _, entryType := range deviceEntryTypes {
+		err := txn.Delete([]byte(entryType + "-" + ip))
+		if err != nil {
+			return errors.Wrap(err, "failed to delete cache entry")
+		}
+	}
+
+	err := txn.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to delete cache entries")
+	}
+	return nil
+}
+
 func (d *badgerDatabase) CheckConnection(_ context.Context) error {
 	if d.db.IsClosed() {
 		return errors.New("badger db is closed")
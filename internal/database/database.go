@@ -7,10 +7,11 @@ import (
 	"github.com/gomodule/redigo/redis"
 	"github.com/inexio/thola/internal/device"
 	"github.com/inexio/thola/internal/network"
-	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq" //needed for sql driver
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
+	_ "modernc.org/sqlite" //needed for sql driver
 	"os"
 	"os/user"
 	"path/filepath"
@@ -27,12 +28,63 @@ var db struct {
 
 var cacheExpiration time.Duration
 
+// deviceClassExpiration is the duration the identified device class of a device is trusted.
+var deviceClassExpiration time.Duration
+
+// diskUsageHistoryExpiration is the duration disk usage samples are kept.
+// It is independent of the cache expiration, otherwise no history could be built up.
+const diskUsageHistoryExpiration = 30 * 24 * time.Hour
+
+// DiskUsageSample is a sample of the used space of a storage at a specific time.
+type DiskUsageSample struct {
+	Time time.Time `json:"time"`
+	Used float64   `json:"used"`
+}
+
+// DiskUsageHistory maps storage descriptions to their usage samples.
+type DiskUsageHistory map[string][]DiskUsageSample
+
+// Job is the state of an asynchronously processed api request.
+type Job struct {
+	ID       string `json:"id"`
+	Route    string `json:"route"`
+	Status   string `json:"status"`
+	Progress string `json:"progress"`
+	// Token is the name of the api token that started the job.
+	Token     string    `json:"token,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// StatusCode and Result are the http status code and the body of the response in the api format.
+	StatusCode int    `json:"status_code,omitempty"`
+	Result     string `json:"result,omitempty"`
+}
+
 // Database represents a database.
 type Database interface {
 	SetDeviceProperties(ctx context.Context, ip string, data device.Device) error
 	GetDeviceProperties(ctx context.Context, ip string) (device.Device, error)
 	SetConnectionData(ctx context.Context, ip string, data network.ConnectionData) error
 	GetConnectionData(ctx context.Context, ip string) (network.ConnectionData, error)
+	SetDiskUsageHistory(ctx context.Context, ip string, data DiskUsageHistory) error
+	GetDiskUsageHistory(ctx context.Context, ip string) (DiskUsageHistory, error)
+	SetSNMPv3EngineData(ctx context.Context, ip string, data network.SNMPv3EngineData) error
+	GetSNMPv3EngineData(ctx context.Context, ip string) (network.SNMPv3EngineData, error)
+	SetDeviceClass(ctx context.Context, ip string, data DeviceClassData) error
+	GetDeviceClass(ctx context.Context, ip string) (DeviceClassData, error)
+	// ListDevices returns the ips of all devices that have cache entries.
+	ListDevices(ctx context.Context) ([]string, error)
+	// GetEntries returns all cache entries of the device.
+	GetEntries(ctx context.Context, ip string) ([]Entry, error)
+	// DeleteEntries deletes all cache entries of the device.
+	DeleteEntries(ctx context.Context, ip string) error
+	SetJob(ctx context.Context, data Job) error
+	GetJob(ctx context.Context, id string) (Job, error)
+	// RequestJobCancel stores that the job should be cancelled by the api instance that processes it.
+	// The request is stored apart from the job, so that updates of the job do not overwrite it.
+	RequestJobCancel(ctx context.Context, id string, expiresAt time.Time) error
+	// IsJobCancelRequested returns whether the cancellation of the job was requested.
+	IsJobCancelRequested(ctx context.Context, id string) (bool, error)
 	CheckConnection(ctx context.Context) error
 	CloseConnection(ctx context.Context) error
 }
@@ -44,10 +96,9 @@ func initDB(ctx context.Context) error {
 		return nil
 	}
 
-	var err error
-	cacheExpiration, err = time.ParseDuration(viper.GetString("db.duration"))
+	err := parseExpirations()
 	if err != nil {
-		return errors.Wrap(err, "failed to parse cache expiration")
+		return err
 	}
 
 	db.ignoreFailure = viper.GetBool("db.ignore-db-failure")
@@ -71,43 +122,31 @@ func initDB(ctx context.Context) error {
 			}
 		}
 		db.Database = &badgerDB
-	} else if drivername == "mysql" {
-		checkIfTableExistsQuery := "SHOW TABLES LIKE 'cache';"
-		sqlDB := sqlDatabase{}
-		if viper.GetString("db.sql.datasourcename") != "" {
-			sqlDB.db, err = sqlx.ConnectContext(ctx, viper.GetString("db.drivername"), viper.GetString("db.sql.datasourcename"))
+	} else if dialect, ok := sqlDialects[drivername]; ok {
+		dataSourceName := viper.GetString("db.sql.datasourcename")
+		if dataSourceName == "" && drivername == "sqlite" {
+			u, err := user.Current()
 			if err != nil {
-				return err
+				return errors.Wrap(err, "failed to get username")
 			}
-		} else {
+			dataSourceName = filepath.Join(os.TempDir(), "thola-"+u.Username+"-cache.sqlite")
+		}
+		if dataSourceName == "" {
 			return errors.New("no datasourcename set")
 		}
-
-		tableNotExist := true
-		rows, err := sqlDB.db.Query(checkIfTableExistsQuery)
-		if rows != nil {
-			tableNotExist = !rows.Next()
-			err := rows.Close()
-			if err != nil {
-				return errors.Wrap(err, "failed to close sql rows")
-			}
+		sqlDB := sqlDatabase{dialect: dialect}
+		sqlDB.db, err = openSQL(ctx, dialect, dataSourceName)
+		if err != nil {
+			return err
 		}
-		if err != nil || tableNotExist || viper.GetBool("db.rebuild") { //!rows.Next() == table does not exist
-			err = sqlDB.setupDatabase()
-			if err != nil {
-				return errors.Wrap(err, "error while setting up database")
-			}
+		err = sqlDB.migrate(ctx, viper.GetBool("db.rebuild"))
+		if err != nil {
+			return errors.Wrap(err, "error while setting up database")
 		}
 		db.Database = &sqlDB
 	} else if drivername == "redis" {
 		redisDB := redisDatabase{
-			pool: redis.Pool{
-				Dial: func() (redis.Conn, error) {
-					return redis.Dial("tcp", viper.GetString("db.redis.addr"),
-						redis.DialPassword(viper.GetString("db.redis.password")),
-						redis.DialDatabase(viper.GetInt("db.redis.db")))
-				},
-			},
+			pool: NewRedisPool(),
 		}
 		err := redisDB.CheckConnection(ctx)
 		if err != nil {
@@ -129,13 +168,72 @@ func initDB(ctx context.Context) error {
 			}
 		}
 		db.Database = &redisDB
+	} else if drivername == "etcd" {
+		etcdDB := newEtcdDatabase(viper.GetStringSlice("db.etcd.endpoints"), viper.GetString("db.etcd.username"), viper.GetString("db.etcd.password"))
+		err := etcdDB.CheckConnection(ctx)
+		if err != nil {
+			return errors.Wrap(err, "failed to connect to etcd")
+		}
+		if viper.GetBool("db.rebuild") {
+			err = etcdDB.call(ctx, "/v3/kv/deleterange", map[string]interface{}{
+				"key":       []byte(etcdKeyPrefix),
+				"range_end": etcdPrefixEnd(etcdKeyPrefix),
+			}, nil)
+			if err != nil {
+				return errors.Wrap(err, "failed to rebuild the db")
+			}
+		}
+		db.Database = etcdDB
 	} else {
-		return errors.New("invalid drivername, only 'built-in', 'mysql' and 'redis' supported")
+		return errors.New("invalid drivername, only 'built-in', 'mysql', 'postgres', 'sqlite', 'redis' and 'etcd' supported")
 	}
+	db.Database = &statisticsDatabase{Database: db.Database}
 	log.Ctx(ctx).Debug().Msg("initialized " + drivername + " database")
 	return nil
 }
 
+// parseExpirations reads the durations the cache entries are kept from the config.
+// The device class is only validated while the device properties are cached,
+// so a longer device class ttl than the cache duration would have no effect.
+func parseExpirations() error {
+	var err error
+	cacheExpiration, err = time.ParseDuration(viper.GetString("db.duration"))
+	if err != nil {
+		return errors.Wrap(err, "failed to parse cache expiration")
+	}
+	deviceClassExpiration = cacheExpiration
+	if ttl := viper.GetString("db.device-class-ttl"); ttl != "" {
+		deviceClassExpiration, err = time.ParseDuration(ttl)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse device class ttl")
+		}
+	}
+	if deviceClassExpiration > cacheExpiration {
+		return errors.New("device class ttl must not be longer than the db duration")
+	}
+	return nil
+}
+
+// The pool of an api instance is used by all of its requests and device locks,
+// so idle connections are kept, but closed if they are not used for a while.
+const (
+	redisMaxIdle     = 16
+	redisIdleTimeout = 4 * time.Minute
+)
+
+// NewRedisPool returns a new pool of connections to the configured redis database.
+func NewRedisPool() *redis.Pool {
+	return &redis.Pool{
+		MaxIdle:     redisMaxIdle,
+		IdleTimeout: redisIdleTimeout,
+		Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", viper.GetString("db.redis.addr"),
+				redis.DialPassword(viper.GetString("db.redis.password")),
+				redis.DialDatabase(viper.GetInt("db.redis.db")))
+		},
+	}
+}
+
 // GetDB returns the current DB.
 func GetDB(ctx context.Context) (Database, error) {
 	var err error
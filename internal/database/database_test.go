@@ -0,0 +1,210 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/dgraph-io/badger/v2"
+	"github.com/gomodule/redigo/redis"
+	"github.com/inexio/thola/internal/device"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The conformance tests run against all backends that are available. Backends that need a server
+// are only tested if the environment variables THOLA_TEST_MYSQL_DSN, THOLA_TEST_POSTGRES_DSN,
+// THOLA_TEST_REDIS_ADDR or THOLA_TEST_ETCD_ENDPOINTS are set.
+
+func TestDatabase_badger(t *testing.T) {
+	badgerDB, err := badger.Open(badger.DefaultOptions(t.TempDir()).WithLogger(nil))
+	require.NoError(t, err)
+	testDatabaseConformance(t, &badgerDatabase{db: badgerDB})
+}
+
+func TestDatabase_sqlite(t *testing.T) {
+	testSQLDatabaseConformance(t, "sqlite", filepath.Join(t.TempDir(), "cache.sqlite"))
+}
+
+func TestDatabase_mysql(t *testing.T) {
+	testSQLDatabaseConformance(t, "mysql", getTestEnv(t, "THOLA_TEST_MYSQL_DSN"))
+}
+
+func TestDatabase_postgres(t *testing.T) {
+	testSQLDatabaseConformance(t, "postgres", getTestEnv(t, "THOLA_TEST_POSTGRES_DSN"))
+}
+
+func TestDatabase_redis(t *testing.T) {
+	addr := getTestEnv(t, "THOLA_TEST_REDIS_ADDR")
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		return redis.Dial("tcp", addr)
+	}}
+	testDatabaseConformance(t, &redisDatabase{pool: pool})
+}
+
+func TestDatabase_etcd(t *testing.T) {
+	_, server := newFakeEtcd()
+	defer server.Close()
+	testDatabaseConformance(t, newEtcdDatabase([]string{server.URL}, "", ""))
+}
+
+func TestDatabase_etcdServer(t *testing.T) {
+	endpoints := getTestEnv(t, "THOLA_TEST_ETCD_ENDPOINTS")
+	testDatabaseConformance(t, newEtcdDatabase(strings.Split(endpoints, ","), "", ""))
+}
+
+func getTestEnv(t *testing.T, key string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		t.Skip(key + " is not set")
+	}
+	return value
+}
+
+func testSQLDatabaseConformance(t *testing.T, drivername, dataSourceName string) {
+	ctx := context.Background()
+	dialect := sqlDialects[drivername]
+	sqlDB, err := openSQL(ctx, dialect, dataSourceName)
+	require.NoError(t, err)
+	d := &sqlDatabase{db: sqlDB, dialect: dialect}
+	require.NoError(t, d.migrate(ctx, true))
+
+	// migrations are only applied once
+	require.NoError(t, d.migrate(ctx, false))
+	var versions []int
+	require.NoError(t, sqlDB.Select(&versions, "SELECT version FROM schema_migrations ORDER BY version;"))
+	assert.Len(t, versions, len(dialect.migrations))
+
+	testDatabaseConformance(t, d)
+}
+
+// testDatabaseConformance checks the behavior every database backend needs to have.
+func testDatabaseConformance(t *testing.T, d Database) {
+	ctx := context.Background()
+	defer func(cache, deviceClass time.Duration) {
+		cacheExpiration, deviceClassExpiration = cache, deviceClass
+	}(cacheExpiration, deviceClassExpiration)
+	cacheExpiration, deviceClassExpiration = time.Hour, time.Hour
+	ip := "192.0.2.1"
+	start := time.Now()
+
+	require.NoError(t, d.CheckConnection(ctx))
+	require.NoError(t, d.DeleteEntries(ctx, ip))
+
+	// entries expire after the cache expiration, the sql database checks it when reading
+	cacheExpiration = time.Second
+	require.NoError(t, d.SetSNMPv3EngineData(ctx, "192.0.2.2", network.SNMPv3EngineData{EngineBoots: 1}))
+	cacheExpiration = time.Hour
+
+	_, err := d.GetDeviceProperties(ctx, ip)
+	assert.True(t, tholaerr.IsNotFoundError(err), "missing device properties: %v", err)
+	_, err = d.GetDeviceClass(ctx, ip)
+	assert.True(t, tholaerr.IsNotFoundError(err), "missing device class: %v", err)
+	_, err = d.GetJob(ctx, "unknown")
+	assert.True(t, tholaerr.IsNotFoundError(err), "missing job: %v", err)
+
+	properties := device.Device{Class: "generic"}
+	require.NoError(t, d.SetDeviceProperties(ctx, ip, properties))
+	properties.Class = "ios"
+	require.NoError(t, d.SetDeviceProperties(ctx, ip, properties))
+	resProperties, err := d.GetDeviceProperties(ctx, ip)
+	require.NoError(t, err)
+	assert.Equal(t, properties, resProperties, "device properties are overwritten")
+
+	community := "public"
+	connectionData := network.ConnectionData{SNMP: &network.SNMPConnectionData{Communities: []string{community}}}
+	require.NoError(t, d.SetConnectionData(ctx, ip, connectionData))
+	resConnectionData, err := d.GetConnectionData(ctx, ip)
+	require.NoError(t, err)
+	assert.Equal(t, connectionData, resConnectionData)
+
+	history := DiskUsageHistory{"/": {{Time: time.Unix(1600000000, 0).UTC(), Used: 42}}}
+	require.NoError(t, d.SetDiskUsageHistory(ctx, ip, history))
+	resHistory, err := d.GetDiskUsageHistory(ctx, ip)
+	require.NoError(t, err)
+	assert.Equal(t, history, resHistory)
+
+	classData := DeviceClassData{Class: "ios", SysObjectID: ".1.3.6.1.4.1.9.1.222", BootTime: time.Unix(1600000000, 0).UTC()}
+	require.NoError(t, d.SetDeviceClass(ctx, ip, classData))
+	resClassData, err := d.GetDeviceClass(ctx, ip)
+	require.NoError(t, err)
+	assert.Equal(t, classData, resClassData)
+
+	job := Job{ID: "job", Status: "done", ExpiresAt: time.Now().Add(time.Hour).Truncate(time.Second).UTC()}
+	require.NoError(t, d.SetJob(ctx, job))
+	resJob, err := d.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, job, resJob)
+
+	requested, err := d.IsJobCancelRequested(ctx, job.ID)
+	require.NoError(t, err)
+	assert.False(t, requested)
+	require.NoError(t, d.RequestJobCancel(ctx, job.ID, job.ExpiresAt))
+	require.NoError(t, d.SetJob(ctx, job))
+	requested, err = d.IsJobCancelRequested(ctx, job.ID)
+	require.NoError(t, err)
+	assert.True(t, requested, "updates of the job keep the cancel request")
+
+	ips, err := d.ListDevices(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, ips, ip)
+	assert.NotContains(t, ips, job.ID, "jobs are no devices")
+
+	entries, err := d.GetEntries(ctx, ip)
+	require.NoError(t, err)
+	var types []string
+	for _, entry := range entries {
+		types = append(types, entry.Type)
+		assert.Equal(t, ip, entry.IP)
+		assert.True(t, json.Valid(entry.Data), "data of %s is json", entry.Type)
+		expiration := time.Hour
+		if entry.Type == EntryDiskUsageHistory {
+			expiration = diskUsageHistoryExpiration
+		}
+		assert.WithinDuration(t, time.Now().Add(expiration), entry.ExpiresAt, time.Minute, "expiration of %s", entry.Type)
+	}
+	assert.Equal(t, []string{EntryDeviceProperties, EntryDeviceClass, EntryConnectionData, EntryDiskUsageHistory}, types)
+
+	require.NoError(t, d.DeleteEntries(ctx, ip))
+	_, err = d.GetDeviceProperties(ctx, ip)
+	assert.True(t, tholaerr.IsNotFoundError(err), "deleted device properties: %v", err)
+	entries, err = d.GetEntries(ctx, ip)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	ips, err = d.ListDevices(ctx)
+	require.NoError(t, err)
+	assert.NotContains(t, ips, ip)
+	_, err = d.GetJob(ctx, job.ID)
+	assert.NoError(t, err, "jobs are not deleted with the entries of a device")
+
+	time.Sleep(time.Until(start.Add(2100 * time.Millisecond)))
+	cacheExpiration = time.Second
+	_, err = d.GetSNMPv3EngineData(ctx, "192.0.2.2")
+	assert.True(t, tholaerr.IsNotFoundError(err), "expired entry: %v", err)
+}
+
+func TestParseExpirations(t *testing.T) {
+	defer func(cache, deviceClass time.Duration) {
+		cacheExpiration, deviceClassExpiration = cache, deviceClass
+	}(cacheExpiration, deviceClassExpiration)
+	defer viper.Set("db.duration", nil)
+	defer viper.Set("db.device-class-ttl", nil)
+
+	viper.Set("db.duration", "1h")
+	require.NoError(t, parseExpirations())
+	assert.Equal(t, time.Hour, deviceClassExpiration)
+
+	viper.Set("db.device-class-ttl", "10m")
+	require.NoError(t, parseExpirations())
+	assert.Equal(t, 10*time.Minute, deviceClassExpiration)
+
+	// the device class is only validated while the device properties are cached
+	viper.Set("db.device-class-ttl", "2h")
+	assert.Error(t, parseExpirations())
+}
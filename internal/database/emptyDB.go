@@ -6,6 +6,7 @@ import (
 	"github.com/inexio/thola/internal/network"
 	"github.com/inexio/thola/internal/tholaerr"
 	"github.com/rs/zerolog/log"
+	"time"
 )
 
 type emptyDatabase struct{}
@@ -26,6 +27,58 @@ func (d *emptyDatabase) GetConnectionData(_ context.Context, _ string) (network.
 	return network.ConnectionData{}, tholaerr.NewNotFoundError("no db available")
 }
 
+func (d *emptyDatabase) SetDiskUsageHistory(_ context.Context, _ string, _ DiskUsageHistory) error {
+	return nil
+}
+
+func (d *emptyDatabase) GetDiskUsageHistory(_ context.Context, _ string) (DiskUsageHistory, error) {
+	return nil, tholaerr.NewNotFoundError("no db available")
+}
+
+func (d *emptyDatabase) SetSNMPv3EngineData(_ context.Context, _ string, _ network.SNMPv3EngineData) error {
+	return nil
+}
+
+func (d *emptyDatabase) GetSNMPv3EngineData(_ context.Context, _ string) (network.SNMPv3EngineData, error) {
+	return network.SNMPv3EngineData{}, tholaerr.NewNotFoundError("no db available")
+}
+
+func (d *emptyDatabase) SetDeviceClass(_ context.Context, _ string, _ DeviceClassData) error {
+	return nil
+}
+
+func (d *emptyDatabase) GetDeviceClass(_ context.Context, _ string) (DeviceClassData, error) {
+	return DeviceClassData{}, tholaerr.NewNotFoundError("no db available")
+}
+
+func (d *emptyDatabase) SetJob(_ context.Context, _ Job) error {
+	return nil
+}
+
+func (d *emptyDatabase) GetJob(_ context.Context, _ string) (Job, error) {
+	return Job{}, tholaerr.NewNotFoundError("no db available")
+}
+
+func (d *emptyDatabase) RequestJobCancel(_ context.Context, _ string, _ time.Time) error {
+	return nil
+}
+
+func (d *emptyDatabase) IsJobCancelRequested(_ context.Context, _ string) (bool, error) {
+	return false, nil
+}
+
+func (d *emptyDatabase) ListDevices(_ context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (d *emptyDatabase) GetEntries(_ context.Context, _ string) ([]Entry, error) {
+	return nil, nil
+}
+
+func (d *emptyDatabase) DeleteEntries(_ context.Context, _ string) error {
+	return nil
+}
+
 func (d *emptyDatabase) CheckConnection(_ context.Context) error {
 	return nil
 }
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Types of the cache entries of a device.
+const (
+	EntryDeviceProperties = "DeviceInfo"
+	EntryDeviceClass      = "DeviceClass"
+	EntryConnectionData   = "ConnectionData"
+	EntryDiskUsageHistory = "DiskUsageHistory"
+	EntrySNMPv3EngineData = "SNMPv3EngineData"
+)
+
+// deviceEntryTypes are the types of all cache entries that belong to a device.
+var deviceEntryTypes = []string{
+	EntryDeviceProperties,
+	EntryDeviceClass,
+	EntryConnectionData,
+	EntryDiskUsageHistory,
+	EntrySNMPv3EngineData,
+}
+
+// DeviceClassData is the device class a device was identified as. It is trusted until it expires,
+// unless the sysObjectID or the boot time of the device show that it was replaced or rebooted in the meantime.
+type DeviceClassData struct {
+	Class        string    `json:"class"`
+	SysObjectID  string    `json:"sys_object_id,omitempty"`
+	BootTime     time.Time `json:"boot_time,omitempty"`
+	IdentifiedAt time.Time `json:"identified_at"`
+}
+
+// Entry is a cache entry of a device.
+type Entry struct {
+	IP        string          `json:"ip"`
+	Type      string          `json:"type"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// entryExpiration returns the duration entries of the type are kept.
+func entryExpiration(entryType string) time.Duration {
+	switch entryType {
+	case EntryDiskUsageHistory:
+		return diskUsageHistoryExpiration
+	case EntryDeviceClass:
+		return deviceClassExpiration
+	default:
+		return cacheExpiration
+	}
+}
+
+func isDeviceEntryType(entryType string) bool {
+	for _, t := range deviceEntryTypes {
+		if t == entryType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,437 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/inexio/thola/internal/device"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/parser"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// etcdKeyPrefix is the prefix of all keys thola stores in etcd.
+const etcdKeyPrefix = "thola/"
+
+// etcdDatabase stores the cache in etcd. It uses the JSON gateway of the etcd v3 api, so no grpc client is needed.
+// Every entry is attached to its own lease, which lets etcd delete it once it expires.
+// The lease of the previous value of an entry is revoked when the entry is overwritten.
+type etcdDatabase struct {
+	client    *http.Client
+	endpoints []string
+	username  string
+	password  string
+
+	tokenLock sync.Mutex
+	token     string
+}
+
+type etcdKeyValue struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+	Lease string `json:"lease"`
+}
+
+type etcdRangeResponse struct {
+	KVs []etcdKeyValue `json:"kvs"`
+}
+
+type etcdPutResponse struct {
+	PrevKV *etcdKeyValue `json:"prev_kv"`
+}
+
+type etcdLeaseResponse struct {
+	ID  string `json:"ID"`
+	TTL string `json:"TTL"`
+}
+
+func newEtcdDatabase(endpoints []string, username, password string) *etcdDatabase {
+	for i, endpoint := range endpoints {
+		endpoints[i] = strings.TrimSuffix(endpoint, "/")
+	}
+	return &etcdDatabase{
+		client:    &http.Client{Timeout: 10 * time.Second},
+		endpoints: endpoints,
+		username:  username,
+		password:  password,
+	}
+}
+
+func (d *etcdDatabase) SetDeviceProperties(ctx context.Context, ip string, data device.Device) error {
+	err := d.setEntry(ctx, EntryDeviceProperties+"-"+ip, data, cacheExpiration)
+	if err != nil && !db.ignoreFailure {
+		return errors.Wrap(err, "failed to store device data")
+	}
+	return nil
+}
+
+func (d *etcdDatabase) GetDeviceProperties(ctx context.Context, ip string) (device.Device, error) {
+	var data device.Device
+	err := d.getEntry(ctx, EntryDeviceProperties+"-"+ip, &data)
+	if err != nil {
+		return device.Device{}, err
+	}
+	return data, nil
+}
+
+func (d *etcdDatabase) SetConnectionData(ctx context.Context, ip string, data network.ConnectionData) error {
+	err := d.setEntry(ctx, EntryConnectionData+"-"+ip, data, cacheExpiration)
+	if err != nil && !db.ignoreFailure {
+		return errors.Wrap(err, "failed to store connection data")
+	}
+	return nil
+}
+
+func (d *etcdDatabase) GetConnectionData(ctx context.Context, ip string) (network.ConnectionData, error) {
+	var data network.ConnectionData
+	err := d.getEntry(ctx, EntryConnectionData+"-"+ip, &data)
+	if err != nil {
+		return network.ConnectionData{}, err
+	}
+	return data, nil
+}
+
+func (d *etcdDatabase) SetDiskUsageHistory(ctx context.Context, ip string, data DiskUsageHistory) error {
+	err := d.setEntry(ctx, EntryDiskUsageHistory+"-"+ip, data, diskUsageHistoryExpiration)
+	if err != nil && !db.ignoreFailure {
+		return errors.Wrap(err, "failed to store disk usage history")
+	}
+	return nil
+}
+
+func (d *etcdDatabase) GetDiskUsageHistory(ctx context.Context, ip string) (DiskUsageHistory, error) {
+	var data DiskUsageHistory
+	err := d.getEntry(ctx, EntryDiskUsageHistory+"-"+ip, &data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (d *etcdDatabase) SetSNMPv3EngineData(ctx context.Context, ip string, data network.SNMPv3EngineData) error {
+	err := d.setEntry(ctx, EntrySNMPv3EngineData+"-"+ip, data, cacheExpiration)
+	if err != nil && !db.ignoreFailure {
+		return errors.Wrap(err, "failed to store snmp v3 engine data")
+	}
+	return nil
+}
+
+func (d *etcdDatabase) GetSNMPv3EngineData(ctx context.Context, ip string) (network.SNMPv3EngineData, error) {
+	var data network.SNMPv3EngineData
+	err := d.getEntry(ctx, EntrySNMPv3EngineData+"-"+ip, &data)
+	if err != nil {
+		return network.SNMPv3EngineData{}, err
+	}
+	return data, nil
+}
+
+func (d *etcdDatabase) SetDeviceClass(ctx context.Context, ip string, data DeviceClassData) error {
+	err := d.setEntry(ctx, EntryDeviceClass+"-"+ip, data, deviceClassExpiration)
+	if err != nil && !db.ignoreFailure {
+		return errors.Wrap(err, "failed to store device class data")
+	}
+	return nil
+}
+
+func (d *etcdDatabase) GetDeviceClass(ctx context.Context, ip string) (DeviceClassData, error) {
+	var data DeviceClassData
+	err := d.getEntry(ctx, EntryDeviceClass+"-"+ip, &data)
+	if err != nil {
+		return DeviceClassData{}, err
+	}
+	return data, nil
+}
+
+func (d *etcdDatabase) SetJob(ctx context.Context, data Job) error {
+	// jobs are not a cache, so failures are never ignored
+	err := d.setEntry(ctx, "Job-"+data.ID, data, time.Until(data.ExpiresAt))
+	if err != nil {
+		return errors.Wrap(err, "failed to store job")
+	}
+	return nil
+}
+
+func (d *etcdDatabase) GetJob(ctx context.Context, id string) (Job, error) {
+	var data Job
+	err := d.getEntry(ctx, "Job-"+id, &data)
+	if err != nil {
+		return Job{}, err
+	}
+	return data, nil
+}
+
+func (d *etcdDatabase) RequestJobCancel(ctx context.Context, id string, expiresAt time.Time) error {
+	err := d.setEntry(ctx, "JobCancel-"+id, true, time.Until(expiresAt))
+	if err != nil {
+		return errors.Wrap(err, "failed to store job cancel request")
+	}
+	return nil
+}
+
+func (d *etcdDatabase) IsJobCancelRequested(ctx context.Context, id string) (bool, error) {
+	var requested bool
+	err := d.getEntry(ctx, "JobCancel-"+id, &requested)
+	if err != nil {
+		if tholaerr.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return requested, nil
+}
+
+func (d *etcdDatabase) ListDevices(ctx context.Context) ([]string, error) {
+	var res etcdRangeResponse
+	err := d.call(ctx, "/v3/kv/range", map[string]interface{}{
+		"key":       []byte(etcdKeyPrefix),
+		"range_end": etcdPrefixEnd(etcdKeyPrefix),
+		"keys_only": true,
+	}, &res)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list keys")
+	}
+
+	found := make(map[string]struct{})
+	var ips []string
+	for _, kv := range res.KVs {
+		key := strings.SplitN(strings.TrimPrefix(string(kv.Key), etcdKeyPrefix), "-", 2)
+		if len(key) != 2 || !isDeviceEntryType(key[0]) {
+			continue
+		}
+		if _, ok := found[key[1]]; !ok {
+			found[key[1]] = struct{}{}
+			ips = append(ips, key[1])
+		}
+	}
+	sort.Strings(ips)
+	return ips, nil
+}
+
+func (d *etcdDatabase) GetEntries(ctx context.Context, ip string) ([]Entry, error) {
+	var entries []Entry
+	for _, entryType := range deviceEntryTypes {
+		kv, err := d.get(ctx, entryType+"-"+ip)
+		if err != nil {
+			if tholaerr.IsNotFoundError(err) {
+				continue
+			}
+			return nil, err
+		}
+		var lease etcdLeaseResponse
+		err = d.call(ctx, "/v3/lease/timetolive", map[string]interface{}{"ID": kv.Lease}, &lease)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get ttl of cache entry")
+		}
+		ttl, err := strconv.ParseInt(lease.TTL, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse ttl of cache entry")
+		}
+		entries = append(entries, Entry{
+			IP:        ip,
+			Type:      entryType,
+			ExpiresAt: time.Now().Add(time.Duration(ttl) * time.Second).Truncate(time.Second),
+			Data:      kv.Value,
+		})
+	}
+	return entries, nil
+}
+
+func (d *etcdDatabase) DeleteEntries(ctx context.Context, ip string) error {
+	for _, entryType := range deviceEntryTypes {
+		err := d.call(ctx, "/v3/kv/deleterange", map[string]interface{}{"key": []byte(etcdKeyPrefix + entryType + "-" + ip)}, nil)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete cache entry")
+		}
+	}
+	return nil
+}
+
+func (d *etcdDatabase) CheckConnection(ctx context.Context) error {
+	return d.call(ctx, "/v3/maintenance/status", struct{}{}, nil)
+}
+
+func (d *etcdDatabase) CloseConnection(ctx context.Context) error {
+	log.Ctx(ctx).Debug().Msg("closing connection to etcd database")
+	d.client.CloseIdleConnections()
+	return nil
+}
+
+// setEntry stores the data under the key with a new lease that expires after the ttl.
+// The lease of the previous value is revoked, so that there is only one lease per entry.
+func (d *etcdDatabase) setEntry(ctx context.Context, key string, data interface{}, ttl time.Duration) error {
+	JSONData, err := parser.ToJSON(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshall data")
+	}
+
+	var lease etcdLeaseResponse
+	err = d.call(ctx, "/v3/lease/grant", map[string]interface{}{"TTL": int64(math.Max(1, math.Ceil(ttl.Seconds())))}, &lease)
+	if err != nil {
+		return errors.Wrap(err, "failed to grant lease")
+	}
+	var res etcdPutResponse
+	err = d.call(ctx, "/v3/kv/put", map[string]interface{}{
+		"key":     []byte(etcdKeyPrefix + key),
+		"value":   JSONData,
+		"lease":   lease.ID,
+		"prev_kv": true,
+	}, &res)
+	if err != nil {
+		return errors.Wrap(err, "failed to put key")
+	}
+	if res.PrevKV != nil && res.PrevKV.Lease != "" && res.PrevKV.Lease != "0" && res.PrevKV.Lease != lease.ID {
+		// the key is attached to the new lease already, so revoking the old one does not delete it
+		err = d.call(ctx, "/v3/lease/revoke", map[string]interface{}{"ID": res.PrevKV.Lease}, nil)
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Str("key", key).Msg("failed to revoke lease of previous value")
+		}
+	}
+	return nil
+}
+
+func (d *etcdDatabase) getEntry(ctx context.Context, key string, dest interface{}) error {
+	kv, err := d.get(ctx, key)
+	if err != nil {
+		return err
+	}
+	err = json.Unmarshal(kv.Value, dest)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshall entry data")
+	}
+	return nil
+}
+
+func (d *etcdDatabase) get(ctx context.Context, key string) (etcdKeyValue, error) {
+	var res etcdRangeResponse
+	err := d.call(ctx, "/v3/kv/range", map[string]interface{}{"key": []byte(etcdKeyPrefix + key)}, &res)
+	if err != nil {
+		return etcdKeyValue{}, errors.Wrap(err, "failed to get key")
+	}
+	if len(res.KVs) == 0 {
+		return etcdKeyValue{}, tholaerr.NewNotFoundError("cannot find cache entry")
+	}
+	return res.KVs[0], nil
+}
+
+// call sends the request to the JSON gateway of the first endpoint that can be reached.
+// If authentication is configured, a token is requested before the first call and when it expired.
+// etcd rejects requests without token with status 400, and only requests with an invalid token with status 401.
+func (d *etcdDatabase) call(ctx context.Context, path string, req, res interface{}) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request")
+	}
+
+	var lastErr error
+	for _, endpoint := range d.endpoints {
+		if d.username != "" && !d.hasToken() {
+			if err := d.authenticate(ctx, endpoint); err != nil {
+				lastErr = err
+				continue
+			}
+		}
+		status, resBody, err := d.post(ctx, endpoint, path, body)
+		if err == nil && status == http.StatusUnauthorized && d.username != "" {
+			err = d.authenticate(ctx, endpoint)
+			if err == nil {
+				status, resBody, err = d.post(ctx, endpoint, path, body)
+			}
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if status != http.StatusOK {
+			var etcdErr struct {
+				Message string `json:"message"`
+				Error   string `json:"error"`
+			}
+			_ = json.Unmarshal(resBody, &etcdErr)
+			if etcdErr.Message == "" {
+				etcdErr.Message = etcdErr.Error
+			}
+			return fmt.Errorf("etcd responded with status %d: %s", status, etcdErr.Message)
+		}
+		if res == nil {
+			return nil
+		}
+		return errors.Wrap(json.Unmarshal(resBody, res), "failed to unmarshal response")
+	}
+	if lastErr == nil {
+		return errors.New("no etcd endpoints configured")
+	}
+	return errors.Wrap(lastErr, "no etcd endpoint reachable")
+}
+
+func (d *etcdDatabase) post(ctx context.Context, endpoint, path string, body []byte) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to build request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	d.tokenLock.Lock()
+	if d.token != "" {
+		req.Header.Set("Authorization", d.token)
+	}
+	d.tokenLock.Unlock()
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return 0, nil, errors.Wrap(err, "failed to read response")
+	}
+	return res.StatusCode, resBody, nil
+}
+
+func (d *etcdDatabase) hasToken() bool {
+	d.tokenLock.Lock()
+	defer d.tokenLock.Unlock()
+	return d.token != ""
+}
+
+func (d *etcdDatabase) authenticate(ctx context.Context, endpoint string) error {
+	body, err := json.Marshal(map[string]string{"name": d.username, "password": d.password})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal request")
+	}
+	status, resBody, err := d.post(ctx, endpoint, "/v3/auth/authenticate", body)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("etcd authentication failed with status %d", status)
+	}
+	var res struct {
+		Token string `json:"token"`
+	}
+	err = json.Unmarshal(resBody, &res)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal authentication response")
+	}
+	d.tokenLock.Lock()
+	d.token = res.Token
+	d.tokenLock.Unlock()
+	return nil
+}
+
+// etcdPrefixEnd returns the end of the range of all keys with the prefix.
+func etcdPrefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+	end[len(end)-1]++
+	return end
+}
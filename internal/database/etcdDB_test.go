@@ -0,0 +1,185 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeEtcd implements the parts of the JSON gateway of etcd that the etcd database uses.
+// If a user is set, all requests need a token, which is rejected like etcd does.
+type fakeEtcd struct {
+	sync.Mutex
+	kvs       map[string]etcdKeyValue
+	leases    map[string]time.Time
+	nextLease int
+
+	user, password string
+	tokens         map[string]bool
+	authenticated  int
+}
+
+func newFakeEtcd() (*fakeEtcd, *httptest.Server) {
+	f := &fakeEtcd{kvs: make(map[string]etcdKeyValue), leases: make(map[string]time.Time), tokens: make(map[string]bool)}
+	return f, httptest.NewServer(f)
+}
+
+type fakeEtcdRequest struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end"`
+	KeysOnly bool   `json:"keys_only"`
+	Value    []byte `json:"value"`
+	Lease    string `json:"lease"`
+	PrevKV   bool   `json:"prev_kv"`
+	ID       string `json:"ID"`
+	TTL      int64  `json:"TTL"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+func (f *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req fakeEtcdRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	f.Lock()
+	defer f.Unlock()
+	if f.user != "" && r.URL.Path != "/v3/auth/authenticate" {
+		token := r.Header.Get("Authorization")
+		if token == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "etcdserver: user name is empty"})
+			return
+		}
+		if !f.tokens[token] {
+			w.WriteHeader(http.StatusUnauthorized)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "etcdserver: invalid auth token"})
+			return
+		}
+	}
+	for key, kv := range f.kvs {
+		if time.Now().After(f.leases[kv.Lease]) {
+			delete(f.kvs, key)
+		}
+	}
+
+	var res interface{} = struct{}{}
+	switch r.URL.Path {
+	case "/v3/lease/grant":
+		f.nextLease++
+		id := strconv.Itoa(f.nextLease)
+		f.leases[id] = time.Now().Add(time.Duration(req.TTL) * time.Second)
+		res = etcdLeaseResponse{ID: id, TTL: strconv.FormatInt(req.TTL, 10)}
+	case "/v3/lease/timetolive":
+		res = etcdLeaseResponse{ID: req.ID, TTL: strconv.Itoa(int(time.Until(f.leases[req.ID]).Seconds()))}
+	case "/v3/lease/revoke":
+		for key, kv := range f.kvs {
+			if kv.Lease == req.ID {
+				delete(f.kvs, key)
+			}
+		}
+		delete(f.leases, req.ID)
+	case "/v3/kv/put":
+		if prev, ok := f.kvs[string(req.Key)]; ok && req.PrevKV {
+			res = etcdPutResponse{PrevKV: &prev}
+		}
+		f.kvs[string(req.Key)] = etcdKeyValue{Key: req.Key, Value: req.Value, Lease: req.Lease}
+	case "/v3/kv/range":
+		var kvs []etcdKeyValue
+		for _, key := range f.keys(req) {
+			kv := f.kvs[key]
+			if req.KeysOnly {
+				kv.Value = nil
+			}
+			kvs = append(kvs, kv)
+		}
+		res = etcdRangeResponse{KVs: kvs}
+	case "/v3/kv/deleterange":
+		for _, key := range f.keys(req) {
+			delete(f.kvs, key)
+		}
+	case "/v3/maintenance/status":
+	case "/v3/auth/authenticate":
+		if req.Name != f.user || req.Password != f.password {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"message": "etcdserver: authentication failed, invalid user ID or password"})
+			return
+		}
+		f.authenticated++
+		token := "token-" + strconv.Itoa(f.authenticated)
+		f.tokens[token] = true
+		res = map[string]string{"token": token}
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// keys returns the sorted keys in the range of the request.
+func (f *fakeEtcd) keys(req fakeEtcdRequest) []string {
+	var keys []string
+	for key := range f.kvs {
+		if req.RangeEnd == nil && key == string(req.Key) ||
+			req.RangeEnd != nil && bytes.Compare([]byte(key), req.Key) >= 0 && bytes.Compare([]byte(key), req.RangeEnd) < 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestEtcdDatabase_leases(t *testing.T) {
+	f, server := newFakeEtcd()
+	defer server.Close()
+	d := newEtcdDatabase([]string{server.URL}, "", "")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, d.SetDeviceClass(ctx, "192.0.2.1", DeviceClassData{Class: "ios"}))
+	}
+	data, err := d.GetDeviceClass(ctx, "192.0.2.1")
+	require.NoError(t, err)
+	assert.Equal(t, "ios", data.Class)
+
+	f.Lock()
+	defer f.Unlock()
+	assert.Len(t, f.leases, 1, "leases of overwritten values are revoked")
+}
+
+func TestEtcdDatabase_auth(t *testing.T) {
+	f, server := newFakeEtcd()
+	defer server.Close()
+	f.user, f.password = "thola", "secret"
+	ctx := context.Background()
+
+	// the token is requested before the first call, as etcd rejects requests without token with status 400
+	d := newEtcdDatabase([]string{server.URL}, "thola", "secret")
+	require.NoError(t, d.CheckConnection(ctx))
+	require.NoError(t, d.SetDeviceClass(ctx, "192.0.2.1", DeviceClassData{Class: "ios"}))
+
+	// an expired token is renewed
+	f.Lock()
+	f.tokens = make(map[string]bool)
+	f.Unlock()
+	data, err := d.GetDeviceClass(ctx, "192.0.2.1")
+	require.NoError(t, err)
+	assert.Equal(t, "ios", data.Class)
+	f.Lock()
+	assert.Equal(t, 2, f.authenticated)
+	f.Unlock()
+
+	assert.Error(t, newEtcdDatabase([]string{server.URL}, "thola", "wrong").CheckConnection(ctx))
+	assert.Error(t, newEtcdDatabase([]string{server.URL}, "", "").CheckConnection(ctx))
+}
@@ -10,10 +10,13 @@ import (
 	"github.com/inexio/thola/internal/tholaerr"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"sort"
+	"strings"
+	"time"
 )
 
 type redisDatabase struct {
-	pool redis.Pool
+	pool *redis.Pool
 }
 
 func (d *redisDatabase) SetDeviceProperties(ctx context.Context, ip string, data device.Device) error {
@@ -27,7 +30,7 @@ func (d *redisDatabase) SetDeviceProperties(ctx context.Context, ip string, data
 	if err != nil {
 		return errors.Wrap(err, "failed to marshall response")
 	}
-	_, err = conn.Do("SETEX", "DeviceInfo-"+ip, cacheExpiration.Seconds(), JSONData)
+	_, err = conn.Do("SETEX", "DeviceInfo-"+ip, int64(cacheExpiration/time.Second), JSONData)
 	if err != nil && !db.ignoreFailure {
 		return errors.Wrap(err, "failed to store device data")
 	}
@@ -64,7 +67,7 @@ func (d *redisDatabase) SetConnectionData(ctx context.Context, ip string, data n
 	if err != nil {
 		return errors.Wrap(err, "failed to marshall connectionData")
 	}
-	_, err = conn.Do("SETEX", "ConnectionData-"+ip, cacheExpiration.Seconds(), JSONData)
+	_, err = conn.Do("SETEX", "ConnectionData-"+ip, int64(cacheExpiration/time.Second), JSONData)
 	if err != nil && !db.ignoreFailure {
 		return errors.Wrap(err, "failed to store connection data")
 	}
@@ -90,6 +93,268 @@ func (d *redisDatabase) GetConnectionData(ctx context.Context, ip string) (netwo
 	return data, nil
 }
 
+func (d *redisDatabase) SetDiskUsageHistory(ctx context.Context, ip string, data DiskUsageHistory) error {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	JSONData, err := parser.ToJSON(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshall disk usage history")
+	}
+	_, err = conn.Do("SETEX", "DiskUsageHistory-"+ip, int64(diskUsageHistoryExpiration/time.Second), JSONData)
+	if err != nil && !db.ignoreFailure {
+		return errors.Wrap(err, "failed to store disk usage history")
+	}
+	return nil
+}
+
+func (d *redisDatabase) GetDiskUsageHistory(ctx context.Context, ip string) (DiskUsageHistory, error) {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	value, err := redis.String(conn.Do("GET", "DiskUsageHistory-"+ip))
+	if err != nil {
+		return nil, tholaerr.NewNotFoundError("cannot find cache entry")
+	}
+	var data DiskUsageHistory
+	err = json.Unmarshal([]byte(value), &data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshall disk usage history")
+	}
+	return data, nil
+}
+
+func (d *redisDatabase) SetSNMPv3EngineData(ctx context.Context, ip string, data network.SNMPv3EngineData) error {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	JSONData, err := parser.ToJSON(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshall snmp v3 engine data")
+	}
+	_, err = conn.Do("SETEX", "SNMPv3EngineData-"+ip, int64(cacheExpiration/time.Second), JSONData)
+	if err != nil && !db.ignoreFailure {
+		return errors.Wrap(err, "failed to store snmp v3 engine data")
+	}
+	return nil
+}
+
+func (d *redisDatabase) GetSNMPv3EngineData(ctx context.Context, ip string) (network.SNMPv3EngineData, error) {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return network.SNMPv3EngineData{}, errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	value, err := redis.String(conn.Do("GET", "SNMPv3EngineData-"+ip))
+	if err != nil {
+		return network.SNMPv3EngineData{}, tholaerr.NewNotFoundError("cannot find cache entry")
+	}
+	var data network.SNMPv3EngineData
+	err = json.Unmarshal([]byte(value), &data)
+	if err != nil {
+		return network.SNMPv3EngineData{}, errors.Wrap(err, "failed to unmarshall snmp v3 engine data")
+	}
+	return data, nil
+}
+
+func (d *redisDatabase) SetDeviceClass(ctx context.Context, ip string, data DeviceClassData) error {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	JSONData, err := parser.ToJSON(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshall device class data")
+	}
+	_, err = conn.Do("SETEX", EntryDeviceClass+"-"+ip, int64(deviceClassExpiration/time.Second), JSONData)
+	if err != nil && !db.ignoreFailure {
+		return errors.Wrap(err, "failed to store device class data")
+	}
+	return nil
+}
+
+func (d *redisDatabase) GetDeviceClass(ctx context.Context, ip string) (DeviceClassData, error) {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return DeviceClassData{}, errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	value, err := redis.String(conn.Do("GET", EntryDeviceClass+"-"+ip))
+	if err != nil {
+		return DeviceClassData{}, tholaerr.NewNotFoundError("cannot find cache entry")
+	}
+	var data DeviceClassData
+	err = json.Unmarshal([]byte(value), &data)
+	if err != nil {
+		return DeviceClassData{}, errors.Wrap(err, "failed to unmarshall device class data")
+	}
+	return data, nil
+}
+
+func (d *redisDatabase) SetJob(ctx context.Context, data Job) error {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	JSONData, err := parser.ToJSON(data)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshall job")
+	}
+	// jobs are not a cache, so failures are never ignored
+	_, err = conn.Do("SETEX", "Job-"+data.ID, int(time.Until(data.ExpiresAt).Seconds())+1, JSONData)
+	if err != nil {
+		return errors.Wrap(err, "failed to store job")
+	}
+	return nil
+}
+
+func (d *redisDatabase) GetJob(ctx context.Context, id string) (Job, error) {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return Job{}, errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	value, err := redis.String(conn.Do("GET", "Job-"+id))
+	if err != nil {
+		return Job{}, tholaerr.NewNotFoundError("cannot find job")
+	}
+	var data Job
+	err = json.Unmarshal([]byte(value), &data)
+	if err != nil {
+		return Job{}, errors.Wrap(err, "failed to unmarshall job")
+	}
+	return data, nil
+}
+
+func (d *redisDatabase) RequestJobCancel(ctx context.Context, id string, expiresAt time.Time) error {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	_, err = conn.Do("SETEX", "JobCancel-"+id, int(time.Until(expiresAt).Seconds())+1, "true")
+	if err != nil {
+		return errors.Wrap(err, "failed to store job cancel request")
+	}
+	return nil
+}
+
+func (d *redisDatabase) IsJobCancelRequested(ctx context.Context, id string) (bool, error) {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	exists, err := redis.Bool(conn.Do("EXISTS", "JobCancel-"+id))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get job cancel request")
+	}
+	return exists, nil
+}
+
+func (d *redisDatabase) ListDevices(ctx context.Context) ([]string, error) {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	found := make(map[string]struct{})
+	var ips []string
+	for _, entryType := range deviceEntryTypes {
+		cursor := 0
+		for {
+			values, err := redis.Values(conn.Do("SCAN", cursor, "MATCH", entryType+"-*", "COUNT", 100))
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to scan redis database")
+			}
+			var keys []string
+			_, err = redis.Scan(values, &cursor, &keys)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to read scan result")
+			}
+			for _, key := range keys {
+				ip := strings.TrimPrefix(key, entryType+"-")
+				if _, ok := found[ip]; !ok {
+					found[ip] = struct{}{}
+					ips = append(ips, ip)
+				}
+			}
+			if cursor == 0 {
+				break
+			}
+		}
+	}
+	sort.Strings(ips)
+	return ips, nil
+}
+
+func (d *redisDatabase) GetEntries(ctx context.Context, ip string) ([]Entry, error) {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	var entries []Entry
+	for _, entryType := range deviceEntryTypes {
+		value, err := redis.Bytes(conn.Do("GET", entryType+"-"+ip))
+		if err != nil {
+			if err == redis.ErrNil {
+				continue
+			}
+			return nil, errors.Wrap(err, "failed to get cache entry")
+		}
+		ttl, err := redis.Int64(conn.Do("PTTL", entryType+"-"+ip))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get ttl of cache entry")
+		}
+		entries = append(entries, Entry{
+			IP:        ip,
+			Type:      entryType,
+			ExpiresAt: time.Now().Add(time.Duration(ttl) * time.Millisecond).Truncate(time.Second),
+			Data:      value,
+		})
+	}
+	return entries, nil
+}
+
+func (d *redisDatabase) DeleteEntries(ctx context.Context, ip string) error {
+	conn, err := d.pool.GetContext(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get connection to redis database")
+	}
+	defer conn.Close()
+
+	keys := make([]interface{}, 0, len(deviceEntryTypes))
+	for _, entryType := range deviceEntryTypes {
+		keys = append(keys, entryType+"-"+ip)
+	}
+	_, err = conn.Do("DEL", keys...)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete cache entries")
+	}
+	return nil
+}
+
 func (d *redisDatabase) CheckConnection(ctx context.Context) error {
 	conn, err := d.pool.GetContext(ctx)
 	if err != nil {
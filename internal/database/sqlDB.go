@@ -14,40 +14,16 @@ import (
 )
 
 type sqlDatabase struct {
-	db *sqlx.DB
+	db      *sqlx.DB
+	dialect sqlDialect
 }
 
 type sqlSelectResults []struct {
-	Time     string
+	Time     sqlTime
 	Data     string
 	Datatype string
 }
 
-var mysqlSchemaArr = []string{
-	`DROP TABLE IF EXISTS cache;`,
-
-	`CREATE TABLE cache (
-		id INTEGER PRIMARY KEY,
-		ip varchar(255) NOT NULL,
-		datatype varchar(255) NOT NULL,
-		data text NOT NULL,
-		time datetime DEFAULT current_timestamp NOT NULL,
-		CONSTRAINT unique_entries UNIQUE (ip, datatype)
-		);`,
-	`ALTER TABLE cache MODIFY id int(11) NOT NULL AUTO_INCREMENT;`,
-}
-
-func (d sqlDatabase) setupDatabase() error {
-	for _, query := range mysqlSchemaArr {
-		_, err := d.db.Exec(query)
-		if err != nil {
-			_, _ = d.db.Exec(`DROP TABLE IF EXISTS cache;`)
-			return errors.Wrap(err, "Could not set up database schema - query: "+query)
-		}
-	}
-	return nil
-}
-
 func (d *sqlDatabase) SetDeviceProperties(ctx context.Context, ip string, data device.Device) error {
 	err := d.insertReplaceQuery(ctx, data, ip, "DeviceInfo")
 	if err != nil {
@@ -58,7 +34,7 @@ func (d *sqlDatabase) SetDeviceProperties(ctx context.Context, ip string, data d
 
 func (d *sqlDatabase) GetDeviceProperties(ctx context.Context, ip string) (device.Device, error) {
 	var identifyResponse device.Device
-	err := d.getEntry(ctx, &identifyResponse, ip, "DeviceInfo")
+	err := d.getEntry(ctx, &identifyResponse, ip, "DeviceInfo", cacheExpiration)
 	if err != nil {
 		return device.Device{}, err
 	}
@@ -71,19 +47,149 @@ func (d *sqlDatabase) SetConnectionData(ctx context.Context, ip string, data net
 
 func (d *sqlDatabase) GetConnectionData(ctx context.Context, ip string) (network.ConnectionData, error) {
 	var connectionData network.ConnectionData
-	err := d.getEntry(ctx, &connectionData, ip, "ConnectionData")
+	err := d.getEntry(ctx, &connectionData, ip, "ConnectionData", cacheExpiration)
 	if err != nil {
 		return network.ConnectionData{}, err
 	}
 	return connectionData, nil
 }
 
+func (d *sqlDatabase) SetDiskUsageHistory(ctx context.Context, ip string, data DiskUsageHistory) error {
+	return d.insertReplaceQuery(ctx, data, ip, "DiskUsageHistory")
+}
+
+func (d *sqlDatabase) GetDiskUsageHistory(ctx context.Context, ip string) (DiskUsageHistory, error) {
+	var history DiskUsageHistory
+	err := d.getEntry(ctx, &history, ip, "DiskUsageHistory", diskUsageHistoryExpiration)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (d *sqlDatabase) SetSNMPv3EngineData(ctx context.Context, ip string, data network.SNMPv3EngineData) error {
+	return d.insertReplaceQuery(ctx, data, ip, "SNMPv3EngineData")
+}
+
+func (d *sqlDatabase) GetSNMPv3EngineData(ctx context.Context, ip string) (network.SNMPv3EngineData, error) {
+	var engineData network.SNMPv3EngineData
+	err := d.getEntry(ctx, &engineData, ip, "SNMPv3EngineData", cacheExpiration)
+	if err != nil {
+		return network.SNMPv3EngineData{}, err
+	}
+	return engineData, nil
+}
+
+func (d *sqlDatabase) SetDeviceClass(ctx context.Context, ip string, data DeviceClassData) error {
+	return d.insertReplaceQuery(ctx, data, ip, EntryDeviceClass)
+}
+
+func (d *sqlDatabase) GetDeviceClass(ctx context.Context, ip string) (DeviceClassData, error) {
+	var data DeviceClassData
+	err := d.getEntry(ctx, &data, ip, EntryDeviceClass, deviceClassExpiration)
+	if err != nil {
+		return DeviceClassData{}, err
+	}
+	return data, nil
+}
+
+func (d *sqlDatabase) SetJob(ctx context.Context, data Job) error {
+	return d.insertReplaceQuery(ctx, data, data.ID, "Job")
+}
+
+func (d *sqlDatabase) GetJob(ctx context.Context, id string) (Job, error) {
+	var job Job
+	// the expiration of jobs is stored in the job itself
+	err := d.getEntry(ctx, &job, id, "Job", time.Since(time.Time{}))
+	if err != nil {
+		return Job{}, err
+	}
+	if time.Now().After(job.ExpiresAt) {
+		_, err = d.db.ExecContext(ctx, d.db.Rebind("DELETE FROM cache WHERE ip=? AND datatype=?;"), id, "Job")
+		if err != nil {
+			return Job{}, errors.Wrap(err, "failed to delete expired job")
+		}
+		return Job{}, tholaerr.NewNotFoundError("found only expired job")
+	}
+	return job, nil
+}
+
+func (d *sqlDatabase) RequestJobCancel(ctx context.Context, id string, expiresAt time.Time) error {
+	return d.insertReplaceQuery(ctx, expiresAt, id, "JobCancel")
+}
+
+func (d *sqlDatabase) IsJobCancelRequested(ctx context.Context, id string) (bool, error) {
+	var expiresAt time.Time
+	// like for jobs, the expiration is stored in the entry itself
+	err := d.getEntry(ctx, &expiresAt, id, "JobCancel", time.Since(time.Time{}))
+	if err != nil {
+		if tholaerr.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return time.Now().Before(expiresAt), nil
+}
+
+func (d *sqlDatabase) ListDevices(ctx context.Context) ([]string, error) {
+	query, args, err := sqlx.In("SELECT DISTINCT ip FROM cache WHERE datatype IN (?) ORDER BY ip;", deviceEntryTypes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build sql query")
+	}
+	var ips []string
+	err = d.db.SelectContext(ctx, &ips, d.db.Rebind(query), args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "db select failed")
+	}
+	return ips, nil
+}
+
+func (d *sqlDatabase) GetEntries(ctx context.Context, ip string) ([]Entry, error) {
+	var results sqlSelectResults
+	err := d.db.SelectContext(ctx, &results, d.db.Rebind("SELECT time, data, datatype FROM cache WHERE ip=?;"), ip)
+	if err != nil {
+		return nil, errors.Wrap(err, "db select failed")
+	}
+
+	var entries []Entry
+	for _, entryType := range deviceEntryTypes {
+		for _, res := range results {
+			if res.Datatype != entryType {
+				continue
+			}
+			expiresAt := res.Time.Add(entryExpiration(entryType))
+			if time.Now().After(expiresAt) {
+				continue
+			}
+			entries = append(entries, Entry{
+				IP:        ip,
+				Type:      entryType,
+				ExpiresAt: expiresAt,
+				Data:      json.RawMessage(res.Data),
+			})
+		}
+	}
+	return entries, nil
+}
+
+func (d *sqlDatabase) DeleteEntries(ctx context.Context, ip string) error {
+	query, args, err := sqlx.In("DELETE FROM cache WHERE ip=? AND datatype IN (?);", ip, deviceEntryTypes)
+	if err != nil {
+		return errors.Wrap(err, "failed to build sql query")
+	}
+	_, err = d.db.ExecContext(ctx, d.db.Rebind(query), args...)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete cache entries")
+	}
+	return nil
+}
+
 func (d *sqlDatabase) CheckConnection(ctx context.Context) error {
 	return d.db.PingContext(ctx)
 }
 
 func (d *sqlDatabase) CloseConnection(ctx context.Context) error {
-	log.Ctx(ctx).Debug().Msg("closing connection to mysql database")
+	log.Ctx(ctx).Debug().Msg("closing connection to " + d.dialect.driver + " database")
 	return d.db.Close()
 }
 
@@ -93,16 +199,16 @@ func (d *sqlDatabase) insertReplaceQuery(ctx context.Context, data interface{},
 		return errors.Wrap(err, "failed to marshall data")
 	}
 
-	_, err = d.db.ExecContext(ctx, d.db.Rebind("REPLACE INTO cache (ip, datatype, data) VALUES (?, ?, ?);"), ip, dataType, string(JSONData))
+	_, err = d.db.ExecContext(ctx, d.db.Rebind(d.dialect.upsert), ip, dataType, string(JSONData), sqlTime{time.Now()})
 	if err != nil {
 		return errors.Wrap(err, "failed to exec sql query")
 	}
 	return nil
 }
 
-func (d *sqlDatabase) getEntry(ctx context.Context, dest interface{}, ip, dataType string) error {
+func (d *sqlDatabase) getEntry(ctx context.Context, dest interface{}, ip, dataType string, expiration time.Duration) error {
 	var results sqlSelectResults
-	err := d.db.SelectContext(ctx, &results, d.db.Rebind("SELECT DATE_FORMAT(time, '%Y-%m-%d %H:%i:%S') as time, data, datatype FROM cache WHERE ip=? AND datatype=?;"), ip, dataType)
+	err := d.db.SelectContext(ctx, &results, d.db.Rebind("SELECT time, data, datatype FROM cache WHERE ip=? AND datatype=?;"), ip, dataType)
 	if err != nil {
 		return errors.Wrap(err, "db select failed")
 	}
@@ -111,12 +217,8 @@ func (d *sqlDatabase) getEntry(ctx context.Context, dest interface{}, ip, dataTy
 	}
 
 	res := results[0]
-	t, err := time.Parse("2006-01-02 15:04:05", res.Time)
-	if err != nil {
-		return errors.Wrap(err, "failed to parse timestamp")
-	}
-	if time.Since(t) > cacheExpiration {
-		_, err = d.db.ExecContext(ctx, d.db.Rebind("DELETE FROM cache WHERE ip=? AND datatype=?;"), ip, "IdentifyResponse")
+	if time.Since(res.Time.Time) > expiration {
+		_, err = d.db.ExecContext(ctx, d.db.Rebind("DELETE FROM cache WHERE ip=? AND datatype=?;"), ip, dataType)
 		if err != nil {
 			return errors.Wrap(err, "failed to delete expired cache element")
 		}
@@ -0,0 +1,183 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// sqlDialect contains everything that differs between the supported sql databases.
+type sqlDialect struct {
+	// driver is the name of the go sql driver, which also determines the bind variables.
+	driver string
+	// upsert inserts or replaces the entry with the ip and datatype.
+	upsert string
+	// migrations are applied in order, the version of the schema is the amount of applied migrations.
+	// They must never be changed once released, changes of the schema need new migrations.
+	migrations []string
+}
+
+var sqlDialects = map[string]sqlDialect{
+	"mysql": {
+		driver: "mysql",
+		upsert: "REPLACE INTO cache (ip, datatype, data, time) VALUES (?, ?, ?, ?);",
+		migrations: []string{
+			// same schema as before migrations were introduced, so existing caches are kept
+			`CREATE TABLE IF NOT EXISTS cache (
+				id int(11) NOT NULL AUTO_INCREMENT PRIMARY KEY,
+				ip varchar(255) NOT NULL,
+				datatype varchar(255) NOT NULL,
+				data text NOT NULL,
+				time datetime DEFAULT current_timestamp NOT NULL,
+				CONSTRAINT unique_entries UNIQUE (ip, datatype)
+			);`,
+			// disk usage histories can exceed the 64 KiB of text columns
+			`ALTER TABLE cache MODIFY data mediumtext NOT NULL;`,
+		},
+	},
+	"postgres": {
+		driver: "postgres",
+		upsert: "INSERT INTO cache (ip, datatype, data, time) VALUES (?, ?, ?, ?) " +
+			"ON CONFLICT (ip, datatype) DO UPDATE SET data = EXCLUDED.data, time = EXCLUDED.time;",
+		migrations: []string{
+			`CREATE TABLE IF NOT EXISTS cache (
+				id SERIAL PRIMARY KEY,
+				ip varchar(255) NOT NULL,
+				datatype varchar(255) NOT NULL,
+				data text NOT NULL,
+				time timestamp NOT NULL,
+				CONSTRAINT unique_entries UNIQUE (ip, datatype)
+			);`,
+		},
+	},
+	"sqlite": {
+		driver: "sqlite",
+		upsert: "INSERT OR REPLACE INTO cache (ip, datatype, data, time) VALUES (?, ?, ?, ?);",
+		migrations: []string{
+			`CREATE TABLE IF NOT EXISTS cache (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				ip TEXT NOT NULL,
+				datatype TEXT NOT NULL,
+				data TEXT NOT NULL,
+				time DATETIME NOT NULL,
+				CONSTRAINT unique_entries UNIQUE (ip, datatype)
+			);`,
+		},
+	},
+}
+
+// migrate brings the schema of the database up to date.
+// The applied migrations are recorded in the table schema_migrations.
+// If rebuild is set, all tables are dropped first.
+func (d *sqlDatabase) migrate(ctx context.Context, rebuild bool) error {
+	if rebuild {
+		for _, table := range []string{"cache", "schema_migrations"} {
+			_, err := d.db.ExecContext(ctx, "DROP TABLE IF EXISTS "+table+";")
+			if err != nil {
+				return errors.Wrapf(err, "failed to drop table '%s'", table)
+			}
+		}
+	}
+
+	_, err := d.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version integer NOT NULL PRIMARY KEY);")
+	if err != nil {
+		return errors.Wrap(err, "failed to create migrations table")
+	}
+	var version int
+	err = d.db.GetContext(ctx, &version, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations;")
+	if err != nil {
+		return errors.Wrap(err, "failed to get schema version")
+	}
+	if version > len(d.dialect.migrations) {
+		return fmt.Errorf("schema version %d of the database is newer than the supported version %d", version, len(d.dialect.migrations))
+	}
+
+	for i := version; i < len(d.dialect.migrations); i++ {
+		err := d.applyMigration(ctx, i+1, d.dialect.migrations[i])
+		if err != nil {
+			return errors.Wrapf(err, "failed to apply migration %d", i+1)
+		}
+		log.Ctx(ctx).Debug().Int("version", i+1).Msg("applied sql migration")
+	}
+	return nil
+}
+
+// applyMigration applies the migration in a transaction. MySQL commits schema changes implicitly,
+// so failed migrations might need to be fixed manually there.
+func (d *sqlDatabase) applyMigration(ctx context.Context, version int, migration string) error {
+	tx, err := d.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to start transaction")
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx, migration)
+	if err != nil {
+		return errors.Wrap(err, "failed to exec migration")
+	}
+	_, err = tx.ExecContext(ctx, tx.Rebind("INSERT INTO schema_migrations (version) VALUES (?);"), version)
+	if err != nil {
+		return errors.Wrap(err, "failed to record migration")
+	}
+	return tx.Commit()
+}
+
+// sqlTime scans the time column, which the drivers return as time.Time or as string.
+type sqlTime struct {
+	time.Time
+}
+
+var sqlTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05",
+}
+
+func (t *sqlTime) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case time.Time:
+		t.Time = v
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return fmt.Errorf("cannot scan %T into time", src)
+	}
+	for _, layout := range sqlTimeLayouts {
+		parsed, err := time.Parse(layout, s)
+		if err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to parse time '%s'", s)
+}
+
+// Value stores times in UTC without time zone, which every dialect can compare.
+func (t sqlTime) Value() (driver.Value, error) {
+	return t.UTC().Format("2006-01-02 15:04:05"), nil
+}
+
+// openSQL connects to the sql database of the dialect.
+func openSQL(ctx context.Context, dialect sqlDialect, dataSourceName string) (*sqlx.DB, error) {
+	db, err := sqlx.ConnectContext(ctx, dialect.driver, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	if dialect.driver == "sqlite" {
+		// sqlite locks the whole database for writes
+		db.SetMaxOpenConns(1)
+	}
+	return db, nil
+}
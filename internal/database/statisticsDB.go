@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"github.com/inexio/thola/internal/device"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/tholaerr"
+	"sync"
+)
+
+var cacheStatistics struct {
+	sync.Mutex
+	network.CacheStatistics
+}
+
+// statisticsDatabase counts the cache hits and misses of the underlying database.
+type statisticsDatabase struct {
+	Database
+}
+
+func addLookup(err error) {
+	if err != nil && !tholaerr.IsNotFoundError(err) {
+		return
+	}
+	cacheStatistics.Lock()
+	defer cacheStatistics.Unlock()
+	if err == nil {
+		cacheStatistics.Hits++
+	} else {
+		cacheStatistics.Misses++
+	}
+}
+
+func (d *statisticsDatabase) GetDeviceProperties(ctx context.Context, ip string) (device.Device, error) {
+	res, err := d.Database.GetDeviceProperties(ctx, ip)
+	addLookup(err)
+	return res, err
+}
+
+func (d *statisticsDatabase) GetConnectionData(ctx context.Context, ip string) (network.ConnectionData, error) {
+	res, err := d.Database.GetConnectionData(ctx, ip)
+	addLookup(err)
+	return res, err
+}
+
+func (d *statisticsDatabase) GetDiskUsageHistory(ctx context.Context, ip string) (DiskUsageHistory, error) {
+	res, err := d.Database.GetDiskUsageHistory(ctx, ip)
+	addLookup(err)
+	return res, err
+}
+
+func (d *statisticsDatabase) GetSNMPv3EngineData(ctx context.Context, ip string) (network.SNMPv3EngineData, error) {
+	res, err := d.Database.GetSNMPv3EngineData(ctx, ip)
+	addLookup(err)
+	return res, err
+}
+
+func (d *statisticsDatabase) GetDeviceClass(ctx context.Context, ip string) (DeviceClassData, error) {
+	res, err := d.Database.GetDeviceClass(ctx, ip)
+	addLookup(err)
+	return res, err
+}
+
+// GetCacheStatistics returns the hits and misses of all database reads.
+// Reads that failed because of other errors than a missing entry are not counted.
+func GetCacheStatistics() network.CacheStatistics {
+	cacheStatistics.Lock()
+	defer cacheStatistics.Unlock()
+	return cacheStatistics.CacheStatistics
+}
@@ -288,6 +288,11 @@ type CPUComponent struct {
 type CPU struct {
 	Label *string  `yaml:"label" json:"label" xml:"label"`
 	Load  *float64 `yaml:"load" json:"load" xml:"load"`
+	// Load1, Load5 and Load15 are the cpu loads averaged over the last 1, 5 and 15 minutes,
+	// if the device exposes them.
+	Load1  *float64 `yaml:"load_1" json:"load_1" xml:"load_1" mapstructure:"load_1"`
+	Load5  *float64 `yaml:"load_5" json:"load_5" xml:"load_5" mapstructure:"load_5"`
+	Load15 *float64 `yaml:"load_15" json:"load_15" xml:"load_15" mapstructure:"load_15"`
 }
 
 // MemoryComponent
@@ -305,8 +310,15 @@ type MemoryComponent struct {
 //
 // swagger:model
 type MemoryPool struct {
-	Label                        *string  `yaml:"label" json:"label" xml:"label"`
-	Usage                        *float64 `yaml:"usage" json:"usage" xml:"usage"`
+	Label *string  `yaml:"label" json:"label" xml:"label"`
+	Usage *float64 `yaml:"usage" json:"usage" xml:"usage"`
+	// Total, Used, Free, Buffers and Cached are given in bytes.
+	// Buffers and Cached are neither part of Used nor of Free.
+	Total                        *uint64 `yaml:"total" json:"total" xml:"total"`
+	Used                         *uint64 `yaml:"used" json:"used" xml:"used"`
+	Free                         *uint64 `yaml:"free" json:"free" xml:"free"`
+	Buffers                      *uint64 `yaml:"buffers" json:"buffers" xml:"buffers"`
+	Cached                       *uint64 `yaml:"cached" json:"cached" xml:"cached"`
 	PerformanceDataPointModifier `yaml:"-" json:"-" xml:"-" human_readable:"-"`
 }
 
@@ -317,6 +329,7 @@ type MemoryPool struct {
 // swagger:model
 type DiskComponent struct {
 	Storages []DiskComponentStorage `yaml:"storages" json:"storages" xml:"storages"`
+	Inodes   []DiskComponentInodes  `yaml:"inodes" json:"inodes" xml:"inodes"`
 }
 
 // DiskComponentStorage
@@ -329,6 +342,20 @@ type DiskComponentStorage struct {
 	Description *string `yaml:"description" json:"description" xml:"description"`
 	Available   *int    `yaml:"available" json:"available" xml:"available"`
 	Used        *int    `yaml:"used" json:"used" xml:"used"`
+	// AllocationUnits is the size of one allocation unit in bytes.
+	// Available and Used are given in allocation units.
+	AllocationUnits *int `yaml:"allocation_units" json:"allocation_units" xml:"allocation_units" mapstructure:"allocation_units"`
+}
+
+// DiskComponentInodes
+//
+// DiskComponentInodes contains the inode usage of a mounted file system.
+//
+// swagger:model
+type DiskComponentInodes struct {
+	Path *string `yaml:"path" json:"path" xml:"path" mapstructure:"path"`
+	// Usage is the percentage of used inodes.
+	Usage *float64 `yaml:"usage" json:"usage" xml:"usage" mapstructure:"usage"`
 }
 
 // UPSComponent
@@ -348,6 +375,38 @@ type UPSComponent struct {
 	MainsVoltageApplied       *bool    `yaml:"mains_voltage_applied" json:"mains_voltage_applied" xml:"mains_voltage_applied"`
 	RectifierCurrent          *float64 `yaml:"rectifier_current" json:"rectifier_current" xml:"rectifier_current"`
 	SystemVoltage             *float64 `yaml:"system_voltage" json:"system_voltage" xml:"system_voltage"`
+	OutputFrequency           *float64 `yaml:"output_frequency" json:"output_frequency" xml:"output_frequency"`
+	// OutputSource is the present source of output power (normal, battery, bypass, booster, reducer, other or none).
+	OutputSource *string `yaml:"output_source" json:"output_source" xml:"output_source"`
+	// BatteryStatus is the status of the battery (unknown, normal, low, depleted or fault).
+	BatteryStatus            *string              `yaml:"battery_status" json:"battery_status" xml:"battery_status"`
+	BatteryReplacementNeeded *bool                `yaml:"battery_replacement_needed" json:"battery_replacement_needed" xml:"battery_replacement_needed"`
+	Inputs                   []UPSComponentInput  `yaml:"inputs" json:"inputs" xml:"inputs"`
+	Outputs                  []UPSComponentOutput `yaml:"outputs" json:"outputs" xml:"outputs"`
+}
+
+// UPSComponentInput
+//
+// UPSComponentInput represents an input line of a UPS component.
+//
+// swagger:model
+type UPSComponentInput struct {
+	Voltage   *float64 `yaml:"voltage" json:"voltage" xml:"voltage" mapstructure:"voltage"`
+	Frequency *float64 `yaml:"frequency" json:"frequency" xml:"frequency" mapstructure:"frequency"`
+	Current   *float64 `yaml:"current" json:"current" xml:"current" mapstructure:"current"`
+}
+
+// UPSComponentOutput
+//
+// UPSComponentOutput represents an output line of a UPS component.
+//
+// swagger:model
+type UPSComponentOutput struct {
+	Voltage *float64 `yaml:"voltage" json:"voltage" xml:"voltage" mapstructure:"voltage"`
+	Current *float64 `yaml:"current" json:"current" xml:"current" mapstructure:"current"`
+	Power   *float64 `yaml:"power" json:"power" xml:"power" mapstructure:"power"`
+	// Load is the percentage of the output capacity which is used.
+	Load *float64 `yaml:"load" json:"load" xml:"load" mapstructure:"load"`
 }
 
 // ServerComponent
@@ -358,6 +417,28 @@ type UPSComponent struct {
 type ServerComponent struct {
 	Procs *int `yaml:"procs" json:"procs" xml:"procs"`
 	Users *int `yaml:"users" json:"users" xml:"users"`
+	// Load1, Load5 and Load15 are the 1, 5 and 15 minute load averages.
+	Load1  *float64 `yaml:"load_1" json:"load_1" xml:"load_1"`
+	Load5  *float64 `yaml:"load_5" json:"load_5" xml:"load_5"`
+	Load15 *float64 `yaml:"load_15" json:"load_15" xml:"load_15"`
+	// SwapTotal and SwapFree are given in bytes.
+	SwapTotal *uint64 `yaml:"swap_total" json:"swap_total" xml:"swap_total"`
+	SwapFree  *uint64 `yaml:"swap_free" json:"swap_free" xml:"swap_free"`
+	// Uptime is given in seconds.
+	Uptime    *uint64                  `yaml:"uptime" json:"uptime" xml:"uptime"`
+	Processes []ServerComponentProcess `yaml:"processes" json:"processes" xml:"processes"`
+}
+
+// ServerComponentProcess
+//
+// ServerComponentProcess contains information per process name.
+//
+// swagger:model
+type ServerComponentProcess struct {
+	Name      *string `yaml:"name" json:"name" xml:"name" mapstructure:"name"`
+	Instances *int    `yaml:"instances" json:"instances" xml:"instances" mapstructure:"instances"`
+	// Memory is the memory used by all instances in bytes.
+	Memory *uint64 `yaml:"memory" json:"memory" xml:"memory" mapstructure:"memory"`
 }
 
 // SBCComponent
@@ -75,6 +75,19 @@ func Interface2Condition(i interface{}, task RelatedTask) (Condition, error) {
 		}
 		return &condition, nil
 	}
+	//gNMI
+	if stringType == "GNMIGet" {
+		var condition gnmiCondition
+		err := mapstructure.Decode(i, &condition)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode condition")
+		}
+		err = condition.validate()
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid gnmi condition")
+		}
+		return &condition, nil
+	}
 
 	if stringType == "Vendor" {
 		if task <= PropertyVendor {
@@ -334,6 +347,51 @@ func (s *httpCondition) validate() error {
 	return nil
 }
 
+// gnmiCondition is a condition which matches if the device returns data for a gNMI path.
+type gnmiCondition struct {
+	Type string
+	Path string
+}
+
+func (s *gnmiCondition) Check(ctx context.Context) (bool, error) {
+	logger := log.Ctx(ctx).With().Str("condition", "gnmi").Str("condition_type", s.Type).Str("path", s.Path).Logger()
+	ctx = logger.WithContext(ctx)
+
+	con, ok := network.DeviceConnectionFromContext(ctx)
+	if !ok || con.GNMI == nil || con.GNMI.GNMIClient == nil {
+		log.Ctx(ctx).Debug().Bool("condition_matched", false).Msg("no gnmi connection data available")
+		return false, nil
+	}
+	res, err := con.GNMI.GNMIClient.Get(ctx, s.Path)
+	if err != nil {
+		if tholaerr.IsNotFoundError(err) || tholaerr.IsNetworkError(err) {
+			log.Ctx(ctx).Debug().Err(err).Bool("condition_matched", false).Msg("gnmi request returned error")
+			return false, nil
+		}
+		return false, errors.Wrap(err, "non-network error during gnmi request!")
+	}
+	matched := res != nil
+	log.Ctx(ctx).Debug().Bool("condition_matched", matched).Msg("gnmi request was successful")
+	return matched, nil
+}
+
+func (s *gnmiCondition) ContainsUniqueRequest() bool {
+	return true
+}
+
+func (s *gnmiCondition) validate() error {
+	if s.Type != "GNMIGet" {
+		return errors.New("invalid condition type for gnmi condition (type = " + s.Type + ")")
+	}
+	if s.Path == "" {
+		return errors.New("path is missing")
+	}
+	if _, err := network.ParseGNMIPath(s.Path); err != nil {
+		return errors.Wrap(err, "invalid path")
+	}
+	return nil
+}
+
 // vendorCondition is a condition based on a vendor.
 type vendorCondition struct {
 	singleCondition `mapstructure:",squash"`
@@ -22,6 +22,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // deviceClass represents a device class.
@@ -73,6 +74,12 @@ type deviceClassComponentsUPS struct {
 	mainsVoltageApplied       property.Reader
 	rectifierCurrent          property.Reader
 	systemVoltage             property.Reader
+	outputFrequency           property.Reader
+	outputSource              property.Reader
+	batteryStatus             property.Reader
+	batteryReplacementNeeded  property.Reader
+	inputs                    groupproperty.Reader
+	outputs                   groupproperty.Reader
 }
 
 // deviceClassComponentsCPU represents the cpu components part of a device class.
@@ -100,13 +107,21 @@ type deviceClassComponentsSBC struct {
 
 // deviceClassComponentsServer represents the server components part of a device class.
 type deviceClassComponentsServer struct {
-	procs property.Reader
-	users property.Reader
+	procs     property.Reader
+	users     property.Reader
+	load1     property.Reader
+	load5     property.Reader
+	load15    property.Reader
+	swapTotal property.Reader
+	swapFree  property.Reader
+	uptime    property.Reader
+	processes groupproperty.Reader
 }
 
 // deviceClassComponentsDisk represents the disk component part of a device class.
 type deviceClassComponentsDisk struct {
 	properties groupproperty.Reader
+	inodes     groupproperty.Reader
 }
 
 // deviceClassComponentsHardwareHealth represents the sbc components part of a device class.
@@ -132,8 +147,20 @@ type deviceClassComponentsInterfaces struct {
 
 // deviceClassSNMP represents the snmp config part of a device class.
 type deviceClassSNMP struct {
-	MaxRepetitions uint32 `yaml:"max_repetitions"`
-	MaxOids        int    `yaml:"max_oids"`
+	MaxRepetitions        uint32        `yaml:"max_repetitions"`
+	MaxOids               int           `yaml:"max_oids"`
+	MaxConcurrentRequests int           `yaml:"max_concurrent_requests"`
+	MinRequestInterval    time.Duration `yaml:"min_request_interval"`
+	MaxRequestsPerSecond  float64       `yaml:"max_requests_per_second"`
+}
+
+// schedule returns the limits of the snmp requests sent to devices of the device class.
+func (d deviceClassSNMP) schedule() network.SNMPSchedule {
+	return network.SNMPSchedule{
+		MaxConcurrentRequests: d.MaxConcurrentRequests,
+		MinRequestInterval:    d.MinRequestInterval,
+		MaxRequestsPerSecond:  d.MaxRequestsPerSecond,
+	}
 }
 
 // yamlDeviceClass represents the structure and the parts of a yaml device class.
@@ -194,6 +221,12 @@ type yamlComponentsUPSProperties struct {
 	MainsVoltageApplied       []interface{} `yaml:"mains_voltage_applied"`
 	RectifierCurrent          []interface{} `yaml:"rectifier_current"`
 	SystemVoltage             []interface{} `yaml:"system_voltage"`
+	OutputFrequency           []interface{} `yaml:"output_frequency"`
+	OutputSource              []interface{} `yaml:"output_source"`
+	BatteryStatus             []interface{} `yaml:"battery_status"`
+	BatteryReplacementNeeded  []interface{} `yaml:"battery_replacement_needed"`
+	Inputs                    interface{}   `yaml:"inputs"`
+	Outputs                   interface{}   `yaml:"outputs"`
 }
 
 // yamlComponentsCPUProperties represents the specific properties of cpu components of a yaml device class.
@@ -221,13 +254,21 @@ type yamlComponentsSBCProperties struct {
 
 // yamlComponentsServerProperties represents the specific properties of server components of a yaml device class.
 type yamlComponentsServerProperties struct {
-	Procs []interface{} `yaml:"procs"`
-	Users []interface{} `yaml:"users"`
+	Procs     []interface{} `yaml:"procs"`
+	Users     []interface{} `yaml:"users"`
+	Load1     []interface{} `yaml:"load_1"`
+	Load5     []interface{} `yaml:"load_5"`
+	Load15    []interface{} `yaml:"load_15"`
+	SwapTotal []interface{} `yaml:"swap_total"`
+	SwapFree  []interface{} `yaml:"swap_free"`
+	Uptime    []interface{} `yaml:"uptime"`
+	Processes interface{}   `yaml:"processes"`
 }
 
 // yamlComponentsDiskProperties represents the specific properties of disk components of a yaml device class.
 type yamlComponentsDiskProperties struct {
 	Properties interface{} `yaml:"properties"`
+	Inodes     interface{} `yaml:"inodes"`
 }
 
 // yamlComponentsHardwareHealthProperties represents the specific properties of hardware health components of a yaml device class.
@@ -597,6 +638,10 @@ func (y *yamlDeviceClassConfig) convert(parentConfig deviceClassConfig) (deviceC
 		cfg.snmp.MaxRepetitions = parentConfig.snmp.MaxRepetitions
 	}
 	cfg.snmp.MaxOids = utility.IfThenElseInt(y.SNMP.MaxOids != 0, y.SNMP.MaxOids, parentConfig.snmp.MaxOids)
+	schedule := parentConfig.snmp.schedule().Override(y.SNMP.schedule())
+	cfg.snmp.MaxConcurrentRequests = schedule.MaxConcurrentRequests
+	cfg.snmp.MinRequestInterval = schedule.MinRequestInterval
+	cfg.snmp.MaxRequestsPerSecond = schedule.MaxRequestsPerSecond
 
 	components := make(map[component.Component]bool)
 	for k, v := range parentConfig.components {
@@ -620,6 +665,9 @@ func (y *yamlDeviceClassConfig) validate() error {
 	if y.SNMP.MaxOids < 0 {
 		return errors.New("invalid snmp max oids")
 	}
+	if err := y.SNMP.schedule().Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -696,6 +744,42 @@ func (y *yamlComponentsUPSProperties) convert(parentComponent *deviceClassCompon
 			return deviceClassComponentsUPS{}, errors.Wrap(err, "failed to convert system voltage property to property reader")
 		}
 	}
+	if y.OutputFrequency != nil {
+		prop.outputFrequency, err = property.InterfaceSlice2Reader(y.OutputFrequency, condition2.PropertyDefault, prop.outputFrequency)
+		if err != nil {
+			return deviceClassComponentsUPS{}, errors.Wrap(err, "failed to convert output frequency property to property reader")
+		}
+	}
+	if y.OutputSource != nil {
+		prop.outputSource, err = property.InterfaceSlice2Reader(y.OutputSource, condition2.PropertyDefault, prop.outputSource)
+		if err != nil {
+			return deviceClassComponentsUPS{}, errors.Wrap(err, "failed to convert output source property to property reader")
+		}
+	}
+	if y.BatteryStatus != nil {
+		prop.batteryStatus, err = property.InterfaceSlice2Reader(y.BatteryStatus, condition2.PropertyDefault, prop.batteryStatus)
+		if err != nil {
+			return deviceClassComponentsUPS{}, errors.Wrap(err, "failed to convert battery status property to property reader")
+		}
+	}
+	if y.BatteryReplacementNeeded != nil {
+		prop.batteryReplacementNeeded, err = property.InterfaceSlice2Reader(y.BatteryReplacementNeeded, condition2.PropertyDefault, prop.batteryReplacementNeeded)
+		if err != nil {
+			return deviceClassComponentsUPS{}, errors.Wrap(err, "failed to convert battery replacement needed property to property reader")
+		}
+	}
+	if y.Inputs != nil {
+		prop.inputs, err = groupproperty.Interface2Reader(y.Inputs, prop.inputs)
+		if err != nil {
+			return deviceClassComponentsUPS{}, errors.Wrap(err, "failed to convert inputs property to group property reader")
+		}
+	}
+	if y.Outputs != nil {
+		prop.outputs, err = groupproperty.Interface2Reader(y.Outputs, prop.outputs)
+		if err != nil {
+			return deviceClassComponentsUPS{}, errors.Wrap(err, "failed to convert outputs property to group property reader")
+		}
+	}
 	return prop, nil
 }
 
@@ -753,6 +837,48 @@ func (y *yamlComponentsServerProperties) convert(parentComponent *deviceClassCom
 			return deviceClassComponentsServer{}, errors.Wrap(err, "failed to convert users property to property reader")
 		}
 	}
+	if y.Load1 != nil {
+		prop.load1, err = property.InterfaceSlice2Reader(y.Load1, condition2.PropertyDefault, prop.load1)
+		if err != nil {
+			return deviceClassComponentsServer{}, errors.Wrap(err, "failed to convert load 1 property to property reader")
+		}
+	}
+	if y.Load5 != nil {
+		prop.load5, err = property.InterfaceSlice2Reader(y.Load5, condition2.PropertyDefault, prop.load5)
+		if err != nil {
+			return deviceClassComponentsServer{}, errors.Wrap(err, "failed to convert load 5 property to property reader")
+		}
+	}
+	if y.Load15 != nil {
+		prop.load15, err = property.InterfaceSlice2Reader(y.Load15, condition2.PropertyDefault, prop.load15)
+		if err != nil {
+			return deviceClassComponentsServer{}, errors.Wrap(err, "failed to convert load 15 property to property reader")
+		}
+	}
+	if y.SwapTotal != nil {
+		prop.swapTotal, err = property.InterfaceSlice2Reader(y.SwapTotal, condition2.PropertyDefault, prop.swapTotal)
+		if err != nil {
+			return deviceClassComponentsServer{}, errors.Wrap(err, "failed to convert swap total property to property reader")
+		}
+	}
+	if y.SwapFree != nil {
+		prop.swapFree, err = property.InterfaceSlice2Reader(y.SwapFree, condition2.PropertyDefault, prop.swapFree)
+		if err != nil {
+			return deviceClassComponentsServer{}, errors.Wrap(err, "failed to convert swap free property to property reader")
+		}
+	}
+	if y.Uptime != nil {
+		prop.uptime, err = property.InterfaceSlice2Reader(y.Uptime, condition2.PropertyDefault, prop.uptime)
+		if err != nil {
+			return deviceClassComponentsServer{}, errors.Wrap(err, "failed to convert uptime property to property reader")
+		}
+	}
+	if y.Processes != nil {
+		prop.processes, err = groupproperty.Interface2Reader(y.Processes, prop.processes)
+		if err != nil {
+			return deviceClassComponentsServer{}, errors.Wrap(err, "failed to convert processes property to group property reader")
+		}
+	}
 	return prop, nil
 }
 
@@ -770,6 +896,12 @@ func (y *yamlComponentsDiskProperties) convert(parentDisk *deviceClassComponents
 			return deviceClassComponentsDisk{}, errors.Wrap(err, "failed to convert storages property to group property reader")
 		}
 	}
+	if y.Inodes != nil {
+		prop.inodes, err = groupproperty.Interface2Reader(y.Inodes, prop.inodes)
+		if err != nil {
+			return deviceClassComponentsDisk{}, errors.Wrap(err, "failed to convert inodes property to group property reader")
+		}
+	}
 	return prop, nil
 }
 
@@ -58,6 +58,8 @@ func (o *deviceClassCommunicator) UpdateConnection(ctx context.Context) error {
 				conn.SNMP.SnmpClient.SetMaxRepetitions(o.deviceClass.config.snmp.MaxRepetitions)
 			}
 
+			conn.SNMP.SnmpClient.SetSchedule(network.GetDefaultSNMPSchedule().Override(o.deviceClass.config.snmp.schedule()))
+
 			if conn.SNMP.SnmpClient.GetVersion() != "1" {
 				log.Ctx(ctx).Debug().Int("max_oids", o.deviceClass.config.snmp.MaxOids).Msg("set snmp max oids of device class")
 				err := conn.SNMP.SnmpClient.SetMaxOIDs(o.deviceClass.config.snmp.MaxOids)
@@ -147,6 +149,16 @@ func (o *deviceClassCommunicator) GetDiskComponent(ctx context.Context) (device.
 		empty = false
 	}
 
+	inodes, err := o.GetDiskComponentInodes(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.DiskComponent{}, errors.Wrap(err, "error occurred during get disk component inodes")
+		}
+	} else {
+		disk.Inodes = inodes
+		empty = false
+	}
+
 	if empty {
 		return device.DiskComponent{}, tholaerr.NewNotFoundError("no disk data available")
 	}
@@ -272,6 +284,66 @@ func (o *deviceClassCommunicator) GetUPSComponent(ctx context.Context) (device.U
 		empty = false
 	}
 
+	outputFrequency, err := o.GetUPSComponentOutputFrequency(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.UPSComponent{}, errors.Wrap(err, "error occurred during get output frequency")
+		}
+	} else {
+		ups.OutputFrequency = &outputFrequency
+		empty = false
+	}
+
+	outputSource, err := o.GetUPSComponentOutputSource(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.UPSComponent{}, errors.Wrap(err, "error occurred during get output source")
+		}
+	} else {
+		ups.OutputSource = &outputSource
+		empty = false
+	}
+
+	batteryStatus, err := o.GetUPSComponentBatteryStatus(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.UPSComponent{}, errors.Wrap(err, "error occurred during get battery status")
+		}
+	} else {
+		ups.BatteryStatus = &batteryStatus
+		empty = false
+	}
+
+	batteryReplacementNeeded, err := o.GetUPSComponentBatteryReplacementNeeded(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.UPSComponent{}, errors.Wrap(err, "error occurred during get battery replacement needed")
+		}
+	} else {
+		ups.BatteryReplacementNeeded = &batteryReplacementNeeded
+		empty = false
+	}
+
+	inputs, err := o.GetUPSComponentInputs(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.UPSComponent{}, errors.Wrap(err, "error occurred during get inputs")
+		}
+	} else {
+		ups.Inputs = inputs
+		empty = false
+	}
+
+	outputs, err := o.GetUPSComponentOutputs(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.UPSComponent{}, errors.Wrap(err, "error occurred during get outputs")
+		}
+	} else {
+		ups.Outputs = outputs
+		empty = false
+	}
+
 	if empty {
 		return device.UPSComponent{}, tholaerr.NewNotFoundError("no ups data available")
 	}
@@ -307,6 +379,76 @@ func (o *deviceClassCommunicator) GetServerComponent(ctx context.Context) (devic
 		empty = false
 	}
 
+	load1, err := o.GetServerComponentLoad1(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component load 1")
+		}
+	} else {
+		server.Load1 = &load1
+		empty = false
+	}
+
+	load5, err := o.GetServerComponentLoad5(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component load 5")
+		}
+	} else {
+		server.Load5 = &load5
+		empty = false
+	}
+
+	load15, err := o.GetServerComponentLoad15(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component load 15")
+		}
+	} else {
+		server.Load15 = &load15
+		empty = false
+	}
+
+	swapTotal, err := o.GetServerComponentSwapTotal(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component swap total")
+		}
+	} else {
+		server.SwapTotal = &swapTotal
+		empty = false
+	}
+
+	swapFree, err := o.GetServerComponentSwapFree(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component swap free")
+		}
+	} else {
+		server.SwapFree = &swapFree
+		empty = false
+	}
+
+	uptime, err := o.GetServerComponentUptime(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component uptime")
+		}
+	} else {
+		server.Uptime = &uptime
+		empty = false
+	}
+
+	processes, err := o.GetServerComponentProcesses(ctx)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) && !tholaerr.IsNotImplementedError(err) {
+			return device.ServerComponent{}, errors.Wrap(err, "error occurred during get server component processes")
+		}
+	} else {
+		server.Processes = processes
+		empty = false
+	}
+
 	if empty {
 		return device.ServerComponent{}, tholaerr.NewNotFoundError("no server data available")
 	}
@@ -653,6 +795,14 @@ func (o *deviceClassCommunicator) GetMemoryComponentMemoryUsage(ctx context.Cont
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to decode group properties into CPUs array")
 	}
+
+	// calculate the usage if the device class only contains absolute values
+	for i, pool := range pools {
+		if pool.Usage == nil && pool.Used != nil && pool.Total != nil && *pool.Total > 0 {
+			usage := math.Round(float64(*pool.Used)/float64(*pool.Total)*10000) / 100
+			pools[i].Usage = &usage
+		}
+	}
 	return pools, nil
 }
 
@@ -682,6 +832,25 @@ func (o *deviceClassCommunicator) GetDiskComponentStorages(ctx context.Context)
 	return filtered, nil
 }
 
+func (o *deviceClassCommunicator) GetDiskComponentInodes(ctx context.Context) ([]device.DiskComponentInodes, error) {
+	if o.components.disk == nil || o.components.disk.inodes == nil {
+		log.Ctx(ctx).Debug().Str("groupProperty", "DiskComponentInodes").Str("device_class", o.name).Msg("no detection information available")
+		return nil, tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("groupProperty", "DiskComponentInodes").Logger()
+	ctx = logger.WithContext(ctx)
+	res, _, err := o.components.disk.inodes.GetProperty(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get property")
+	}
+	var inodes []device.DiskComponentInodes
+	err = mapstructure.WeakDecode(res, &inodes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode property into inodes struct")
+	}
+	return inodes, nil
+}
+
 func (o *deviceClassCommunicator) GetUPSComponentAlarmLowVoltageDisconnect(ctx context.Context) (int, error) {
 	if o.components.ups == nil || o.components.ups.alarmLowVoltageDisconnect == nil {
 		log.Ctx(ctx).Debug().Str("property", "UPSComponentAlarmLowVoltageDisconnect").Str("device_class", o.name).Msg("no detection information available")
@@ -891,6 +1060,112 @@ func (o *deviceClassCommunicator) GetUPSComponentSystemVoltage(ctx context.Conte
 	return result, nil
 }
 
+func (o *deviceClassCommunicator) GetUPSComponentOutputFrequency(ctx context.Context) (float64, error) {
+	if o.components.ups == nil || o.components.ups.outputFrequency == nil {
+		log.Ctx(ctx).Debug().Str("property", "UPSComponentOutputFrequency").Str("device_class", o.name).Msg("no detection information available")
+		return 0, tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("property", "UPSComponentOutputFrequency").Logger()
+	ctx = logger.WithContext(ctx)
+	res, err := o.components.ups.outputFrequency.GetProperty(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get property")
+		return 0, errors.Wrap(err, "failed to get UPSComponentOutputFrequency")
+	}
+	result, err := res.Float64()
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to convert result '%v' to float64", res)
+	}
+	return result, nil
+}
+
+func (o *deviceClassCommunicator) GetUPSComponentOutputSource(ctx context.Context) (string, error) {
+	if o.components.ups == nil || o.components.ups.outputSource == nil {
+		log.Ctx(ctx).Debug().Str("property", "UPSComponentOutputSource").Str("device_class", o.name).Msg("no detection information available")
+		return "", tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("property", "UPSComponentOutputSource").Logger()
+	ctx = logger.WithContext(ctx)
+	res, err := o.components.ups.outputSource.GetProperty(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get property")
+		return "", errors.Wrap(err, "failed to get UPSComponentOutputSource")
+	}
+	return strings.TrimSpace(res.String()), nil
+}
+
+func (o *deviceClassCommunicator) GetUPSComponentBatteryStatus(ctx context.Context) (string, error) {
+	if o.components.ups == nil || o.components.ups.batteryStatus == nil {
+		log.Ctx(ctx).Debug().Str("property", "UPSComponentBatteryStatus").Str("device_class", o.name).Msg("no detection information available")
+		return "", tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("property", "UPSComponentBatteryStatus").Logger()
+	ctx = logger.WithContext(ctx)
+	res, err := o.components.ups.batteryStatus.GetProperty(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get property")
+		return "", errors.Wrap(err, "failed to get UPSComponentBatteryStatus")
+	}
+	return strings.TrimSpace(res.String()), nil
+}
+
+func (o *deviceClassCommunicator) GetUPSComponentBatteryReplacementNeeded(ctx context.Context) (bool, error) {
+	if o.components.ups == nil || o.components.ups.batteryReplacementNeeded == nil {
+		log.Ctx(ctx).Debug().Str("property", "UPSComponentBatteryReplacementNeeded").Str("device_class", o.name).Msg("no detection information available")
+		return false, tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("property", "UPSComponentBatteryReplacementNeeded").Logger()
+	ctx = logger.WithContext(ctx)
+	res, err := o.components.ups.batteryReplacementNeeded.GetProperty(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get property")
+		return false, errors.Wrap(err, "failed to get UPSComponentBatteryReplacementNeeded")
+	}
+	result, err := res.Bool()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to convert result '%v' to bool", res)
+	}
+	return result, nil
+}
+
+func (o *deviceClassCommunicator) GetUPSComponentInputs(ctx context.Context) ([]device.UPSComponentInput, error) {
+	if o.components.ups == nil || o.components.ups.inputs == nil {
+		log.Ctx(ctx).Debug().Str("groupProperty", "UPSComponentInputs").Str("device_class", o.name).Msg("no detection information available")
+		return nil, tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("groupProperty", "UPSComponentInputs").Logger()
+	ctx = logger.WithContext(ctx)
+	res, _, err := o.components.ups.inputs.GetProperty(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get property")
+	}
+	var result []device.UPSComponentInput
+	err = res.Decode(&result)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode property into inputs struct")
+	}
+	return result, nil
+}
+
+func (o *deviceClassCommunicator) GetUPSComponentOutputs(ctx context.Context) ([]device.UPSComponentOutput, error) {
+	if o.components.ups == nil || o.components.ups.outputs == nil {
+		log.Ctx(ctx).Debug().Str("groupProperty", "UPSComponentOutputs").Str("device_class", o.name).Msg("no detection information available")
+		return nil, tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("groupProperty", "UPSComponentOutputs").Logger()
+	ctx = logger.WithContext(ctx)
+	res, _, err := o.components.ups.outputs.GetProperty(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get property")
+	}
+	var result []device.UPSComponentOutput
+	err = res.Decode(&result)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode property into outputs struct")
+	}
+	return result, nil
+}
+
 func (o *deviceClassCommunicator) GetSBCComponentAgents(ctx context.Context) ([]device.SBCComponentAgent, error) {
 	if o.components.sbc == nil || o.components.sbc.agents == nil {
 		log.Ctx(ctx).Debug().Str("groupProperty", "SBCComponentAgents").Str("device_class", o.name).Msg("no detection information available")
@@ -1100,6 +1375,176 @@ func (o *deviceClassCommunicator) GetServerComponentUsers(ctx context.Context) (
 	return r, nil
 }
 
+func (o *deviceClassCommunicator) GetServerComponentLoad1(ctx context.Context) (float64, error) {
+	if o.components.server == nil || o.components.server.load1 == nil {
+		log.Ctx(ctx).Debug().Str("property", "ServerComponentLoad1").Str("device_class", o.name).Msg("no detection information available")
+		return 0, tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("property", "ServerComponentLoad1").Logger()
+	ctx = logger.WithContext(ctx)
+	res, err := o.components.server.load1.GetProperty(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get property")
+		return 0, errors.Wrap(err, "failed to get ServerComponentLoad1")
+	}
+	result, err := res.Float64()
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to convert result '%v' to float64", res)
+	}
+	return result, nil
+}
+
+func (o *deviceClassCommunicator) GetServerComponentLoad5(ctx context.Context) (float64, error) {
+	if o.components.server == nil || o.components.server.load5 == nil {
+		log.Ctx(ctx).Debug().Str("property", "ServerComponentLoad5").Str("device_class", o.name).Msg("no detection information available")
+		return 0, tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("property", "ServerComponentLoad5").Logger()
+	ctx = logger.WithContext(ctx)
+	res, err := o.components.server.load5.GetProperty(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get property")
+		return 0, errors.Wrap(err, "failed to get ServerComponentLoad5")
+	}
+	result, err := res.Float64()
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to convert result '%v' to float64", res)
+	}
+	return result, nil
+}
+
+func (o *deviceClassCommunicator) GetServerComponentLoad15(ctx context.Context) (float64, error) {
+	if o.components.server == nil || o.components.server.load15 == nil {
+		log.Ctx(ctx).Debug().Str("property", "ServerComponentLoad15").Str("device_class", o.name).Msg("no detection information available")
+		return 0, tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("property", "ServerComponentLoad15").Logger()
+	ctx = logger.WithContext(ctx)
+	res, err := o.components.server.load15.GetProperty(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get property")
+		return 0, errors.Wrap(err, "failed to get ServerComponentLoad15")
+	}
+	result, err := res.Float64()
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to convert result '%v' to float64", res)
+	}
+	return result, nil
+}
+
+func (o *deviceClassCommunicator) GetServerComponentSwapTotal(ctx context.Context) (uint64, error) {
+	if o.components.server == nil || o.components.server.swapTotal == nil {
+		log.Ctx(ctx).Debug().Str("property", "ServerComponentSwapTotal").Str("device_class", o.name).Msg("no detection information available")
+		return 0, tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("property", "ServerComponentSwapTotal").Logger()
+	ctx = logger.WithContext(ctx)
+	res, err := o.components.server.swapTotal.GetProperty(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get property")
+		return 0, errors.Wrap(err, "failed to get ServerComponentSwapTotal")
+	}
+	result, err := res.UInt64()
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to convert result '%v' to uint64", res)
+	}
+	return result, nil
+}
+
+func (o *deviceClassCommunicator) GetServerComponentSwapFree(ctx context.Context) (uint64, error) {
+	if o.components.server == nil || o.components.server.swapFree == nil {
+		log.Ctx(ctx).Debug().Str("property", "ServerComponentSwapFree").Str("device_class", o.name).Msg("no detection information available")
+		return 0, tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("property", "ServerComponentSwapFree").Logger()
+	ctx = logger.WithContext(ctx)
+	res, err := o.components.server.swapFree.GetProperty(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get property")
+		return 0, errors.Wrap(err, "failed to get ServerComponentSwapFree")
+	}
+	result, err := res.UInt64()
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to convert result '%v' to uint64", res)
+	}
+	return result, nil
+}
+
+func (o *deviceClassCommunicator) GetServerComponentUptime(ctx context.Context) (uint64, error) {
+	if o.components.server == nil || o.components.server.uptime == nil {
+		log.Ctx(ctx).Debug().Str("property", "ServerComponentUptime").Str("device_class", o.name).Msg("no detection information available")
+		return 0, tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("property", "ServerComponentUptime").Logger()
+	ctx = logger.WithContext(ctx)
+	res, err := o.components.server.uptime.GetProperty(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get property")
+		return 0, errors.Wrap(err, "failed to get ServerComponentUptime")
+	}
+	result, err := res.UInt64()
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to convert result '%v' to uint64", res)
+	}
+	return result, nil
+}
+
+func (o *deviceClassCommunicator) GetServerComponentProcesses(ctx context.Context) ([]device.ServerComponentProcess, error) {
+	if o.components.server == nil || o.components.server.processes == nil {
+		log.Ctx(ctx).Debug().Str("groupProperty", "ServerComponentProcesses").Str("device_class", o.name).Msg("no detection information available")
+		return nil, tholaerr.NewNotImplementedError("no detection information available")
+	}
+	logger := log.Ctx(ctx).With().Str("groupProperty", "ServerComponentProcesses").Logger()
+	ctx = logger.WithContext(ctx)
+	res, _, err := o.components.server.processes.GetProperty(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get property")
+	}
+	var entries []device.ServerComponentProcess
+	err = res.Decode(&entries)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode property into process struct")
+	}
+
+	return mergeServerComponentProcesses(entries), nil
+}
+
+// mergeServerComponentProcesses merges the entries of the process table, which contains one entry per
+// running instance, into one process per name. Instances and memory are summed up.
+func mergeServerComponentProcesses(entries []device.ServerComponentProcess) []device.ServerComponentProcess {
+	var processes []device.ServerComponentProcess
+	positions := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Name == nil {
+			continue
+		}
+		instances := 1
+		if entry.Instances != nil {
+			instances = *entry.Instances
+		}
+
+		pos, ok := positions[*entry.Name]
+		if !ok {
+			entry.Instances = &instances
+			positions[*entry.Name] = len(processes)
+			processes = append(processes, entry)
+			continue
+		}
+
+		process := &processes[pos]
+		sum := *process.Instances + instances
+		process.Instances = &sum
+		if entry.Memory != nil {
+			memory := *entry.Memory
+			if process.Memory != nil {
+				memory += *process.Memory
+			}
+			process.Memory = &memory
+		}
+	}
+	return processes
+}
+
 func (o *deviceClassCommunicator) GetHardwareHealthComponentEnvironmentMonitorState(ctx context.Context) (device.HardwareHealthComponentState, error) {
 	if o.components.hardwareHealth == nil || o.components.hardwareHealth.environmentMonitorState == nil {
 		log.Ctx(ctx).Debug().Str("property", "HardwareHealthComponentEnvironmentMonitorState").Str("device_class", o.name).Msg("no detection information available")
@@ -0,0 +1,31 @@
+package deviceclass
+
+import (
+	"github.com/inexio/thola/internal/device"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMergeServerComponentProcesses(t *testing.T) {
+	name := func(s string) *string { return &s }
+	number := func(i int) *int { return &i }
+	bytes := func(b uint64) *uint64 { return &b }
+
+	entries := []device.ServerComponentProcess{
+		{Name: name("sshd"), Memory: bytes(100)},
+		{Name: name("nginx"), Memory: bytes(1000)},
+		{Name: nil, Memory: bytes(5)},
+		{Name: name("sshd"), Memory: bytes(200)},
+		{Name: name("nginx"), Instances: number(3)},
+		{Name: name("cron")},
+		{Name: name("sshd")},
+	}
+
+	expected := []device.ServerComponentProcess{
+		{Name: name("sshd"), Instances: number(3), Memory: bytes(300)},
+		{Name: name("nginx"), Instances: number(4), Memory: bytes(1000)},
+		{Name: name("cron"), Instances: number(1)},
+	}
+	assert.Equal(t, expected, mergeServerComponentProcesses(entries))
+	assert.Nil(t, mergeServerComponentProcesses(nil))
+}
@@ -1,11 +1,172 @@
 package deviceclass
 
 import (
+	"context"
+	"github.com/inexio/thola/internal/component"
+	"github.com/inexio/thola/internal/device"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/network/gnmitest"
+	"github.com/inexio/thola/internal/network/redfishtest"
+	"github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
 	"testing"
+	"time"
 )
 
 func TestDeviceClass_GetHierarchy(t *testing.T) {
 	_, err := GetHierarchy()
 	assert.NoError(t, err, "hierarchy building failed")
 }
+
+func TestDeviceClass_redfish(t *testing.T) {
+	server := redfishtest.NewServer(redfishtest.Resources())
+	defer server.Close()
+	httpClient, err := network.NewHTTPClient(server.URL())
+	require.NoError(t, err)
+	ctx := network.NewContextWithDeviceConnection(context.Background(), &network.RequestDeviceConnection{
+		HTTP: &network.RequestDeviceConnectionHTTP{
+			HTTPClient:     httpClient,
+			ConnectionData: &network.HTTPConnectionData{HTTPPorts: []int{server.Port()}},
+		},
+	})
+
+	hier, err := GetHierarchy()
+	require.NoError(t, err)
+	redfish, ok := hier.Children["redfish"]
+	require.True(t, ok)
+	com := redfish.NetworkDeviceCommunicator
+
+	match, err := com.Match(ctx)
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	properties, err := com.GetIdentifyProperties(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "Test Inc.", *properties.Vendor)
+	assert.Equal(t, "TestServer R100", *properties.Model)
+	assert.Equal(t, "SN12345", *properties.SerialNumber)
+
+	assert.False(t, com.HasComponent(component.Interfaces))
+	hardwareHealth, err := com.GetHardwareHealthComponent(ctx)
+	require.NoError(t, err)
+
+	stringPtr := func(s string) *string { return &s }
+	normal := device.HardwareHealthComponentStateNormal
+	warning := device.HardwareHealthComponentStateWarning
+	critical := device.HardwareHealthComponentStateCritical
+	assert.Equal(t, &warning, hardwareHealth.EnvironmentMonitorState)
+	assert.Equal(t, []device.HardwareHealthComponentFan{
+		{Description: stringPtr("Fan1"), State: &normal},
+		{Description: stringPtr("Fan2"), State: &critical},
+	}, hardwareHealth.Fans)
+	assert.Equal(t, []device.HardwareHealthComponentPowerSupply{
+		{Description: stringPtr("PSU1"), State: &normal},
+		{Description: stringPtr("PSU2")},
+	}, hardwareHealth.PowerSupply)
+	require.Len(t, hardwareHealth.Temperature, 2)
+	assert.Equal(t, 45.5, *hardwareHealth.Temperature[0].Temperature)
+	assert.Equal(t, &warning, hardwareHealth.Temperature[1].State)
+	require.Len(t, hardwareHealth.Voltage, 1)
+	assert.Equal(t, 230.0, *hardwareHealth.Voltage[0].Voltage)
+}
+
+func TestDeviceClass_junosOpenconfig(t *testing.T) {
+	update := func(path string, val *gnmi.TypedValue) *gnmi.Update {
+		p, err := network.ParseGNMIPath(path)
+		require.NoError(t, err)
+		return &gnmi.Update{Path: p, Val: val}
+	}
+	str := func(s string) *gnmi.TypedValue {
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: s}}
+	}
+	unsigned := func(u uint64) *gnmi.TypedValue { return &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: u}} }
+	server, err := gnmitest.NewServer(
+		update("/interfaces/interface[name=ge-0/0/0]/state/ifindex", unsigned(513)),
+		update("/interfaces/interface[name=ge-0/0/0]/state/type", str("iana-if-type:ethernetCsmacd")),
+		update("/interfaces/interface[name=ge-0/0/0]/state/admin-status", str("UP")),
+		update("/interfaces/interface[name=ge-0/0/0]/state/oper-status", str("LOWER_LAYER_DOWN")),
+		update("/interfaces/interface[name=ge-0/0/0]/state/counters/in-octets", unsigned(100)),
+		update("/interfaces/interface[name=ge-0/0/0]/ethernet/state/mac-address", str("00:11:22:33:44:55")),
+		update("/components/component[name=FPC0]/state/type", str("openconfig-platform-types:FAN")),
+		update("/components/component[name=FPC0]/state/oper-status", str("openconfig-platform-types:ACTIVE")),
+		update("/components/component[name=PEM0]/state/type", str("openconfig-platform-types:POWER_SUPPLY")),
+		update("/components/component[name=PEM0]/state/oper-status", str("openconfig-platform-types:INACTIVE")),
+		update("/components/component[name=RE0]/state/type", str("openconfig-platform-types:CPU")),
+		update("/components/component[name=RE0]/state/temperature/instant", str("45.5")),
+		update("/components/component[name=RE0]/state/temperature/alarm-status", &gnmi.TypedValue{Value: &gnmi.TypedValue_BoolVal{BoolVal: true}}),
+	)
+	require.NoError(t, err)
+	defer server.Close()
+	gnmiClient, err := network.NewGNMIClient(server.Addr().IP.String(), server.Addr().Port, "", "", true)
+	require.NoError(t, err)
+	defer gnmiClient.Disconnect()
+	ctx := network.NewContextWithDeviceConnection(context.Background(), &network.RequestDeviceConnection{
+		GNMI: &network.RequestDeviceConnectionGNMI{
+			GNMIClient:     gnmiClient,
+			ConnectionData: &network.GNMIConnectionData{},
+		},
+	})
+
+	hier, err := GetHierarchy()
+	require.NoError(t, err)
+	openconfig, ok := hier.Children["junos"].Children["junos/openconfig"]
+	require.True(t, ok)
+	com := openconfig.NetworkDeviceCommunicator
+
+	match, err := com.Match(ctx)
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	interfaces, err := com.GetInterfaces(ctx)
+	require.NoError(t, err)
+	require.Len(t, interfaces, 1)
+	assert.Equal(t, uint64(513), *interfaces[0].IfIndex)
+	assert.Equal(t, "ge-0/0/0", *interfaces[0].IfDescr)
+	assert.Equal(t, "ethernetCsmacd", *interfaces[0].IfType)
+	assert.Equal(t, "00:11:22:33:44:55", *interfaces[0].IfPhysAddress)
+	assert.Equal(t, device.Status("up"), *interfaces[0].IfAdminStatus)
+	assert.Equal(t, device.Status("lowerLayerDown"), *interfaces[0].IfOperStatus)
+	assert.Equal(t, uint64(100), *interfaces[0].IfHCInOctets)
+
+	hardwareHealth, err := com.GetHardwareHealthComponent(ctx)
+	require.NoError(t, err)
+
+	stringPtr := func(s string) *string { return &s }
+	normal := device.HardwareHealthComponentStateNormal
+	notFunctioning := device.HardwareHealthComponentStateNotFunctioning
+	critical := device.HardwareHealthComponentStateCritical
+	assert.Equal(t, []device.HardwareHealthComponentFan{
+		{Description: stringPtr("FPC0"), State: &normal},
+	}, hardwareHealth.Fans)
+	assert.Equal(t, []device.HardwareHealthComponentPowerSupply{
+		{Description: stringPtr("PEM0"), State: &notFunctioning},
+	}, hardwareHealth.PowerSupply)
+	temperature := 45.5
+	assert.Equal(t, []device.HardwareHealthComponentTemperature{
+		{Description: stringPtr("RE0"), Temperature: &temperature, State: &critical},
+	}, hardwareHealth.Temperature)
+}
+
+func TestDeviceClassConfig_snmpSchedule(t *testing.T) {
+	var parent yamlDeviceClassConfig
+	require.NoError(t, yaml.Unmarshal([]byte("snmp:\n  max_concurrent_requests: 1\n  min_request_interval: 50ms\n"), &parent))
+	parentConfig, err := parent.convert(deviceClassConfig{})
+	require.NoError(t, err)
+
+	var child yamlDeviceClassConfig
+	require.NoError(t, yaml.Unmarshal([]byte("snmp:\n  max_requests_per_second: 10\n"), &child))
+	cfg, err := child.convert(parentConfig)
+	require.NoError(t, err)
+	assert.Equal(t, network.SNMPSchedule{
+		MaxConcurrentRequests: 1,
+		MinRequestInterval:    50 * time.Millisecond,
+		MaxRequestsPerSecond:  10,
+	}, cfg.snmp.schedule())
+
+	var invalid yamlDeviceClassConfig
+	require.NoError(t, yaml.Unmarshal([]byte("snmp:\n  max_concurrent_requests: -1\n"), &invalid))
+	_, err = invalid.convert(parentConfig)
+	assert.Error(t, err)
+}
@@ -0,0 +1,332 @@
+package groupproperty
+
+import (
+	"context"
+	"fmt"
+	relatedTask "github.com/inexio/thola/internal/deviceclass/condition"
+	"github.com/inexio/thola/internal/deviceclass/property"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/inexio/thola/internal/value"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"regexp"
+	"strconv"
+)
+
+func interface2DocumentReader(m map[interface{}]interface{}, detection string, parentReader Reader) (Reader, error) {
+	request, err := network.NewDocumentRequest(detection)
+	if err != nil {
+		return nil, err
+	}
+	err = mapstructure.Decode(m, request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s request", detection)
+	}
+	err = request.Validate()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s request", detection)
+	}
+
+	var index *network.DocumentExpression
+	if idx, ok := m["index"]; ok {
+		index, err = interface2DocumentExpression(idx, request.IsXPath())
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid index")
+		}
+	}
+
+	if _, ok := m["values"]; !ok {
+		return nil, errors.New("values are missing")
+	}
+	values, err := interface2DocumentValues(m["values"], request.IsXPath())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s values", detection)
+	}
+
+	inheritValuesFromParent := true
+	if b, ok := m["inherit_values"]; ok {
+		bb, ok := b.(bool)
+		if !ok {
+			return nil, errors.New("inherit_values needs to be a boolean")
+		}
+		inheritValuesFromParent = bb
+	}
+
+	//overwrite parent
+	if inheritValuesFromParent && parentReader != nil {
+		parentBaseReader, ok := parentReader.(*baseReader)
+		if !ok {
+			return nil, errors.New("parent group property reader is not of type base group property reader")
+		}
+
+		parentDocumentReader, ok := parentBaseReader.reader.(*documentReader)
+		if !ok || parentDocumentReader.detection != detection {
+			return nil, fmt.Errorf("can't merge %s group property reader with property reader of different type", detection)
+		}
+		if parentDocumentReader.IsXPath() != request.IsXPath() {
+			return nil, fmt.Errorf("can't merge %s group property readers with different expression types", detection)
+		}
+
+		values = parentDocumentReader.values.merge(values)
+		if index == nil {
+			index = parentDocumentReader.index
+		}
+	}
+
+	return &baseReader{
+		reader: &documentReader{
+			detection: detection,
+			request:   request,
+			index:     index,
+			values:    values,
+		},
+	}, nil
+}
+
+func interface2DocumentExpression(i interface{}, xpath bool) (*network.DocumentExpression, error) {
+	s, ok := i.(string)
+	if !ok {
+		return nil, errors.New("expression needs to be a string")
+	}
+	var expression network.DocumentExpression
+	if xpath {
+		expression.XPath = s
+	} else {
+		expression.JSONPath = s
+	}
+	err := expression.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return &expression, nil
+}
+
+func interface2DocumentValues(i interface{}, xpath bool) (documentValues, error) {
+	values, ok := i.(map[interface{}]interface{})
+	if !ok {
+		return nil, errors.New("values needs to be a map")
+	}
+
+	result := make(documentValues)
+	for val, data := range values {
+		valString, ok := val.(string)
+		if !ok {
+			return nil, errors.New("key of document property reader must be a string")
+		}
+
+		// a string is a shorthand for a value which only consists of an expression
+		if _, ok := data.(string); ok {
+			expression, err := interface2DocumentExpression(data, xpath)
+			if err != nil {
+				return nil, errors.Wrapf(err, "value reader for %s is invalid", valString)
+			}
+			result[valString] = &documentValue{expression: *expression}
+			continue
+		}
+
+		dataMap, ok := data.(map[interface{}]interface{})
+		if !ok {
+			return nil, errors.New("value data needs to be a map or an expression")
+		}
+
+		if v, ok := dataMap["values"]; ok {
+			if len(dataMap) != 1 {
+				return nil, errors.New("value with subvalues has to many keys")
+			}
+			reader, err := interface2DocumentValues(v, xpath)
+			if err != nil {
+				return nil, err
+			}
+			result[valString] = reader
+			continue
+		}
+
+		if ignore, ok := dataMap["ignore"]; ok {
+			if b, ok := ignore.(bool); ok && b {
+				result[valString] = &emptyDocumentValue{}
+				continue
+			}
+		}
+
+		var y yamlDocumentValue
+		err := mapstructure.Decode(data, &y)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode values map to yamlDocumentValue")
+		}
+		err = y.Validate()
+		if err != nil {
+			return nil, errors.Wrapf(err, "value reader for %s is invalid", valString)
+		}
+		if y.IsXPath() != xpath {
+			return nil, fmt.Errorf("value reader for %s has to use the same expression type as the group reader", valString)
+		}
+		reader := documentValue{
+			expression: y.DocumentExpression,
+		}
+		if y.Operators != nil {
+			reader.operators, err = property.InterfaceSlice2Operators(y.Operators, relatedTask.PropertyDefault)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to read yaml document value operators")
+			}
+		}
+		result[valString] = &reader
+	}
+	return result, nil
+}
+
+type yamlDocumentValue struct {
+	network.DocumentExpression `mapstructure:",squash"`
+	Operators                  []interface{}
+}
+
+// documentReader reads groups from a http, gnmi, netconf or redfish response.
+// The expression of the request selects the groups, the expressions of the values are relative to each group.
+type documentReader struct {
+	detection string
+	request   network.DocumentRequest
+	index     *network.DocumentExpression
+	values    documentValues
+	filters   []documentFilter
+}
+
+// documentFilter filters out groups whose value matches the regex.
+type documentFilter struct {
+	key   string
+	regex *regexp.Regexp
+	value *documentValue
+}
+
+func (d documentReader) IsXPath() bool {
+	return d.request.IsXPath()
+}
+
+func (d documentReader) getProperty(ctx context.Context) (PropertyGroups, []value.Value, error) {
+	doc, err := d.request.Request(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "%s request failed", d.detection)
+	}
+
+	groups, err := d.request.Query(doc)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read groups")
+	}
+
+	var res PropertyGroups
+	var indices []value.Value
+
+groups:
+	for i, group := range groups {
+		index := value.New(strconv.Itoa(i + 1))
+		if d.index != nil {
+			index, err = d.index.QueryValue(group)
+			if err != nil {
+				log.Ctx(ctx).Debug().Err(err).Msgf("failed to read index of group %d, skipping group", i+1)
+				continue
+			}
+		}
+
+		values, err := d.values.readGroup(ctx, group)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to read group '%s'", index)
+		}
+
+		for _, filter := range d.filters {
+			v, err := filter.value.read(ctx, group)
+			if err != nil {
+				log.Ctx(ctx).Debug().Err(err).Str("filter_key", filter.key).Msg("failed to read out filter value, skipping filter")
+				continue
+			}
+			if filter.regex.MatchString(v.(value.Value).String()) {
+				log.Ctx(ctx).Debug().Str("filter_key", filter.key).Str("filter_regex", filter.regex.String()).Msgf("filter matched on index '%s'", index)
+				continue groups
+			}
+		}
+
+		res = append(res, values)
+		indices = append(indices, index)
+	}
+
+	return res, indices, nil
+}
+
+func (d documentReader) applyFilter(ctx context.Context, filter Filter) (reader, error) {
+	return filter.applyDocument(ctx, d)
+}
+
+type documentValueReader interface {
+	read(ctx context.Context, group interface{}) (interface{}, error)
+}
+
+// documentValues is a recursive data structure which maps labels to either a single documentValue or another documentValues
+type documentValues map[string]documentValueReader
+
+func (d documentValues) read(ctx context.Context, group interface{}) (interface{}, error) {
+	return d.readGroup(ctx, group)
+}
+
+func (d documentValues) readGroup(ctx context.Context, group interface{}) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for label, reader := range d {
+		res, err := reader.read(ctx, group)
+		if err != nil {
+			if tholaerr.IsNotFoundError(err) || tholaerr.IsComponentNotFoundError(err) || tholaerr.IsDidNotMatchError(err) {
+				log.Ctx(ctx).Debug().Err(err).Msgf("failed to get value '%s'", label)
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get value '%s'", label)
+		}
+		result[label] = res
+	}
+	return result, nil
+}
+
+func (d documentValues) merge(overwrite documentValues) documentValues {
+	merged := make(documentValues)
+	for k, v := range d {
+		merged[k] = v
+	}
+	for k, v := range overwrite {
+		if reader, ok := merged[k]; ok {
+			valuesOld, oldIsValues := reader.(documentValues)
+			valuesOverwrite, overwriteIsValues := v.(documentValues)
+			if oldIsValues && overwriteIsValues {
+				merged[k] = valuesOld.merge(valuesOverwrite)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
+// documentValue represents a single value which is read with a relative expression
+type documentValue struct {
+	expression network.DocumentExpression
+	operators  property.Operators
+}
+
+func (d *documentValue) read(ctx context.Context, group interface{}) (interface{}, error) {
+	res, err := d.expression.QueryValue(group)
+	if err != nil {
+		return nil, err
+	}
+	if res.IsEmpty() {
+		return nil, tholaerr.NewNotFoundError("value is empty")
+	}
+	resNormalized, err := d.operators.Apply(ctx, res)
+	if err != nil {
+		if tholaerr.IsDidNotMatchError(err) {
+			return nil, err
+		}
+		return nil, errors.Wrapf(err, "response couldn't be normalized (response: %s)", res)
+	}
+	return resNormalized, nil
+}
+
+type emptyDocumentValue struct{}
+
+func (n *emptyDocumentValue) read(context.Context, interface{}) (interface{}, error) {
+	return nil, tholaerr.NewComponentNotFoundError("value is ignored")
+}
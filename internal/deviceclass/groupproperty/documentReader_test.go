@@ -0,0 +1,242 @@
+package groupproperty
+
+import (
+	"context"
+	"fmt"
+	"github.com/inexio/thola/internal/device"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/network/gnmitest"
+	"github.com/inexio/thola/internal/value"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+)
+
+func newHTTPTestContext(t *testing.T, contentType, body string) context.Context {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/interfaces" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := network.NewHTTPClient(server.URL)
+	require.NoError(t, err)
+	return network.NewContextWithDeviceConnection(context.Background(), &network.RequestDeviceConnection{
+		HTTP: &network.RequestDeviceConnectionHTTP{
+			HTTPClient:     client,
+			ConnectionData: &network.HTTPConnectionData{},
+		},
+	})
+}
+
+func newDocumentTestReader(t *testing.T, config string) Reader {
+	var i interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(config), &i))
+	reader, err := Interface2Reader(i, nil)
+	require.NoError(t, err)
+	return reader
+}
+
+const httpTestJSON = `{"interfaces": [
+	{"id": 10, "name": "eth0", "status": "up", "counters": {"in": 100, "out": 200}},
+	{"id": 11, "name": "eth1", "status": "down", "counters": {"in": 300}}
+]}`
+
+// TestHTTPReader_getProperty tests documentReader.getProperty(...) with a json response
+func TestHTTPReader_getProperty(t *testing.T) {
+	ctx := newHTTPTestContext(t, "application/json", httpTestJSON)
+	sut := newDocumentTestReader(t, `
+detection: http
+path: /api/interfaces
+headers:
+  Accept: application/json
+jsonpath: $.interfaces[*]
+index: $.id
+values:
+  ifDescr: $.name
+  ifOperStatus:
+    jsonpath: $.status
+    operators:
+      - type: modify
+        modify_method: toUpperCase
+  counters:
+    values:
+      in: $.counters.in
+      out: $.counters.out
+`)
+
+	expected := PropertyGroups{
+		propertyGroup{
+			"ifDescr":      value.New("eth0"),
+			"ifOperStatus": value.New("UP"),
+			"counters": map[string]interface{}{
+				"in":  value.New(100),
+				"out": value.New(200),
+			},
+		},
+		propertyGroup{
+			"ifDescr":      value.New("eth1"),
+			"ifOperStatus": value.New("DOWN"),
+			"counters": map[string]interface{}{
+				"in": value.New(300),
+			},
+		},
+	}
+
+	res, indices, err := sut.GetProperty(ctx)
+	if assert.NoError(t, err) {
+		assert.Equal(t, expected, res)
+		assert.Equal(t, []value.Value{value.New(10), value.New(11)}, indices)
+	}
+}
+
+// TestHTTPReader_getProperty_filter tests documentReader.getProperty(...) with group and value filters
+func TestHTTPReader_getProperty_filter(t *testing.T) {
+	ctx := newHTTPTestContext(t, "application/json", httpTestJSON)
+	sut := newDocumentTestReader(t, `
+detection: http
+path: /api/interfaces
+jsonpath: $.interfaces
+values:
+  ifDescr: $.name
+  ifOperStatus: $.status
+`)
+
+	res, indices, err := sut.GetProperty(ctx, GetGroupFilter("ifOperStatus", "down"), GetValueFilter("ifOperStatus"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, PropertyGroups{propertyGroup{"ifDescr": value.New("eth0")}}, res)
+		assert.Equal(t, []value.Value{value.New(1)}, indices)
+	}
+
+	_, _, err = sut.GetProperty(ctx, GetGroupFilter("ifAlias", "test"))
+	assert.Error(t, err)
+}
+
+// TestHTTPReader_getProperty_xml tests documentReader.getProperty(...) with a xml response
+func TestHTTPReader_getProperty_xml(t *testing.T) {
+	ctx := newHTTPTestContext(t, "application/xml", `<interfaces>
+	<interface id="10"><name>eth0</name></interface>
+	<interface id="11"><name>eth1</name></interface>
+</interfaces>`)
+	sut := newDocumentTestReader(t, `
+detection: http
+path: /api/interfaces
+xpath: //interface
+index: "@id"
+values:
+  ifDescr: name
+`)
+
+	res, indices, err := sut.GetProperty(ctx)
+	if assert.NoError(t, err) {
+		assert.Equal(t, PropertyGroups{propertyGroup{"ifDescr": value.New("eth0")}, propertyGroup{"ifDescr": value.New("eth1")}}, res)
+		assert.Equal(t, []value.Value{value.New(10), value.New(11)}, indices)
+	}
+}
+
+// TestInterface2Reader_http tests that invalid http group property readers are rejected
+func TestInterface2Reader_http(t *testing.T) {
+	for _, config := range []string{
+		"{detection: http, jsonpath: $.a, values: {a: $.a}}",
+		"{detection: http, path: /a, values: {a: $.a}}",
+		"{detection: http, path: /a, jsonpath: $.a, values: {a: {xpath: a}}}",
+		"{detection: http, path: /a, jsonpath: $.a, xpath: a, values: {a: $.a}}",
+	} {
+		var i interface{}
+		require.NoError(t, yaml.Unmarshal([]byte(config), &i))
+		_, err := Interface2Reader(i, nil)
+		assert.Error(t, err, config)
+	}
+}
+
+func newGNMITestContext(t *testing.T, updates map[string]interface{}) context.Context {
+	// the server returns the updates in the given order, which has to be stable for the assertions
+	paths := make([]string, 0, len(updates))
+	for path := range updates {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var gnmiUpdates []*gnmi.Update
+	for _, path := range paths {
+		val := updates[path]
+		p, err := network.ParseGNMIPath(path)
+		require.NoError(t, err)
+		typedValue := &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: fmt.Sprint(val)}}
+		if v, ok := val.(uint64); ok {
+			typedValue = &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: v}}
+		}
+		gnmiUpdates = append(gnmiUpdates, &gnmi.Update{Path: p, Val: typedValue})
+	}
+	server, err := gnmitest.NewServer(gnmiUpdates...)
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	client, err := network.NewGNMIClient(server.Addr().IP.String(), server.Addr().Port, "", "", true)
+	require.NoError(t, err)
+	return network.NewContextWithDeviceConnection(context.Background(), &network.RequestDeviceConnection{
+		GNMI: &network.RequestDeviceConnectionGNMI{
+			GNMIClient:     client,
+			ConnectionData: &network.GNMIConnectionData{},
+		},
+	})
+}
+
+// TestGNMIReader_openconfig tests that openconfig data read with gnmi can be decoded to the device structs
+func TestGNMIReader_openconfig(t *testing.T) {
+	ctx := newGNMITestContext(t, map[string]interface{}{
+		"/interfaces/interface[name=eth0]/state/oper-status":         "UP",
+		"/interfaces/interface[name=eth0]/state/counters/in-octets":  uint64(100),
+		"/interfaces/interface[name=eth1]/state/oper-status":         "DOWN",
+		"/interfaces/interface[name=eth1]/state/counters/in-octets":  uint64(200),
+		"/components/component[name=CPU0]/state/temperature/instant": "45.5",
+		"/components/component[name=FAN1]/state/oper-status":         "ACTIVE",
+	})
+
+	interfaces, _, err := newDocumentTestReader(t, `
+detection: gnmi
+path: /interfaces/interface
+index: $.name
+values:
+  ifDescr: $.name
+  ifOperStatus:
+    jsonpath: $.state["oper-status"]
+    operators:
+      - type: modify
+        modify_method: toLowerCase
+  ifHCInOctets: $.state.counters["in-octets"]
+`).GetProperty(ctx)
+	require.NoError(t, err)
+
+	var decodedInterfaces []device.Interface
+	require.NoError(t, interfaces.Decode(&decodedInterfaces))
+	require.Len(t, decodedInterfaces, 2)
+	assert.Equal(t, "eth1", *decodedInterfaces[1].IfDescr)
+	assert.Equal(t, device.Status("down"), *decodedInterfaces[1].IfOperStatus)
+	assert.Equal(t, uint64(200), *decodedInterfaces[1].IfHCInOctets)
+
+	temperatures, _, err := newDocumentTestReader(t, `
+detection: gnmi
+path: /components/component
+values:
+  description: $.name
+  temperature: $.state.temperature.instant
+`).GetProperty(ctx)
+	require.NoError(t, err)
+
+	var decodedTemperatures []device.HardwareHealthComponentTemperature
+	require.NoError(t, temperatures.Decode(&decodedTemperatures))
+	require.Len(t, decodedTemperatures, 2)
+	assert.Equal(t, "CPU0", *decodedTemperatures[0].Description)
+	assert.Equal(t, 45.5, *decodedTemperatures[0].Temperature)
+	assert.Nil(t, decodedTemperatures[1].Temperature)
+}
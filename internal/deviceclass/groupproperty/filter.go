@@ -11,6 +11,7 @@ import (
 
 type Filter interface {
 	applySNMP(ctx context.Context, reader snmpReader) (snmpReader, error)
+	applyDocument(ctx context.Context, reader documentReader) (documentReader, error)
 }
 
 type GroupFilter interface {
@@ -103,6 +104,41 @@ func (g *groupFilter) applySNMP(ctx context.Context, reader snmpReader) (snmpRea
 	return reader, nil
 }
 
+func (g *groupFilter) applyDocument(_ context.Context, reader documentReader) (documentReader, error) {
+	// compile filter regex
+	regex, err := regexp.Compile(g.regex)
+	if err != nil {
+		return documentReader{}, errors.Wrap(err, "filter regex failed to compile")
+	}
+
+	// find filter value
+	var valueReader documentValueReader = reader.values
+	for _, attr := range strings.Split(g.key, "/") {
+		// check if current value reader contains multiple values
+		multipleReader, ok := valueReader.(documentValues)
+		if !ok {
+			return documentReader{}, errors.New("filter attribute does not exist")
+		}
+		if valueReader, ok = multipleReader[attr]; !ok {
+			return documentReader{}, errors.New("filter attribute does not exist")
+		}
+	}
+
+	// check if the current value reader contains only a single value
+	singleReader, ok := valueReader.(*documentValue)
+	if !ok {
+		return documentReader{}, errors.New("filter attribute does not exist")
+	}
+
+	// all groups are read with a single request, so they are filtered while reading them
+	reader.filters = append(append([]documentFilter{}, reader.filters...), documentFilter{
+		key:   g.key,
+		regex: regex,
+		value: singleReader,
+	})
+	return reader, nil
+}
+
 type ValueFilter interface {
 	GetFilterProperties() string
 }
@@ -159,3 +195,42 @@ func (g *valueFilter) applySNMP(ctx context.Context, reader snmpReader) (snmpRea
 	}
 	return reader, nil
 }
+
+func (g *valueFilter) applyDocument(ctx context.Context, reader documentReader) (documentReader, error) {
+	var recursiveAnonymous func(documentValueReader, []string) (documentValues, error)
+	recursiveAnonymous = func(currentReader documentValueReader, key []string) (documentValues, error) {
+		// check if current value reader contains multiple values
+		multipleReader, ok := currentReader.(documentValues)
+		if !ok {
+			return nil, errors.New("filter attribute does not exist")
+		}
+
+		//copy values
+		readerCopy := make(documentValues)
+		for k, v := range multipleReader {
+			if k == key[0] {
+				if len(key) > 1 {
+					r, err := recursiveAnonymous(v, key[1:])
+					if err != nil {
+						return nil, err
+					}
+					readerCopy[k] = r
+				} else {
+					log.Ctx(ctx).Debug().Str("value", k).Msg("filter matched on value in documentReader")
+				}
+				continue
+			}
+			readerCopy[k] = v
+		}
+
+		return readerCopy, nil
+	}
+
+	var err error
+	attrs := strings.Split(g.value, "/")
+	reader.values, err = recursiveAnonymous(reader.values, attrs)
+	if err != nil {
+		return documentReader{}, err
+	}
+	return reader, nil
+}
@@ -95,6 +95,8 @@ func Interface2Reader(i interface{}, parentReader Reader) (Reader, error) {
 				oids:  devClassOIDs,
 			},
 		}, nil
+	case "http", "gnmi", "netconf", "redfish":
+		return interface2DocumentReader(m, stringDetection, parentReader)
 	default:
 		return nil, fmt.Errorf("unknown detection type '%s'", stringDetection)
 	}
@@ -48,6 +48,33 @@ func interface2PReader(i interface{}, task condition2.RelatedTask) (Reader, erro
 			return nil, errors.Wrap(err, "failed to decode constant reader")
 		}
 		basePropReader.reader = &pr
+	case "http", "gnmi", "netconf", "redfish":
+		request, err := network.NewDocumentRequest(stringDetection)
+		if err != nil {
+			return nil, err
+		}
+		err = mapstructure.Decode(i, request)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %s reader", stringDetection)
+		}
+		err = request.Validate()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s reader", stringDetection)
+		}
+		basePropReader.reader = &documentReader{
+			detection: stringDetection,
+			request:   request,
+		}
+	case "ssh":
+		var pr sshReader
+		err := mapstructure.Decode(i, &pr)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode ssh reader")
+		}
+		if pr.Command == "" {
+			return nil, errors.New("command is missing in ssh reader")
+		}
+		basePropReader.reader = &pr
 	case "constant":
 		v, ok := m["value"]
 		if !ok {
@@ -258,6 +285,50 @@ func (s *snmpGetReader) GetProperty(ctx context.Context) (value.Value, error) {
 	return value.New(val), nil
 }
 
+// documentReader reads a property from a http, gnmi, netconf or redfish response.
+type documentReader struct {
+	detection string
+	request   network.DocumentRequest
+}
+
+func (d *documentReader) GetProperty(ctx context.Context) (value.Value, error) {
+	doc, err := d.request.Request(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Str("property_reader", d.detection).Msg(d.detection + " request failed")
+		return nil, errors.Wrap(err, d.detection+" request failed")
+	}
+
+	val, err := d.request.QueryValue(doc)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Str("property_reader", d.detection).Msg("failed to read value from " + d.detection + " response")
+		return nil, err
+	}
+	log.Ctx(ctx).Debug().Str("property_reader", d.detection).Msg(d.detection + " request successful")
+	return val, nil
+}
+
+type sshReader struct {
+	Command string `yaml:"command" mapstructure:"command"`
+}
+
+func (s *sshReader) GetProperty(ctx context.Context) (value.Value, error) {
+	con, ok := network.DeviceConnectionFromContext(ctx)
+	if !ok || con.SSH == nil || con.SSH.SSHClient == nil {
+		return nil, errors.New("no ssh connection data available")
+	}
+	output, err := con.SSH.SSHClient.RunCommand(ctx, s.Command)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Str("property_reader", "ssh").Msg("command '" + s.Command + "' failed")
+		return nil, errors.Wrap(err, "ssh command failed")
+	}
+	if output == "" {
+		log.Ctx(ctx).Debug().Str("property_reader", "ssh").Msg("command '" + s.Command + "' returned no output")
+		return nil, tholaerr.NewNotFoundError("command returned no output")
+	}
+	log.Ctx(ctx).Debug().Str("property_reader", "ssh").Msg("ssh command successful")
+	return value.New(output), nil
+}
+
 type vendorReader struct{}
 
 func (v *vendorReader) GetProperty(ctx context.Context) (value.Value, error) {
@@ -0,0 +1,86 @@
+// Package devicelock limits the number of concurrent requests per device, also across multiple api instances.
+package devicelock
+
+import (
+	"context"
+	"fmt"
+	"github.com/inexio/thola/internal/database"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"time"
+)
+
+// Provider hands out the locks of devices.
+type Provider interface {
+	// Acquire waits until one of the slots of the device is free and returns the function that releases it.
+	// If the context is done before, a TooManyRequestsError is returned.
+	Acquire(ctx context.Context, device string) (release func(), err error)
+}
+
+// Config is the config of a lock provider.
+type Config struct {
+	// Concurrency is the amount of requests that may be processed at the same time per device.
+	Concurrency int
+	// Timeout is the maximum duration to wait for a lock, 0 means no timeout.
+	Timeout time.Duration
+	// Lease is the duration after which a lock of a crashed instance expires, only used by shared providers.
+	Lease time.Duration
+}
+
+// New returns the lock provider for the configured database. Locks are shared between api instances if redis is used.
+func New() (Provider, error) {
+	config := Config{
+		Concurrency: viper.GetInt("request.device-lock.concurrency"),
+	}
+	var err error
+	if t := viper.GetString("request.device-lock.timeout"); t != "" {
+		config.Timeout, err = time.ParseDuration(t)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid device lock timeout")
+		}
+	}
+	config.Lease = 30 * time.Second
+	if l := viper.GetString("request.device-lock.lease"); l != "" {
+		config.Lease, err = time.ParseDuration(l)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid device lock lease")
+		}
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+
+	if viper.GetString("db.drivername") == "redis" {
+		return NewRedisProvider(database.NewRedisPool(), config), nil
+	}
+	return NewMemoryProvider(config), nil
+}
+
+func (c *Config) validate() error {
+	if c.Concurrency == 0 {
+		c.Concurrency = 1
+	}
+	if c.Concurrency < 0 {
+		return errors.New("device lock concurrency must be positive")
+	}
+	if c.Timeout < 0 {
+		return errors.New("device lock timeout must not be negative")
+	}
+	if c.Lease <= 0 {
+		return errors.New("device lock lease must be positive")
+	}
+	return nil
+}
+
+// withTimeout returns the context that bounds the wait for a lock.
+func (c Config) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.Timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.Timeout)
+}
+
+func waitError(device string, err error) error {
+	return tholaerr.NewTooManyRequestsError(fmt.Sprintf("failed to acquire lock of device '%s': %s", device, err))
+}
@@ -0,0 +1,40 @@
+package devicelock
+
+import (
+	"context"
+	"sync"
+)
+
+// memoryProvider holds the locks of the devices in memory, so they are only valid for this instance.
+type memoryProvider struct {
+	config Config
+
+	sync.Mutex
+	slots map[string]chan struct{}
+}
+
+// NewMemoryProvider returns a provider that holds the locks in memory.
+func NewMemoryProvider(config Config) Provider {
+	_ = config.validate()
+	return &memoryProvider{config: config, slots: make(map[string]chan struct{})}
+}
+
+func (p *memoryProvider) Acquire(ctx context.Context, device string) (func(), error) {
+	p.Lock()
+	slots, ok := p.slots[device]
+	if !ok {
+		slots = make(chan struct{}, p.config.Concurrency)
+		p.slots[device] = slots
+	}
+	p.Unlock()
+
+	ctx, cancel := p.config.withTimeout(ctx)
+	defer cancel()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, waitError(device, ctx.Err())
+	}
+}
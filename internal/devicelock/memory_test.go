@@ -0,0 +1,54 @@
+package devicelock
+
+import (
+	"context"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestMemoryProvider_concurrency(t *testing.T) {
+	p := NewMemoryProvider(Config{Concurrency: 2, Timeout: 20 * time.Millisecond})
+	ctx := context.Background()
+
+	release1, err := p.Acquire(ctx, "192.0.2.1")
+	require.NoError(t, err)
+	release2, err := p.Acquire(ctx, "192.0.2.1")
+	require.NoError(t, err)
+
+	_, err = p.Acquire(ctx, "192.0.2.1")
+	assert.True(t, tholaerr.IsTooManyRequestsError(err), "expected TooManyRequestsError, got %v", err)
+
+	// other devices are not affected
+	release3, err := p.Acquire(ctx, "192.0.2.2")
+	require.NoError(t, err)
+	release3()
+
+	release1()
+	release1, err = p.Acquire(ctx, "192.0.2.1")
+	require.NoError(t, err)
+	release1()
+	release2()
+}
+
+func TestMemoryProvider_wait(t *testing.T) {
+	p := NewMemoryProvider(Config{})
+
+	release, err := p.Acquire(context.Background(), "192.0.2.1")
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		release()
+	}()
+
+	release, err = p.Acquire(context.Background(), "192.0.2.1")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = p.Acquire(ctx, "192.0.2.1")
+	assert.True(t, tholaerr.IsTooManyRequestsError(err), "expected TooManyRequestsError, got %v", err)
+	release()
+}
@@ -0,0 +1,134 @@
+package devicelock
+
+import (
+	"context"
+	"github.com/gomodule/redigo/redis"
+	"github.com/pkg/errors"
+	"github.com/rs/xid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// acquireScript removes expired leases and adds the holder if one of the slots is free.
+// KEYS[1] = lock key, ARGV[1] = now, ARGV[2] = lease expiry, ARGV[3] = concurrency, ARGV[4] = holder
+var acquireScript = redis.NewScript(1, `
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[1])
+if redis.call("ZCARD", KEYS[1]) < tonumber(ARGV[3]) then
+	redis.call("ZADD", KEYS[1], ARGV[2], ARGV[4])
+	redis.call("PEXPIREAT", KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// renewScript extends the lease of the holder if it still holds the lock.
+var renewScript = redis.NewScript(1, `
+if redis.call("ZSCORE", KEYS[1], ARGV[2]) then
+	redis.call("ZADD", KEYS[1], ARGV[1], ARGV[2])
+	if redis.call("PTTL", KEYS[1]) < tonumber(ARGV[3]) then
+		redis.call("PEXPIREAT", KEYS[1], ARGV[1])
+	end
+	return 1
+end
+return 0
+`)
+
+// pollInterval is the interval in which a waiting instance tries to acquire the lock again.
+const pollInterval = 50 * time.Millisecond
+
+// redisProvider shares the locks of the devices between api instances.
+// Every holder has a lease which is renewed while the lock is held, so locks of crashed instances expire.
+// The leases are based on the local time, so the clocks of the instances need to be in sync.
+type redisProvider struct {
+	config Config
+	pool   *redis.Pool
+}
+
+// NewRedisProvider returns a provider that holds the locks in redis.
+func NewRedisProvider(pool *redis.Pool, config Config) Provider {
+	_ = config.validate()
+	return &redisProvider{config: config, pool: pool}
+}
+
+func (p *redisProvider) Acquire(ctx context.Context, device string) (func(), error) {
+	ctx, cancel := p.config.withTimeout(ctx)
+	defer cancel()
+
+	key := "DeviceLock-" + device
+	holder := xid.New().String()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		ok, err := p.tryAcquire(ctx, key, holder)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to acquire device lock in redis")
+		}
+		if ok {
+			break
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, waitError(device, ctx.Err())
+		}
+	}
+
+	renewCtx, stopRenew := context.WithCancel(context.Background())
+	go p.renew(renewCtx, key, holder)
+
+	return func() {
+		stopRenew()
+		if err := p.release(key, holder); err != nil {
+			log.Error().Err(err).Str("device", device).Msg("failed to release device lock in redis")
+		}
+	}, nil
+}
+
+func (p *redisProvider) tryAcquire(ctx context.Context, key, holder string) (bool, error) {
+	conn, err := p.pool.GetContext(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	now := time.Now()
+	return redis.Bool(acquireScript.Do(conn, key, millis(now), millis(now.Add(p.config.Lease)), p.config.Concurrency, holder))
+}
+
+func (p *redisProvider) renew(ctx context.Context, key, holder string) {
+	ticker := time.NewTicker(p.config.Lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		conn, err := p.pool.GetContext(ctx)
+		if err != nil {
+			log.Error().Err(err).Str("key", key).Msg("failed to renew device lock in redis")
+			continue
+		}
+		expiry := millis(time.Now().Add(p.config.Lease))
+		ok, err := redis.Bool(renewScript.Do(conn, key, expiry, holder, p.config.Lease.Milliseconds()))
+		conn.Close()
+		if err != nil {
+			log.Error().Err(err).Str("key", key).Msg("failed to renew device lock in redis")
+		} else if !ok {
+			log.Warn().Str("key", key).Msg("device lock expired before it was released")
+			return
+		}
+	}
+}
+
+func (p *redisProvider) release(key, holder string) error {
+	conn := p.pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("ZREM", key, holder)
+	return err
+}
+
+func millis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
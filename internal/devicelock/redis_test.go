@@ -0,0 +1,79 @@
+package devicelock
+
+import (
+	"context"
+	"github.com/gomodule/redigo/redis"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"testing"
+	"time"
+)
+
+// The redis provider is only tested if the environment variable THOLA_TEST_REDIS_ADDR is set.
+
+func newTestRedisPool(t *testing.T) *redis.Pool {
+	addr := os.Getenv("THOLA_TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("THOLA_TEST_REDIS_ADDR is not set")
+	}
+	pool := &redis.Pool{Dial: func() (redis.Conn, error) {
+		return redis.Dial("tcp", addr)
+	}}
+	t.Cleanup(func() { _ = pool.Close() })
+	return pool
+}
+
+func TestRedisProvider_concurrency(t *testing.T) {
+	pool := newTestRedisPool(t)
+	// the providers of two api instances share the same locks
+	p1 := NewRedisProvider(pool, Config{Concurrency: 2, Timeout: 200 * time.Millisecond, Lease: time.Second})
+	p2 := NewRedisProvider(pool, Config{Concurrency: 2, Timeout: 200 * time.Millisecond, Lease: time.Second})
+	ctx := context.Background()
+	device := xid.New().String()
+
+	release1, err := p1.Acquire(ctx, device)
+	require.NoError(t, err)
+	release2, err := p2.Acquire(ctx, device)
+	require.NoError(t, err)
+
+	_, err = p1.Acquire(ctx, device)
+	assert.True(t, tholaerr.IsTooManyRequestsError(err), "expected TooManyRequestsError, got %v", err)
+
+	// other devices are not affected
+	release3, err := p2.Acquire(ctx, xid.New().String())
+	require.NoError(t, err)
+	release3()
+
+	release1()
+	release1, err = p2.Acquire(ctx, device)
+	require.NoError(t, err)
+	release1()
+	release2()
+}
+
+func TestRedisProvider_lease(t *testing.T) {
+	pool := newTestRedisPool(t)
+	p := NewRedisProvider(pool, Config{Timeout: 100 * time.Millisecond, Lease: 300 * time.Millisecond})
+	ctx := context.Background()
+	device := xid.New().String()
+
+	// the lease is renewed while the lock is held
+	release, err := p.Acquire(ctx, device)
+	require.NoError(t, err)
+	time.Sleep(time.Second)
+	_, err = p.Acquire(ctx, device)
+	assert.True(t, tholaerr.IsTooManyRequestsError(err), "expected TooManyRequestsError, got %v", err)
+	release()
+
+	// the lock of a crashed instance expires with its lease
+	conn := pool.Get()
+	_, err = conn.Do("ZADD", "DeviceLock-"+device, millis(time.Now().Add(-time.Second)), "crashed")
+	conn.Close()
+	require.NoError(t, err)
+	release, err = p.Acquire(ctx, device)
+	require.NoError(t, err)
+	release()
+}
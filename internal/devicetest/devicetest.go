@@ -0,0 +1,146 @@
+// Package devicetest creates the testdata of devices, which contains the expected responses of the requests
+// that are run by the integration tests against a snmprec recording of the device.
+package devicetest
+
+import (
+	"context"
+	"errors"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/parser"
+	"github.com/inexio/thola/internal/request"
+	"github.com/rs/zerolog/log"
+)
+
+// Data represents the testdata file of a recorded device.
+type Data struct {
+	Type         string                 `json:"type"`
+	Expectations Expectations           `json:"expectations"`
+	Connection   network.ConnectionData `json:"-"`
+}
+
+// Expectations represents the expectations part of a testdata file.
+type Expectations struct {
+	Identify              *request.IdentifyResponse            `json:"identify" mapstructure:"identify"`
+	ReadCountInterfaces   *request.ReadCountInterfacesResponse `json:"readCountInterfaces" mapstructure:"readCountInterfaces"`
+	CheckInterfaceMetrics *request.CheckResponse               `json:"checkInterfaceMetrics" mapstructure:"checkInterfaceMetrics"`
+	CheckUPS              *request.CheckResponse               `json:"checkUPS" mapstructure:"checkUPS"`
+	CheckCPULoad          *request.CheckResponse               `json:"checkCPULoad" mapstructure:"checkCPULoad"`
+	CheckMemoryUsage      *request.CheckResponse               `json:"checkMemoryUsage" mapstructure:"checkMemoryUsage"`
+	CheckDisk             *request.CheckResponse               `json:"checkDisk" mapstructure:"checkDisk"`
+	CheckServer           *request.CheckResponse               `json:"checkServer" mapstructure:"checkServer"`
+	CheckSBC              *request.CheckResponse               `json:"checkSBC" mapstructure:"checkSBC"`
+	CheckHardwareHealth   *request.CheckResponse               `json:"checkHardwareHealth" mapstructure:"checkHardwareHealth"`
+}
+
+// GetAvailableRequestTypes returns all available request types
+func (d *Data) GetAvailableRequestTypes() []string {
+	var res []string
+
+	if d.Expectations.Identify != nil {
+		res = append(res, "identify")
+	}
+
+	if d.Expectations.ReadCountInterfaces != nil {
+		res = append(res, "read count-interfaces")
+	}
+
+	if d.Expectations.CheckInterfaceMetrics != nil {
+		res = append(res, "check interface-metrics")
+	}
+
+	if d.Expectations.CheckUPS != nil {
+		res = append(res, "check ups")
+	}
+
+	if d.Expectations.CheckCPULoad != nil {
+		res = append(res, "check cpu-load")
+	}
+
+	if d.Expectations.CheckMemoryUsage != nil {
+		res = append(res, "check memory-usage")
+	}
+
+	if d.Expectations.CheckDisk != nil {
+		res = append(res, "check disk")
+	}
+
+	if d.Expectations.CheckServer != nil {
+		res = append(res, "check server")
+	}
+
+	if d.Expectations.CheckSBC != nil {
+		res = append(res, "check sbc")
+	}
+
+	if d.Expectations.CheckHardwareHealth != nil {
+		res = append(res, "check hardware-health")
+	}
+
+	return res
+}
+
+// RequestProcessor processes a request and returns the response.
+type RequestProcessor func(r request.Request) (request.Response, error)
+
+// Create processes all requests that are covered by testdata and returns their responses as testdata.
+// Requests that fail are logged with the logger of the context and left out of the expectations.
+func Create(ctx context.Context, baseRequest request.BaseRequest, process RequestProcessor) (Data, error) {
+	var expectations Expectations
+
+	res, err := process(&request.IdentifyRequest{BaseRequest: baseRequest})
+	if err != nil {
+		log.Ctx(ctx).Info().Err(err).Msg("identify failed")
+	} else {
+		expectations.Identify = res.(*request.IdentifyResponse)
+	}
+
+	res, err = process(&request.ReadCountInterfacesRequest{ReadRequest: request.ReadRequest{BaseRequest: baseRequest}})
+	if err != nil {
+		log.Ctx(ctx).Info().Err(err).Msg("read count interfaces failed")
+	} else {
+		expectations.ReadCountInterfaces = res.(*request.ReadCountInterfacesResponse)
+	}
+
+	checkDeviceRequest := request.CheckDeviceRequest{
+		BaseRequest:  baseRequest,
+		CheckRequest: request.CheckRequest{},
+	}
+	checks := []struct {
+		name        string
+		request     request.Request
+		expectation **request.CheckResponse
+	}{
+		{"check interface metrics", &request.CheckInterfaceMetricsRequest{CheckDeviceRequest: checkDeviceRequest, PrintInterfaces: true}, &expectations.CheckInterfaceMetrics},
+		{"check ups", &request.CheckUPSRequest{CheckDeviceRequest: checkDeviceRequest}, &expectations.CheckUPS},
+		{"check cpu load", &request.CheckCPULoadRequest{CheckDeviceRequest: checkDeviceRequest}, &expectations.CheckCPULoad},
+		{"check memory usage", &request.CheckMemoryUsageRequest{CheckDeviceRequest: checkDeviceRequest}, &expectations.CheckMemoryUsage},
+		{"check disk", &request.CheckDiskRequest{CheckDeviceRequest: checkDeviceRequest}, &expectations.CheckDisk},
+		{"check server", &request.CheckServerRequest{CheckDeviceRequest: checkDeviceRequest}, &expectations.CheckServer},
+		{"check sbc", &request.CheckSBCRequest{CheckDeviceRequest: checkDeviceRequest}, &expectations.CheckSBC},
+		{"check hardware-health", &request.CheckHardwareHealthRequest{CheckDeviceRequest: checkDeviceRequest}, &expectations.CheckHardwareHealth},
+	}
+	for _, check := range checks {
+		res, err := process(check.request)
+		if err != nil {
+			log.Ctx(ctx).Info().Err(err).Msg(check.name + " failed")
+			continue
+		}
+		if res.GetExitCode() == 3 {
+			errString, err := parser.Parse(res, "")
+			if err != nil {
+				return Data{}, errors.New("failed to parse error message")
+			}
+			log.Ctx(ctx).Info().Err(errors.New(string(errString))).Msg(check.name + " failed")
+			continue
+		}
+		checkResponse := res.(*request.CheckResponse)
+		checkResponse.RawOutput = ""
+		*check.expectation = checkResponse
+	}
+
+	return Data{
+		Type:         "snmpsim",
+		Expectations: expectations,
+		Connection:   baseRequest.DeviceData.ConnectionData,
+	}, nil
+}
@@ -10,6 +10,12 @@ type ConnectionData struct {
 	SNMP *SNMPConnectionData `json:"snmp" xml:"snmp" yaml:"snmp"`
 	// Data of the http connection to the device
 	HTTP *HTTPConnectionData `json:"http" xml:"http" yaml:"http"`
+	// Data of the ssh connection to the device
+	SSH *SSHConnectionData `json:"ssh" xml:"ssh" yaml:"ssh"`
+	// Data of the gnmi connection to the device
+	GNMI *GNMIConnectionData `json:"gnmi" xml:"gnmi" yaml:"gnmi"`
+	// Data of the netconf connection to the device
+	NETCONF *NETCONFConnectionData `json:"netconf" xml:"netconf" yaml:"netconf"`
 }
 
 // SNMPConnectionData
@@ -34,6 +40,11 @@ type SNMPConnectionData struct {
 	//
 	// example: 20
 	MaxRepetitions *uint32 `json:"maxRepetitions" xml:"maxRepetitions" yaml:"maxRepetitions"`
+	// The Max Repetitions that worked for the device in former bulk walks. Thola learns them and stores them in the
+	// connection data cache. Bulk walks start with them if they are lower than the Max Repetitions.
+	//
+	// example: 10
+	AdaptiveMaxRepetitions *uint32 `json:"adaptiveMaxRepetitions" xml:"adaptiveMaxRepetitions" yaml:"adaptiveMaxRepetitions"`
 	// The amount of parallel connection requests used while trying to get a valid SNMP connection.
 	//
 	// example: 5
@@ -118,3 +129,75 @@ type HTTPConnectionData struct {
 	// example: password
 	AuthPassword *string `json:"auth_password" xml:"auth_password" yaml:"auth_password"`
 }
+
+// SSHConnectionData
+//
+// SSHConnectionData includes all SSH connection data for a device.
+//
+// swagger:model
+type SSHConnectionData struct {
+	// The SSH port(s) of the device.
+	//
+	// example: [22]
+	Ports []int `json:"ports" xml:"ports" yaml:"ports"`
+	// The username for the login on the device.
+	//
+	// example: username
+	Username *string `json:"username" xml:"username" yaml:"username"`
+	// The password for the login on the device.
+	//
+	// example: password
+	Password *string `json:"password" xml:"password" yaml:"password"`
+}
+
+// GNMIConnectionData
+//
+// GNMIConnectionData includes all gNMI connection data for a device.
+//
+// swagger:model
+type GNMIConnectionData struct {
+	// The gNMI port(s) of the device.
+	//
+	// example: [57400]
+	Ports []int `json:"ports" xml:"ports" yaml:"ports"`
+	// The username for authorization on the device.
+	//
+	// example: username
+	Username *string `json:"username" xml:"username" yaml:"username"`
+	// The password for authorization on the device.
+	//
+	// example: password
+	Password *string `json:"password" xml:"password" yaml:"password"`
+	// Connect without TLS.
+	//
+	// example: false
+	Insecure *bool `json:"insecure" xml:"insecure" yaml:"insecure"`
+	// Accept the TLS certificate of the device without verification.
+	//
+	// example: false
+	InsecureSkipVerify *bool `json:"insecureSkipVerify" xml:"insecureSkipVerify" yaml:"insecureSkipVerify"`
+	// The CA bundle to verify the TLS certificate of the device against instead of the system pool.
+	//
+	// example: /etc/thola/gnmi-ca.pem
+	CAFile *string `json:"caFile" xml:"caFile" yaml:"caFile"`
+}
+
+// NETCONFConnectionData
+//
+// NETCONFConnectionData includes all NETCONF connection data for a device.
+//
+// swagger:model
+type NETCONFConnectionData struct {
+	// The NETCONF port(s) of the device.
+	//
+	// example: [830]
+	Ports []int `json:"ports" xml:"ports" yaml:"ports"`
+	// The username for the login on the device.
+	//
+	// example: username
+	Username *string `json:"username" xml:"username" yaml:"username"`
+	// The password for the login on the device.
+	//
+	// example: password
+	Password *string `json:"password" xml:"password" yaml:"password"`
+}
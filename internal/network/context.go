@@ -7,6 +7,7 @@ type ctxKey byte
 const (
 	requestDeviceConnectionKey ctxKey = iota + 1
 	snmpGetsInsteadOfWalk
+	snmpRecorderKey
 )
 
 // NewContextWithDeviceConnection returns a new context with the device connection
@@ -30,3 +31,14 @@ func SNMPGetsInsteadOfWalkFromContext(ctx context.Context) (bool, bool) {
 	con, ok := ctx.Value(snmpGetsInsteadOfWalk).(bool)
 	return con, ok
 }
+
+// NewContextWithSNMPRecorder returns a new context with the snmp recorder
+func NewContextWithSNMPRecorder(ctx context.Context, recorder *SNMPRecorder) context.Context {
+	return context.WithValue(ctx, snmpRecorderKey, recorder)
+}
+
+// SNMPRecorderFromContext gets the snmp recorder from the context
+func SNMPRecorderFromContext(ctx context.Context) (*SNMPRecorder, bool) {
+	recorder, ok := ctx.Value(snmpRecorderKey).(*SNMPRecorder)
+	return recorder, ok
+}
@@ -0,0 +1,154 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/antchfx/xmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/inexio/thola/internal/value"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// DocumentRequest is a request whose response is parsed into a document that can be queried with its expression.
+type DocumentRequest interface {
+	Request(ctx context.Context) (interface{}, error)
+	Query(doc interface{}) ([]interface{}, error)
+	QueryValue(doc interface{}) (value.Value, error)
+	IsXPath() bool
+	Validate() error
+}
+
+// NewDocumentRequest returns an empty document request for the given detection type,
+// which can be decoded from the reader configuration of a device class.
+func NewDocumentRequest(detection string) (DocumentRequest, error) {
+	switch detection {
+	case "http":
+		return &HTTPRequestConfiguration{}, nil
+	case "gnmi":
+		return &GNMIRequestConfiguration{}, nil
+	case "netconf":
+		return &NETCONFRequestConfiguration{}, nil
+	case "redfish":
+		return &RedfishRequestConfiguration{}, nil
+	}
+	return nil, fmt.Errorf("unknown document detection type '%s'", detection)
+}
+
+// DocumentExpression is a JSONPath or XPath expression which extracts values from a parsed response.
+// Http responses can be queried with both, gNMI and Redfish responses with JSONPath and NETCONF responses with XPath.
+type DocumentExpression struct {
+	JSONPath string `yaml:"jsonpath" mapstructure:"jsonpath"`
+	XPath    string `yaml:"xpath" mapstructure:"xpath"`
+}
+
+// Validate checks if exactly one valid expression is set.
+func (d *DocumentExpression) Validate() error {
+	switch {
+	case d.JSONPath != "" && d.XPath != "":
+		return errors.New("jsonpath and xpath cannot be used together")
+	case d.JSONPath != "":
+		if _, err := jsonpath.New(d.JSONPath); err != nil {
+			return errors.Wrap(err, "invalid jsonpath")
+		}
+	case d.XPath != "":
+		if _, err := xpath.Compile(d.XPath); err != nil {
+			return errors.Wrap(err, "invalid xpath")
+		}
+	default:
+		return errors.New("jsonpath or xpath is missing")
+	}
+	return nil
+}
+
+// IsXPath returns if the expression is a XPath expression.
+func (d *DocumentExpression) IsXPath() bool {
+	return d.XPath != ""
+}
+
+// Parse parses the response body as xml if the expression is a XPath expression, otherwise as json.
+func (d *DocumentExpression) Parse(body []byte) (interface{}, error) {
+	if d.IsXPath() {
+		doc, err := xmlquery.Parse(bytes.NewReader(body))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse xml response")
+		}
+		return doc, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, errors.Wrap(err, "failed to parse json response")
+	}
+	return doc, nil
+}
+
+// Query returns all nodes of the parsed document that are selected by the expression.
+// The nodes can be queried again with relative expressions or converted to values with DocumentNodeValue.
+func (d *DocumentExpression) Query(doc interface{}) ([]interface{}, error) {
+	if d.IsXPath() {
+		node, ok := doc.(*xmlquery.Node)
+		if !ok {
+			return nil, errors.New("xpath can only be used on xml responses")
+		}
+		nodes, err := xmlquery.QueryAll(node, d.XPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to evaluate xpath")
+		}
+		if len(nodes) == 0 {
+			return nil, tholaerr.NewNotFoundError(fmt.Sprintf("xpath '%s' did not match", d.XPath))
+		}
+		res := make([]interface{}, len(nodes))
+		for i, n := range nodes {
+			res[i] = n
+		}
+		return res, nil
+	}
+
+	if _, ok := doc.(*xmlquery.Node); ok {
+		return nil, errors.New("jsonpath can only be used on json responses")
+	}
+	res, err := jsonpath.Get(d.JSONPath, doc)
+	if err != nil {
+		return nil, tholaerr.NewNotFoundError(fmt.Sprintf("jsonpath '%s' did not match: %s", d.JSONPath, err))
+	}
+	if nodes, ok := res.([]interface{}); ok {
+		if len(nodes) == 0 {
+			return nil, tholaerr.NewNotFoundError(fmt.Sprintf("jsonpath '%s' did not match", d.JSONPath))
+		}
+		return nodes, nil
+	}
+	return []interface{}{res}, nil
+}
+
+// QueryValue returns the single value selected by the expression.
+func (d *DocumentExpression) QueryValue(doc interface{}) (value.Value, error) {
+	nodes, err := d.Query(doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) != 1 {
+		return nil, fmt.Errorf("expression matched %d values instead of one", len(nodes))
+	}
+	return DocumentNodeValue(nodes[0])
+}
+
+// DocumentNodeValue converts a node of a parsed response to a value.
+// Json nodes need to be scalars, xml nodes are converted to their text.
+func DocumentNodeValue(node interface{}) (value.Value, error) {
+	switch n := node.(type) {
+	case *xmlquery.Node:
+		return value.New(strings.TrimSpace(n.InnerText())), nil
+	case nil:
+		return nil, tholaerr.NewNotFoundError("value is null")
+	case map[string]interface{}, []interface{}:
+		return nil, errors.New("value is not a scalar")
+	default:
+		return value.New(n), nil
+	}
+}
@@ -0,0 +1,50 @@
+package network
+
+import (
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/inexio/thola/internal/value"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDocumentExpression_Validate(t *testing.T) {
+	assert.NoError(t, (&DocumentExpression{JSONPath: "$.system.vendor"}).Validate())
+	assert.NoError(t, (&DocumentExpression{XPath: "//system/vendor"}).Validate())
+	assert.Error(t, (&DocumentExpression{}).Validate())
+	assert.Error(t, (&DocumentExpression{JSONPath: "$.a", XPath: "a"}).Validate())
+	assert.Error(t, (&DocumentExpression{JSONPath: "$.a["}).Validate())
+	assert.Error(t, (&DocumentExpression{XPath: "//a["}).Validate())
+}
+
+func TestDocumentExpression_QueryValue(t *testing.T) {
+	jsonExpression := DocumentExpression{JSONPath: "$.system.serial"}
+	doc, err := jsonExpression.Parse([]byte(`{"system": {"serial": 12345678901234, "vendor": null}}`))
+	require.NoError(t, err)
+
+	v, err := jsonExpression.QueryValue(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, value.New("12345678901234"), v)
+
+	_, err = (&DocumentExpression{JSONPath: "$.system.vendor"}).QueryValue(doc)
+	assert.True(t, tholaerr.IsNotFoundError(err))
+	_, err = (&DocumentExpression{JSONPath: "$.system.model"}).QueryValue(doc)
+	assert.True(t, tholaerr.IsNotFoundError(err))
+	_, err = (&DocumentExpression{JSONPath: "$.system"}).QueryValue(doc)
+	assert.Error(t, err)
+
+	xmlExpression := DocumentExpression{XPath: "/system/@vendor"}
+	doc, err = xmlExpression.Parse([]byte(`<system vendor="VMware"><version> 7.0.2 </version></system>`))
+	require.NoError(t, err)
+
+	v, err = xmlExpression.QueryValue(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, value.New("VMware"), v)
+
+	v, err = (&DocumentExpression{XPath: "/system/version"}).QueryValue(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, value.New("7.0.2"), v)
+
+	_, err = (&DocumentExpression{XPath: "/system/model"}).QueryValue(doc)
+	assert.True(t, tholaerr.IsNotFoundError(err))
+}
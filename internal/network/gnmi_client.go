@@ -0,0 +1,493 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GNMIClient is used for communication over gNMI.
+// The responses are converted to json documents, so values can be read with the same expressions as http responses.
+type GNMIClient struct {
+	host string
+	port int
+
+	username string
+	password string
+
+	insecure  bool
+	tlsConfig *tls.Config
+
+	timeout time.Duration
+
+	useCache bool
+	cache    requestCache
+
+	mutex  sync.Mutex
+	conn   *grpc.ClientConn
+	client gnmi.GNMIClient
+}
+
+// NewGNMIClient returns a new gNMI client.
+// If insecure is true, the connection is not encrypted, otherwise TLS is used and the certificate of the device
+// is verified against the system pool.
+func NewGNMIClient(host string, port int, username, password string, insecure bool) (*GNMIClient, error) {
+	if host == "" {
+		return nil, errors.New("invalid host")
+	}
+	if port <= 0 {
+		return nil, errors.New("invalid port")
+	}
+	return &GNMIClient{
+		host:      host,
+		port:      port,
+		username:  username,
+		password:  password,
+		insecure:  insecure,
+		tlsConfig: &tls.Config{},
+		timeout:   15 * time.Second,
+		useCache:  true,
+		cache:     newRequestCache("gnmi"),
+	}, nil
+}
+
+// SetTimeout sets a timeout for every request.
+func (g *GNMIClient) SetTimeout(timeout time.Duration) {
+	g.timeout = timeout
+}
+
+// InsecureSkipVerify defines whether the certificate of the device is accepted without verification.
+func (g *GNMIClient) InsecureSkipVerify(b bool) {
+	g.tlsConfig.InsecureSkipVerify = b
+}
+
+// SetRootCAs sets the CA bundle which is used to verify the certificate of the device instead of the system pool.
+func (g *GNMIClient) SetRootCAs(caFile string) error {
+	pool, err := ReadCertPool(caFile)
+	if err != nil {
+		return err
+	}
+	g.tlsConfig.RootCAs = pool
+	return nil
+}
+
+// UseCache configures whether the gnmi cache should be used or not.
+func (g *GNMIClient) UseCache(b bool) {
+	g.useCache = b
+}
+
+// HasSuccessfulCachedRequest returns if there was at least one successful cached request.
+func (g *GNMIClient) HasSuccessfulCachedRequest() bool {
+	return len(g.cache.getSuccessfulRequests()) > 0
+}
+
+// GetHostname returns the hostname.
+func (g *GNMIClient) GetHostname() string {
+	return g.host
+}
+
+// GetPort returns the port.
+func (g *GNMIClient) GetPort() int {
+	return g.port
+}
+
+// Get reads the subtree of a YANG path, e.g. "/interfaces/interface[name=eth0]/state".
+// The subtree is returned as json document whose structure follows the YANG model, lists are json arrays.
+// If the device does not implement the gNMI Get RPC, a subscription with mode ONCE is used instead.
+func (g *GNMIClient) Get(ctx context.Context, path string) (interface{}, error) {
+	if g.useCache {
+		x, err := g.cache.get(path)
+		if err == nil {
+			if x.returnedError() {
+				return nil, x.err
+			}
+			return x.res, nil
+		}
+	}
+
+	res, err := g.get(ctx, path)
+	if g.useCache {
+		g.cache.add(path, res, err)
+	}
+	return res, err
+}
+
+func (g *GNMIClient) get(ctx context.Context, path string) (interface{}, error) {
+	gnmiPath, err := ParseGNMIPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := g.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+	if g.username != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "username", g.username, "password", g.password)
+	}
+
+	var notifications []*gnmi.Notification
+	res, err := client.Get(ctx, &gnmi.GetRequest{
+		Path:     []*gnmi.Path{gnmiPath},
+		Type:     gnmi.GetRequest_ALL,
+		Encoding: gnmi.Encoding_JSON_IETF,
+	})
+	if status.Code(err) == codes.Unimplemented {
+		notifications, err = subscribeOnce(ctx, client, gnmiPath)
+	} else if err == nil {
+		notifications = res.GetNotification()
+	}
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, tholaerr.NewNotFoundError(fmt.Sprintf("path '%s' does not exist", path))
+		}
+		return nil, tholaerr.NewGNMIError(err.Error())
+	}
+
+	doc, err := gnmiNotificationsToDocument(notifications)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert gnmi response")
+	}
+	return gnmiSubtree(doc, gnmiPath)
+}
+
+func subscribeOnce(ctx context.Context, client gnmi.GNMIClient, path *gnmi.Path) ([]*gnmi.Notification, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.Subscribe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	err = stream.Send(&gnmi.SubscribeRequest{
+		Request: &gnmi.SubscribeRequest_Subscribe{
+			Subscribe: &gnmi.SubscriptionList{
+				Subscription: []*gnmi.Subscription{{Path: path}},
+				Mode:         gnmi.SubscriptionList_ONCE,
+				Encoding:     gnmi.Encoding_JSON_IETF,
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var notifications []*gnmi.Notification
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return notifications, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch r := res.GetResponse().(type) {
+		case *gnmi.SubscribeResponse_Update:
+			notifications = append(notifications, r.Update)
+		case *gnmi.SubscribeResponse_SyncResponse:
+			return notifications, nil
+		}
+	}
+}
+
+func (g *GNMIClient) getClient(ctx context.Context) (gnmi.GNMIClient, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if g.client != nil {
+		return g.client, nil
+	}
+
+	transportCredentials := grpc.WithTransportCredentials(credentials.NewTLS(g.tlsConfig))
+	if g.insecure {
+		transportCredentials = grpc.WithInsecure()
+	}
+	conn, err := grpc.DialContext(ctx, net.JoinHostPort(g.host, strconv.Itoa(g.port)), transportCredentials)
+	if err != nil {
+		return nil, tholaerr.NewGNMIError(err.Error())
+	}
+	g.conn = conn
+	g.client = gnmi.NewGNMIClient(conn)
+	return g.client, nil
+}
+
+// Disconnect closes the gnmi connection.
+func (g *GNMIClient) Disconnect() error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.conn == nil {
+		return nil
+	}
+	err := g.conn.Close()
+	g.conn = nil
+	g.client = nil
+	return err
+}
+
+// ParseGNMIPath parses a YANG path like "/components/component[name=FAN1]/state" to a gnmi path.
+func ParseGNMIPath(path string) (*gnmi.Path, error) {
+	var origin string
+	if idx := strings.Index(path, ":/"); idx != -1 && !strings.ContainsAny(path[:idx], "/[") {
+		origin = path[:idx]
+		path = path[idx+1:]
+	}
+	path = strings.TrimPrefix(path, "/")
+
+	var elems []*gnmi.PathElem
+	var elem *gnmi.PathElem
+	for len(path) > 0 {
+		switch {
+		case path[0] == '/':
+			if elem == nil {
+				return nil, errors.New("empty path element")
+			}
+			elems = append(elems, elem)
+			elem = nil
+			path = path[1:]
+		case path[0] == '[':
+			if elem == nil {
+				return nil, errors.New("key without path element")
+			}
+			end := strings.IndexByte(path, ']')
+			eq := strings.IndexByte(path, '=')
+			if end == -1 || eq == -1 || eq > end {
+				return nil, errors.New("invalid key in path")
+			}
+			if elem.Key == nil {
+				elem.Key = make(map[string]string)
+			}
+			elem.Key[path[1:eq]] = path[eq+1 : end]
+			path = path[end+1:]
+		default:
+			if elem != nil {
+				return nil, errors.New("invalid path element")
+			}
+			end := strings.IndexAny(path, "/[")
+			if end == -1 {
+				end = len(path)
+			}
+			elem = &gnmi.PathElem{Name: path[:end]}
+			path = path[end:]
+		}
+	}
+	if elem != nil {
+		elems = append(elems, elem)
+	}
+	return &gnmi.Path{Origin: origin, Elem: elems}, nil
+}
+
+// gnmiNotificationsToDocument merges all updates of the notifications into one json document.
+func gnmiNotificationsToDocument(notifications []*gnmi.Notification) (map[string]interface{}, error) {
+	doc := make(map[string]interface{})
+	for _, notification := range notifications {
+		for _, update := range notification.GetUpdate() {
+			val, err := gnmiTypedValue(update.GetVal())
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to convert value")
+			}
+			elems := append(append([]*gnmi.PathElem{}, notification.GetPrefix().GetElem()...), update.GetPath().GetElem()...)
+			insertGNMIValue(doc, elems, val)
+		}
+	}
+	return doc, nil
+}
+
+func insertGNMIValue(node map[string]interface{}, elems []*gnmi.PathElem, val interface{}) {
+	if len(elems) == 0 {
+		if m, ok := val.(map[string]interface{}); ok {
+			mergeGNMIDocuments(node, m)
+		}
+		return
+	}
+	for i, elem := range elems {
+		name := stripYANGModule(elem.GetName())
+		last := i == len(elems)-1
+		if len(elem.GetKey()) > 0 {
+			node = gnmiListEntry(node, name, elem.GetKey())
+			if last {
+				if m, ok := val.(map[string]interface{}); ok {
+					mergeGNMIDocuments(node, m)
+				}
+			}
+			continue
+		}
+		if last {
+			node[name] = mergeGNMIValues(node[name], val)
+			return
+		}
+		child, ok := node[name].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			node[name] = child
+		}
+		node = child
+	}
+}
+
+// gnmiListEntry returns the entry of a list with the given keys, the entry is created if it does not exist.
+func gnmiListEntry(node map[string]interface{}, name string, keys map[string]string) map[string]interface{} {
+	list, _ := node[name].([]interface{})
+	for _, e := range list {
+		if entry, ok := e.(map[string]interface{}); ok && gnmiKeysMatch(entry, keys) {
+			return entry
+		}
+	}
+	entry := make(map[string]interface{})
+	for k, v := range keys {
+		entry[k] = v
+	}
+	node[name] = append(list, entry)
+	return entry
+}
+
+func gnmiKeysMatch(entry map[string]interface{}, keys map[string]string) bool {
+	for k, v := range keys {
+		if fmt.Sprint(entry[k]) != v {
+			return false
+		}
+	}
+	return true
+}
+
+func mergeGNMIValues(old, val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		if o, ok := old.(map[string]interface{}); ok {
+			mergeGNMIDocuments(o, v)
+			return o
+		}
+	case []interface{}:
+		if o, ok := old.([]interface{}); ok {
+			return append(o, v...)
+		}
+	}
+	return val
+}
+
+func mergeGNMIDocuments(node, m map[string]interface{}) {
+	for k, v := range m {
+		node[k] = mergeGNMIValues(node[k], v)
+	}
+}
+
+// gnmiSubtree returns the part of the document that belongs to the path.
+func gnmiSubtree(doc interface{}, path *gnmi.Path) (interface{}, error) {
+	node := doc
+	for _, elem := range path.GetElem() {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, tholaerr.NewNotFoundError(fmt.Sprintf("path element '%s' does not exist", elem.GetName()))
+		}
+		child, ok := m[stripYANGModule(elem.GetName())]
+		if !ok {
+			return nil, tholaerr.NewNotFoundError(fmt.Sprintf("path element '%s' does not exist", elem.GetName()))
+		}
+		if list, ok := child.([]interface{}); ok && len(elem.GetKey()) > 0 {
+			child = nil
+			for _, e := range list {
+				if entry, ok := e.(map[string]interface{}); ok && gnmiKeysMatch(entry, elem.GetKey()) {
+					child = entry
+					break
+				}
+			}
+			if child == nil {
+				return nil, tholaerr.NewNotFoundError(fmt.Sprintf("list entry of path element '%s' does not exist", elem.GetName()))
+			}
+		}
+		node = child
+	}
+	return node, nil
+}
+
+func gnmiTypedValue(val *gnmi.TypedValue) (interface{}, error) {
+	switch v := val.GetValue().(type) {
+	case *gnmi.TypedValue_StringVal:
+		return v.StringVal, nil
+	case *gnmi.TypedValue_AsciiVal:
+		return v.AsciiVal, nil
+	case *gnmi.TypedValue_IntVal:
+		return v.IntVal, nil
+	case *gnmi.TypedValue_UintVal:
+		return v.UintVal, nil
+	case *gnmi.TypedValue_BoolVal:
+		return v.BoolVal, nil
+	case *gnmi.TypedValue_FloatVal:
+		return float64(v.FloatVal), nil
+	case *gnmi.TypedValue_DecimalVal:
+		return float64(v.DecimalVal.GetDigits()) / math.Pow10(int(v.DecimalVal.GetPrecision())), nil
+	case *gnmi.TypedValue_BytesVal:
+		return string(v.BytesVal), nil
+	case *gnmi.TypedValue_LeaflistVal:
+		var res []interface{}
+		for _, e := range v.LeaflistVal.GetElement() {
+			x, err := gnmiTypedValue(e)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, x)
+		}
+		return res, nil
+	case *gnmi.TypedValue_JsonVal:
+		return decodeGNMIJSON(v.JsonVal)
+	case *gnmi.TypedValue_JsonIetfVal:
+		return decodeGNMIJSON(v.JsonIetfVal)
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", v)
+	}
+}
+
+func decodeGNMIJSON(b []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var res interface{}
+	if err := decoder.Decode(&res); err != nil {
+		return nil, errors.Wrap(err, "failed to decode json value")
+	}
+	return stripYANGModules(res), nil
+}
+
+// stripYANGModules removes the module prefixes of json_ietf encoded member names, e.g. "openconfig-interfaces:interface".
+func stripYANGModules(i interface{}) interface{} {
+	switch v := i.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			res[stripYANGModule(k)] = stripYANGModules(e)
+		}
+		return res
+	case []interface{}:
+		for idx, e := range v {
+			v[idx] = stripYANGModules(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func stripYANGModule(name string) string {
+	if idx := strings.IndexByte(name, ':'); idx != -1 {
+		return name[idx+1:]
+	}
+	return name
+}
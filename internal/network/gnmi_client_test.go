@@ -0,0 +1,208 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/inexio/thola/internal/network/gnmitest"
+	"github.com/inexio/thola/internal/network/tlstest"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseGNMIPath(t *testing.T) {
+	path, err := ParseGNMIPath("/interfaces/interface[name=Ethernet1/1]/state")
+	require.NoError(t, err)
+	assert.Equal(t, &gnmi.Path{Elem: []*gnmi.PathElem{
+		{Name: "interfaces"},
+		{Name: "interface", Key: map[string]string{"name": "Ethernet1/1"}},
+		{Name: "state"},
+	}}, path)
+
+	path, err = ParseGNMIPath("openconfig:/components/component[name=PSU 1][type=POWER_SUPPLY]")
+	require.NoError(t, err)
+	assert.Equal(t, "openconfig", path.Origin)
+	assert.Equal(t, map[string]string{"name": "PSU 1", "type": "POWER_SUPPLY"}, path.Elem[1].Key)
+
+	_, err = ParseGNMIPath("/interfaces//interface")
+	assert.Error(t, err)
+	_, err = ParseGNMIPath("/interfaces/interface[name]")
+	assert.Error(t, err)
+}
+
+func testGNMIUpdate(t *testing.T, path string, val *gnmi.TypedValue) *gnmi.Update {
+	p, err := ParseGNMIPath(path)
+	require.NoError(t, err)
+	return &gnmi.Update{Path: p, Val: val}
+}
+
+func startTestGNMIServer(t *testing.T) *gnmitest.Server {
+	state, err := json.Marshal(map[string]interface{}{
+		"openconfig-interfaces:name":        "eth1",
+		"openconfig-interfaces:oper-status": "DOWN",
+		"openconfig-interfaces:counters":    map[string]interface{}{"in-octets": "200"},
+	})
+	require.NoError(t, err)
+
+	server, err := gnmitest.NewServer(
+		testGNMIUpdate(t, "/interfaces/interface[name=eth0]/state/name", &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "eth0"}}),
+		testGNMIUpdate(t, "/interfaces/interface[name=eth0]/state/oper-status", &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "UP"}}),
+		testGNMIUpdate(t, "/interfaces/interface[name=eth0]/state/counters/in-octets", &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 100}}),
+		testGNMIUpdate(t, "/interfaces/interface[name=eth1]/state", &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: state}}),
+		testGNMIUpdate(t, "/components/component[name=CPU0]/state/temperature/instant", &gnmi.TypedValue{Value: &gnmi.TypedValue_DecimalVal{DecimalVal: &gnmi.Decimal64{Digits: 455, Precision: 1}}}),
+	)
+	require.NoError(t, err)
+	server.Username = "admin"
+	server.Password = "secret"
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGNMIClient_Get(t *testing.T) {
+	server := startTestGNMIServer(t)
+
+	for _, getUnimplemented := range []bool{false, true} {
+		server.GetUnimplemented = getUnimplemented
+
+		client, err := NewGNMIClient(server.Addr().IP.String(), server.Addr().Port, "admin", "secret", true)
+		require.NoError(t, err)
+		client.SetTimeout(5 * time.Second)
+
+		doc, err := client.Get(context.Background(), "/interfaces/interface")
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{
+				"name": "eth0",
+				"state": map[string]interface{}{
+					"name":        "eth0",
+					"oper-status": "UP",
+					"counters":    map[string]interface{}{"in-octets": uint64(100)},
+				},
+			},
+			map[string]interface{}{
+				"name": "eth1",
+				"state": map[string]interface{}{
+					"name":        "eth1",
+					"oper-status": "DOWN",
+					"counters":    map[string]interface{}{"in-octets": "200"},
+				},
+			},
+		}, doc)
+
+		doc, err = client.Get(context.Background(), "/components/component[name=CPU0]/state/temperature/instant")
+		require.NoError(t, err)
+		assert.Equal(t, 45.5, doc)
+
+		_, err = client.Get(context.Background(), "/system")
+		assert.True(t, tholaerr.IsNotFoundError(err))
+
+		assert.True(t, client.HasSuccessfulCachedRequest())
+		assert.NoError(t, client.Disconnect())
+	}
+}
+
+func TestGNMIClient_Get_authFailed(t *testing.T) {
+	server := startTestGNMIServer(t)
+
+	client, err := NewGNMIClient(server.Addr().IP.String(), server.Addr().Port, "admin", "wrong", true)
+	require.NoError(t, err)
+	defer client.Disconnect()
+
+	_, err = client.Get(context.Background(), "/interfaces/interface")
+	assert.True(t, tholaerr.IsNetworkError(err))
+}
+
+func TestGNMIRequestConfiguration_Request(t *testing.T) {
+	server := startTestGNMIServer(t)
+	client, err := NewGNMIClient(server.Addr().IP.String(), server.Addr().Port, "admin", "secret", true)
+	require.NoError(t, err)
+	defer client.Disconnect()
+	ctx := NewContextWithDeviceConnection(context.Background(), &RequestDeviceConnection{
+		GNMI: &RequestDeviceConnectionGNMI{GNMIClient: client},
+	})
+
+	request := GNMIRequestConfiguration{Path: "/interfaces/interface[name=eth1]/state/oper-status"}
+	require.NoError(t, request.Validate())
+	doc, err := request.Request(ctx)
+	require.NoError(t, err)
+	v, err := request.QueryValue(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, "DOWN", v.String())
+
+	request = GNMIRequestConfiguration{Path: "/interfaces/interface", DocumentExpression: DocumentExpression{JSONPath: `$[*].state.counters["in-octets"]`}}
+	require.NoError(t, request.Validate())
+	doc, err = request.Request(ctx)
+	require.NoError(t, err)
+	nodes, err := request.Query(doc)
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 2)
+
+	assert.Error(t, (&GNMIRequestConfiguration{Path: "/interfaces", DocumentExpression: DocumentExpression{XPath: "a"}}).Validate())
+}
+
+func TestRequestDeviceConnection_GetIdealConnectionData_gnmi(t *testing.T) {
+	server := startTestGNMIServer(t)
+	client, err := NewGNMIClient(server.Addr().IP.String(), server.Addr().Port, "admin", "secret", true)
+	require.NoError(t, err)
+	defer client.Disconnect()
+	con := RequestDeviceConnection{GNMI: &RequestDeviceConnectionGNMI{GNMIClient: client}}
+
+	assert.Nil(t, con.GetIdealConnectionData().GNMI, "unused gnmi connection data is not cached")
+
+	_, err = client.Get(context.Background(), "/interfaces/interface")
+	require.NoError(t, err)
+	username, insecure := "admin", true
+	assert.Equal(t, &GNMIConnectionData{Ports: []int{server.Addr().Port}, Username: &username, Insecure: &insecure}, con.GetIdealConnectionData().GNMI)
+}
+
+func TestGNMIClient_Get_tls(t *testing.T) {
+	ca, err := tlstest.NewCA(t.TempDir())
+	require.NoError(t, err)
+	cert, err := ca.IssueServer("gnmi")
+	require.NoError(t, err)
+	otherCA, err := tlstest.NewCA(t.TempDir())
+	require.NoError(t, err)
+
+	server, err := gnmitest.NewTLSServer(cert.CertFile, cert.KeyFile,
+		testGNMIUpdate(t, "/system/state/hostname", &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "router"}}),
+	)
+	require.NoError(t, err)
+	server.Username = "admin"
+	server.Password = "secret"
+	defer server.Close()
+
+	tests := []struct {
+		name               string
+		caFile             string
+		insecureSkipVerify bool
+		valid              bool
+	}{
+		{"unknown CA", "", false, false},
+		{"other CA", otherCA.CertFile, false, false},
+		{"CA file", ca.CertFile, false, true},
+		{"skip verify", "", true, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, err := NewGNMIClient(server.Addr().IP.String(), server.Addr().Port, "admin", "secret", false)
+			require.NoError(t, err)
+			defer client.Disconnect()
+			client.SetTimeout(5 * time.Second)
+			client.InsecureSkipVerify(test.insecureSkipVerify)
+			if test.caFile != "" {
+				require.NoError(t, client.SetRootCAs(test.caFile))
+			}
+
+			doc, err := client.Get(context.Background(), "/system/state/hostname")
+			if test.valid {
+				require.NoError(t, err)
+				assert.Equal(t, "router", doc)
+			} else {
+				assert.True(t, tholaerr.IsNetworkError(err))
+			}
+		})
+	}
+}
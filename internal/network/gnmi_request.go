@@ -0,0 +1,43 @@
+package network
+
+import (
+	"context"
+	"github.com/pkg/errors"
+)
+
+// GNMIRequestConfiguration represents the configuration needed to read values from a gNMI response.
+type GNMIRequestConfiguration struct {
+	Path               string `yaml:"path" mapstructure:"path"`
+	DocumentExpression `yaml:",inline" mapstructure:",squash"`
+}
+
+// Validate checks if the gnmi request configuration is valid.
+// If no jsonpath is set, it defaults to "$" which selects the whole subtree of the path.
+func (g *GNMIRequestConfiguration) Validate() error {
+	if g.Path == "" {
+		return errors.New("path is missing")
+	}
+	if _, err := ParseGNMIPath(g.Path); err != nil {
+		return errors.Wrap(err, "invalid path")
+	}
+	if g.XPath != "" {
+		return errors.New("xpath cannot be used for gnmi responses")
+	}
+	if g.JSONPath == "" {
+		g.JSONPath = "$"
+	}
+	return g.DocumentExpression.Validate()
+}
+
+// Request reads the subtree of the path and returns it as json document, which can be queried by the expression.
+func (g *GNMIRequestConfiguration) Request(ctx context.Context) (interface{}, error) {
+	con, ok := DeviceConnectionFromContext(ctx)
+	if !ok || con.GNMI == nil || con.GNMI.GNMIClient == nil {
+		return nil, errors.New("no gnmi connection data available")
+	}
+	doc, err := con.GNMI.GNMIClient.Get(ctx, g.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "gnmi request failed")
+	}
+	return doc, nil
+}
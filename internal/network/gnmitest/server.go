@@ -0,0 +1,158 @@
+// Package gnmitest contains a local gNMI server for tests.
+package gnmitest
+
+import (
+	"context"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"net"
+	"strings"
+)
+
+// Server is a gNMI server that answers Get and Subscribe ONCE requests with a fixed set of updates.
+type Server struct {
+	gnmi.UnimplementedGNMIServer
+
+	// GetUnimplemented makes the Get RPC return codes.Unimplemented, like devices that only support subscriptions.
+	GetUnimplemented bool
+	// Username and Password are checked if Username is not empty.
+	Username string
+	Password string
+
+	updates  []*gnmi.Update
+	listener net.Listener
+	server   *grpc.Server
+}
+
+// NewServer starts a new gNMI server on a random local port which serves the given updates.
+// The paths of the updates need to be absolute.
+func NewServer(updates ...*gnmi.Update) (*Server, error) {
+	return newServer(updates)
+}
+
+// NewTLSServer is like NewServer, but the server uses TLS with the given certificate.
+func NewTLSServer(certFile, keyFile string, updates ...*gnmi.Update) (*Server, error) {
+	creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load server certificate")
+	}
+	return newServer(updates, grpc.Creds(creds))
+}
+
+func newServer(updates []*gnmi.Update, opts ...grpc.ServerOption) (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen")
+	}
+	s := &Server{
+		updates:  updates,
+		listener: listener,
+		server:   grpc.NewServer(opts...),
+	}
+	gnmi.RegisterGNMIServer(s.server, s)
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+	return s, nil
+}
+
+// Addr returns the address of the server.
+func (s *Server) Addr() *net.TCPAddr {
+	return s.listener.Addr().(*net.TCPAddr)
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	s.server.Stop()
+}
+
+// Get returns all updates in the subtrees of the requested paths and the updates that contain the requested paths.
+func (s *Server) Get(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetResponse, error) {
+	if s.GetUnimplemented {
+		return nil, status.Error(codes.Unimplemented, "get is not implemented")
+	}
+	if err := s.authenticate(ctx); err != nil {
+		return nil, err
+	}
+	var notifications []*gnmi.Notification
+	for _, path := range req.GetPath() {
+		notification, err := s.notification(path)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, notification)
+	}
+	return &gnmi.GetResponse{Notification: notifications}, nil
+}
+
+// Subscribe supports subscriptions with mode ONCE.
+func (s *Server) Subscribe(stream gnmi.GNMI_SubscribeServer) error {
+	if err := s.authenticate(stream.Context()); err != nil {
+		return err
+	}
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if req.GetSubscribe().GetMode() != gnmi.SubscriptionList_ONCE {
+		return status.Error(codes.Unimplemented, "only subscriptions with mode ONCE are supported")
+	}
+	for _, subscription := range req.GetSubscribe().GetSubscription() {
+		notification, err := s.notification(subscription.GetPath())
+		if err != nil {
+			return err
+		}
+		err = stream.Send(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: notification}})
+		if err != nil {
+			return err
+		}
+	}
+	return stream.Send(&gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true}})
+}
+
+func (s *Server) authenticate(ctx context.Context) error {
+	if s.Username == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if strings.Join(md.Get("username"), "") != s.Username || strings.Join(md.Get("password"), "") != s.Password {
+		return status.Error(codes.Unauthenticated, "invalid username or password")
+	}
+	return nil
+}
+
+func (s *Server) notification(path *gnmi.Path) (*gnmi.Notification, error) {
+	notification := &gnmi.Notification{}
+	for _, update := range s.updates {
+		// updates with json values can contain the requested path
+		if hasPrefix(update.GetPath(), path) || hasPrefix(path, update.GetPath()) {
+			notification.Update = append(notification.Update, update)
+		}
+	}
+	if len(notification.Update) == 0 {
+		return nil, status.Error(codes.NotFound, "path does not exist")
+	}
+	return notification, nil
+}
+
+func hasPrefix(path, prefix *gnmi.Path) bool {
+	if len(prefix.GetElem()) > len(path.GetElem()) {
+		return false
+	}
+	for i, elem := range prefix.GetElem() {
+		if elem.GetName() != path.GetElem()[i].GetName() {
+			return false
+		}
+		for k, v := range elem.GetKey() {
+			if v != "*" && path.GetElem()[i].GetKey()[k] != v {
+				return false
+			}
+		}
+	}
+	return true
+}
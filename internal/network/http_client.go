@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -17,7 +18,8 @@ import (
 
 // HTTPClient is used for communication over HTTP(s).
 type HTTPClient struct {
-	client *resty.Client
+	client    *resty.Client
+	tlsConfig *tls.Config
 
 	host string
 	port *int
@@ -51,7 +53,7 @@ func NewHTTPClient(URI string) (*HTTPClient, error) {
 		uri += path
 	}
 
-	httpClient := HTTPClient{host: uri, client: resty.New(), useAuth: false, useHTTPS: true, useCache: true, cache: newRequestCache(), format: "application/json"}
+	httpClient := HTTPClient{host: uri, client: resty.New(), tlsConfig: &tls.Config{}, useAuth: false, useHTTPS: true, useCache: true, cache: newRequestCache("http"), format: "application/json"}
 
 	if u.Scheme == "http" {
 		httpClient.useHTTPS = false
@@ -102,7 +104,30 @@ func (h *HTTPClient) SetTimeout(timeout time.Duration) {
 
 // InsecureSSLCert defines weather insecure ssl certificates are allowed.
 func (h *HTTPClient) InsecureSSLCert(b bool) {
-	h.client.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: b})
+	h.tlsConfig.InsecureSkipVerify = b
+	h.client.SetTLSClientConfig(h.tlsConfig)
+}
+
+// SetClientCertificate sets the certificate which is sent to servers that require client authentication.
+func (h *HTTPClient) SetClientCertificate(certFile, keyFile string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return errors.Wrap(err, "failed to load client certificate")
+	}
+	h.tlsConfig.Certificates = []tls.Certificate{cert}
+	h.client.SetTLSClientConfig(h.tlsConfig)
+	return nil
+}
+
+// SetRootCAs sets the CA bundle which is used to verify the certificates of servers instead of the system pool.
+func (h *HTTPClient) SetRootCAs(caFile string) error {
+	pool, err := ReadCertPool(caFile)
+	if err != nil {
+		return err
+	}
+	h.tlsConfig.RootCAs = pool
+	h.client.SetTLSClientConfig(h.tlsConfig)
+	return nil
 }
 
 // UseCache configures whether the http cache should be used or not.
@@ -131,7 +156,7 @@ func (h *HTTPClient) HasSuccessfulCachedRequest() bool {
 // Request sends an http request.
 func (h *HTTPClient) Request(ctx context.Context, method, path, body string, header, queryParams map[string]string) (*resty.Response, error) {
 	if h.useCache && method == http.MethodGet {
-		x, err := h.cache.get(h.getRequestCacheKey(path))
+		x, err := h.cache.get(h.getRequestCacheKey(path, header))
 		if err == nil {
 			res, ok := x.res.(*resty.Response)
 			if !ok {
@@ -192,7 +217,7 @@ func (h *HTTPClient) Request(ctx context.Context, method, path, body string, hea
 	}
 	// save cache
 	if h.useCache && method == http.MethodGet {
-		h.cache.add(h.getRequestCacheKey(path), response, err)
+		h.cache.add(h.getRequestCacheKey(path, header), response, err)
 	}
 	if err != nil {
 		return nil, tholaerr.NewHTTPError(err.Error())
@@ -200,8 +225,21 @@ func (h *HTTPClient) Request(ctx context.Context, method, path, body string, hea
 	return response, nil
 }
 
-func (h *HTTPClient) getRequestCacheKey(path string) string {
-	return fmt.Sprintf("%s:%d:%s", h.GetProtocolString(), h.port, path)
+func (h *HTTPClient) getRequestCacheKey(path string, header map[string]string) string {
+	var port int
+	if h.port != nil {
+		port = *h.port
+	}
+	key := fmt.Sprintf("%s:%d:%s", h.GetProtocolString(), port, path)
+	var headerKeys []string
+	for k := range header {
+		headerKeys = append(headerKeys, k)
+	}
+	sort.Strings(headerKeys)
+	for _, k := range headerKeys {
+		key += fmt.Sprintf(":%s=%s", k, header[k])
+	}
+	return key
 }
 
 // GetProtocolString returns the protocol as a string.
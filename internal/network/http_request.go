@@ -0,0 +1,77 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/inexio/thola/internal/utility"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"net/http"
+)
+
+// HTTPRequestConfiguration represents the configuration needed to read values from a http response.
+type HTTPRequestConfiguration struct {
+	Method             string            `yaml:"method" mapstructure:"method"`
+	Path               string            `yaml:"path" mapstructure:"path"`
+	Body               string            `yaml:"body" mapstructure:"body"`
+	Headers            map[string]string `yaml:"headers" mapstructure:"headers"`
+	DocumentExpression `yaml:",inline" mapstructure:",squash"`
+}
+
+// Validate checks if the http request configuration is valid.
+func (h *HTTPRequestConfiguration) Validate() error {
+	switch h.Method {
+	case "", http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete:
+	default:
+		return fmt.Errorf("invalid http method '%s'", h.Method)
+	}
+	if h.Path == "" {
+		return errors.New("path is missing")
+	}
+	return h.DocumentExpression.Validate()
+}
+
+// Request sends the http request and returns the parsed response body, which can be queried by the expression.
+func (h *HTTPRequestConfiguration) Request(ctx context.Context) (interface{}, error) {
+	con, ok := DeviceConnectionFromContext(ctx)
+	if !ok || con.HTTP == nil || con.HTTP.HTTPClient == nil {
+		return nil, errors.New("no http connection data available")
+	}
+
+	method := h.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	r, err := con.HTTP.request(ctx, method, h.Path, h.Body, h.Headers)
+	if err != nil {
+		return nil, errors.Wrap(err, "http request failed")
+	}
+	if r.IsError() {
+		return nil, tholaerr.NewNotFoundError(fmt.Sprintf("http request to '%s' returned status %d", h.Path, r.StatusCode()))
+	}
+	return h.DocumentExpression.Parse(r.Body())
+}
+
+// request sends a http request to the device.
+// If the device does not answer, the configured https and http ports are tried like in the http conditions.
+func (r *RequestDeviceConnectionHTTP) request(ctx context.Context, method, path, body string, headers map[string]string) (*resty.Response, error) {
+	res, err := r.HTTPClient.Request(ctx, method, path, body, headers, nil)
+	if err == nil || !tholaerr.IsNetworkError(err) || r.ConnectionData == nil {
+		return res, err
+	}
+	for _, useHTTPS := range []bool{true, false} {
+		r.HTTPClient.UseHTTPS(useHTTPS)
+		for _, port := range utility.IfThenElse(useHTTPS, r.ConnectionData.HTTPSPorts, r.ConnectionData.HTTPPorts).([]int) {
+			r.HTTPClient.SetPort(port)
+			res, err = r.HTTPClient.Request(ctx, method, path, body, headers, nil)
+			if err == nil || !tholaerr.IsNetworkError(err) {
+				return res, err
+			}
+			log.Ctx(ctx).Debug().Err(err).Str("protocol", r.HTTPClient.GetProtocolString()).Int("port", port).Msg("http(s) request returned error")
+		}
+	}
+	return res, err
+}
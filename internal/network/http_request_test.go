@@ -0,0 +1,65 @@
+package network
+
+import (
+	"context"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/inexio/thola/internal/value"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestHTTPRequestConfiguration_Request(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-Api-Version") != "2" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"vendor": "VMware"}`))
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	// the client starts with https on the default port, so the configured http port has to be found
+	client, err := NewHTTPClient("https://" + u.Hostname())
+	require.NoError(t, err)
+	ctx := NewContextWithDeviceConnection(context.Background(), &RequestDeviceConnection{
+		HTTP: &RequestDeviceConnectionHTTP{
+			HTTPClient:     client,
+			ConnectionData: &HTTPConnectionData{HTTPPorts: []int{port}},
+		},
+	})
+
+	sut := HTTPRequestConfiguration{
+		Path:               "/api/system",
+		Headers:            map[string]string{"X-Api-Version": "2"},
+		DocumentExpression: DocumentExpression{JSONPath: "$.vendor"},
+	}
+	require.NoError(t, sut.Validate())
+
+	doc, err := sut.Request(ctx)
+	require.NoError(t, err)
+	v, err := sut.QueryValue(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, value.New("VMware"), v)
+
+	// the response is cached
+	_, err = sut.Request(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	sut.Headers = nil
+	_, err = sut.Request(ctx)
+	assert.True(t, tholaerr.IsNotFoundError(err))
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,319 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/antchfx/xmlquery"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	netconfEndOfMessage = "]]>]]>"
+	netconfBase11       = "urn:ietf:params:netconf:base:1.1"
+	netconfHello        = `<?xml version="1.0" encoding="UTF-8"?><hello xmlns="urn:ietf:params:xml:ns:netconf:base:1.0"><capabilities><capability>urn:ietf:params:netconf:base:1.0</capability><capability>` + netconfBase11 + `</capability></capabilities></hello>`
+)
+
+// NETCONFClient is used for communication over NETCONF.
+// The replies are parsed as xml documents, so values can be read with the same XPath expressions as http responses.
+type NETCONFClient struct {
+	host string
+	port int
+
+	username string
+	password string
+
+	config *ssh.ClientConfig
+
+	timeout time.Duration
+
+	useCache bool
+	cache    requestCache
+
+	mutex     sync.Mutex
+	conn      net.Conn
+	client    *ssh.Client
+	stdin     io.Writer
+	stdout    *bufio.Reader
+	chunked   bool
+	messageID int
+}
+
+// NewNETCONFClient returns a new NETCONF client.
+// The connection to the device is established when the first rpc is sent.
+func NewNETCONFClient(host string, port int, username, password string) (*NETCONFClient, error) {
+	if host == "" {
+		return nil, errors.New("invalid host")
+	}
+	if port <= 0 {
+		return nil, errors.New("invalid port")
+	}
+	if username == "" {
+		return nil, errors.New("invalid username")
+	}
+	return &NETCONFClient{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		config:   newSSHClientConfig(username, password),
+		timeout:  15 * time.Second,
+		useCache: true,
+		cache:    newRequestCache("netconf"),
+	}, nil
+}
+
+// SetTimeout sets a timeout for the connection setup and every rpc.
+func (n *NETCONFClient) SetTimeout(timeout time.Duration) {
+	n.timeout = timeout
+	n.config.Timeout = timeout
+}
+
+// UseCache configures whether the netconf cache should be used or not.
+func (n *NETCONFClient) UseCache(b bool) {
+	n.useCache = b
+}
+
+// HasSuccessfulCachedRequest returns if there was at least one successful cached request.
+func (n *NETCONFClient) HasSuccessfulCachedRequest() bool {
+	return len(n.cache.getSuccessfulRequests()) > 0
+}
+
+// GetHostname returns the hostname.
+func (n *NETCONFClient) GetHostname() string {
+	return n.host
+}
+
+// GetPort returns the port.
+func (n *NETCONFClient) GetPort() int {
+	return n.port
+}
+
+// Get sends a get rpc with the given subtree filter and returns the data element of the reply.
+// If the filter is empty, the whole running configuration and state data is requested.
+func (n *NETCONFClient) Get(ctx context.Context, filter string) (*xmlquery.Node, error) {
+	if n.useCache {
+		x, err := n.cache.get(filter)
+		if err == nil {
+			if x.returnedError() {
+				return nil, x.err
+			}
+			res, ok := x.res.(*xmlquery.Node)
+			if !ok {
+				return nil, errors.New("cached netconf result is not a xml node")
+			}
+			return res, nil
+		}
+	}
+
+	res, err := n.get(ctx, filter)
+	if n.useCache {
+		n.cache.add(filter, res, err)
+	}
+	return res, err
+}
+
+func (n *NETCONFClient) get(ctx context.Context, filter string) (*xmlquery.Node, error) {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if n.client == nil {
+		err := n.connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if filter != "" {
+		filter = `<filter type="subtree">` + filter + `</filter>`
+	}
+	n.messageID++
+	rpc := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?><rpc message-id="%d" xmlns="urn:ietf:params:xml:ns:netconf:base:1.0"><get>%s</get></rpc>`, n.messageID, filter)
+
+	n.setDeadline(ctx)
+	err := n.writeMessage(rpc)
+	if err != nil {
+		_ = n.disconnect()
+		return nil, tholaerr.NewNETCONFError(fmt.Sprintf("failed to send rpc: %s", err))
+	}
+	reply, err := n.readMessage()
+	if err != nil {
+		_ = n.disconnect()
+		return nil, tholaerr.NewNETCONFError(fmt.Sprintf("failed to read rpc reply: %s", err))
+	}
+	_ = n.conn.SetDeadline(time.Time{})
+
+	doc, err := xmlquery.Parse(bytes.NewReader(reply))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse rpc reply")
+	}
+	if rpcError := xmlquery.FindOne(doc, "//*[local-name()='rpc-error']"); rpcError != nil {
+		msg := strings.TrimSpace(rpcError.InnerText())
+		if errorMessage := xmlquery.FindOne(rpcError, "*[local-name()='error-message']"); errorMessage != nil {
+			msg = strings.TrimSpace(errorMessage.InnerText())
+		}
+		return nil, fmt.Errorf("rpc returned error: %s", msg)
+	}
+	data := xmlquery.FindOne(doc, "//*[local-name()='data']")
+	if data == nil {
+		return nil, tholaerr.NewNotFoundError("rpc reply contains no data")
+	}
+	return data, nil
+}
+
+func (n *NETCONFClient) connect(ctx context.Context) error {
+	address := net.JoinHostPort(n.host, strconv.Itoa(n.port))
+	dialer := net.Dialer{Timeout: n.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return tholaerr.NewNETCONFError(err.Error())
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(n.timeout))
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, address, n.config)
+	if err != nil {
+		_ = conn.Close()
+		return tholaerr.NewNETCONFError(fmt.Sprintf("ssh handshake failed: %s", err))
+	}
+	client := ssh.NewClient(sshConn, chans, reqs)
+
+	session, err := client.NewSession()
+	if err != nil {
+		_ = client.Close()
+		return tholaerr.NewNETCONFError(fmt.Sprintf("failed to open ssh session: %s", err))
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		_ = client.Close()
+		return errors.Wrap(err, "failed to get stdin of ssh session")
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		_ = client.Close()
+		return errors.Wrap(err, "failed to get stdout of ssh session")
+	}
+	err = session.RequestSubsystem("netconf")
+	if err != nil {
+		_ = client.Close()
+		return tholaerr.NewNETCONFError(fmt.Sprintf("failed to start netconf subsystem: %s", err))
+	}
+
+	n.conn = conn
+	n.client = client
+	n.stdin = stdin
+	n.stdout = bufio.NewReader(stdout)
+	n.chunked = false
+
+	// the hello messages are always framed with the end of message marker
+	err = n.writeMessage(netconfHello)
+	if err != nil {
+		_ = n.disconnect()
+		return tholaerr.NewNETCONFError(fmt.Sprintf("failed to send hello: %s", err))
+	}
+	hello, err := n.readMessage()
+	if err != nil {
+		_ = n.disconnect()
+		return tholaerr.NewNETCONFError(fmt.Sprintf("failed to read hello: %s", err))
+	}
+	n.chunked = bytes.Contains(hello, []byte(netconfBase11))
+	_ = conn.SetDeadline(time.Time{})
+	return nil
+}
+
+func (n *NETCONFClient) setDeadline(ctx context.Context) {
+	deadline := time.Now().Add(n.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = n.conn.SetDeadline(deadline)
+}
+
+func (n *NETCONFClient) writeMessage(msg string) error {
+	if n.chunked {
+		msg = fmt.Sprintf("\n#%d\n%s\n##\n", len(msg), msg)
+	} else {
+		msg += netconfEndOfMessage
+	}
+	_, err := io.WriteString(n.stdin, msg)
+	return err
+}
+
+func (n *NETCONFClient) readMessage() ([]byte, error) {
+	if n.chunked {
+		return readNETCONFChunkedMessage(n.stdout)
+	}
+	var msg []byte
+	for {
+		b, err := n.stdout.ReadBytes('>')
+		msg = append(msg, b...)
+		if bytes.HasSuffix(msg, []byte(netconfEndOfMessage)) {
+			return msg[:len(msg)-len(netconfEndOfMessage)], nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readNETCONFChunkedMessage reads a message with chunked framing (RFC 6242).
+// Every chunk starts with "\n#<size>\n", the message ends with "\n##\n".
+func readNETCONFChunkedMessage(r *bufio.Reader) ([]byte, error) {
+	var msg []byte
+	for {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if header != "\n" {
+			return nil, fmt.Errorf("invalid chunk header '%s'", header)
+		}
+		header, err = r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if header == "##\n" {
+			return msg, nil
+		}
+		if !strings.HasPrefix(header, "#") {
+			return nil, fmt.Errorf("invalid chunk header '%s'", header)
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("invalid chunk size '%s'", header)
+		}
+		chunk := make([]byte, size)
+		_, err = io.ReadFull(r, chunk)
+		if err != nil {
+			return nil, err
+		}
+		msg = append(msg, chunk...)
+	}
+}
+
+// Disconnect closes the netconf connection.
+func (n *NETCONFClient) Disconnect() error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	return n.disconnect()
+}
+
+func (n *NETCONFClient) disconnect() error {
+	if n.client == nil {
+		return nil
+	}
+	err := n.client.Close()
+	n.conn = nil
+	n.client = nil
+	n.stdin = nil
+	n.stdout = nil
+	return err
+}
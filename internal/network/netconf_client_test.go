@@ -0,0 +1,104 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/antchfx/xmlquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testNETCONFData = `<data><interfaces xmlns="http://openconfig.net/yang/interfaces"><interface><name>eth0</name><state><oper-status>UP</oper-status></state></interface><interface><name>eth1</name><state><oper-status>DOWN</oper-status></state></interface></interfaces></data>`
+
+// serveTestNETCONF behaves like the netconf subsystem of a device, it answers every get rpc with the test data.
+func serveTestNETCONF(base11 bool) func(channel ssh.Channel) {
+	return func(channel ssh.Channel) {
+		capabilities := "<capability>urn:ietf:params:netconf:base:1.0</capability>"
+		if base11 {
+			capabilities += "<capability>" + netconfBase11 + "</capability>"
+		}
+		_, _ = io.WriteString(channel, `<hello xmlns="urn:ietf:params:xml:ns:netconf:base:1.0"><capabilities>`+capabilities+`</capabilities><session-id>1</session-id></hello>`+netconfEndOfMessage)
+
+		server := NETCONFClient{stdin: channel, stdout: bufio.NewReader(channel)}
+		if _, err := server.readMessage(); err != nil {
+			return
+		}
+		server.chunked = base11
+		for {
+			msg, err := server.readMessage()
+			if err != nil {
+				return
+			}
+			rpc, err := xmlquery.Parse(bytes.NewReader(msg))
+			if err != nil {
+				return
+			}
+			messageID := xmlquery.FindOne(rpc, "/rpc/@message-id").InnerText()
+			reply := `<rpc-reply xmlns="urn:ietf:params:xml:ns:netconf:base:1.0" message-id="` + messageID + `">`
+			if filter := xmlquery.FindOne(rpc, "/rpc/get/filter"); filter != nil && strings.Contains(filter.OutputXML(false), "unknown") {
+				reply += `<rpc-error><error-type>application</error-type><error-message>unknown element</error-message></rpc-error>`
+			} else {
+				reply += testNETCONFData
+			}
+			if err := server.writeMessage(reply + `</rpc-reply>`); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestNETCONFClient_Get(t *testing.T) {
+	for _, base11 := range []bool{false, true} {
+		t.Run(fmt.Sprintf("base11=%t", base11), func(t *testing.T) {
+			addr := startTestSSHServer(t, serveTestNETCONF(base11))
+
+			client, err := NewNETCONFClient(addr.IP.String(), addr.Port, "admin", "secret")
+			require.NoError(t, err)
+			client.SetTimeout(5 * time.Second)
+			defer client.Disconnect()
+
+			data, err := client.Get(context.Background(), `<interfaces xmlns="http://openconfig.net/yang/interfaces"/>`)
+			require.NoError(t, err)
+			assert.Equal(t, base11, client.chunked)
+
+			nodes, err := xmlquery.QueryAll(data, "interfaces/interface/name")
+			require.NoError(t, err)
+			require.Len(t, nodes, 2)
+			assert.Equal(t, "eth1", nodes[1].InnerText())
+
+			_, err = client.Get(context.Background(), `<unknown/>`)
+			assert.EqualError(t, err, "rpc returned error: unknown element")
+			assert.True(t, client.HasSuccessfulCachedRequest())
+		})
+	}
+}
+
+func TestNETCONFRequestConfiguration_Request(t *testing.T) {
+	addr := startTestSSHServer(t, serveTestNETCONF(true))
+	client, err := NewNETCONFClient(addr.IP.String(), addr.Port, "admin", "secret")
+	require.NoError(t, err)
+	defer client.Disconnect()
+	ctx := NewContextWithDeviceConnection(context.Background(), &RequestDeviceConnection{
+		NETCONF: &RequestDeviceConnectionNETCONF{NETCONFClient: client},
+	})
+
+	request := NETCONFRequestConfiguration{
+		Filter:             `<interfaces xmlns="http://openconfig.net/yang/interfaces"/>`,
+		DocumentExpression: DocumentExpression{XPath: "interfaces/interface[name='eth0']/state/oper-status"},
+	}
+	require.NoError(t, request.Validate())
+	doc, err := request.Request(ctx)
+	require.NoError(t, err)
+	v, err := request.QueryValue(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, "UP", v.String())
+
+	assert.Error(t, (&NETCONFRequestConfiguration{DocumentExpression: DocumentExpression{JSONPath: "$"}}).Validate())
+}
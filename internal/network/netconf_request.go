@@ -0,0 +1,34 @@
+package network
+
+import (
+	"context"
+	"github.com/pkg/errors"
+)
+
+// NETCONFRequestConfiguration represents the configuration needed to read values from a NETCONF get reply.
+type NETCONFRequestConfiguration struct {
+	Filter             string `yaml:"filter" mapstructure:"filter"`
+	DocumentExpression `yaml:",inline" mapstructure:",squash"`
+}
+
+// Validate checks if the netconf request configuration is valid.
+func (n *NETCONFRequestConfiguration) Validate() error {
+	if n.JSONPath != "" {
+		return errors.New("jsonpath cannot be used for netconf replies")
+	}
+	return n.DocumentExpression.Validate()
+}
+
+// Request sends a get rpc with the subtree filter and returns the data of the reply.
+// XPath expressions are evaluated relative to the data element of the reply.
+func (n *NETCONFRequestConfiguration) Request(ctx context.Context) (interface{}, error) {
+	con, ok := DeviceConnectionFromContext(ctx)
+	if !ok || con.NETCONF == nil || con.NETCONF.NETCONFClient == nil {
+		return nil, errors.New("no netconf connection data available")
+	}
+	data, err := con.NETCONF.NETCONFClient.Get(ctx, n.Filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "netconf request failed")
+	}
+	return data, nil
+}
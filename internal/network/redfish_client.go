@@ -0,0 +1,115 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/pkg/errors"
+	"net/http"
+	"strings"
+)
+
+var redfishHeaders = map[string]string{
+	"Accept":        "application/json",
+	"OData-Version": "4.0",
+}
+
+// RedfishClient reads the resources of a Redfish service over the http connection of a device.
+// Wildcards in resource paths are expanded with the members of the collection,
+// so that e.g. the thermal resources of all chassis can be read with "/redfish/v1/Chassis/*/Thermal".
+type RedfishClient struct {
+	con *RequestDeviceConnectionHTTP
+}
+
+// NewRedfishClient returns a new Redfish client which uses the given http connection.
+func NewRedfishClient(con *RequestDeviceConnectionHTTP) (*RedfishClient, error) {
+	if con == nil || con.HTTPClient == nil {
+		return nil, errors.New("no http connection data available")
+	}
+	return &RedfishClient{con: con}, nil
+}
+
+// Get returns the json document of the resource at the given path.
+// If the path contains wildcards, an array with the resources of all collection members is returned.
+func (r *RedfishClient) Get(ctx context.Context, path string) (interface{}, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if segment != "*" {
+			continue
+		}
+		members, err := r.members(ctx, "/"+strings.Join(segments[:i], "/"))
+		if err != nil {
+			return nil, err
+		}
+		rest := strings.Join(segments[i+1:], "/")
+
+		var docs []interface{}
+		for _, member := range members {
+			memberPath := member
+			if rest != "" {
+				memberPath = strings.TrimSuffix(member, "/") + "/" + rest
+			}
+			doc, err := r.Get(ctx, memberPath)
+			if err != nil {
+				if tholaerr.IsNotFoundError(err) {
+					continue
+				}
+				return nil, err
+			}
+			if nested, ok := doc.([]interface{}); ok && strings.Contains(rest, "*") {
+				docs = append(docs, nested...)
+			} else {
+				docs = append(docs, doc)
+			}
+		}
+		if len(docs) == 0 {
+			return nil, tholaerr.NewNotFoundError(fmt.Sprintf("no resources found for '%s'", path))
+		}
+		return docs, nil
+	}
+	return r.getResource(ctx, path)
+}
+
+// members returns the paths of all members of the collection.
+func (r *RedfishClient) members(ctx context.Context, path string) ([]string, error) {
+	doc, err := r.getResource(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	collection, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("resource '%s' is not a collection", path)
+	}
+	members, ok := collection["Members"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("resource '%s' is not a collection", path)
+	}
+
+	var res []string
+	for _, member := range members {
+		m, ok := member.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid member in collection '%s'", path)
+		}
+		id, ok := m["@odata.id"].(string)
+		if !ok || id == "" {
+			return nil, fmt.Errorf("member without @odata.id in collection '%s'", path)
+		}
+		res = append(res, id)
+	}
+	return res, nil
+}
+
+func (r *RedfishClient) getResource(ctx context.Context, path string) (interface{}, error) {
+	res, err := r.con.request(ctx, http.MethodGet, path, "", redfishHeaders)
+	if err != nil {
+		return nil, errors.Wrap(err, "redfish request failed")
+	}
+	switch {
+	case res.StatusCode() == http.StatusNotFound:
+		return nil, tholaerr.NewNotFoundError(fmt.Sprintf("redfish resource '%s' not found", path))
+	case res.IsError():
+		return nil, fmt.Errorf("redfish request to '%s' returned status %d", path, res.StatusCode())
+	}
+	return (&DocumentExpression{}).Parse(res.Body())
+}
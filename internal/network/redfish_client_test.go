@@ -0,0 +1,76 @@
+package network
+
+import (
+	"context"
+	"github.com/inexio/thola/internal/network/redfishtest"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func startTestRedfishServer(t *testing.T, password string) (*redfishtest.Server, context.Context) {
+	server := redfishtest.NewServer(redfishtest.Resources())
+	server.Username = "admin"
+	server.Password = "secret"
+	t.Cleanup(server.Close)
+
+	httpClient, err := NewHTTPClient(server.URL())
+	require.NoError(t, err)
+	require.NoError(t, httpClient.SetUsernameAndPassword("admin", password))
+	ctx := NewContextWithDeviceConnection(context.Background(), &RequestDeviceConnection{
+		HTTP: &RequestDeviceConnectionHTTP{
+			HTTPClient:     httpClient,
+			ConnectionData: &HTTPConnectionData{HTTPPorts: []int{server.Port()}},
+		},
+	})
+	return server, ctx
+}
+
+func TestRedfishClient_Get(t *testing.T) {
+	_, ctx := startTestRedfishServer(t, "secret")
+	con, _ := DeviceConnectionFromContext(ctx)
+	client, err := NewRedfishClient(con.HTTP)
+	require.NoError(t, err)
+
+	doc, err := client.Get(ctx, "/redfish/v1/Systems/1")
+	require.NoError(t, err)
+	assert.Equal(t, "SN12345", doc.(map[string]interface{})["SerialNumber"])
+
+	doc, err = client.Get(ctx, "/redfish/v1/Chassis/*/Thermal")
+	require.NoError(t, err)
+	require.Len(t, doc, 1)
+	assert.Len(t, doc.([]interface{})[0].(map[string]interface{})["Fans"], 2)
+
+	_, err = client.Get(ctx, "/redfish/v1/Chassis/*/Unknown")
+	assert.True(t, tholaerr.IsNotFoundError(err))
+
+	_, err = client.Get(ctx, "/redfish/v1/Chassis/1/Thermal/*")
+	assert.Error(t, err)
+}
+
+func TestRedfishClient_Get_authFailed(t *testing.T) {
+	_, ctx := startTestRedfishServer(t, "wrong")
+	con, _ := DeviceConnectionFromContext(ctx)
+	client, err := NewRedfishClient(con.HTTP)
+	require.NoError(t, err)
+
+	_, err = client.Get(ctx, "/redfish/v1/Systems/*")
+	assert.Error(t, err)
+	assert.False(t, tholaerr.IsNotFoundError(err))
+}
+
+func TestRedfishRequestConfiguration_Request(t *testing.T) {
+	_, ctx := startTestRedfishServer(t, "secret")
+
+	request := RedfishRequestConfiguration{Path: "/redfish/v1/Chassis/*/Power", DocumentExpression: DocumentExpression{JSONPath: "$[*].PowerSupplies[*].Name"}}
+	require.NoError(t, request.Validate())
+	doc, err := request.Request(ctx)
+	require.NoError(t, err)
+	nodes, err := request.Query(doc)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"PSU1", "PSU2"}, nodes)
+
+	assert.Error(t, (&RedfishRequestConfiguration{Path: "/Chassis"}).Validate())
+	assert.Error(t, (&RedfishRequestConfiguration{Path: "/redfish/v1", DocumentExpression: DocumentExpression{XPath: "a"}}).Validate())
+}
@@ -0,0 +1,42 @@
+package network
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// RedfishRequestConfiguration represents the configuration needed to read values from Redfish resources.
+type RedfishRequestConfiguration struct {
+	Path               string `yaml:"path" mapstructure:"path"`
+	DocumentExpression `yaml:",inline" mapstructure:",squash"`
+}
+
+// Validate checks if the redfish request configuration is valid.
+// If no jsonpath is set, it defaults to "$" which selects the whole resource.
+func (r *RedfishRequestConfiguration) Validate() error {
+	if !strings.HasPrefix(r.Path, "/redfish/") {
+		return errors.New("path needs to start with /redfish/")
+	}
+	if r.XPath != "" {
+		return errors.New("xpath cannot be used for redfish resources")
+	}
+	if r.JSONPath == "" {
+		r.JSONPath = "$"
+	}
+	return r.DocumentExpression.Validate()
+}
+
+// Request reads the resource at the path and returns it as json document, which can be queried by the expression.
+// If the path contains wildcards, the document is an array of the resources of all collection members.
+func (r *RedfishRequestConfiguration) Request(ctx context.Context) (interface{}, error) {
+	con, ok := DeviceConnectionFromContext(ctx)
+	if !ok {
+		return nil, errors.New("no http connection data available")
+	}
+	client, err := NewRedfishClient(con.HTTP)
+	if err != nil {
+		return nil, err
+	}
+	return client.Get(ctx, r.Path)
+}
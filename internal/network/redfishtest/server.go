@@ -0,0 +1,160 @@
+// Package redfishtest contains a local Redfish service for tests.
+package redfishtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Server is a Redfish service that serves a fixed set of resources.
+type Server struct {
+	// Username and Password are checked for all resources except the service root if Username is not empty.
+	Username string
+	Password string
+
+	resources map[string]interface{}
+	server    *httptest.Server
+}
+
+// NewServer starts a new Redfish service on a random local port which serves the given resources.
+// The keys of the resources are their paths without trailing slash, e.g. "/redfish/v1/Chassis".
+func NewServer(resources map[string]interface{}) *Server {
+	s := &Server{
+		resources: resources,
+	}
+	s.server = httptest.NewServer(s)
+	return s
+}
+
+// URL returns the base url of the server.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Port returns the port of the server.
+func (s *Server) Port() int {
+	u, err := url.Parse(s.server.URL)
+	if err != nil {
+		return 0
+	}
+	port, _ := strconv.Atoi(u.Port())
+	return port
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// ServeHTTP answers GET requests with the resource at the path.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "Base.1.0.OperationNotAllowed")
+		return
+	}
+	if s.Username != "" && path != "/redfish/v1" {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != s.Username || password != s.Password {
+			writeError(w, http.StatusUnauthorized, "Base.1.0.NoValidSession")
+			return
+		}
+	}
+	resource, ok := s.resources[path]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Base.1.0.ResourceMissingAtURI")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("OData-Version", "4.0")
+	_ = json.NewEncoder(w).Encode(resource)
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": http.StatusText(status),
+		},
+	})
+}
+
+// Resources returns the resources of a rack server with one chassis, which has
+// two temperature sensors, two fans, two power supplies of which one is absent and one voltage sensor.
+func Resources() map[string]interface{} {
+	return map[string]interface{}{
+		"/redfish/v1": map[string]interface{}{
+			"@odata.id":      "/redfish/v1/",
+			"@odata.type":    "#ServiceRoot.v1_5_0.ServiceRoot",
+			"Id":             "RootService",
+			"Name":           "Root Service",
+			"RedfishVersion": "1.6.0",
+			"Vendor":         "Test",
+			"Chassis":        map[string]interface{}{"@odata.id": "/redfish/v1/Chassis"},
+			"Systems":        map[string]interface{}{"@odata.id": "/redfish/v1/Systems"},
+		},
+		"/redfish/v1/Systems": collection("/redfish/v1/Systems", "/redfish/v1/Systems/1"),
+		"/redfish/v1/Systems/1": map[string]interface{}{
+			"@odata.id":    "/redfish/v1/Systems/1",
+			"Id":           "1",
+			"Manufacturer": "Test Inc.",
+			"Model":        "TestServer R100",
+			"SerialNumber": "SN12345",
+			"BiosVersion":  "2.10.1",
+			"Status":       status("Enabled", "Warning"),
+		},
+		"/redfish/v1/Chassis": collection("/redfish/v1/Chassis", "/redfish/v1/Chassis/1"),
+		"/redfish/v1/Chassis/1": map[string]interface{}{
+			"@odata.id": "/redfish/v1/Chassis/1",
+			"Id":        "1",
+			"Thermal":   map[string]interface{}{"@odata.id": "/redfish/v1/Chassis/1/Thermal"},
+			"Power":     map[string]interface{}{"@odata.id": "/redfish/v1/Chassis/1/Power"},
+		},
+		"/redfish/v1/Chassis/1/Thermal": map[string]interface{}{
+			"@odata.id": "/redfish/v1/Chassis/1/Thermal",
+			"Temperatures": []interface{}{
+				map[string]interface{}{"Name": "CPU1 Temp", "ReadingCelsius": 45.5, "Status": status("Enabled", "OK")},
+				map[string]interface{}{"Name": "Inlet Temp", "ReadingCelsius": 41, "Status": status("Enabled", "Warning")},
+			},
+			"Fans": []interface{}{
+				map[string]interface{}{"Name": "Fan1", "Reading": 6000, "ReadingUnits": "RPM", "Status": status("Enabled", "OK")},
+				map[string]interface{}{"Name": "Fan2", "Reading": 0, "ReadingUnits": "RPM", "Status": status("Enabled", "Critical")},
+			},
+		},
+		"/redfish/v1/Chassis/1/Power": map[string]interface{}{
+			"@odata.id": "/redfish/v1/Chassis/1/Power",
+			"PowerSupplies": []interface{}{
+				map[string]interface{}{"Name": "PSU1", "Status": status("Enabled", "OK")},
+				map[string]interface{}{"Name": "PSU2", "Status": map[string]interface{}{"State": "Absent"}},
+			},
+			"Voltages": []interface{}{
+				map[string]interface{}{"Name": "PSU1 Voltage", "ReadingVolts": 230, "Status": status("Enabled", "OK")},
+			},
+		},
+	}
+}
+
+func collection(path string, members ...string) map[string]interface{} {
+	var m []interface{}
+	for _, member := range members {
+		m = append(m, map[string]interface{}{"@odata.id": member})
+	}
+	return map[string]interface{}{
+		"@odata.id":           path,
+		"Members":             m,
+		"Members@odata.count": len(m),
+	}
+}
+
+func status(state, health string) map[string]interface{} {
+	return map[string]interface{}{
+		"State":  state,
+		"Health": health,
+	}
+}
@@ -11,11 +11,14 @@ requestCache stores all sent requests and its results.
 type requestCache struct {
 	sync.RWMutex
 
+	// name identifies the cache in the statistics, lookups of caches without name are not counted.
+	name  string
 	cache map[string]cachedRequestResult
 }
 
-func newRequestCache() requestCache {
+func newRequestCache(name string) requestCache {
 	return requestCache{
+		name:  name,
 		cache: make(map[string]cachedRequestResult),
 	}
 }
@@ -42,8 +45,12 @@ func (r *requestCache) getSuccessfulRequests() map[string]cachedRequestResult {
 // get returns the cache entry for an identifier, an error if its not in the cache
 func (r *requestCache) get(identifier string) (cachedRequestResult, error) {
 	r.RLock()
-	defer r.RUnlock()
-	if v, ok := (r.cache)[identifier]; ok {
+	v, ok := (r.cache)[identifier]
+	r.RUnlock()
+	if r.name != "" {
+		addCacheLookup(r.name, ok)
+	}
+	if ok {
 		return v, nil
 	}
 	return cachedRequestResult{}, tholaerr.NewNotFoundError("no cache entry for this value")
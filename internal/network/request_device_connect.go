@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/inexio/thola/internal/utility"
 	"github.com/pkg/errors"
+	"time"
 )
 
 // RequestDeviceConnection represents the request device connection
@@ -11,6 +12,9 @@ type RequestDeviceConnection struct {
 	RawConnectionData ConnectionData
 	HTTP              *RequestDeviceConnectionHTTP
 	SNMP              *RequestDeviceConnectionSNMP
+	SSH               *RequestDeviceConnectionSSH
+	GNMI              *RequestDeviceConnectionGNMI
+	NETCONF           *RequestDeviceConnectionNETCONF
 }
 
 // RequestDeviceConnectionHTTP represents the http request device connection
@@ -25,6 +29,24 @@ type RequestDeviceConnectionSNMP struct {
 	CommonOIDs CommonOIDs
 }
 
+// RequestDeviceConnectionSSH represents the ssh request device connection
+type RequestDeviceConnectionSSH struct {
+	SSHClient      *SSHClient
+	ConnectionData *SSHConnectionData
+}
+
+// RequestDeviceConnectionGNMI represents the gnmi request device connection
+type RequestDeviceConnectionGNMI struct {
+	GNMIClient     *GNMIClient
+	ConnectionData *GNMIConnectionData
+}
+
+// RequestDeviceConnectionNETCONF represents the netconf request device connection
+type RequestDeviceConnectionNETCONF struct {
+	NETCONFClient  *NETCONFClient
+	ConnectionData *NETCONFConnectionData
+}
+
 // CommonOIDs represents the common oids
 type CommonOIDs struct {
 	SysObjectID    *string
@@ -66,6 +88,23 @@ func (r *RequestDeviceConnectionSNMP) GetSysObjectID(ctx context.Context) (strin
 	return *r.CommonOIDs.SysObjectID, nil
 }
 
+// GetSysUpTime returns the sysUpTime, which is the time since the snmp agent was last restarted.
+func (r *RequestDeviceConnectionSNMP) GetSysUpTime(ctx context.Context) (time.Duration, error) {
+	response, err := r.SnmpClient.SNMPGet(ctx, "1.3.6.1.2.1.1.3.0")
+	if err != nil {
+		return 0, errors.Wrap(err, "error during snmpget")
+	}
+	sysUpTime, err := response[0].GetValue()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get snmp result")
+	}
+	ticks, err := sysUpTime.UInt64()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse sysUpTime")
+	}
+	return time.Duration(ticks) * 10 * time.Millisecond, nil
+}
+
 // GetIdealConnectionData returns the ideal connection data.
 func (r *RequestDeviceConnection) GetIdealConnectionData() ConnectionData {
 	connectionData := ConnectionData{}
@@ -85,6 +124,9 @@ func (r *RequestDeviceConnection) GetIdealConnectionData() ConnectionData {
 				PrivProtocol: r.SNMP.SnmpClient.GetV3PrivProto(),
 			},
 		}
+		if maxRepetitions := r.SNMP.SnmpClient.GetAdaptiveMaxRepetitions(); maxRepetitions != 0 {
+			connectionData.SNMP.AdaptiveMaxRepetitions = &maxRepetitions
+		}
 	}
 
 	if r.HTTP != nil {
@@ -101,6 +143,30 @@ func (r *RequestDeviceConnection) GetIdealConnectionData() ConnectionData {
 		}
 	}
 
+	// the ssh connection is only established if a value is read via ssh, the password is not cached
+	if r.SSH != nil && r.SSH.SSHClient.HasSuccessfulConnection() {
+		connectionData.SSH = &SSHConnectionData{
+			Ports:    []int{r.SSH.SSHClient.port},
+			Username: &r.SSH.SSHClient.username,
+		}
+	}
+
+	// like for ssh, the gnmi and netconf passwords are not cached
+	if r.GNMI != nil && r.GNMI.GNMIClient.HasSuccessfulCachedRequest() {
+		connectionData.GNMI = &GNMIConnectionData{
+			Ports:    []int{r.GNMI.GNMIClient.port},
+			Username: &r.GNMI.GNMIClient.username,
+			Insecure: &r.GNMI.GNMIClient.insecure,
+		}
+	}
+
+	if r.NETCONF != nil && r.NETCONF.NETCONFClient.HasSuccessfulCachedRequest() {
+		connectionData.NETCONF = &NETCONFConnectionData{
+			Ports:    []int{r.NETCONF.NETCONFClient.port},
+			Username: &r.NETCONF.NETCONFClient.username,
+		}
+	}
+
 	return connectionData
 }
 
@@ -109,4 +175,13 @@ func (r *RequestDeviceConnection) CloseConnections() {
 	if r.SNMP != nil && r.SNMP.SnmpClient != nil {
 		_ = r.SNMP.SnmpClient.Disconnect()
 	}
+	if r.SSH != nil && r.SSH.SSHClient != nil {
+		_ = r.SSH.SSHClient.Disconnect()
+	}
+	if r.GNMI != nil && r.GNMI.GNMIClient != nil {
+		_ = r.GNMI.GNMIClient.Disconnect()
+	}
+	if r.NETCONF != nil && r.NETCONF.NETCONFClient != nil {
+		_ = r.NETCONF.NETCONFClient.Disconnect()
+	}
 }
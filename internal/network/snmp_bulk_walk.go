@@ -0,0 +1,157 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"github.com/gosnmp/gosnmp"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"net"
+	"strings"
+	"time"
+)
+
+// defaultMaxRepetitions is used for bulk walks if no max repetitions are set, like in gosnmp.
+const defaultMaxRepetitions = 50
+
+// maxRepetitionsGrowInterval is the number of successful GETBULK requests in a row after which
+// lowered max repetitions are doubled, so devices that were only busy for a while are walked faster again.
+const maxRepetitionsGrowInterval = 20
+
+// gosnmpTimeoutMessage is the start of the error gosnmp returns if a request was not answered after all retries.
+// gosnmp creates it with fmt.Errorf, so there is no error type to check for.
+const gosnmpTimeoutMessage = "request timeout"
+
+// configuredMaxRepetitions returns the max repetitions of the client, which the learned ones never exceed.
+func (s *snmpClient) configuredMaxRepetitions() uint32 {
+	if s.client.MaxRepetitions == 0 {
+		return defaultMaxRepetitions
+	}
+	return s.client.MaxRepetitions
+}
+
+// startMaxRepetitions returns the max repetitions the next bulk walk starts with.
+// The learned max repetitions of the device are only used if they are lower than the configured ones.
+func (s *snmpClient) startMaxRepetitions() uint32 {
+	maxRepetitions := s.configuredMaxRepetitions()
+	if s.adaptiveMaxRepetitions != 0 && s.adaptiveMaxRepetitions < maxRepetitions {
+		maxRepetitions = s.adaptiveMaxRepetitions
+	}
+	return maxRepetitions
+}
+
+// bulkWalk walks the oid with GETBULK requests.
+// If the device answers with tooBig, or does not answer after it already answered in this walk, the request is repeated
+// with half the max repetitions, and the lowered max repetitions are used for all following bulk walks of the client.
+// After maxRepetitionsGrowInterval successful requests in a row, the lowered max repetitions are doubled again.
+// Unlike gosnmp, a tooBig response is never treated as the end of the walk. gosnmp drops responses without varbinds,
+// so tooBig responses of agents following RFC 3416 surface as timeouts although a response was received.
+func (s *snmpClient) bulkWalk(ctx context.Context, rootOID string) ([]gosnmp.SnmpPDU, error) {
+	if !strings.HasPrefix(rootOID, ".") {
+		rootOID = "." + rootOID
+	}
+
+	var received bool
+	s.client.OnRecv = func(*gosnmp.GoSNMP) {
+		received = true
+	}
+	defer func() {
+		s.client.OnRecv = nil
+	}()
+
+	start := time.Now()
+	startMaxRepetitions := s.startMaxRepetitions()
+	maxRepetitions := startMaxRepetitions
+	requests := 0
+	answered := false
+	oid := rootOID
+	var res []gosnmp.SnmpPDU
+
+RequestLoop:
+	for {
+		requests++
+		received = false
+		response, err := s.client.GetBulk([]string{oid}, uint8(s.client.NonRepeaters), maxRepetitions)
+		if err != nil && ctx.Err() != nil {
+			return nil, err
+		}
+		timeout := err != nil && isSNMPTimeout(err)
+		if err != nil && !(timeout && (received || answered)) {
+			return nil, err
+		}
+		if err != nil || response.Error == gosnmp.TooBig {
+			if err == nil || received {
+				err = errors.New("response is too big")
+			}
+			if maxRepetitions <= 1 {
+				return nil, errors.Wrap(err, "bulk walk failed with max repetitions 1")
+			}
+			maxRepetitions /= 2
+			s.adaptiveMaxRepetitions = maxRepetitions
+			s.bulkSuccesses = 0
+			log.Ctx(ctx).Debug().Str("network_request", "snmpwalk").Str("oid", oid).Err(err).
+				Uint32("max_repetitions", maxRepetitions).Msg("lowered snmp max repetitions")
+			continue
+		}
+
+		answered = true
+		s.bulkSuccesses++
+		if s.bulkSuccesses >= maxRepetitionsGrowInterval && s.adaptiveMaxRepetitions != 0 && s.adaptiveMaxRepetitions < s.configuredMaxRepetitions() {
+			s.bulkSuccesses = 0
+			s.adaptiveMaxRepetitions *= 2
+			if s.adaptiveMaxRepetitions > s.configuredMaxRepetitions() {
+				s.adaptiveMaxRepetitions = s.configuredMaxRepetitions()
+			}
+			maxRepetitions = s.adaptiveMaxRepetitions
+			log.Ctx(ctx).Debug().Str("network_request", "snmpwalk").Str("oid", oid).
+				Uint32("max_repetitions", maxRepetitions).Msg("raised snmp max repetitions")
+		}
+
+		if len(response.Variables) == 0 || response.Error != gosnmp.NoError {
+			break
+		}
+
+		for i, pdu := range response.Variables {
+			if pdu.Type == gosnmp.EndOfMibView || pdu.Type == gosnmp.NoSuchObject || pdu.Type == gosnmp.NoSuchInstance {
+				break RequestLoop
+			}
+			if !strings.HasPrefix(pdu.Name, rootOID+".") {
+				// the first result is out of range, so the root oid may be a leaf, see gosnmp issue #78
+				if requests == 1 && i == 0 {
+					leaf, err := s.client.Get([]string{rootOID})
+					if err != nil {
+						return nil, err
+					}
+					for _, pdu := range leaf.Variables {
+						if pdu.Type != gosnmp.NoSuchObject && pdu.Type != gosnmp.NoSuchInstance {
+							res = append(res, pdu)
+						}
+					}
+				} else if pdu.Name == rootOID && pdu.Type != gosnmp.NoSuchInstance {
+					res = append(res, pdu)
+				}
+				break RequestLoop
+			}
+			if pdu.Name == oid {
+				return nil, fmt.Errorf("OID not increasing: %s", pdu.Name)
+			}
+			res = append(res, pdu)
+		}
+		oid = response.Variables[len(response.Variables)-1].Name
+	}
+
+	log.Ctx(ctx).Debug().Str("network_request", "snmpwalk").Str("oid", rootOID).
+		Dur("duration", time.Since(start)).Int("requests", requests).Int("responses", len(res)).
+		Uint32("start_max_repetitions", startMaxRepetitions).Uint32("max_repetitions", maxRepetitions).
+		Msg("snmp bulk walk finished")
+	return res, nil
+}
+
+// isSNMPTimeout returns whether the device did not answer the request in time, even after all retries.
+func isSNMPTimeout(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return strings.HasPrefix(err.Error(), gosnmpTimeoutMessage)
+}
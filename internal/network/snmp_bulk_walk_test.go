@@ -0,0 +1,202 @@
+package network
+
+import (
+	"context"
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tooBigAgent answers GETBULK requests with tooBig if they ask for more than maxRepetitions varbinds.
+// Like RFC 3416 requires, tooBig responses contain no varbinds.
+type tooBigAgent struct {
+	conn           net.PacketConn
+	pdus           []gosnmp.SnmpPDU
+	maxRepetitions int
+
+	sync.Mutex
+	requested []int
+	// silent drops requests for more than maxRepetitions varbinds instead of answering with tooBig.
+	silent bool
+}
+
+// requests returns the max repetitions of all requests received since the last call.
+func (a *tooBigAgent) requests() []int {
+	a.Lock()
+	defer a.Unlock()
+	requested := a.requested
+	a.requested = nil
+	return requested
+}
+
+func newTooBigAgent(t *testing.T, pdus []gosnmp.SnmpPDU, maxRepetitions int) *tooBigAgent {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	a := &tooBigAgent{conn: conn, pdus: pdus, maxRepetitions: maxRepetitions}
+	go a.serve()
+	return a
+}
+
+func (a *tooBigAgent) serve() {
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := a.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		decoder := gosnmp.GoSNMP{Version: gosnmp.Version2c}
+		req, err := decoder.SnmpDecodePacket(buf[:n])
+		if err != nil {
+			continue
+		}
+		res := gosnmp.SnmpPacket{Version: gosnmp.Version2c, Community: req.Community, PDUType: gosnmp.GetResponse, RequestID: req.RequestID}
+		repetitions := bulkMaxRepetitions(buf[:n])
+		a.Lock()
+		a.requested = append(a.requested, repetitions)
+		silent, maxRepetitions := a.silent, a.maxRepetitions
+		a.Unlock()
+		if repetitions > maxRepetitions && silent {
+			continue
+		}
+		if repetitions > maxRepetitions {
+			res.Error = gosnmp.TooBig
+		} else {
+			for _, pdu := range a.pdus {
+				if len(res.Variables) < repetitions && pdu.Name > req.Variables[0].Name {
+					res.Variables = append(res.Variables, pdu)
+				}
+			}
+			if len(res.Variables) == 0 {
+				res.Variables = []gosnmp.SnmpPDU{{Name: req.Variables[0].Name, Type: gosnmp.EndOfMibView}}
+			}
+		}
+		b, err := res.MarshalMsg()
+		if err != nil {
+			continue
+		}
+		_, _ = a.conn.WriteTo(b, addr)
+	}
+}
+
+// bulkMaxRepetitions returns the max repetitions of an encoded GETBULK request, which gosnmp does not decode.
+// The message is a sequence of the version, the community and the pdu, whose third element are the max repetitions.
+func bulkMaxRepetitions(packet []byte) int {
+	// header returns the start of the value and the end of the element at i
+	header := func(i int) (int, int) {
+		length, start := int(packet[i+1]), i+2
+		if length&0x80 != 0 {
+			n := length & 0x7f
+			length = 0
+			for _, b := range packet[start : start+n] {
+				length = length<<8 | int(b)
+			}
+			start += n
+		}
+		return start, start + length
+	}
+
+	i, _ := header(0) // message
+	_, i = header(i)  // version
+	_, i = header(i)  // community
+	i, _ = header(i)  // pdu
+	_, i = header(i)  // request id
+	_, i = header(i)  // non repeaters
+	start, end := header(i)
+	repetitions := 0
+	for _, b := range packet[start:end] {
+		repetitions = repetitions<<8 | int(b)
+	}
+	return repetitions
+}
+
+// newTestBulkWalkClient returns a client with the max repetitions 20 for the agent.
+func newTestBulkWalkClient(t *testing.T, agent *tooBigAgent) *snmpClient {
+	client := &gosnmp.GoSNMP{
+		Context:        context.Background(),
+		Target:         "127.0.0.1",
+		Port:           uint16(agent.conn.LocalAddr().(*net.UDPAddr).Port),
+		Transport:      "udp",
+		Community:      "public",
+		Version:        gosnmp.Version2c,
+		Timeout:        100 * time.Millisecond,
+		MaxRepetitions: 20,
+	}
+	require.NoError(t, client.Connect())
+	t.Cleanup(func() { _ = client.Conn.Close() })
+	return &snmpClient{client: client, device: getDeviceSchedule("127.0.0.1")}
+}
+
+func TestSNMPClient_bulkWalk_tooBig(t *testing.T) {
+	var pdus []gosnmp.SnmpPDU
+	for _, index := range strings.Split("1 2 3 4 5 6 7", " ") {
+		pdus = append(pdus, gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.2.2.1.1." + index, Type: gosnmp.Integer, Value: 1})
+	}
+	pdus = append(pdus, gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.2.2.1.2.1", Type: gosnmp.OctetString, Value: []byte("eth0")})
+
+	agent := newTooBigAgent(t, pdus, 5)
+	defer agent.conn.Close()
+
+	ctx := context.Background()
+	s := newTestBulkWalkClient(t, agent)
+
+	res, err := s.SNMPRawWalk(ctx, ".1.3.6.1.2.1.2.2.1.1", true)
+	require.NoError(t, err)
+	assert.Len(t, res, 7)
+	assert.Equal(t, []int{20, 10, 5, 5}, agent.requests())
+	assert.Equal(t, uint32(5), s.GetAdaptiveMaxRepetitions())
+
+	// the next walk starts with the learned max repetitions
+	res, err = s.SNMPRawWalk(ctx, ".1.3.6.1.2.1.2.2.1.2", true)
+	require.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, []int{5, 5}, agent.requests())
+}
+
+func TestSNMPClient_bulkWalk_timeout(t *testing.T) {
+	var pdus []gosnmp.SnmpPDU
+	for i := 100; i < 220; i++ {
+		pdus = append(pdus, gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.2.2.1.1." + strconv.Itoa(i), Type: gosnmp.Integer, Value: i})
+	}
+	agent := newTooBigAgent(t, pdus, 5)
+	defer agent.conn.Close()
+	agent.Lock()
+	agent.silent = true
+	agent.Unlock()
+
+	ctx := context.Background()
+	s := newTestBulkWalkClient(t, agent)
+
+	// a device that does not answer at all is not walked with lower max repetitions
+	_, err := s.SNMPRawWalk(ctx, ".1.3.6.1.2.1.2.2.1.1", true)
+	assert.Error(t, err)
+	assert.Equal(t, []int{20}, agent.requests())
+	assert.Equal(t, uint32(0), s.GetAdaptiveMaxRepetitions())
+
+	// the learned max repetitions are doubled after successful requests,
+	// and lowered again if the device stops answering within the walk
+	s.SetAdaptiveMaxRepetitions(5)
+	res, err := s.SNMPRawWalk(ctx, ".1.3.6.1.2.1.2.2.1.1", true)
+	require.NoError(t, err)
+	assert.Len(t, res, 120)
+	requested := agent.requests()
+	require.Greater(t, len(requested), maxRepetitionsGrowInterval+1)
+	assert.Equal(t, []int{10, 5}, requested[maxRepetitionsGrowInterval:maxRepetitionsGrowInterval+2])
+	assert.Equal(t, uint32(5), s.GetAdaptiveMaxRepetitions())
+
+	// without limits of the device, the learned max repetitions grow up to the configured ones
+	agent.Lock()
+	agent.maxRepetitions = 20
+	agent.Unlock()
+	s.SetAdaptiveMaxRepetitions(5)
+	for i := 0; i < 3; i++ {
+		_, err = s.SNMPRawWalk(ctx, ".1.3.6.1.2.1.2.2.1.1", true)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, uint32(20), s.GetAdaptiveMaxRepetitions())
+}
@@ -27,6 +27,7 @@ type SNMPClient interface {
 
 	SNMPGet(ctx context.Context, oid ...OID) ([]SNMPResponse, error)
 	SNMPWalk(ctx context.Context, oid OID) ([]SNMPResponse, error)
+	SNMPRawWalk(ctx context.Context, oid OID, bulk bool) ([]SNMPResponse, error)
 
 	UseCache(b bool)
 	HasSuccessfulCachedRequest() bool
@@ -38,7 +39,10 @@ type SNMPClient interface {
 	GetMaxRepetitions() uint32
 
 	SetMaxRepetitions(maxRepetitions uint32)
+	GetAdaptiveMaxRepetitions() uint32
+	SetAdaptiveMaxRepetitions(maxRepetitions uint32)
 	SetMaxOIDs(maxOIDs int) error
+	SetSchedule(schedule SNMPSchedule)
 
 	GetV3Level() *string
 	GetV3ContextName() *string
@@ -47,6 +51,40 @@ type SNMPClient interface {
 	GetV3AuthProto() *string
 	GetV3PrivKey() *string
 	GetV3PrivProto() *string
+	GetV3EngineData() *SNMPv3EngineData
+}
+
+// SNMPv3EngineData contains the engine id, boots and time of the snmp engine of a device.
+// They are discovered before the first snmpv3 request, so caching them saves a round trip for every new connection.
+type SNMPv3EngineData struct {
+	// EngineID is the hex encoded authoritative engine id.
+	EngineID    string `json:"engine_id"`
+	EngineBoots uint32 `json:"engine_boots"`
+	EngineTime  uint32 `json:"engine_time"`
+	// Time is the local time at which the engine time was received.
+	Time time.Time `json:"time"`
+}
+
+// usmStatsReasons maps the usmStats counters of RFC 3414, which are sent in reports, to the reasons of the errors.
+var usmStatsReasons = map[string]tholaerr.SNMPv3USMReason{
+	".1.3.6.1.6.3.15.1.1.1.0": tholaerr.SNMPv3USMUnsupportedSecurityLevel,
+	".1.3.6.1.6.3.15.1.1.2.0": tholaerr.SNMPv3USMNotInTimeWindow,
+	".1.3.6.1.6.3.15.1.1.3.0": tholaerr.SNMPv3USMUnknownUserName,
+	".1.3.6.1.6.3.15.1.1.4.0": tholaerr.SNMPv3USMUnknownEngineID,
+	".1.3.6.1.6.3.15.1.1.5.0": tholaerr.SNMPv3USMWrongDigest,
+	".1.3.6.1.6.3.15.1.1.6.0": tholaerr.SNMPv3USMDecryptionError,
+}
+
+// checkSNMPv3Report returns a SNMPv3USMError if the packet is a usmStats report instead of a response.
+// gosnmp returns these reports like responses, only unknown engine ids and time windows are handled by gosnmp itself.
+func checkSNMPv3Report(packet *gosnmp.SnmpPacket) error {
+	if packet == nil || packet.PDUType != gosnmp.Report || len(packet.Variables) != 1 {
+		return nil
+	}
+	if reason, ok := usmStatsReasons[packet.Variables[0].Name]; ok {
+		return tholaerr.NewSNMPv3USMError(reason)
+	}
+	return nil
 }
 
 type snmpClient struct {
@@ -54,6 +92,14 @@ type snmpClient struct {
 	useCache  bool
 	getCache  requestCache
 	walkCache requestCache
+
+	schedule SNMPSchedule
+	device   *deviceSchedule
+
+	// adaptiveMaxRepetitions are the max repetitions that were lowered by bulk walks, 0 if they were never lowered.
+	adaptiveMaxRepetitions uint32
+	// bulkSuccesses is the number of successful GETBULK requests since the max repetitions were changed.
+	bulkSuccesses int
 }
 
 type snmpClientCreation struct {
@@ -70,10 +116,12 @@ type snmpClientCreationData struct {
 	timeout     int
 	retries     int
 	v3Data      SNMPv3ConnectionData
+	v3Engine    *SNMPv3EngineData
 }
 
 // NewSNMPClientByConnectionData tries to create a new snmp client by SNMPConnectionData and returns it.
-func NewSNMPClientByConnectionData(ctx context.Context, ipAddress string, data *SNMPConnectionData) (SNMPClient, error) {
+// If v3Engine is not nil, the snmp v3 clients use it instead of discovering the engine of the device.
+func NewSNMPClientByConnectionData(ctx context.Context, ipAddress string, data *SNMPConnectionData, v3Engine *SNMPv3EngineData) (SNMPClient, error) {
 	if data == nil {
 		return nil, errors.New("snmp connection data is nil")
 	}
@@ -114,6 +162,7 @@ func NewSNMPClientByConnectionData(ctx context.Context, ipAddress string, data *
 					timeout:     *data.DiscoverTimeout,
 					retries:     *data.DiscoverRetries,
 					v3Data:      data.V3Data,
+					v3Engine:    v3Engine,
 				}
 				amount++
 			} else {
@@ -182,6 +231,10 @@ func NewSNMPClientByConnectionData(ctx context.Context, ipAddress string, data *
 			log.Ctx(ctx).Debug().Msg("set snmp max repetitions of connection data")
 			successfulClient.SetMaxRepetitions(*data.MaxRepetitions)
 		}
+		if data.AdaptiveMaxRepetitions != nil {
+			log.Ctx(ctx).Debug().Uint32("max_repetitions", *data.AdaptiveMaxRepetitions).Msg("set learned snmp max repetitions of connection data")
+			successfulClient.SetAdaptiveMaxRepetitions(*data.AdaptiveMaxRepetitions)
+		}
 		return successfulClient, nil
 	}
 	if criticalError != nil {
@@ -201,7 +254,7 @@ func createNewSNMPClientConcurrent(ctx context.Context, in chan snmpClientCreati
 				var client SNMPClient
 				var err error
 				if data.snmpVersion == "3" {
-					client, err = NewSNMPv3Client(ctx, data.ipAddress, data.port, data.timeout, data.retries, data.v3Data)
+					client, err = NewSNMPv3Client(ctx, data.ipAddress, data.port, data.timeout, data.retries, data.v3Data, data.v3Engine)
 				} else {
 					client, err = NewSNMPClient(ctx, data.ipAddress, data.snmpVersion, data.community, data.port, data.timeout, data.retries)
 				}
@@ -236,7 +289,9 @@ func NewSNMPClient(ctx context.Context, ipAddress, snmpVersion, community string
 }
 
 // NewSNMPv3Client creates a new SNMP v3 Client.
-func NewSNMPv3Client(ctx context.Context, ipAddress string, port, timeout, retries int, v3Data SNMPv3ConnectionData) (SNMPClient, error) {
+// If engine is not nil, it is used instead of discovering the engine of the device.
+// Should the device have been restarted or replaced in the meantime, gosnmp updates the engine with the report of the device.
+func NewSNMPv3Client(ctx context.Context, ipAddress string, port, timeout, retries int, v3Data SNMPv3ConnectionData, engine *SNMPv3EngineData) (SNMPClient, error) {
 	client := &gosnmp.GoSNMP{
 		Context:       ctx,
 		Target:        ipAddress,
@@ -292,30 +347,81 @@ func NewSNMPv3Client(ctx context.Context, ipAddress string, port, timeout, retri
 		}
 	}
 
-	return newSNMPClientTestConnection(client)
+	if engine != nil {
+		engineID, err := hex.DecodeString(engine.EngineID)
+		if err == nil && len(engineID) > 0 {
+			params := client.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+			params.AuthoritativeEngineID = string(engineID)
+			params.AuthoritativeEngineBoots = engine.EngineBoots
+			params.AuthoritativeEngineTime = engine.EngineTime + uint32(time.Since(engine.Time).Seconds())
+		} else {
+			log.Ctx(ctx).Debug().Str("engine_id", engine.EngineID).Msg("ignoring invalid cached snmp v3 engine id")
+			engine = nil
+		}
+	}
+
+	snmpClient, err := newSNMPClientTestConnection(client)
+	if err != nil {
+		if engine != nil && isOutdatedSNMPv3EngineError(err) {
+			// the keys are localized with the cached engine id, which is outdated if the engine of the device changed
+			log.Ctx(ctx).Debug().Err(err).Msg("snmp v3 request with cached engine failed, retrying with engine discovery")
+			return NewSNMPv3Client(ctx, ipAddress, port, timeout, retries, v3Data, nil)
+		}
+		return nil, err
+	}
+	return snmpClient, nil
+}
+
+// gosnmpNotAuthenticMessage is the message of the error which gosnmp returns if a response is not authenticated with the localized keys.
+const gosnmpNotAuthenticMessage = "incoming packet is not authentic"
+
+// isOutdatedSNMPv3EngineError returns if the device rejected a request because the engine id or time were outdated.
+// Devices send the unknown engine id report without authentication, which gosnmp discards as not authentic
+// if the keys were localized with a cached engine id. gosnmp has no error type for this, so the message is checked.
+func isOutdatedSNMPv3EngineError(err error) bool {
+	if reason, ok := tholaerr.GetSNMPv3USMReason(err); ok {
+		return reason == tholaerr.SNMPv3USMUnknownEngineID || reason == tholaerr.SNMPv3USMNotInTimeWindow
+	}
+	return tholaerr.IsNetworkError(err) && strings.Contains(err.Error(), gosnmpNotAuthenticMessage)
 }
 
 func newSNMPClientTestConnection(client *gosnmp.GoSNMP) (*snmpClient, error) {
+	s := &snmpClient{
+		client:    client,
+		useCache:  true,
+		getCache:  newRequestCache("snmp_get"),
+		walkCache: newRequestCache("snmp_walk"),
+		schedule:  GetDefaultSNMPSchedule(),
+		device:    getDeviceSchedule(client.Target),
+	}
+	countSNMPRequests(client)
+	scheduleSNMPRequests(s)
 	err := client.ConnectIPv4()
 	if err != nil {
 		return nil, errors.Wrap(err, "connect ip v4 failed")
 	}
 
+	release, err := s.device.acquire(client.Context, s.schedule)
+	if err != nil {
+		_ = client.Conn.Close()
+		return nil, err
+	}
 	oids := []string{".0.0"}
-	_, err = client.GetNext(oids)
+	res, err := client.GetNext(oids)
+	release()
 	if err != nil {
 		return nil, tholaerr.NewSNMPError(err.Error())
 	}
+	err = checkSNMPv3Report(res)
+	if err != nil {
+		_ = client.Conn.Close()
+		return nil, err
+	}
 
 	client.Retries = gosnmp.Default.Retries
 	client.Timeout = gosnmp.Default.Timeout
 
-	return &snmpClient{
-		client:    client,
-		useCache:  true,
-		getCache:  newRequestCache(),
-		walkCache: newRequestCache(),
-	}, nil
+	return s, nil
 }
 
 func getGoSNMPVersion(version string) (gosnmp.SnmpVersion, error) {
@@ -336,6 +442,11 @@ func ValidateSNMPv3AuthProtocol(protocol string) error {
 	return err
 }
 
+// GetGoSNMPV3AuthProtocol returns the gosnmp representation of the authentication protocol.
+func GetGoSNMPV3AuthProtocol(protocol string) (gosnmp.SnmpV3AuthProtocol, error) {
+	return getGoSNMPV3AuthProtocol(protocol)
+}
+
 func getGoSNMPV3AuthProtocol(protocol string) (gosnmp.SnmpV3AuthProtocol, error) {
 	switch protocol {
 	case "noAuth":
@@ -362,6 +473,11 @@ func ValidateSNMPv3PrivProtocol(protocol string) error {
 	return err
 }
 
+// GetGoSNMPV3PrivProtocol returns the gosnmp representation of the privacy protocol.
+func GetGoSNMPV3PrivProtocol(protocol string) (gosnmp.SnmpV3PrivProtocol, error) {
+	return getGoSNMPV3PrivProtocol(protocol)
+}
+
 func getGoSNMPV3PrivProtocol(protocol string) (gosnmp.SnmpV3PrivProtocol, error) {
 	switch protocol {
 	case "noAuth":
@@ -426,7 +542,15 @@ func (s *snmpClient) SNMPGet(ctx context.Context, oid ...OID) ([]SNMPResponse, e
 		for _, elem := range batch {
 			batchString = append(batchString, elem.String())
 		}
+		release, err := s.device.acquire(ctx, s.schedule)
+		if err != nil {
+			return nil, err
+		}
 		response, err := s.client.Get(batchString)
+		release()
+		if err == nil {
+			err = checkSNMPv3Report(response)
+		}
 		if err != nil {
 			log.Ctx(ctx).Trace().Str("network_request", "snmpget").Strs("oid", batchString).Err(err).Msg("SNMP Get failed")
 			return nil, errors.Wrap(err, "error during snmpget")
@@ -448,6 +572,7 @@ func (s *snmpClient) SNMPGet(ctx context.Context, oid ...OID) ([]SNMPResponse, e
 				}
 			}
 
+			recordSNMPResponses(ctx, snmpResponse)
 			snmpResponses = append(snmpResponses, snmpResponse)
 		}
 	}
@@ -478,17 +603,24 @@ func (s *snmpClient) SNMPWalk(ctx context.Context, oid OID) ([]SNMPResponse, err
 
 	s.client.Context = ctx
 
+	release, err := s.device.acquire(ctx, s.schedule)
+	if err != nil {
+		return nil, err
+	}
 	var response []gosnmp.SnmpPDU
-	var err error
 	if s.client.Version != gosnmp.Version1 {
-		response, err = s.client.BulkWalkAll(oid.String())
+		response, err = s.bulkWalk(ctx, oid.String())
 		if err != nil {
 			log.Ctx(ctx).Trace().Str("network_request", "snmpwalk").Str("oid", oid.String()).Err(err).Msg("snmp bulk walk failed")
 		}
 	}
 	if s.client.Version == gosnmp.Version1 || err != nil {
+		start := time.Now()
 		response, err = s.client.WalkAll(oid.String())
+		log.Ctx(ctx).Debug().Str("network_request", "snmpwalk").Str("oid", oid.String()).Dur("duration", time.Since(start)).
+			Int("responses", len(response)).Msg("snmp walk finished")
 	}
+	release()
 	if err != nil {
 		log.Ctx(ctx).Trace().Str("network_request", "snmpwalk").Str("oid", oid.String()).Err(err).Msg("snmp walk failed")
 		err = errors.Wrap(err, "snmpwalk failed")
@@ -521,6 +653,8 @@ func (s *snmpClient) SNMPWalk(ctx context.Context, oid OID) ([]SNMPResponse, err
 		}
 	}
 
+	recordSNMPResponses(ctx, res...)
+
 	if s.useCache {
 		s.walkCache.add(oid.String(), res, nil)
 	}
@@ -530,6 +664,39 @@ func (s *snmpClient) SNMPWalk(ctx context.Context, oid OID) ([]SNMPResponse, err
 	return res, nil
 }
 
+// SNMPRawWalk sends a snmpwalk request to the specified oid without using the cache.
+// If bulk is true, GETBULK requests are used, otherwise GETNEXT requests. There is no fallback between both.
+func (s *snmpClient) SNMPRawWalk(ctx context.Context, oid OID, bulk bool) ([]SNMPResponse, error) {
+	if bulk && s.client.Version == gosnmp.Version1 {
+		return nil, errors.New("bulk walks are not supported by snmp v1")
+	}
+
+	s.client.Context = ctx
+
+	release, err := s.device.acquire(ctx, s.schedule)
+	if err != nil {
+		return nil, err
+	}
+	var response []gosnmp.SnmpPDU
+	if bulk {
+		response, err = s.bulkWalk(ctx, oid.String())
+	} else {
+		response, err = s.client.WalkAll(oid.String())
+	}
+	release()
+	if err != nil {
+		log.Ctx(ctx).Trace().Str("network_request", "snmpwalk").Str("oid", oid.String()).Bool("bulk", bulk).Err(err).Msg("snmp walk failed")
+		return nil, errors.Wrap(err, "snmpwalk failed")
+	}
+
+	var res []SNMPResponse
+	for _, currentResponse := range response {
+		res = append(res, NewSNMPResponse(OID(currentResponse.Name), currentResponse.Type, currentResponse.Value))
+	}
+	recordSNMPResponses(ctx, res...)
+	return res, nil
+}
+
 // UseCache configures whether the snmp cache should be used or not
 func (s *snmpClient) UseCache(b bool) {
 	s.useCache = b
@@ -595,6 +762,22 @@ func (s *snmpClient) SetMaxOIDs(maxOIDs int) error {
 	return nil
 }
 
+// GetAdaptiveMaxRepetitions returns the max repetitions that were learned by bulk walks, 0 if there are none.
+func (s *snmpClient) GetAdaptiveMaxRepetitions() uint32 {
+	return s.adaptiveMaxRepetitions
+}
+
+// SetAdaptiveMaxRepetitions sets the learned max repetitions of the device, which bulk walks start with if they are lower than the max repetitions.
+func (s *snmpClient) SetAdaptiveMaxRepetitions(maxRepetitions uint32) {
+	s.adaptiveMaxRepetitions = maxRepetitions
+	s.bulkSuccesses = 0
+}
+
+// SetSchedule sets the limits of the snmp requests sent to the device.
+func (s *snmpClient) SetSchedule(schedule SNMPSchedule) {
+	s.schedule = schedule
+}
+
 // GetV3Level returns the security level of the snmp v3 connection.
 // Return value is nil if no snmp v3 is being used.
 func (s *snmpClient) GetV3Level() *string {
@@ -768,6 +951,46 @@ func (s *SNMPResponse) GetValueRaw() (value.Value, error) {
 	return value.New(s.value), nil
 }
 
+// GetSNMPRecValue returns the type tag and the value of the response in the snmprec format.
+func (s *SNMPResponse) GetSNMPRecValue() (string, string, error) {
+	if !s.WasSuccessful() {
+		return "", "", tholaerr.NewNotFoundError("no such object")
+	}
+	switch s.snmpType {
+	case gosnmp.OctetString, gosnmp.Opaque:
+		var b []byte
+		switch x := s.value.(type) {
+		case string:
+			b = []byte(x)
+		case []byte:
+			b = x
+		default:
+			return "", "", fmt.Errorf("unexpected value type %T of octet string", s.value)
+		}
+		tag := strconv.Itoa(int(s.snmpType))
+		if s.snmpType == gosnmp.OctetString && isPrintableASCII(b) {
+			return tag, string(b), nil
+		}
+		return tag + "x", hex.EncodeToString(b), nil
+	case gosnmp.ObjectIdentifier:
+		return strconv.Itoa(int(s.snmpType)), strings.TrimPrefix(fmt.Sprint(s.value), "."), nil
+	case gosnmp.Integer, gosnmp.Counter32, gosnmp.Gauge32, gosnmp.TimeTicks, gosnmp.Counter64, gosnmp.Uinteger32, gosnmp.IPAddress:
+		return strconv.Itoa(int(s.snmpType)), fmt.Sprint(s.value), nil
+	default:
+		return "", "", fmt.Errorf("snmp type '%s' is not supported by the snmprec format", s.snmpType)
+	}
+}
+
+// isPrintableASCII returns if the bytes only consist of printable ascii characters.
+func isPrintableASCII(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *SNMPResponse) getValueDecoded() (interface{}, error) {
 	var err error
 	i := s.value
@@ -897,3 +1120,18 @@ func (o OID) AddIndex(index string) OID {
 	}
 	return OID(o.String() + index)
 }
+
+// GetV3EngineData returns the engine of the device which was discovered or updated with the last snmp v3 request.
+// Return value is nil if no snmp v3 is being used.
+func (s *snmpClient) GetV3EngineData() *SNMPv3EngineData {
+	r, ok := s.client.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	if !ok || r.AuthoritativeEngineID == "" {
+		return nil
+	}
+	return &SNMPv3EngineData{
+		EngineID:    hex.EncodeToString([]byte(r.AuthoritativeEngineID)),
+		EngineBoots: r.AuthoritativeEngineBoots,
+		EngineTime:  r.AuthoritativeEngineTime,
+		Time:        time.Now(),
+	}
+}
@@ -1,6 +1,9 @@
 package network
 
 import (
+	"github.com/gosnmp/gosnmp"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -70,3 +73,41 @@ func TestOID_AddIndex_noDot(t *testing.T) {
 func TestOID_AddIndex_doubleDot(t *testing.T) {
 	assert.Equal(t, OID("1.1"), OID("1.").AddIndex(".1"))
 }
+
+func TestSNMPResponse_GetSNMPRecValue(t *testing.T) {
+	cases := []struct {
+		response SNMPResponse
+		tag      string
+		value    string
+	}{
+		{NewSNMPResponse("1.3.6.1.2.1.1.5.0", gosnmp.OctetString, []byte("MikroTik")), "4", "MikroTik"},
+		{NewSNMPResponse("1.3.6.1.2.1.1.4.0", gosnmp.OctetString, []byte{}), "4", ""},
+		{NewSNMPResponse("1.3.6.1.2.1.2.2.1.6.1", gosnmp.OctetString, []byte{0x00, 0x1a, 0x2b}), "4x", "001a2b"},
+		{NewSNMPResponse("1.3.6.1.2.1.1.2.0", gosnmp.ObjectIdentifier, ".1.3.6.1.4.1.14988.1"), "6", "1.3.6.1.4.1.14988.1"},
+		{NewSNMPResponse("1.3.6.1.2.1.1.7.0", gosnmp.Integer, 78), "2", "78"},
+		{NewSNMPResponse("1.3.6.1.2.1.1.3.0", gosnmp.TimeTicks, uint32(1097900)), "67", "1097900"},
+		{NewSNMPResponse("1.3.6.1.2.1.31.1.1.1.6.1", gosnmp.Counter64, uint64(12345678901)), "70", "12345678901"},
+		{NewSNMPResponse("1.3.6.1.2.1.4.20.1.1.10.0.0.1", gosnmp.IPAddress, "10.0.0.1"), "64", "10.0.0.1"},
+	}
+	for _, c := range cases {
+		tag, value, err := c.response.GetSNMPRecValue()
+		if assert.NoError(t, err, c.response.GetOID()) {
+			assert.Equal(t, c.tag, tag, c.response.GetOID())
+			assert.Equal(t, c.value, value, c.response.GetOID())
+		}
+	}
+}
+
+func TestSNMPResponse_GetSNMPRecValue_noSuchObject(t *testing.T) {
+	response := NewSNMPResponse("1.3.6.1.2.1.1.5.0", gosnmp.NoSuchObject, nil)
+	_, _, err := response.GetSNMPRecValue()
+	assert.Error(t, err)
+}
+
+func TestIsOutdatedSNMPv3EngineError(t *testing.T) {
+	assert.True(t, isOutdatedSNMPv3EngineError(tholaerr.NewSNMPv3USMError(tholaerr.SNMPv3USMUnknownEngineID)))
+	assert.True(t, isOutdatedSNMPv3EngineError(errors.Wrap(tholaerr.NewSNMPv3USMError(tholaerr.SNMPv3USMNotInTimeWindow), "request failed")))
+	assert.False(t, isOutdatedSNMPv3EngineError(tholaerr.NewSNMPv3USMError(tholaerr.SNMPv3USMWrongDigest)))
+	assert.True(t, isOutdatedSNMPv3EngineError(tholaerr.NewSNMPError("incoming packet is not authentic, discarding")))
+	assert.False(t, isOutdatedSNMPv3EngineError(tholaerr.NewSNMPError("request timeout (after 0 retries)")))
+}
@@ -0,0 +1,46 @@
+package network
+
+import (
+	"context"
+	"sort"
+)
+
+// SNMPRecorder records all successful snmp responses that snmp clients receive with a context containing the recorder.
+type SNMPRecorder struct {
+	cache requestCache
+}
+
+// NewSNMPRecorder creates a new snmp recorder.
+func NewSNMPRecorder() *SNMPRecorder {
+	return &SNMPRecorder{
+		cache: newRequestCache(""),
+	}
+}
+
+// GetResponses returns all recorded responses sorted by their oid.
+func (r *SNMPRecorder) GetResponses() []SNMPResponse {
+	var responses []SNMPResponse
+	for _, entry := range r.cache.getSuccessfulRequests() {
+		if res, ok := entry.res.(SNMPResponse); ok {
+			responses = append(responses, res)
+		}
+	}
+	sort.Slice(responses, func(i, j int) bool {
+		cmp, err := responses[i].GetOID().Cmp(responses[j].GetOID())
+		return err == nil && cmp == -1
+	})
+	return responses
+}
+
+// recordSNMPResponses adds the responses to the snmp recorder of the context if there is one.
+func recordSNMPResponses(ctx context.Context, responses ...SNMPResponse) {
+	recorder, ok := SNMPRecorderFromContext(ctx)
+	if !ok {
+		return
+	}
+	for _, response := range responses {
+		if response.WasSuccessful() {
+			recorder.cache.add(response.GetOID().String(), response, nil)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package network
+
+import (
+	"context"
+	"github.com/gosnmp/gosnmp"
+	"github.com/inexio/thola/internal/snmprec"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/pkg/errors"
+	"net"
+	"strings"
+)
+
+// snmpReplayClient is a SNMPClient which answers all requests from a snmprec recording instead of a device.
+type snmpReplayClient struct {
+	recording      *snmprec.Recording
+	community      string
+	version        string
+	maxRepetitions uint32
+	successful     bool
+}
+
+// NewSNMPReplayClient returns a SNMPClient that replays the given snmprec records.
+// It behaves like a snmp client connected to a device that serves exactly these records, which makes it possible
+// to test device classes and code communicators against recorded devices.
+func NewSNMPReplayClient(records []snmprec.Record) (SNMPClient, error) {
+	rec, err := snmprec.NewRecording(records)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create recording")
+	}
+	return &snmpReplayClient{
+		recording: rec,
+		community: "public",
+		version:   "2c",
+	}, nil
+}
+
+// SNMPGet returns the recorded values of the oids.
+func (s *snmpReplayClient) SNMPGet(ctx context.Context, oid ...OID) ([]SNMPResponse, error) {
+	var snmpResponses []SNMPResponse
+	var successful bool
+	for _, o := range oid {
+		parsed, err := snmprec.ParseOID(o.String())
+		if err != nil {
+			return nil, errors.Wrap(err, "error during snmpget")
+		}
+		var snmpResponse SNMPResponse
+		if pdu, ok := s.recording.Get(parsed); ok {
+			snmpResponse = replayResponse(pdu)
+			successful = true
+		} else {
+			snmpResponse = NewSNMPResponse(OID("."+strings.TrimPrefix(o.String(), ".")), gosnmp.NoSuchObject, nil)
+		}
+		recordSNMPResponses(ctx, snmpResponse)
+		snmpResponses = append(snmpResponses, snmpResponse)
+	}
+
+	if !successful {
+		return nil, tholaerr.NewNotFoundError("No Such Object available on this agent at this OID")
+	}
+	s.successful = true
+	return snmpResponses, nil
+}
+
+// SNMPWalk returns the recorded values in the subtree of the oid.
+func (s *snmpReplayClient) SNMPWalk(ctx context.Context, oid OID) ([]SNMPResponse, error) {
+	res, err := s.walk(oid)
+	if err != nil {
+		return nil, errors.Wrap(err, "snmpwalk failed")
+	}
+	if res == nil {
+		return nil, tholaerr.NewNotFoundError("No Such Object available on this agent at this OID")
+	}
+	recordSNMPResponses(ctx, res...)
+	s.successful = true
+	return res, nil
+}
+
+// SNMPRawWalk returns the recorded values in the subtree of the oid.
+func (s *snmpReplayClient) SNMPRawWalk(ctx context.Context, oid OID, bulk bool) ([]SNMPResponse, error) {
+	if bulk && s.version == "1" {
+		return nil, errors.New("bulk walks are not supported by snmp v1")
+	}
+	res, err := s.walk(oid)
+	if err != nil {
+		return nil, errors.Wrap(err, "snmpwalk failed")
+	}
+	recordSNMPResponses(ctx, res...)
+	return res, nil
+}
+
+func (s *snmpReplayClient) walk(oid OID) ([]SNMPResponse, error) {
+	parsed, err := snmprec.ParseOID(oid.String())
+	if err != nil {
+		return nil, err
+	}
+	var res []SNMPResponse
+	for _, pdu := range s.recording.Walk(parsed) {
+		res = append(res, replayResponse(pdu))
+	}
+	return res, nil
+}
+
+// replayResponse converts the recorded pdu to a response with the value types that gosnmp uses for decoded responses.
+func replayResponse(pdu gosnmp.SnmpPDU) SNMPResponse {
+	val := pdu.Value
+	switch pdu.Type {
+	case gosnmp.Counter32, gosnmp.Gauge32:
+		val = uint(pdu.Value.(uint32))
+	case gosnmp.IPAddress:
+		val = net.IP(pdu.Value.([]byte)).String()
+	}
+	return NewSNMPResponse(OID(pdu.Name), pdu.Type, val)
+}
+
+// UseCache does nothing, the replay client does not need a cache.
+func (s *snmpReplayClient) UseCache(bool) {}
+
+// HasSuccessfulCachedRequest returns if there was at least one successful request.
+func (s *snmpReplayClient) HasSuccessfulCachedRequest() bool {
+	return s.successful
+}
+
+// Disconnect does nothing, the replay client has no connection.
+func (s *snmpReplayClient) Disconnect() error {
+	return nil
+}
+
+// GetCommunity returns the community string
+func (s *snmpReplayClient) GetCommunity() string {
+	return s.community
+}
+
+// SetCommunity updates the community string.
+func (s *snmpReplayClient) SetCommunity(community string) {
+	s.community = community
+}
+
+// GetPort returns the port
+func (s *snmpReplayClient) GetPort() int {
+	return 161
+}
+
+// GetVersion returns the snmp version.
+func (s *snmpReplayClient) GetVersion() string {
+	return s.version
+}
+
+// GetMaxRepetitions returns the max repetitions.
+func (s *snmpReplayClient) GetMaxRepetitions() uint32 {
+	return s.maxRepetitions
+}
+
+// SetMaxRepetitions sets the maximum repetitions.
+func (s *snmpReplayClient) SetMaxRepetitions(maxRepetitions uint32) {
+	s.maxRepetitions = maxRepetitions
+}
+
+// GetAdaptiveMaxRepetitions returns 0, the replay client never lowers the max repetitions.
+func (s *snmpReplayClient) GetAdaptiveMaxRepetitions() uint32 {
+	return 0
+}
+
+// SetAdaptiveMaxRepetitions does nothing, the replay client has no limit.
+func (s *snmpReplayClient) SetAdaptiveMaxRepetitions(uint32) {}
+
+// SetMaxOIDs checks the maximum OIDs, the replay client has no limit.
+func (s *snmpReplayClient) SetMaxOIDs(maxOIDs int) error {
+	if maxOIDs < 1 {
+		return errors.New("invalid max oids")
+	}
+	return nil
+}
+
+// SetSchedule does nothing, the replay client sends no requests.
+func (s *snmpReplayClient) SetSchedule(SNMPSchedule) {}
+
+// GetV3Level returns nil, the replay client does not use snmp v3.
+func (s *snmpReplayClient) GetV3Level() *string {
+	return nil
+}
+
+// GetV3ContextName returns nil, the replay client does not use snmp v3.
+func (s *snmpReplayClient) GetV3ContextName() *string {
+	return nil
+}
+
+// GetV3User returns nil, the replay client does not use snmp v3.
+func (s *snmpReplayClient) GetV3User() *string {
+	return nil
+}
+
+// GetV3AuthKey returns nil, the replay client does not use snmp v3.
+func (s *snmpReplayClient) GetV3AuthKey() *string {
+	return nil
+}
+
+// GetV3AuthProto returns nil, the replay client does not use snmp v3.
+func (s *snmpReplayClient) GetV3AuthProto() *string {
+	return nil
+}
+
+// GetV3PrivKey returns nil, the replay client does not use snmp v3.
+func (s *snmpReplayClient) GetV3PrivKey() *string {
+	return nil
+}
+
+// GetV3PrivProto returns nil, the replay client does not use snmp v3.
+func (s *snmpReplayClient) GetV3PrivProto() *string {
+	return nil
+}
+
+// GetV3EngineData returns nil, the replay client does not use snmp v3.
+func (s *snmpReplayClient) GetV3EngineData() *SNMPv3EngineData {
+	return nil
+}
@@ -0,0 +1,91 @@
+package network
+
+import (
+	"context"
+	"github.com/gosnmp/gosnmp"
+	"github.com/inexio/thola/internal/snmprec"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var replayRecords = []snmprec.Record{
+	{OID: "1.3.6.1.2.1.2.2.1.10.2", Type: "65", Value: "200"},
+	{OID: "1.3.6.1.2.1.1.1.0", Type: "4", Value: "Test Device"},
+	{OID: "1.3.6.1.2.1.2.2.1.10.1", Type: "65", Value: "100"},
+	{OID: "1.3.6.1.2.1.4.20.1.1.192.0.2.1", Type: "64", Value: "192.0.2.1"},
+}
+
+func TestSNMPReplayClient_SNMPGet(t *testing.T) {
+	client, err := NewSNMPReplayClient(replayRecords)
+	require.NoError(t, err)
+	assert.False(t, client.HasSuccessfulCachedRequest())
+
+	res, err := client.SNMPGet(context.Background(), "1.3.6.1.2.1.1.1.0", ".1.3.6.1.2.1.1.2.0")
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	assert.Equal(t, NewSNMPResponse(".1.3.6.1.2.1.1.1.0", gosnmp.OctetString, []byte("Test Device")), res[0])
+	assert.False(t, res[1].WasSuccessful())
+	assert.True(t, client.HasSuccessfulCachedRequest())
+
+	_, err = client.SNMPGet(context.Background(), "1.3.6.1.2.1.1.2.0")
+	assert.True(t, tholaerr.IsNotFoundError(err))
+}
+
+func TestSNMPReplayClient_SNMPWalk(t *testing.T) {
+	client, err := NewSNMPReplayClient(replayRecords)
+	require.NoError(t, err)
+
+	res, err := client.SNMPWalk(context.Background(), "1.3.6.1.2.1.2.2.1.10")
+	require.NoError(t, err)
+	assert.Equal(t, []SNMPResponse{
+		NewSNMPResponse(".1.3.6.1.2.1.2.2.1.10.1", gosnmp.Counter32, uint(100)),
+		NewSNMPResponse(".1.3.6.1.2.1.2.2.1.10.2", gosnmp.Counter32, uint(200)),
+	}, res)
+
+	res, err = client.SNMPWalk(context.Background(), "1.3.6.1.2.1.4.20.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, []SNMPResponse{NewSNMPResponse(".1.3.6.1.2.1.4.20.1.1.192.0.2.1", gosnmp.IPAddress, "192.0.2.1")}, res)
+
+	_, err = client.SNMPWalk(context.Background(), "1.3.6.1.2.1.2.2.1.1")
+	assert.True(t, tholaerr.IsNotFoundError(err))
+
+	res, err = client.SNMPRawWalk(context.Background(), "1.3.6.1.2.1.2.2.1.1", true)
+	assert.NoError(t, err)
+	assert.Empty(t, res)
+}
+
+func TestSNMPReplayClient_snmprecRoundTrip(t *testing.T) {
+	responses := []SNMPResponse{
+		NewSNMPResponse(".1.3.6.1.2.1.1.5.0", gosnmp.OctetString, []byte("MikroTik")),
+		NewSNMPResponse(".1.3.6.1.2.1.2.2.1.6.1", gosnmp.OctetString, []byte{0x00, 0x1a, 0x2b}),
+		NewSNMPResponse(".1.3.6.1.2.1.1.2.0", gosnmp.ObjectIdentifier, ".1.3.6.1.4.1.14988.1"),
+		NewSNMPResponse(".1.3.6.1.2.1.1.7.0", gosnmp.Integer, 78),
+		NewSNMPResponse(".1.3.6.1.2.1.1.3.0", gosnmp.TimeTicks, uint32(1097900)),
+		NewSNMPResponse(".1.3.6.1.2.1.31.1.1.1.6.1", gosnmp.Counter64, uint64(12345678901)),
+		NewSNMPResponse(".1.3.6.1.2.1.4.20.1.1.10.0.0.1", gosnmp.IPAddress, "10.0.0.1"),
+		NewSNMPResponse(".1.3.6.1.4.1.2021.10.1.6.1", gosnmp.Opaque, []byte{0x9f, 0x78, 0x04, 0x3e, 0x4c, 0xcc, 0xcd}),
+		NewSNMPResponse(".1.3.6.1.4.1.2021.10.1.6.2", gosnmp.Opaque, []byte("load")),
+	}
+	for _, response := range responses {
+		tag, value, err := response.GetSNMPRecValue()
+		require.NoError(t, err, response.GetOID())
+
+		client, err := NewSNMPReplayClient([]snmprec.Record{{OID: response.GetOID().String()[1:], Type: tag, Value: value}})
+		require.NoError(t, err, response.GetOID())
+		res, err := client.SNMPGet(context.Background(), response.GetOID())
+		require.NoError(t, err, response.GetOID())
+		require.Len(t, res, 1)
+
+		replayedTag, replayedValue, err := res[0].GetSNMPRecValue()
+		require.NoError(t, err, response.GetOID())
+		assert.Equal(t, tag, replayedTag, response.GetOID())
+		assert.Equal(t, value, replayedValue, response.GetOID())
+	}
+
+	pdu, err := snmprec.Record{OID: "1.3.6.1.4.1.2021.10.1.6.2", Type: "68", Value: "load"}.PDU()
+	require.NoError(t, err)
+	assert.Equal(t, gosnmp.Opaque, pdu.Type)
+	assert.Equal(t, []byte("load"), pdu.Value)
+}
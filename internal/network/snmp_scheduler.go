@@ -0,0 +1,186 @@
+package network
+
+import (
+	"context"
+	"github.com/gosnmp/gosnmp"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+// SNMPSchedule limits the snmp requests sent to a device.
+// Zero values mean no limit.
+type SNMPSchedule struct {
+	// MaxConcurrentRequests is the amount of snmp requests that may be outstanding at the same time.
+	// A walk counts as one request, as it waits for each response before it sends the next packet.
+	MaxConcurrentRequests int
+	// MinRequestInterval is the minimum delay between two packets.
+	MinRequestInterval time.Duration
+	// MaxRequestsPerSecond is the maximum amount of packets per second.
+	MaxRequestsPerSecond float64
+}
+
+// Override returns the schedule with all limits that are set in o replaced.
+func (s SNMPSchedule) Override(o SNMPSchedule) SNMPSchedule {
+	if o.MaxConcurrentRequests != 0 {
+		s.MaxConcurrentRequests = o.MaxConcurrentRequests
+	}
+	if o.MinRequestInterval != 0 {
+		s.MinRequestInterval = o.MinRequestInterval
+	}
+	if o.MaxRequestsPerSecond != 0 {
+		s.MaxRequestsPerSecond = o.MaxRequestsPerSecond
+	}
+	return s
+}
+
+// Validate checks if the limits of the schedule are valid.
+func (s SNMPSchedule) Validate() error {
+	if s.MaxConcurrentRequests < 0 {
+		return errors.New("snmp max concurrent requests must not be negative")
+	}
+	if s.MinRequestInterval < 0 {
+		return errors.New("snmp min request interval must not be negative")
+	}
+	if s.MaxRequestsPerSecond < 0 {
+		return errors.New("snmp max requests per second must not be negative")
+	}
+	return nil
+}
+
+// interval returns the minimum delay between two packets.
+func (s SNMPSchedule) interval() time.Duration {
+	interval := s.MinRequestInterval
+	if s.MaxRequestsPerSecond > 0 {
+		if i := time.Duration(float64(time.Second) / s.MaxRequestsPerSecond); i > interval {
+			interval = i
+		}
+	}
+	return interval
+}
+
+// snmpScheduler schedules the snmp requests of all clients of this process per device.
+var snmpScheduler = struct {
+	sync.Mutex
+
+	defaultSchedule SNMPSchedule
+	devices         map[string]*deviceSchedule
+}{
+	devices: make(map[string]*deviceSchedule),
+}
+
+// SetDefaultSNMPSchedule sets the schedule of all devices whose device class does not override it.
+func SetDefaultSNMPSchedule(schedule SNMPSchedule) {
+	snmpScheduler.Lock()
+	defer snmpScheduler.Unlock()
+	snmpScheduler.defaultSchedule = schedule
+}
+
+// GetDefaultSNMPSchedule returns the schedule of all devices whose device class does not override it.
+func GetDefaultSNMPSchedule() SNMPSchedule {
+	snmpScheduler.Lock()
+	defer snmpScheduler.Unlock()
+	return snmpScheduler.defaultSchedule
+}
+
+func getDeviceSchedule(device string) *deviceSchedule {
+	snmpScheduler.Lock()
+	defer snmpScheduler.Unlock()
+
+	d, ok := snmpScheduler.devices[device]
+	if !ok {
+		d = &deviceSchedule{released: make(chan struct{})}
+		snmpScheduler.devices[device] = d
+	}
+	return d
+}
+
+// deviceSchedule contains the requests of a device that are currently outstanding and when the next packet may be sent.
+// The limits are passed by the clients, as clients of the same device can have different device classes while identifying.
+type deviceSchedule struct {
+	sync.Mutex
+
+	active   int
+	released chan struct{}
+	next     time.Time
+}
+
+// acquire waits until less than the maximum concurrent requests are outstanding and returns the function that releases the slot.
+func (d *deviceSchedule) acquire(ctx context.Context, schedule SNMPSchedule) (func(), error) {
+	if schedule.MaxConcurrentRequests <= 0 {
+		return func() {}, nil
+	}
+	for {
+		d.Lock()
+		if d.active < schedule.MaxConcurrentRequests {
+			d.active++
+			d.Unlock()
+			return d.release, nil
+		}
+		released := d.released
+		d.Unlock()
+
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "failed to wait for a free snmp request slot")
+		}
+	}
+}
+
+func (d *deviceSchedule) release() {
+	d.Lock()
+	defer d.Unlock()
+	d.active--
+	close(d.released)
+	d.released = make(chan struct{})
+}
+
+// wait reserves the next point in time at which a packet may be sent and waits until then.
+// It returns whether it had to wait.
+func (d *deviceSchedule) wait(ctx context.Context, schedule SNMPSchedule) bool {
+	interval := schedule.interval()
+	if interval <= 0 {
+		return false
+	}
+
+	d.Lock()
+	now := time.Now()
+	sendAt := d.next
+	if sendAt.Before(now) {
+		sendAt = now
+	}
+	d.next = sendAt.Add(interval)
+	d.Unlock()
+
+	delay := time.Until(sendAt)
+	if delay <= 0 {
+		return false
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return true
+}
+
+// scheduleSNMPRequests paces all packets sent by the client according to the schedule of the snmp client.
+func scheduleSNMPRequests(s *snmpClient) {
+	s.client.PreSend = func(x *gosnmp.GoSNMP) {
+		ctx := x.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if !s.device.wait(ctx, s.schedule) {
+			return
+		}
+		// gosnmp sets the deadline of the response before the packet is sent, so the wait must not shorten it
+		deadline := time.Now().Add(x.Timeout)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+			deadline = ctxDeadline
+		}
+		_ = x.Conn.SetDeadline(deadline)
+	}
+}
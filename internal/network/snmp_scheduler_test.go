@@ -0,0 +1,50 @@
+package network
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestDeviceSchedule_acquire(t *testing.T) {
+	d := getDeviceSchedule("192.0.2.10")
+	schedule := SNMPSchedule{MaxConcurrentRequests: 2}
+
+	release1, err := d.acquire(context.Background(), schedule)
+	require.NoError(t, err)
+	release2, err := d.acquire(context.Background(), schedule)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = d.acquire(ctx, schedule)
+	assert.Error(t, err)
+
+	// a higher limit of another device class is not blocked
+	release3, err := d.acquire(context.Background(), SNMPSchedule{MaxConcurrentRequests: 3})
+	require.NoError(t, err)
+	release3()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		release1()
+	}()
+	release1, err = d.acquire(context.Background(), schedule)
+	require.NoError(t, err)
+	release1()
+	release2()
+}
+
+func TestDeviceSchedule_wait(t *testing.T) {
+	d := getDeviceSchedule("192.0.2.11")
+	schedule := SNMPSchedule{MinRequestInterval: 10 * time.Millisecond, MaxRequestsPerSecond: 50}
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		d.wait(context.Background(), schedule)
+	}
+	// the rate limit of 50 packets per second is stricter than the min interval
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(60*time.Millisecond))
+}
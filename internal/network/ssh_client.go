@@ -0,0 +1,358 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultSSHPrompt = `^[\w\-.@:/()\[\]~ ]*[>#$%]\s*$`
+	defaultSSHPager  = `(?i)(-+ *\(?more\b[^\n]*|press any key to continue[^\n]*)$`
+)
+
+var ansiEscapeSequence = regexp.MustCompile(`\x1b(\[[0-9;?]*[a-zA-Z]|[()][0-9A-Za-z]|[=>])`)
+
+// SSHClient is used for communication with the cli of a device over SSH.
+// All commands are sent to the same interactive shell, so commands that change the state of the cli
+// (e.g. disabling the pager) also affect all following commands.
+type SSHClient struct {
+	host string
+	port int
+
+	username string
+	password string
+
+	config *ssh.ClientConfig
+
+	prompt *regexp.Regexp
+	pager  *regexp.Regexp
+
+	timeout time.Duration
+
+	useCache bool
+	cache    requestCache
+
+	mutex sync.Mutex
+	// connected is set once a session was established, so that the connection data is known to work.
+	connected bool
+	client    *ssh.Client
+	session   *ssh.Session
+	stdin     io.Writer
+	output    chan []byte
+	done      chan struct{}
+}
+
+// NewSSHClient returns a new SSH client.
+// The connection to the device is established when the first command is run.
+func NewSSHClient(host string, port int, username, password string) (*SSHClient, error) {
+	if host == "" {
+		return nil, errors.New("invalid host")
+	}
+	if port <= 0 {
+		return nil, errors.New("invalid port")
+	}
+	if username == "" {
+		return nil, errors.New("invalid username")
+	}
+
+	sshClient := SSHClient{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		prompt:   regexp.MustCompile(defaultSSHPrompt),
+		pager:    regexp.MustCompile(defaultSSHPager),
+		timeout:  15 * time.Second,
+		useCache: true,
+		cache:    newRequestCache("ssh"),
+	}
+	sshClient.config = newSSHClientConfig(username, password)
+	return &sshClient, nil
+}
+
+func newSSHClientConfig(username, password string) *ssh.ClientConfig {
+	return &ssh.ClientConfig{
+		User: username,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(password),
+			// a lot of network devices only offer keyboard interactive authentication which asks for the password
+			ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = password
+				}
+				return answers, nil
+			}),
+		},
+		// network devices usually have self generated host keys which cannot be verified
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	}
+}
+
+// SetPrompt sets the regex that matches the prompt of the cli.
+func (s *SSHClient) SetPrompt(prompt string) error {
+	regex, err := regexp.Compile(prompt)
+	if err != nil {
+		return errors.Wrap(err, "invalid prompt regex")
+	}
+	s.prompt = regex
+	return nil
+}
+
+// SetTimeout sets a timeout for the connection setup and every command.
+func (s *SSHClient) SetTimeout(timeout time.Duration) {
+	s.timeout = timeout
+	s.config.Timeout = timeout
+}
+
+// UseCache configures whether the ssh cache should be used or not.
+func (s *SSHClient) UseCache(b bool) {
+	s.useCache = b
+}
+
+// HasSuccessfulCachedRequest returns if there was at least one successful cached request.
+func (s *SSHClient) HasSuccessfulCachedRequest() bool {
+	return len(s.cache.getSuccessfulRequests()) > 0
+}
+
+// HasSuccessfulConnection returns if a session to the device was established at least once.
+func (s *SSHClient) HasSuccessfulConnection() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.connected
+}
+
+// GetHostname returns the hostname.
+func (s *SSHClient) GetHostname() string {
+	return s.host
+}
+
+// GetPort returns the port.
+func (s *SSHClient) GetPort() int {
+	return s.port
+}
+
+// RunCommand runs a command on the cli of the device and returns its output.
+// The echoed command and the prompt are removed from the output, pagers are skipped.
+func (s *SSHClient) RunCommand(ctx context.Context, command string) (string, error) {
+	if s.useCache {
+		x, err := s.cache.get(command)
+		if err == nil {
+			if x.returnedError() {
+				return "", x.err
+			}
+			res, ok := x.res.(string)
+			if !ok {
+				return "", errors.New("cached ssh result is not a string")
+			}
+			return res, nil
+		}
+	}
+
+	res, err := s.runCommand(ctx, command)
+	if s.useCache {
+		s.cache.add(command, res, err)
+	}
+	return res, err
+}
+
+func (s *SSHClient) runCommand(ctx context.Context, command string) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.session == nil {
+		err := s.connect(ctx)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	_, err := s.stdin.Write([]byte(command + "\n"))
+	if err != nil {
+		_ = s.disconnect()
+		return "", tholaerr.NewSSHError(fmt.Sprintf("failed to send command: %s", err))
+	}
+
+	out, err := s.readUntilPrompt(ctx)
+	if err != nil {
+		_ = s.disconnect()
+		return "", errors.Wrapf(err, "failed to read output of command '%s'", command)
+	}
+
+	lines := strings.Split(out, "\n")
+	// remove the prompt
+	lines = lines[:len(lines)-1]
+	// remove the echoed command
+	if len(lines) > 0 && strings.Contains(lines[0], command) {
+		lines = lines[1:]
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n"), nil
+}
+
+func (s *SSHClient) connect(ctx context.Context) error {
+	address := net.JoinHostPort(s.host, strconv.Itoa(s.port))
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return tholaerr.NewSSHError(err.Error())
+	}
+
+	_ = conn.SetDeadline(time.Now().Add(s.timeout))
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, address, s.config)
+	if err != nil {
+		_ = conn.Close()
+		return tholaerr.NewSSHError(fmt.Sprintf("ssh handshake failed: %s", err))
+	}
+	_ = conn.SetDeadline(time.Time{})
+	client := ssh.NewClient(sshConn, chans, reqs)
+
+	session, err := client.NewSession()
+	if err != nil {
+		_ = client.Close()
+		return tholaerr.NewSSHError(fmt.Sprintf("failed to open ssh session: %s", err))
+	}
+
+	// a wide terminal prevents line wraps in the command output
+	err = session.RequestPty("vt100", 0, 512, ssh.TerminalModes{})
+	if err != nil {
+		_ = client.Close()
+		return tholaerr.NewSSHError(fmt.Sprintf("failed to request pty: %s", err))
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		_ = client.Close()
+		return errors.Wrap(err, "failed to get stdin of ssh session")
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		_ = client.Close()
+		return errors.Wrap(err, "failed to get stdout of ssh session")
+	}
+	err = session.Shell()
+	if err != nil {
+		_ = client.Close()
+		return tholaerr.NewSSHError(fmt.Sprintf("failed to start shell: %s", err))
+	}
+
+	s.client = client
+	s.session = session
+	s.stdin = stdin
+	s.output = make(chan []byte)
+	s.done = make(chan struct{})
+	go readSSHOutput(stdout, s.output, s.done)
+
+	// skip the banner
+	_, err = s.readUntilPrompt(ctx)
+	if err != nil {
+		_ = s.disconnect()
+		return errors.Wrap(err, "failed to read prompt after login")
+	}
+	s.connected = true
+	return nil
+}
+
+func readSSHOutput(r io.Reader, output chan<- []byte, done <-chan struct{}) {
+	defer close(output)
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			b := make([]byte, n)
+			copy(b, buf[:n])
+			select {
+			case output <- b:
+			case <-done:
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readUntilPrompt reads the output of the shell until the prompt appears and returns the normalized output.
+func (s *SSHClient) readUntilPrompt(ctx context.Context) (string, error) {
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+
+	var buf []byte
+	for {
+		if loc := s.pager.FindIndex(buf); loc != nil {
+			buf = buf[:loc[0]]
+			_, err := s.stdin.Write([]byte(" "))
+			if err != nil {
+				return "", tholaerr.NewSSHError(fmt.Sprintf("failed to skip pager: %s", err))
+			}
+		} else if out := normalizeSSHOutput(buf); s.prompt.MatchString(out[strings.LastIndex(out, "\n")+1:]) {
+			return out, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-timer.C:
+			return "", tholaerr.NewSSHError("timeout while waiting for prompt")
+		case b, ok := <-s.output:
+			if !ok {
+				return "", tholaerr.NewSSHError("ssh session was closed by the device")
+			}
+			buf = append(buf, ansiEscapeSequence.ReplaceAll(b, nil)...)
+		}
+	}
+}
+
+// normalizeSSHOutput converts the terminal output to plain text lines.
+// Carriage returns and backspaces are interpreted like a terminal would do it.
+func normalizeSSHOutput(b []byte) string {
+	lines := strings.Split(strings.ReplaceAll(string(b), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if idx := strings.LastIndex(line, "\r"); idx != -1 {
+			line = line[idx+1:]
+		}
+		if strings.Contains(line, "\b") {
+			var l []rune
+			for _, r := range line {
+				if r == '\b' {
+					if len(l) > 0 {
+						l = l[:len(l)-1]
+					}
+					continue
+				}
+				l = append(l, r)
+			}
+			line = string(l)
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Disconnect closes the ssh connection.
+func (s *SSHClient) Disconnect() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.disconnect()
+}
+
+func (s *SSHClient) disconnect() error {
+	if s.client == nil {
+		return nil
+	}
+	close(s.done)
+	err := s.client.Close()
+	s.client = nil
+	s.session = nil
+	s.stdin = nil
+	return err
+}
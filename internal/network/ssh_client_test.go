@@ -0,0 +1,170 @@
+package network
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startTestSSHServer starts a ssh server which calls serve for every shell or subsystem.
+func startTestSSHServer(t *testing.T, serve func(channel ssh.Channel)) *net.TCPAddr {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == "admin" && string(password) == "secret" {
+				return nil, nil
+			}
+			return nil, ssh.ErrNoAuth
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSSHConn(conn, config, serve)
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr)
+}
+
+func serveTestSSHConn(conn net.Conn, config *ssh.ServerConfig, serve func(channel ssh.Channel)) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChannel := range chans {
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range requests {
+				_ = req.Reply(req.Type == "pty-req" || req.Type == "shell" || req.Type == "subsystem", nil)
+				if req.Type == "shell" || req.Type == "subsystem" {
+					go func() {
+						defer channel.Close()
+						serve(channel)
+					}()
+				}
+			}
+		}()
+	}
+}
+
+// serveTestCLI behaves like the cli of a network device.
+func serveTestCLI(commands *int32) func(channel ssh.Channel) {
+	return func(channel ssh.Channel) {
+		_, _ = channel.Write([]byte("Welcome to the test device\r\n\x1b[1mdevice>\x1b[0m "))
+		reader := bufio.NewReader(channel)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			command := strings.TrimSpace(line)
+			atomic.AddInt32(commands, 1)
+			_, _ = channel.Write([]byte(command + "\r\n"))
+			switch command {
+			case "show version":
+				_, _ = channel.Write([]byte("Software Version 1.2.3\r\nSerial Number: SN12345\r\n"))
+			case "show interfaces":
+				_, _ = channel.Write([]byte("eth0 up\r\n -- More -- "))
+				if b, err := reader.ReadByte(); err != nil || b != ' ' {
+					return
+				}
+				_, _ = channel.Write([]byte("\r           \reth1 down\r\n"))
+			}
+			_, _ = channel.Write([]byte("device> "))
+		}
+	}
+}
+
+func TestSSHClient_RunCommand(t *testing.T) {
+	var commands int32
+	addr := startTestSSHServer(t, serveTestCLI(&commands))
+
+	client, err := NewSSHClient(addr.IP.String(), addr.Port, "admin", "secret")
+	require.NoError(t, err)
+	client.SetTimeout(5 * time.Second)
+	defer client.Disconnect()
+
+	out, err := client.RunCommand(context.Background(), "show version")
+	assert.NoError(t, err)
+	assert.Equal(t, "Software Version 1.2.3\nSerial Number: SN12345", out)
+
+	out, err = client.RunCommand(context.Background(), "show interfaces")
+	assert.NoError(t, err)
+	assert.Equal(t, "eth0 up\neth1 down", out)
+
+	out, err = client.RunCommand(context.Background(), "unknown")
+	assert.NoError(t, err)
+	assert.Equal(t, "", out)
+
+	// cached
+	out, err = client.RunCommand(context.Background(), "show version")
+	assert.NoError(t, err)
+	assert.Equal(t, "Software Version 1.2.3\nSerial Number: SN12345", out)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&commands))
+	assert.True(t, client.HasSuccessfulCachedRequest())
+}
+
+func TestSSHClient_RunCommand_authFailed(t *testing.T) {
+	var commands int32
+	addr := startTestSSHServer(t, serveTestCLI(&commands))
+
+	client, err := NewSSHClient(addr.IP.String(), addr.Port, "admin", "wrong")
+	require.NoError(t, err)
+	client.SetTimeout(5 * time.Second)
+
+	_, err = client.RunCommand(context.Background(), "show version")
+	assert.True(t, tholaerr.IsNetworkError(err))
+	assert.False(t, client.HasSuccessfulCachedRequest())
+	assert.False(t, client.HasSuccessfulConnection())
+}
+
+func TestRequestDeviceConnection_GetIdealConnectionData_ssh(t *testing.T) {
+	var commands int32
+	addr := startTestSSHServer(t, serveTestCLI(&commands))
+
+	client, err := NewSSHClient(addr.IP.String(), addr.Port, "admin", "secret")
+	require.NoError(t, err)
+	client.SetTimeout(5 * time.Second)
+	defer client.Disconnect()
+	con := RequestDeviceConnection{SSH: &RequestDeviceConnectionSSH{SSHClient: client}}
+
+	assert.Nil(t, con.GetIdealConnectionData().SSH, "unused ssh connection data is not cached")
+
+	_, err = client.RunCommand(context.Background(), "show version")
+	require.NoError(t, err)
+	username := "admin"
+	assert.Equal(t, &SSHConnectionData{Ports: []int{addr.Port}, Username: &username}, con.GetIdealConnectionData().SSH)
+}
+
+func TestNormalizeSSHOutput(t *testing.T) {
+	assert.Equal(t, "line1\nline2\nabd", normalizeSSHOutput([]byte("line1\r\nfoo\rline2\r\nabc\bd")))
+}
@@ -0,0 +1,131 @@
+package network
+
+import (
+	"github.com/gosnmp/gosnmp"
+	"sync"
+	"time"
+)
+
+// CacheStatistics contains the hits and misses of a cache.
+type CacheStatistics struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// SNMPStatistics contains the sent snmp packets of a device and how many of them timed out.
+type SNMPStatistics struct {
+	Requests uint64 `json:"requests"`
+	Timeouts uint64 `json:"timeouts"`
+
+	lastRequest time.Time
+}
+
+var statistics = struct {
+	sync.Mutex
+
+	caches map[string]*CacheStatistics
+	snmp   map[string]*SNMPStatistics
+	// maxSNMPDevices limits the devices with snmp statistics, the least recently requested device is dropped first.
+	maxSNMPDevices int
+}{
+	caches:         make(map[string]*CacheStatistics),
+	snmp:           make(map[string]*SNMPStatistics),
+	maxSNMPDevices: 1000,
+}
+
+// SetMaxSNMPStatisticsDevices sets the maximum amount of devices with snmp statistics, 0 disables them.
+func SetMaxSNMPStatisticsDevices(max int) {
+	statistics.Lock()
+	defer statistics.Unlock()
+	statistics.maxSNMPDevices = max
+	for len(statistics.snmp) > max {
+		evictSNMPStatistics()
+	}
+}
+
+func addCacheLookup(cache string, hit bool) {
+	statistics.Lock()
+	defer statistics.Unlock()
+
+	s, ok := statistics.caches[cache]
+	if !ok {
+		s = &CacheStatistics{}
+		statistics.caches[cache] = s
+	}
+	if hit {
+		s.Hits++
+	} else {
+		s.Misses++
+	}
+}
+
+// snmpStatisticsOf returns the snmp statistics of the target, or nil if there are no statistics per device.
+func snmpStatisticsOf(target string) *SNMPStatistics {
+	if statistics.maxSNMPDevices <= 0 {
+		return nil
+	}
+	s, ok := statistics.snmp[target]
+	if !ok {
+		for len(statistics.snmp) >= statistics.maxSNMPDevices {
+			evictSNMPStatistics()
+		}
+		s = &SNMPStatistics{}
+		statistics.snmp[target] = s
+	}
+	s.lastRequest = time.Now()
+	return s
+}
+
+// evictSNMPStatistics removes the snmp statistics of the least recently requested device.
+func evictSNMPStatistics() {
+	var oldest string
+	for target, s := range statistics.snmp {
+		if oldest == "" || s.lastRequest.Before(statistics.snmp[oldest].lastRequest) {
+			oldest = target
+		}
+	}
+	delete(statistics.snmp, oldest)
+}
+
+// countSNMPRequests counts all packets sent by the client and all attempts that were not answered.
+func countSNMPRequests(client *gosnmp.GoSNMP) {
+	client.OnSent = func(x *gosnmp.GoSNMP) {
+		statistics.Lock()
+		defer statistics.Unlock()
+		if s := snmpStatisticsOf(x.Target); s != nil {
+			s.Requests++
+		}
+	}
+	// gosnmp retries a request only if the previous attempt failed, which is nearly always a timeout
+	client.OnRetry = func(x *gosnmp.GoSNMP) {
+		statistics.Lock()
+		defer statistics.Unlock()
+		if s := snmpStatisticsOf(x.Target); s != nil {
+			s.Timeouts++
+		}
+	}
+}
+
+// GetCacheStatistics returns the statistics of all request caches by their name.
+func GetCacheStatistics() map[string]CacheStatistics {
+	statistics.Lock()
+	defer statistics.Unlock()
+
+	res := make(map[string]CacheStatistics, len(statistics.caches))
+	for name, s := range statistics.caches {
+		res[name] = *s
+	}
+	return res
+}
+
+// GetSNMPStatistics returns the snmp statistics of all devices by their address.
+func GetSNMPStatistics() map[string]SNMPStatistics {
+	statistics.Lock()
+	defer statistics.Unlock()
+
+	res := make(map[string]SNMPStatistics, len(statistics.snmp))
+	for target, s := range statistics.snmp {
+		res[target] = *s
+	}
+	return res
+}
@@ -0,0 +1,24 @@
+package network
+
+import (
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSetMaxSNMPStatisticsDevices(t *testing.T) {
+	defer SetMaxSNMPStatisticsDevices(1000)
+	SetMaxSNMPStatisticsDevices(1)
+
+	for _, target := range []string{"192.0.2.1", "192.0.2.2"} {
+		client := &gosnmp.GoSNMP{Target: target}
+		countSNMPRequests(client)
+		client.OnSent(client)
+	}
+	stats := GetSNMPStatistics()
+	assert.NotContains(t, stats, "192.0.2.1")
+	assert.Equal(t, uint64(1), stats["192.0.2.2"].Requests)
+
+	SetMaxSNMPStatisticsDevices(0)
+	assert.Empty(t, GetSNMPStatistics())
+}
@@ -0,0 +1,20 @@
+package network
+
+import (
+	"crypto/x509"
+	"github.com/pkg/errors"
+	"io/ioutil"
+)
+
+// ReadCertPool reads a CA bundle of PEM encoded certificates.
+func ReadCertPool(file string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read CA file")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, errors.Errorf("CA file '%s' contains no PEM encoded certificates", file)
+	}
+	return pool, nil
+}
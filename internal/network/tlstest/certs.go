@@ -0,0 +1,122 @@
+// Package tlstest generates local certificates for tests with (mutual) TLS.
+package tlstest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"time"
+)
+
+// CA is a certificate authority which issues server and client certificates.
+type CA struct {
+	// CertFile is the PEM encoded certificate of the CA.
+	CertFile string
+
+	dir    string
+	cert   *x509.Certificate
+	key    *ecdsa.PrivateKey
+	serial int64
+}
+
+// Certificate contains the paths of a PEM encoded certificate and its key.
+type Certificate struct {
+	CertFile string
+	KeyFile  string
+}
+
+// NewCA creates a new self-signed CA whose files are written to dir.
+func NewCA(dir string) (*CA, error) {
+	ca := &CA{dir: dir, serial: 1}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate key")
+	}
+	template := ca.template("thola test CA")
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA certificate")
+	}
+	ca.cert, err = x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse CA certificate")
+	}
+	ca.key = key
+	ca.CertFile = filepath.Join(dir, "ca.pem")
+	if err := writePEM(ca.CertFile, "CERTIFICATE", der); err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
+
+// IssueServer issues a certificate for a server on localhost.
+func (ca *CA) IssueServer(name string) (Certificate, error) {
+	template := ca.template(name)
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	template.DNSNames = []string{"localhost"}
+	template.IPAddresses = []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+	return ca.issue(name, template)
+}
+
+// IssueClient issues a client certificate with the name as common name and the given DNS SANs.
+func (ca *CA) IssueClient(name string, dnsNames ...string) (Certificate, error) {
+	template := ca.template(name)
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	template.DNSNames = dnsNames
+	return ca.issue(name, template)
+}
+
+func (ca *CA) template(commonName string) *x509.Certificate {
+	ca.serial++
+	return &x509.Certificate{
+		SerialNumber: big.NewInt(ca.serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+}
+
+func (ca *CA) issue(name string, template *x509.Certificate) (Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return Certificate{}, errors.Wrap(err, "failed to generate key")
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return Certificate{}, errors.Wrap(err, "failed to create certificate")
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return Certificate{}, errors.Wrap(err, "failed to marshal key")
+	}
+
+	c := Certificate{
+		CertFile: filepath.Join(ca.dir, name+".pem"),
+		KeyFile:  filepath.Join(ca.dir, name+"-key.pem"),
+	}
+	if err := writePEM(c.CertFile, "CERTIFICATE", der); err != nil {
+		return Certificate{}, err
+	}
+	if err := writePEM(c.KeyFile, "EC PRIVATE KEY", keyDER); err != nil {
+		return Certificate{}, err
+	}
+	return c, nil
+}
+
+func writePEM(file, blockType string, der []byte) error {
+	err := ioutil.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	return errors.Wrapf(err, "failed to write '%s'", file)
+}
@@ -33,6 +33,10 @@ type toCheckPluginOutputParser interface {
 	ToCheckPluginOutput() ([]byte, error)
 }
 
+type snmpRecParser interface {
+	ToSNMPRec() ([]byte, error)
+}
+
 // Parse parses the object into the desired format
 func Parse(i interface{}, format string) ([]byte, error) {
 	switch format {
@@ -44,6 +48,8 @@ func Parse(i interface{}, format string) ([]byte, error) {
 		return ToCSV(i)
 	case "check-plugin":
 		return ToCheckPluginOutput(i)
+	case "snmprec":
+		return ToSNMPRec(i)
 	default:
 		return ToHumanReadable(i)
 	}
@@ -106,6 +112,15 @@ func ToCheckPluginOutput(i interface{}) ([]byte, error) {
 	return nil, errors.New("object cannot be passed to check plugin output")
 }
 
+// ToSNMPRec parses the object to the snmprec format.
+func ToSNMPRec(i interface{}) ([]byte, error) {
+	i = checkIfError(i)
+	if p, ok := i.(snmpRecParser); ok {
+		return p.ToSNMPRec()
+	}
+	return ToHumanReadable(i)
+}
+
 // ToStruct parses the formatted content into the struct with the correct unmarshal method.
 func ToStruct(contents []byte, format string, i interface{}) error {
 	switch format {
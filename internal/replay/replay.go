@@ -0,0 +1,177 @@
+// Package replay is a test harness to run device classes and code communicators against snmprec recordings.
+//
+// The snmp requests of a device class are answered by an in-memory replay of the snmprec file instead of a device,
+// so regression tests only need a recording and a golden file with the expected output:
+//
+//	ctx, com := replay.NewDevice(t, "testdata/device.snmprec", "")
+//	interfaces, err := com.GetInterfaces(ctx)
+//	require.NoError(t, err)
+//	replay.AssertGolden(t, "testdata/device_interfaces.json", interfaces)
+//
+// Golden files are created or updated by running the tests with the -update flag.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"github.com/inexio/thola/internal/communicator"
+	"github.com/inexio/thola/internal/communicator/create"
+	"github.com/inexio/thola/internal/device"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/snmprec"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files of replay tests")
+
+// NewContext returns a context with a device connection whose snmp client replays the snmprec file.
+func NewContext(ctx context.Context, snmpRecFile string) (context.Context, error) {
+	f, err := os.Open(snmpRecFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open snmprec file")
+	}
+	defer f.Close()
+
+	records, err := snmprec.Read(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read snmprec file")
+	}
+
+	client, err := network.NewSNMPReplayClient(records)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create snmp replay client")
+	}
+
+	return network.NewContextWithDeviceConnection(ctx, &network.RequestDeviceConnection{
+		RawConnectionData: network.ConnectionData{
+			SNMP: &network.SNMPConnectionData{
+				Communities: []string{client.GetCommunity()},
+				Versions:    []string{client.GetVersion()},
+				Ports:       []int{client.GetPort()},
+			},
+		},
+		SNMP: &network.RequestDeviceConnectionSNMP{
+			SnmpClient: client,
+		},
+	}), nil
+}
+
+// Communicator returns the communicator of the replayed device in the context and a context containing its device properties.
+// If class is empty, the device class is identified like for a real device, otherwise the given device class is used.
+func Communicator(ctx context.Context, class string) (context.Context, communicator.Communicator, error) {
+	var com communicator.Communicator
+	var err error
+	if class == "" {
+		com, err = create.IdentifyNetworkDeviceCommunicator(ctx)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to identify device class")
+		}
+	} else {
+		com, err = create.GetNetworkDeviceCommunicator(ctx, class)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to get communicator for device class '%s'", class)
+		}
+	}
+
+	properties, err := com.GetIdentifyProperties(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get identify properties")
+	}
+	ctx = device.NewContextWithDeviceProperties(ctx, device.Device{
+		Class:      com.GetIdentifier(),
+		Properties: properties,
+	})
+
+	err = com.UpdateConnection(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to update connection")
+	}
+	return ctx, com, nil
+}
+
+// NewDevice replays the snmprec file and returns the communicator of the device together with its context.
+// The test fails immediately if the device cannot be set up.
+func NewDevice(t testing.TB, snmpRecFile, class string) (context.Context, communicator.Communicator) {
+	t.Helper()
+	ctx, err := NewContext(context.Background(), snmpRecFile)
+	require.NoError(t, err)
+	ctx, com, err := Communicator(ctx, class)
+	require.NoError(t, err)
+	return ctx, com
+}
+
+// Snapshot reads the identify properties and all available components of the device.
+// Components that cannot be read are contained with their error message, so that changes of the behavior are visible as well.
+func Snapshot(ctx context.Context, com communicator.Communicator) map[string]interface{} {
+	components := com.GetAvailableComponents()
+	sort.Strings(components)
+
+	snapshot := map[string]interface{}{
+		"class":      com.GetIdentifier(),
+		"components": components,
+	}
+	if properties, ok := device.DevicePropertiesFromContext(ctx); ok {
+		snapshot["properties"] = properties.Properties
+	}
+
+	for _, c := range components {
+		var res interface{}
+		var err error
+		switch c {
+		case "interfaces":
+			res, err = com.GetInterfaces(ctx)
+		case "ups":
+			res, err = com.GetUPSComponent(ctx)
+		case "cpu":
+			res, err = com.GetCPUComponentCPULoad(ctx)
+		case "memory":
+			res, err = com.GetMemoryComponentMemoryUsage(ctx)
+		case "sbc":
+			res, err = com.GetSBCComponent(ctx)
+		case "server":
+			res, err = com.GetServerComponent(ctx)
+		case "disk":
+			res, err = com.GetDiskComponent(ctx)
+		case "hardware_health":
+			res, err = com.GetHardwareHealthComponent(ctx)
+		default:
+			continue
+		}
+		if err != nil {
+			res = map[string]string{"error": err.Error()}
+		}
+		snapshot[c] = res
+	}
+	return snapshot
+}
+
+// AssertGolden asserts that the json encoding of actual equals the content of the golden file.
+// If the tests are run with -update, the golden file is written instead.
+func AssertGolden(t testing.TB, goldenFile string, actual interface{}) bool {
+	t.Helper()
+	b, err := json.MarshalIndent(actual, "", "  ")
+	require.NoError(t, err)
+	b = append(b, '\n')
+
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(goldenFile), 0755))
+		require.NoError(t, ioutil.WriteFile(goldenFile, b, 0644))
+		return true
+	}
+
+	expected, err := ioutil.ReadFile(goldenFile)
+	if os.IsNotExist(err) {
+		t.Errorf("golden file '%s' does not exist, run the test with -update to create it", goldenFile)
+		return false
+	}
+	require.NoError(t, err)
+	return assert.JSONEq(t, string(expected), string(b))
+}
@@ -0,0 +1,33 @@
+package replay
+
+import (
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const devicesDir = "../../test/testdata/devices"
+
+func TestDevices(t *testing.T) {
+	var files []string
+	err := filepath.Walk(devicesDir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && strings.HasSuffix(path, ".snmprec") {
+			files = append(files, path)
+		}
+		return err
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		name, err := filepath.Rel(devicesDir, strings.TrimSuffix(file, ".snmprec"))
+		require.NoError(t, err)
+
+		t.Run(filepath.ToSlash(name), func(t *testing.T) {
+			ctx, com := NewDevice(t, file, "")
+			AssertGolden(t, filepath.Join("testdata", name+".json"), Snapshot(ctx, com))
+		})
+	}
+}
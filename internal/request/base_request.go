@@ -69,6 +69,15 @@ func (r *BaseRequest) validate(ctx context.Context) error {
 	if configData.HTTP == nil {
 		configData.HTTP = &network.HTTPConnectionData{}
 	}
+	if configData.SSH == nil {
+		configData.SSH = &network.SSHConnectionData{}
+	}
+	if configData.GNMI == nil {
+		configData.GNMI = &network.GNMIConnectionData{}
+	}
+	if configData.NETCONF == nil {
+		configData.NETCONF = &network.NETCONFConnectionData{}
+	}
 
 	db, err := database.GetDB(ctx)
 	if err != nil {
@@ -88,6 +97,15 @@ func (r *BaseRequest) validate(ctx context.Context) error {
 	if cacheData.HTTP == nil {
 		cacheData.HTTP = &network.HTTPConnectionData{}
 	}
+	if cacheData.SSH == nil {
+		cacheData.SSH = &network.SSHConnectionData{}
+	}
+	if cacheData.GNMI == nil {
+		cacheData.GNMI = &network.GNMIConnectionData{}
+	}
+	if cacheData.NETCONF == nil {
+		cacheData.NETCONF = &network.NETCONFConnectionData{}
+	}
 
 	mergedData := network.ConnectionData{
 		SNMP: &network.SNMPConnectionData{
@@ -97,6 +115,7 @@ func (r *BaseRequest) validate(ctx context.Context) error {
 			DiscoverParallelRequests: configData.SNMP.DiscoverParallelRequests,
 			DiscoverTimeout:          configData.SNMP.DiscoverTimeout,
 			DiscoverRetries:          configData.SNMP.DiscoverRetries,
+			AdaptiveMaxRepetitions:   cacheData.SNMP.AdaptiveMaxRepetitions,
 			V3Data: network.SNMPv3ConnectionData{
 				Level:        utility.IfThenElse(cacheData.SNMP.V3Data.Level != nil, cacheData.SNMP.V3Data.Level, configData.SNMP.V3Data.Level).(*string),
 				ContextName:  utility.IfThenElse(cacheData.SNMP.V3Data.ContextName != nil, cacheData.SNMP.V3Data.ContextName, configData.SNMP.V3Data.ContextName).(*string),
@@ -113,6 +132,24 @@ func (r *BaseRequest) validate(ctx context.Context) error {
 			AuthUsername: utility.IfThenElse(cacheData.HTTP.AuthUsername != nil, cacheData.HTTP.AuthUsername, configData.HTTP.AuthUsername).(*string),
 			AuthPassword: utility.IfThenElse(cacheData.HTTP.AuthPassword != nil, cacheData.HTTP.AuthPassword, configData.HTTP.AuthPassword).(*string),
 		},
+		SSH: &network.SSHConnectionData{
+			Ports:    utility.SliceUniqueInt(append(cacheData.SSH.Ports, configData.SSH.Ports...)),
+			Username: utility.IfThenElse(cacheData.SSH.Username != nil, cacheData.SSH.Username, configData.SSH.Username).(*string),
+			Password: utility.IfThenElse(cacheData.SSH.Password != nil, cacheData.SSH.Password, configData.SSH.Password).(*string),
+		},
+		GNMI: &network.GNMIConnectionData{
+			Ports:              utility.SliceUniqueInt(append(cacheData.GNMI.Ports, configData.GNMI.Ports...)),
+			Username:           utility.IfThenElse(cacheData.GNMI.Username != nil, cacheData.GNMI.Username, configData.GNMI.Username).(*string),
+			Password:           utility.IfThenElse(cacheData.GNMI.Password != nil, cacheData.GNMI.Password, configData.GNMI.Password).(*string),
+			Insecure:           utility.IfThenElse(cacheData.GNMI.Insecure != nil, cacheData.GNMI.Insecure, configData.GNMI.Insecure).(*bool),
+			InsecureSkipVerify: utility.IfThenElse(cacheData.GNMI.InsecureSkipVerify != nil, cacheData.GNMI.InsecureSkipVerify, configData.GNMI.InsecureSkipVerify).(*bool),
+			CAFile:             utility.IfThenElse(cacheData.GNMI.CAFile != nil, cacheData.GNMI.CAFile, configData.GNMI.CAFile).(*string),
+		},
+		NETCONF: &network.NETCONFConnectionData{
+			Ports:    utility.SliceUniqueInt(append(cacheData.NETCONF.Ports, configData.NETCONF.Ports...)),
+			Username: utility.IfThenElse(cacheData.NETCONF.Username != nil, cacheData.NETCONF.Username, configData.NETCONF.Username).(*string),
+			Password: utility.IfThenElse(cacheData.NETCONF.Password != nil, cacheData.NETCONF.Password, configData.NETCONF.Password).(*string),
+		},
 	}
 
 	if r.DeviceData.ConnectionData.SNMP == nil {
@@ -153,6 +190,10 @@ func (r *BaseRequest) validate(ctx context.Context) error {
 		r.DeviceData.ConnectionData.SNMP.DiscoverRetries = mergedData.SNMP.DiscoverRetries
 	}
 
+	if r.DeviceData.ConnectionData.SNMP.AdaptiveMaxRepetitions == nil {
+		r.DeviceData.ConnectionData.SNMP.AdaptiveMaxRepetitions = mergedData.SNMP.AdaptiveMaxRepetitions
+	}
+
 	if (r.DeviceData.ConnectionData.SNMP.DiscoverParallelRequests != nil && *r.DeviceData.ConnectionData.SNMP.DiscoverParallelRequests <= 0) ||
 		(r.DeviceData.ConnectionData.SNMP.DiscoverTimeout != nil && *r.DeviceData.ConnectionData.SNMP.DiscoverTimeout <= 0) {
 		return errors.New("invalid snmp connection discover preferences")
@@ -251,6 +292,81 @@ func (r *BaseRequest) validate(ctx context.Context) error {
 		r.DeviceData.ConnectionData.HTTP.AuthPassword = mergedData.HTTP.AuthPassword
 	}
 
+	if r.DeviceData.ConnectionData.SSH == nil {
+		r.DeviceData.ConnectionData.SSH = mergedData.SSH
+	}
+
+	if len(r.DeviceData.ConnectionData.SSH.Ports) == 0 {
+		r.DeviceData.ConnectionData.SSH.Ports = mergedData.SSH.Ports
+	}
+	for _, port := range r.DeviceData.ConnectionData.SSH.Ports {
+		if port <= 0 {
+			return errors.New("invalid SSH port")
+		}
+	}
+
+	if r.DeviceData.ConnectionData.SSH.Username == nil {
+		r.DeviceData.ConnectionData.SSH.Username = mergedData.SSH.Username
+	}
+
+	if r.DeviceData.ConnectionData.SSH.Password == nil {
+		r.DeviceData.ConnectionData.SSH.Password = mergedData.SSH.Password
+	}
+
+	if r.DeviceData.ConnectionData.GNMI == nil {
+		r.DeviceData.ConnectionData.GNMI = mergedData.GNMI
+	}
+
+	if len(r.DeviceData.ConnectionData.GNMI.Ports) == 0 {
+		r.DeviceData.ConnectionData.GNMI.Ports = mergedData.GNMI.Ports
+	}
+	for _, port := range r.DeviceData.ConnectionData.GNMI.Ports {
+		if port <= 0 {
+			return errors.New("invalid gNMI port")
+		}
+	}
+
+	if r.DeviceData.ConnectionData.GNMI.Username == nil {
+		r.DeviceData.ConnectionData.GNMI.Username = mergedData.GNMI.Username
+	}
+
+	if r.DeviceData.ConnectionData.GNMI.Password == nil {
+		r.DeviceData.ConnectionData.GNMI.Password = mergedData.GNMI.Password
+	}
+
+	if r.DeviceData.ConnectionData.GNMI.Insecure == nil {
+		r.DeviceData.ConnectionData.GNMI.Insecure = mergedData.GNMI.Insecure
+	}
+
+	if r.DeviceData.ConnectionData.GNMI.InsecureSkipVerify == nil {
+		r.DeviceData.ConnectionData.GNMI.InsecureSkipVerify = mergedData.GNMI.InsecureSkipVerify
+	}
+
+	if r.DeviceData.ConnectionData.GNMI.CAFile == nil {
+		r.DeviceData.ConnectionData.GNMI.CAFile = mergedData.GNMI.CAFile
+	}
+
+	if r.DeviceData.ConnectionData.NETCONF == nil {
+		r.DeviceData.ConnectionData.NETCONF = mergedData.NETCONF
+	}
+
+	if len(r.DeviceData.ConnectionData.NETCONF.Ports) == 0 {
+		r.DeviceData.ConnectionData.NETCONF.Ports = mergedData.NETCONF.Ports
+	}
+	for _, port := range r.DeviceData.ConnectionData.NETCONF.Ports {
+		if port <= 0 {
+			return errors.New("invalid NETCONF port")
+		}
+	}
+
+	if r.DeviceData.ConnectionData.NETCONF.Username == nil {
+		r.DeviceData.ConnectionData.NETCONF.Username = mergedData.NETCONF.Username
+	}
+
+	if r.DeviceData.ConnectionData.NETCONF.Password == nil {
+		r.DeviceData.ConnectionData.NETCONF.Password = mergedData.NETCONF.Password
+	}
+
 	if r.Timeout == nil {
 		timeout := viper.GetInt("request.timeout")
 		r.Timeout = &timeout
@@ -280,6 +396,15 @@ func getConfigConnectionData() network.ConnectionData {
 	v3PrivProto := viper.GetString("device.snmp-v3-priv-proto")
 	authUsername := viper.GetString("device.http-username")
 	authPassword := viper.GetString("device.http-password")
+	sshUsername := viper.GetString("device.ssh-username")
+	sshPassword := viper.GetString("device.ssh-password")
+	gnmiUsername := viper.GetString("device.gnmi-username")
+	gnmiPassword := viper.GetString("device.gnmi-password")
+	gnmiInsecure := viper.GetBool("device.gnmi-insecure")
+	gnmiInsecureSkipVerify := viper.GetBool("device.gnmi-insecure-skip-verify")
+	gnmiCAFile := viper.GetString("device.gnmi-ca-file")
+	netconfUsername := viper.GetString("device.netconf-username")
+	netconfPassword := viper.GetString("device.netconf-password")
 	return network.ConnectionData{
 		SNMP: &network.SNMPConnectionData{
 			Communities:              viper.GetStringSlice("device.snmp-communities"),
@@ -304,6 +429,24 @@ func getConfigConnectionData() network.ConnectionData {
 			AuthUsername: &authUsername,
 			AuthPassword: &authPassword,
 		},
+		SSH: &network.SSHConnectionData{
+			Ports:    viper.GetIntSlice("device.ssh-ports"),
+			Username: &sshUsername,
+			Password: &sshPassword,
+		},
+		GNMI: &network.GNMIConnectionData{
+			Ports:              viper.GetIntSlice("device.gnmi-ports"),
+			Username:           &gnmiUsername,
+			Password:           &gnmiPassword,
+			Insecure:           &gnmiInsecure,
+			InsecureSkipVerify: &gnmiInsecureSkipVerify,
+			CAFile:             &gnmiCAFile,
+		},
+		NETCONF: &network.NETCONFConnectionData{
+			Ports:    viper.GetIntSlice("device.netconf-ports"),
+			Username: &netconfUsername,
+			Password: &netconfPassword,
+		},
 	}
 }
 
@@ -332,6 +475,37 @@ func (r *BaseRequest) setupConnection(ctx context.Context) (*network.RequestDevi
 			createdData = true
 		}
 	}
+	if r.DeviceData.ConnectionData.SSH != nil && r.DeviceData.ConnectionData.SSH.Username != nil && *r.DeviceData.ConnectionData.SSH.Username != "" {
+		sshCon, err := r.setupSSHConnection()
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Msg("failed to setup ssh connection data")
+		} else {
+			log.Ctx(ctx).Debug().Err(err).Msg("successfully setup ssh connection data")
+			con.SSH = sshCon
+			createdData = true
+		}
+	}
+	if r.DeviceData.ConnectionData.GNMI != nil && r.DeviceData.ConnectionData.GNMI.Username != nil && *r.DeviceData.ConnectionData.GNMI.Username != "" {
+		gnmiCon, err := r.setupGNMIConnection()
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Msg("failed to setup gnmi connection data")
+		} else {
+			log.Ctx(ctx).Debug().Err(err).Msg("successfully setup gnmi connection data")
+			con.GNMI = gnmiCon
+			createdData = true
+		}
+	}
+
+	if r.DeviceData.ConnectionData.NETCONF != nil && r.DeviceData.ConnectionData.NETCONF.Username != nil && *r.DeviceData.ConnectionData.NETCONF.Username != "" {
+		netconfCon, err := r.setupNETCONFConnection()
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Msg("failed to setup netconf connection data")
+		} else {
+			log.Ctx(ctx).Debug().Err(err).Msg("successfully setup netconf connection data")
+			con.NETCONF = netconfCon
+			createdData = true
+		}
+	}
 	if !createdData {
 		return nil, errors.New("cannot create any connection to the device")
 	}
@@ -343,11 +517,31 @@ func (r *BaseRequest) setupSNMPConnection(ctx context.Context) (*network.Request
 		return nil, errors.New("no SNMP connection data available")
 	}
 
-	snmpClient, err := network.NewSNMPClientByConnectionData(ctx, r.DeviceData.IPAddress, r.DeviceData.ConnectionData.SNMP)
+	db, err := database.GetDB(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get DB")
+	}
+
+	var v3Engine *network.SNMPv3EngineData
+	cachedEngine, err := db.GetSNMPv3EngineData(ctx, r.DeviceData.IPAddress)
+	if err == nil {
+		v3Engine = &cachedEngine
+	} else if !tholaerr.IsNotFoundError(err) {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get cached snmp v3 engine data")
+	}
+
+	snmpClient, err := network.NewSNMPClientByConnectionData(ctx, r.DeviceData.IPAddress, r.DeviceData.ConnectionData.SNMP, v3Engine)
 	if err != nil {
 		return nil, errors.Wrap(err, "error during NewSNMPClientByConnectionData")
 	}
 
+	if engine := snmpClient.GetV3EngineData(); engine != nil && (v3Engine == nil || engine.EngineID != v3Engine.EngineID || engine.EngineBoots != v3Engine.EngineBoots) {
+		err = db.SetSNMPv3EngineData(ctx, r.DeviceData.IPAddress, *engine)
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Msg("failed to cache snmp v3 engine data")
+		}
+	}
+
 	var con network.RequestDeviceConnectionSNMP
 	con.SnmpClient = snmpClient
 
@@ -392,6 +586,88 @@ func (r *BaseRequest) setupHTTPConnection() (*network.RequestDeviceConnectionHTT
 	return con, nil
 }
 
+func (r *BaseRequest) setupSSHConnection() (*network.RequestDeviceConnectionSSH, error) {
+	if r.DeviceData.ConnectionData.SSH == nil || r.DeviceData.ConnectionData.SSH.Username == nil {
+		return nil, errors.New("no SSH connection data available")
+	}
+
+	port := 22
+	if len(r.DeviceData.ConnectionData.SSH.Ports) != 0 {
+		port = r.DeviceData.ConnectionData.SSH.Ports[0]
+	}
+	var password string
+	if r.DeviceData.ConnectionData.SSH.Password != nil {
+		password = *r.DeviceData.ConnectionData.SSH.Password
+	}
+
+	sshClient, err := network.NewSSHClient(r.DeviceData.IPAddress, port, *r.DeviceData.ConnectionData.SSH.Username, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create ssh client")
+	}
+	sshClient.SetTimeout(15 * time.Second)
+	con := &network.RequestDeviceConnectionSSH{}
+	con.SSHClient = sshClient
+	con.ConnectionData = r.DeviceData.ConnectionData.SSH
+	return con, nil
+}
+
+func (r *BaseRequest) setupGNMIConnection() (*network.RequestDeviceConnectionGNMI, error) {
+	if r.DeviceData.ConnectionData.GNMI == nil || r.DeviceData.ConnectionData.GNMI.Username == nil {
+		return nil, errors.New("no gNMI connection data available")
+	}
+
+	port := 57400
+	if len(r.DeviceData.ConnectionData.GNMI.Ports) != 0 {
+		port = r.DeviceData.ConnectionData.GNMI.Ports[0]
+	}
+	var password string
+	if r.DeviceData.ConnectionData.GNMI.Password != nil {
+		password = *r.DeviceData.ConnectionData.GNMI.Password
+	}
+	insecure := r.DeviceData.ConnectionData.GNMI.Insecure != nil && *r.DeviceData.ConnectionData.GNMI.Insecure
+
+	gnmiClient, err := network.NewGNMIClient(r.DeviceData.IPAddress, port, *r.DeviceData.ConnectionData.GNMI.Username, password, insecure)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create gnmi client")
+	}
+	gnmiClient.SetTimeout(15 * time.Second)
+	gnmiClient.InsecureSkipVerify(r.DeviceData.ConnectionData.GNMI.InsecureSkipVerify != nil && *r.DeviceData.ConnectionData.GNMI.InsecureSkipVerify)
+	if r.DeviceData.ConnectionData.GNMI.CAFile != nil && *r.DeviceData.ConnectionData.GNMI.CAFile != "" {
+		if err := gnmiClient.SetRootCAs(*r.DeviceData.ConnectionData.GNMI.CAFile); err != nil {
+			return nil, errors.Wrap(err, "failed to set gnmi CA file")
+		}
+	}
+	con := &network.RequestDeviceConnectionGNMI{}
+	con.GNMIClient = gnmiClient
+	con.ConnectionData = r.DeviceData.ConnectionData.GNMI
+	return con, nil
+}
+
+func (r *BaseRequest) setupNETCONFConnection() (*network.RequestDeviceConnectionNETCONF, error) {
+	if r.DeviceData.ConnectionData.NETCONF == nil || r.DeviceData.ConnectionData.NETCONF.Username == nil {
+		return nil, errors.New("no NETCONF connection data available")
+	}
+
+	port := 830
+	if len(r.DeviceData.ConnectionData.NETCONF.Ports) != 0 {
+		port = r.DeviceData.ConnectionData.NETCONF.Ports[0]
+	}
+	var password string
+	if r.DeviceData.ConnectionData.NETCONF.Password != nil {
+		password = *r.DeviceData.ConnectionData.NETCONF.Password
+	}
+
+	netconfClient, err := network.NewNETCONFClient(r.DeviceData.IPAddress, port, *r.DeviceData.ConnectionData.NETCONF.Username, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create netconf client")
+	}
+	netconfClient.SetTimeout(15 * time.Second)
+	con := &network.RequestDeviceConnectionNETCONF{}
+	con.NETCONFClient = netconfClient
+	con.ConnectionData = r.DeviceData.ConnectionData.NETCONF
+	return con, nil
+}
+
 // BaseResponse
 //
 // BaseResponse defines attributes every response has.
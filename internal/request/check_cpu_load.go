@@ -3,6 +3,8 @@ package request
 import (
 	"context"
 	"github.com/inexio/go-monitoringplugin"
+	"github.com/pkg/errors"
+	"regexp"
 )
 
 // CheckCPULoadRequest
@@ -12,12 +14,62 @@ import (
 // swagger:model
 type CheckCPULoadRequest struct {
 	CheckDeviceRequest
+	// CPULoadThresholds are applied to the single CPU or, if there are multiple CPUs, to the aggregated load.
 	CPULoadThresholds monitoringplugin.Thresholds `json:"cpuLoadThresholds" xml:"cpuLoadThresholds"`
+	// CPUThresholds are applied to every CPU with a matching label.
+	// If multiple entries match a CPU, the first one is used.
+	CPUThresholds []CheckCPUThresholds `json:"cpuThresholds" xml:"cpuThresholds"`
+	// Mode defines how the load of multiple CPUs is aggregated, either "average" (default) or "max".
+	Mode string `json:"mode" xml:"mode"`
+	// Window selects the checked load, either the current load (default) or the "1", "5" or "15" minute average.
+	Window string `json:"window" xml:"window"`
+}
+
+// CheckCPUThresholds
+//
+// CheckCPUThresholds contains the thresholds for all CPUs matching the label regex.
+//
+// swagger:model
+type CheckCPUThresholds struct {
+	LabelRegex        string                      `json:"labelRegex" xml:"labelRegex"`
+	CPULoadThresholds monitoringplugin.Thresholds `json:"cpuLoadThresholds" xml:"cpuLoadThresholds"`
+
+	labelRegex *regexp.Regexp
 }
 
 func (r *CheckCPULoadRequest) validate(ctx context.Context) error {
 	if err := r.CPULoadThresholds.Validate(); err != nil {
 		return err
 	}
+	for i := range r.CPUThresholds {
+		if err := r.CPUThresholds[i].CPULoadThresholds.Validate(); err != nil {
+			return errors.Wrapf(err, "cpu thresholds %d are invalid", i)
+		}
+		regex, err := regexp.Compile(r.CPUThresholds[i].LabelRegex)
+		if err != nil {
+			return errors.Wrapf(err, "failed to compile label regex of cpu thresholds %d", i)
+		}
+		r.CPUThresholds[i].labelRegex = regex
+	}
+	switch r.Mode {
+	case "", "average", "max":
+	default:
+		return errors.New("invalid mode, only 'average' and 'max' are supported")
+	}
+	switch r.Window {
+	case "", "1", "5", "15":
+	default:
+		return errors.New("invalid window, only '1', '5' and '15' are supported")
+	}
 	return r.CheckDeviceRequest.validate(ctx)
 }
+
+// getCPUThresholds returns the thresholds for the CPU with the given label if there are any.
+func (r *CheckCPULoadRequest) getCPUThresholds(label string) (monitoringplugin.Thresholds, bool) {
+	for _, t := range r.CPUThresholds {
+		if t.labelRegex != nil && t.labelRegex.MatchString(label) {
+			return t.CPULoadThresholds, true
+		}
+	}
+	return monitoringplugin.Thresholds{}, false
+}
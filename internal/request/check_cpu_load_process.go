@@ -6,6 +6,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/device"
 	"strconv"
 )
 
@@ -21,44 +22,110 @@ func (r *CheckCPULoadRequest) process(ctx context.Context) (Response, error) {
 	if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while reading cpu load", true) {
 		return &CheckResponse{r.mon.GetInfo()}, nil
 	}
+	r.checkCPULoad(result)
+	return &CheckResponse{r.mon.GetInfo()}, nil
+}
+
+// checkCPULoad adds the loads of the cpus and checks them for the requested window.
+// It returns true if an error occurred.
+func (r *CheckCPULoadRequest) checkCPULoad(cpus []device.CPU) bool {
 	cpuSum := 0.0
-	cpuAmount := len(result)
+	cpuMax := 0.0
+	cpuAmount := 0
+	for _, cpu := range cpus {
+		if r.getWindowLoad(cpu) != nil {
+			cpuAmount++
+		}
+	}
 
-	for k, cpu := range result {
-		if cpu.Load == nil {
-			cpuAmount -= 1
+	for k, cpu := range cpus {
+		load := r.getWindowLoad(cpu)
+		if load == nil {
 			continue
 		}
-		cpuSum += *cpu.Load
-
-		point := monitoringplugin.NewPerformanceDataPoint("cpu_load", *cpu.Load).SetUnit("%")
-		if cpuAmount == 1 {
-			point.SetThresholds(r.CPULoadThresholds)
+		cpuSum += *load
+		if *load > cpuMax {
+			cpuMax = *load
 		}
+
+		var label string
 		if cpu.Label != nil {
-			point.SetLabel(*cpu.Label)
+			label = *cpu.Label
 		} else if cpuAmount > 1 {
-			point.SetLabel(strconv.Itoa(k))
+			label = strconv.Itoa(k)
 		}
-		err = r.mon.AddPerformanceDataPoint(point)
-		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
-			return &CheckResponse{r.mon.GetInfo()}, nil
+
+		thresholds, ok := r.getCPUThresholds(label)
+		if !ok && cpuAmount == 1 {
+			thresholds, ok = r.CPULoadThresholds, true
+		}
+
+		windows := []struct {
+			name string
+			load *float64
+		}{
+			{"cpu_load", cpu.Load},
+			{"cpu_load_1", cpu.Load1},
+			{"cpu_load_5", cpu.Load5},
+			{"cpu_load_15", cpu.Load15},
+		}
+		for _, window := range windows {
+			if window.load == nil {
+				continue
+			}
+			point := monitoringplugin.NewPerformanceDataPoint(window.name, *window.load).SetUnit("%")
+			if ok && window.name == r.getWindowMetricName() {
+				point.SetThresholds(thresholds)
+			}
+			if label != "" {
+				point.SetLabel(label)
+			}
+			err := r.mon.AddPerformanceDataPoint(point)
+			if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+				return true
+			}
 		}
 	}
 
 	if cpuAmount > 1 {
-		val := cpuSum / float64(cpuAmount)
-		err = r.mon.AddPerformanceDataPoint(
-			monitoringplugin.NewPerformanceDataPoint("cpu_load", fmt.Sprintf("%.3f", val)).
+		label, val := "average", cpuSum/float64(cpuAmount)
+		if r.Mode == "max" {
+			label, val = "max", cpuMax
+		}
+		err := r.mon.AddPerformanceDataPoint(
+			monitoringplugin.NewPerformanceDataPoint(r.getWindowMetricName(), fmt.Sprintf("%.3f", val)).
 				SetUnit("%").
-				SetLabel("average").
+				SetLabel(label).
 				SetThresholds(r.CPULoadThresholds))
 		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
-			return &CheckResponse{r.mon.GetInfo()}, nil
+			return true
 		}
+	} else if cpuAmount == 0 && r.Window != "" && len(cpus) > 0 {
+		r.mon.UpdateStatus(monitoringplugin.UNKNOWN, fmt.Sprintf("the %s minute cpu load is not supported by the device", r.Window))
 	} else if cpuAmount == 0 {
 		r.mon.UpdateStatus(monitoringplugin.UNKNOWN, "no CPUs found")
 	}
+	return false
+}
 
-	return &CheckResponse{r.mon.GetInfo()}, nil
+// getWindowLoad returns the load of the cpu for the requested window.
+func (r *CheckCPULoadRequest) getWindowLoad(cpu device.CPU) *float64 {
+	switch r.Window {
+	case "1":
+		return cpu.Load1
+	case "5":
+		return cpu.Load5
+	case "15":
+		return cpu.Load15
+	default:
+		return cpu.Load
+	}
+}
+
+// getWindowMetricName returns the name of the performance data point for the requested window.
+func (r *CheckCPULoadRequest) getWindowMetricName() string {
+	if r.Window == "" {
+		return "cpu_load"
+	}
+	return "cpu_load_" + r.Window
 }
@@ -0,0 +1,112 @@
+// +build !client
+
+package request
+
+import (
+	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/device"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestCheckCPULoadRequest_checkCPULoad(t *testing.T) {
+	number := func(f float64) *float64 { return &f }
+	text := func(s string) *string { return &s }
+
+	cpus := []device.CPU{
+		{Label: text("cpu0"), Load: number(20), Load1: number(30), Load5: number(40)},
+		{Label: text("cpu1"), Load: number(90), Load1: number(50), Load5: number(60)},
+	}
+	critical := monitoringplugin.Thresholds{CriticalMin: 0, CriticalMax: 80}
+
+	tests := []struct {
+		name          string
+		cpus          []device.CPU
+		request       CheckCPULoadRequest
+		status        int
+		aggregated    string
+		aggregatedVal interface{}
+		message       string
+	}{
+		{
+			name:          "average",
+			cpus:          cpus,
+			request:       CheckCPULoadRequest{CPULoadThresholds: critical},
+			status:        monitoringplugin.OK,
+			aggregated:    "average",
+			aggregatedVal: "55.000",
+		},
+		{
+			name:          "max",
+			cpus:          cpus,
+			request:       CheckCPULoadRequest{CPULoadThresholds: critical, Mode: "max"},
+			status:        monitoringplugin.CRITICAL,
+			aggregated:    "max",
+			aggregatedVal: "90.000",
+		},
+		{
+			name: "per cpu thresholds",
+			cpus: cpus,
+			request: CheckCPULoadRequest{CPUThresholds: []CheckCPUThresholds{
+				{LabelRegex: "^cpu1$", CPULoadThresholds: critical, labelRegex: regexp.MustCompile("^cpu1$")},
+			}},
+			status:        monitoringplugin.CRITICAL,
+			aggregated:    "average",
+			aggregatedVal: "55.000",
+		},
+		{
+			name:          "window",
+			cpus:          cpus,
+			request:       CheckCPULoadRequest{CPULoadThresholds: critical, Mode: "max", Window: "5"},
+			status:        monitoringplugin.OK,
+			aggregated:    "max",
+			aggregatedVal: "60.000",
+		},
+		{
+			name:    "single cpu",
+			cpus:    []device.CPU{{Load: number(85)}},
+			request: CheckCPULoadRequest{CPULoadThresholds: critical},
+			status:  monitoringplugin.CRITICAL,
+		},
+		{
+			name:    "window not supported",
+			cpus:    []device.CPU{{Load: number(85)}},
+			request: CheckCPULoadRequest{Window: "15"},
+			status:  monitoringplugin.UNKNOWN,
+			message: "the 15 minute cpu load is not supported by the device",
+		},
+		{
+			name:    "no cpus",
+			request: CheckCPULoadRequest{Window: "15"},
+			status:  monitoringplugin.UNKNOWN,
+			message: "no CPUs found",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := test.request
+			r.init()
+			assert.False(t, r.checkCPULoad(test.cpus))
+			info := r.mon.GetInfo()
+			assert.Equal(t, test.status, info.StatusCode)
+			if test.aggregated != "" {
+				assert.Equal(t, test.aggregatedVal, getPerformanceDataPoint(t, info, r.getWindowMetricName(), test.aggregated).Value)
+			}
+			if test.message != "" {
+				assert.Len(t, info.Messages, 1)
+				assert.Equal(t, test.message, info.Messages[0].Message)
+			}
+		})
+	}
+
+	// thresholds are only set on the point of the requested window
+	r := CheckCPULoadRequest{CPULoadThresholds: critical, Window: "1"}
+	r.init()
+	assert.False(t, r.checkCPULoad([]device.CPU{{Load: number(95), Load1: number(10)}}))
+	info := r.mon.GetInfo()
+	assert.Equal(t, monitoringplugin.OK, info.StatusCode)
+	thresholds := getPerformanceDataPoint(t, info, "cpu_load", "").Thresholds
+	assert.True(t, thresholds.IsEmpty())
+	assert.Equal(t, critical, getPerformanceDataPoint(t, info, "cpu_load_1", "").Thresholds)
+}
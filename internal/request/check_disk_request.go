@@ -3,6 +3,8 @@ package request
 import (
 	"context"
 	"github.com/inexio/go-monitoringplugin"
+	"github.com/pkg/errors"
+	"regexp"
 )
 
 // CheckDiskRequest
@@ -12,12 +14,108 @@ import (
 // swagger:model
 type CheckDiskRequest struct {
 	CheckDeviceRequest
+	// DiskThresholds are applied to the used space of every storage in percent.
 	DiskThresholds monitoringplugin.Thresholds `json:"diskThresholds" xml:"diskThresholds"`
+	// StorageThresholds overwrite the DiskThresholds for all matching storages.
+	// If multiple entries match a storage, the first one is used.
+	StorageThresholds []CheckDiskStorageThresholds `json:"storageThresholds" xml:"storageThresholds"`
+	// InodeThresholds are applied to the inode usage of every file system in percent.
+	InodeThresholds monitoringplugin.Thresholds `json:"inodeThresholds" xml:"inodeThresholds"`
+	// ExcludeStorages are regular expressions matched against the storage descriptions and file system paths.
+	// Matching storages and file systems are not checked.
+	ExcludeStorages []string `json:"excludeStorages" xml:"excludeStorages"`
+	// DaysUntilFull enables the estimation of the days until a storage is full,
+	// based on the samples of previous checks which are stored in the cache.
+	DaysUntilFull           bool                        `json:"daysUntilFull" xml:"daysUntilFull"`
+	DaysUntilFullThresholds monitoringplugin.Thresholds `json:"daysUntilFullThresholds" xml:"daysUntilFullThresholds"`
+
+	excludeRegexes []*regexp.Regexp
+}
+
+// CheckDiskStorageThresholds
+//
+// CheckDiskStorageThresholds contains the thresholds for all storages matching the description regex and type.
+// Empty selectors match every storage.
+//
+// swagger:model
+type CheckDiskStorageThresholds struct {
+	// DescriptionRegex is matched against the storage description, e.g. the mount point.
+	DescriptionRegex *string `json:"descriptionRegex" xml:"descriptionRegex"`
+	// Type is compared to the storage type as mapped by hrStorageType.yaml, e.g. "Fixed Disk".
+	Type *string `json:"type" xml:"type"`
+	// Thresholds are applied to the used space in percent.
+	Thresholds monitoringplugin.Thresholds `json:"thresholds" xml:"thresholds"`
+	// AbsoluteThresholds are applied to the used space in bytes, which is reported as disk_used_bytes.
+	// They take precedence over Thresholds if the allocation units of the storage are known.
+	// Otherwise Thresholds are used, or the DiskThresholds if there are none.
+	AbsoluteThresholds monitoringplugin.Thresholds `json:"absoluteThresholds" xml:"absoluteThresholds"`
+
+	descriptionRegex *regexp.Regexp
 }
 
 func (r *CheckDiskRequest) validate(ctx context.Context) error {
 	if err := r.DiskThresholds.Validate(); err != nil {
 		return err
 	}
+
+	if err := r.InodeThresholds.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.DaysUntilFullThresholds.Validate(); err != nil {
+		return err
+	}
+
+	for i := range r.StorageThresholds {
+		if err := r.StorageThresholds[i].validate(); err != nil {
+			return errors.Wrapf(err, "storage thresholds %d are invalid", i)
+		}
+	}
+
+	r.excludeRegexes = nil
+	for _, exclude := range r.ExcludeStorages {
+		regex, err := regexp.Compile(exclude)
+		if err != nil {
+			return errors.Wrapf(err, "failed to compile exclude regex '%s'", exclude)
+		}
+		r.excludeRegexes = append(r.excludeRegexes, regex)
+	}
+
 	return r.CheckDeviceRequest.validate(ctx)
 }
+
+func (s *CheckDiskStorageThresholds) validate() error {
+	if err := s.Thresholds.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.AbsoluteThresholds.Validate(); err != nil {
+		return err
+	}
+
+	s.descriptionRegex = nil
+	if s.DescriptionRegex != nil {
+		regex, err := regexp.Compile(*s.DescriptionRegex)
+		if err != nil {
+			return errors.Wrap(err, "failed to compile description regex")
+		}
+		s.descriptionRegex = regex
+	}
+	return nil
+}
+
+func (s *CheckDiskStorageThresholds) matches(description, storageType string) bool {
+	if s.descriptionRegex != nil && !s.descriptionRegex.MatchString(description) {
+		return false
+	}
+	return s.Type == nil || *s.Type == storageType
+}
+
+func (r *CheckDiskRequest) isExcluded(description string) bool {
+	for _, regex := range r.excludeRegexes {
+		if regex.MatchString(description) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,8 +5,22 @@ package request
 import (
 	"context"
 	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/database"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/inexio/thola/internal/value"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"sort"
+	"time"
 )
 
+// daysUntilFullSampleWindow is the period of samples which are used for the days until full estimation.
+const daysUntilFullSampleWindow = 7 * 24 * time.Hour
+
+// daysUntilFullSampleInterval is the minimum interval between stored samples. Samples of more frequent checks
+// replace each other, which limits the history of a storage to 2 * daysUntilFullSampleWindow / daysUntilFullSampleInterval samples.
+const daysUntilFullSampleInterval = 30 * time.Minute
+
 func (r *CheckDiskRequest) process(ctx context.Context) (Response, error) {
 	r.init()
 
@@ -17,26 +31,228 @@ func (r *CheckDiskRequest) process(ctx context.Context) (Response, error) {
 	}
 	disk := response.(*ReadDiskResponse).Disk
 
-	for _, storage := range disk.Storages {
-		if storage.Type != nil && storage.Description != nil && storage.Available != nil && storage.Used != nil {
-			var p *monitoringplugin.PerformanceDataPoint
-
-			if r.DiskThresholds.HasWarning() && r.DiskThresholds.HasCritical() { // check if max thresholds exist
-				warningMax := float64(*storage.Available) * r.DiskThresholds.WarningMax.(float64) / 100
-				criticalMax := float64(*storage.Available) * r.DiskThresholds.CriticalMax.(float64) / 100
-				thresholds := monitoringplugin.Thresholds{WarningMin: 0, WarningMax: warningMax, CriticalMin: 0, CriticalMax: criticalMax}
-				p = monitoringplugin.NewPerformanceDataPoint("disk_used", *storage.Used).SetUnit("KB").SetLabel(*storage.Description).SetThresholds(thresholds).SetMax(float64(*storage.Available))
-			} else {
-				p = monitoringplugin.NewPerformanceDataPoint("disk_used", *storage.Used).SetUnit("KB").SetLabel(*storage.Description).SetMax(float64(*storage.Available))
+	var history database.DiskUsageHistory
+	var db database.Database
+	if r.DaysUntilFull {
+		db, err = database.GetDB(ctx)
+		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while getting database", true) {
+			return &CheckResponse{r.mon.GetInfo()}, nil
+		}
+		history, err = db.GetDiskUsageHistory(ctx, r.DeviceData.IPAddress)
+		if err != nil {
+			if !tholaerr.IsNotFoundError(err) {
+				r.mon.UpdateStatus(monitoringplugin.UNKNOWN, "error while reading disk usage history")
+				return &CheckResponse{r.mon.GetInfo()}, nil
 			}
+			log.Ctx(ctx).Debug().Err(err).Msg("no disk usage history available")
+		}
+		if history == nil {
+			history = make(database.DiskUsageHistory)
+		}
+	}
+
+	now := time.Now()
+	newSamples := make(map[string]database.DiskUsageSample)
+	for _, storage := range disk.Storages {
+		if storage.Type == nil || storage.Description == nil || storage.Available == nil || storage.Used == nil || r.isExcluded(*storage.Description) {
+			continue
+		}
+
+		used, total := float64(*storage.Used), float64(*storage.Available)
+		bytesKnown := storage.AllocationUnits != nil && *storage.AllocationUnits > 0
+
+		thresholds, absolute, err := r.getStorageThresholds(ctx, *storage.Description, *storage.Type, total, bytesKnown)
+		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while computing thresholds", true) {
+			r.mon.PrintPerformanceData(false)
+			return &CheckResponse{r.mon.GetInfo()}, nil
+		}
+
+		// disk_used is in allocation units like before, the used space in bytes is an additional point
+		p := monitoringplugin.NewPerformanceDataPoint("disk_used", *storage.Used).SetUnit("KB").SetLabel(*storage.Description).SetMax(total)
+		if !thresholds.IsEmpty() && !absolute {
+			p.SetThresholds(thresholds)
+		}
+		err = r.mon.AddPerformanceDataPoint(p)
+		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+			r.mon.PrintPerformanceData(false)
+			return &CheckResponse{r.mon.GetInfo()}, nil
+		}
 
+		if bytesKnown {
+			used *= float64(*storage.AllocationUnits)
+			total *= float64(*storage.AllocationUnits)
+			p := monitoringplugin.NewPerformanceDataPoint("disk_used_bytes", used).SetUnit("B").SetLabel(*storage.Description).SetMax(total)
+			if !thresholds.IsEmpty() && absolute {
+				p.SetThresholds(thresholds)
+			}
 			err = r.mon.AddPerformanceDataPoint(p)
 			if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
 				r.mon.PrintPerformanceData(false)
 				return &CheckResponse{r.mon.GetInfo()}, nil
 			}
 		}
+
+		if r.DaysUntilFull {
+			sample := database.DiskUsageSample{Time: now, Used: used}
+			newSamples[*storage.Description] = sample
+			samples := compactDiskUsageSamples(append(append([]database.DiskUsageSample{}, history[*storage.Description]...), sample), now)
+
+			if days, ok := estimateDaysUntilFull(samples, total); ok {
+				p := monitoringplugin.NewPerformanceDataPoint("disk_days_until_full", days).SetUnit("d").SetLabel(*storage.Description)
+				if !r.DaysUntilFullThresholds.IsEmpty() {
+					p.SetThresholds(r.DaysUntilFullThresholds)
+				}
+				err = r.mon.AddPerformanceDataPoint(p)
+				if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+					r.mon.PrintPerformanceData(false)
+					return &CheckResponse{r.mon.GetInfo()}, nil
+				}
+			}
+		}
+	}
+
+	for _, inodes := range disk.Inodes {
+		if inodes.Path == nil || inodes.Usage == nil || r.isExcluded(*inodes.Path) {
+			continue
+		}
+
+		p := monitoringplugin.NewPerformanceDataPoint("inodes_usage", *inodes.Usage).SetUnit("%").SetLabel(*inodes.Path).SetMin(0).SetMax(100)
+		if !r.InodeThresholds.IsEmpty() {
+			p.SetThresholds(r.InodeThresholds)
+		}
+		err = r.mon.AddPerformanceDataPoint(p)
+		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+			r.mon.PrintPerformanceData(false)
+			return &CheckResponse{r.mon.GetInfo()}, nil
+		}
+	}
+
+	if r.DaysUntilFull {
+		err = storeDiskUsageSamples(ctx, db, r.DeviceData.IPAddress, newSamples, now)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to store disk usage history")
+		}
 	}
 
 	return &CheckResponse{r.mon.GetInfo()}, nil
 }
+
+// storeDiskUsageSamples adds the samples to the stored disk usage history of the device.
+// The history is read again right before it is written, so samples stored by concurrent checks of the same device
+// since the beginning of this check are kept.
+func storeDiskUsageSamples(ctx context.Context, db database.Database, ip string, samples map[string]database.DiskUsageSample, now time.Time) error {
+	history, err := db.GetDiskUsageHistory(ctx, ip)
+	if err != nil {
+		if !tholaerr.IsNotFoundError(err) {
+			return errors.Wrap(err, "failed to read disk usage history")
+		}
+		history = make(database.DiskUsageHistory)
+	}
+
+	for description, storageSamples := range history {
+		history[description] = compactDiskUsageSamples(storageSamples, now)
+		if len(history[description]) == 0 {
+			delete(history, description)
+		}
+	}
+	for description, sample := range samples {
+		history[description] = compactDiskUsageSamples(append(history[description], sample), now)
+	}
+	return db.SetDiskUsageHistory(ctx, ip, history)
+}
+
+// compactDiskUsageSamples sorts the samples, removes the ones outside of the sample window and
+// replaces samples that are closer than the sample interval to their predecessor.
+func compactDiskUsageSamples(samples []database.DiskUsageSample, now time.Time) []database.DiskUsageSample {
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Time.Before(samples[j].Time)
+	})
+
+	var res []database.DiskUsageSample
+	for _, sample := range samples {
+		if now.Sub(sample.Time) > daysUntilFullSampleWindow {
+			continue
+		}
+		// a sample replaces its predecessor if both are within one interval of the sample before them,
+		// so the newest sample is always kept
+		if n := len(res); n > 1 && sample.Time.Sub(res[n-2].Time) < daysUntilFullSampleInterval {
+			res[n-1] = sample
+			continue
+		}
+		res = append(res, sample)
+	}
+	return res
+}
+
+// getStorageThresholds returns the thresholds for the used space of a storage.
+// Percent thresholds are scaled to total, absolute thresholds are in bytes, which is reported by the returned bool.
+// If the matching entry has no usable thresholds, the DiskThresholds are used.
+func (r *CheckDiskRequest) getStorageThresholds(ctx context.Context, description, storageType string, total float64, bytesKnown bool) (monitoringplugin.Thresholds, bool, error) {
+	thresholds := r.DiskThresholds
+	for _, storageThresholds := range r.StorageThresholds {
+		if !storageThresholds.matches(description, storageType) {
+			continue
+		}
+		if !storageThresholds.AbsoluteThresholds.IsEmpty() {
+			if bytesKnown {
+				return storageThresholds.AbsoluteThresholds, true, nil
+			}
+			log.Ctx(ctx).Debug().Str("storage", description).Msg("allocation units of storage are unknown, ignoring absolute thresholds")
+		}
+		if !storageThresholds.Thresholds.IsEmpty() {
+			thresholds = storageThresholds.Thresholds
+		}
+		break
+	}
+	thresholds, err := scaleThresholds(thresholds, total/100)
+	return thresholds, false, err
+}
+
+// scaleThresholds multiplies all set bounds of the thresholds with the given factor.
+func scaleThresholds(thresholds monitoringplugin.Thresholds, factor float64) (monitoringplugin.Thresholds, error) {
+	bounds := []*interface{}{&thresholds.WarningMin, &thresholds.WarningMax, &thresholds.CriticalMin, &thresholds.CriticalMax}
+	for _, bound := range bounds {
+		if *bound == nil {
+			continue
+		}
+		f, err := value.New(*bound).Float64()
+		if err != nil {
+			return monitoringplugin.Thresholds{}, errors.Wrapf(err, "threshold '%v' is not a number", *bound)
+		}
+		*bound = f * factor
+	}
+	return thresholds, nil
+}
+
+// estimateDaysUntilFull estimates the days until the storage is full by a linear regression over the samples.
+// It returns false if there are not enough samples or the usage is not growing.
+func estimateDaysUntilFull(samples []database.DiskUsageSample, total float64) (float64, bool) {
+	if len(samples) < 2 || samples[len(samples)-1].Time.Sub(samples[0].Time) < time.Hour {
+		return 0, false
+	}
+
+	var sumX, sumY, sumXY, sumXX float64
+	n := float64(len(samples))
+	for _, sample := range samples {
+		x := sample.Time.Sub(samples[0].Time).Hours() / 24
+		sumX += x
+		sumY += sample.Used
+		sumXY += x * sample.Used
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, false
+	}
+	slope := (n*sumXY - sumX*sumY) / denominator
+	if slope <= 0 {
+		return 0, false
+	}
+
+	days := (total - samples[len(samples)-1].Used) / slope
+	if days < 0 {
+		days = 0
+	}
+	return days, true
+}
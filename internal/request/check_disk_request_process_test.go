@@ -0,0 +1,234 @@
+// +build !client
+
+package request
+
+import (
+	"context"
+	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/database"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestEstimateDaysUntilFull(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	day := 24 * time.Hour
+	tests := []struct {
+		name    string
+		samples []database.DiskUsageSample
+		total   float64
+		days    float64
+		ok      bool
+	}{
+		{
+			name:    "single sample",
+			samples: []database.DiskUsageSample{{Time: start, Used: 10}},
+			total:   100,
+		},
+		{
+			name:    "samples within an hour",
+			samples: []database.DiskUsageSample{{Time: start, Used: 10}, {Time: start.Add(time.Minute), Used: 20}},
+			total:   100,
+		},
+		{
+			name:    "linear growth",
+			samples: []database.DiskUsageSample{{Time: start, Used: 10}, {Time: start.Add(day), Used: 20}, {Time: start.Add(2 * day), Used: 30}},
+			total:   100,
+			days:    7,
+			ok:      true,
+		},
+		{
+			name:    "constant usage",
+			samples: []database.DiskUsageSample{{Time: start, Used: 10}, {Time: start.Add(day), Used: 10}},
+			total:   100,
+		},
+		{
+			name:    "shrinking usage",
+			samples: []database.DiskUsageSample{{Time: start, Used: 20}, {Time: start.Add(day), Used: 10}},
+			total:   100,
+		},
+		{
+			name:    "already full",
+			samples: []database.DiskUsageSample{{Time: start, Used: 90}, {Time: start.Add(day), Used: 110}},
+			total:   100,
+			days:    0,
+			ok:      true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			days, ok := estimateDaysUntilFull(test.samples, test.total)
+			assert.Equal(t, test.ok, ok)
+			assert.InDelta(t, test.days, days, 0.0001)
+		})
+	}
+}
+
+func TestScaleThresholds(t *testing.T) {
+	tests := []struct {
+		name       string
+		thresholds monitoringplugin.Thresholds
+		factor     float64
+		expected   monitoringplugin.Thresholds
+		err        bool
+	}{
+		{
+			name:       "empty",
+			thresholds: monitoringplugin.Thresholds{},
+			factor:     10,
+			expected:   monitoringplugin.Thresholds{},
+		},
+		{
+			name:       "max bounds",
+			thresholds: monitoringplugin.Thresholds{WarningMin: 0, WarningMax: 80, CriticalMin: 0, CriticalMax: 90},
+			factor:     10,
+			expected:   monitoringplugin.Thresholds{WarningMin: float64(0), WarningMax: float64(800), CriticalMin: float64(0), CriticalMax: float64(900)},
+		},
+		{
+			name:       "numeric strings",
+			thresholds: monitoringplugin.Thresholds{CriticalMax: "1.5"},
+			factor:     2,
+			expected:   monitoringplugin.Thresholds{CriticalMax: float64(3)},
+		},
+		{
+			name:       "invalid bound",
+			thresholds: monitoringplugin.Thresholds{WarningMax: "high"},
+			factor:     2,
+			err:        true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := scaleThresholds(test.thresholds, test.factor)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, res)
+		})
+	}
+}
+
+func TestCheckDiskRequest_getStorageThresholds(t *testing.T) {
+	percent := monitoringplugin.Thresholds{WarningMax: 80, CriticalMax: 90}
+	global := monitoringplugin.Thresholds{CriticalMax: 95}
+	absolute := monitoringplugin.Thresholds{CriticalMax: 1024}
+	fixedDisk := "Fixed Disk"
+
+	tests := []struct {
+		name       string
+		storage    []CheckDiskStorageThresholds
+		bytesKnown bool
+		expected   monitoringplugin.Thresholds
+		absolute   bool
+	}{
+		{
+			name:     "global thresholds",
+			expected: monitoringplugin.Thresholds{CriticalMax: float64(950)},
+		},
+		{
+			name:     "percent thresholds of storage",
+			storage:  []CheckDiskStorageThresholds{{Thresholds: percent}},
+			expected: monitoringplugin.Thresholds{WarningMax: float64(800), CriticalMax: float64(900)},
+		},
+		{
+			name:     "storage thresholds of other type",
+			storage:  []CheckDiskStorageThresholds{{Type: &fixedDisk, Thresholds: percent}},
+			expected: monitoringplugin.Thresholds{CriticalMax: float64(950)},
+		},
+		{
+			name:       "absolute thresholds",
+			storage:    []CheckDiskStorageThresholds{{Thresholds: percent, AbsoluteThresholds: absolute}},
+			bytesKnown: true,
+			expected:   absolute,
+			absolute:   true,
+		},
+		{
+			name:     "absolute thresholds with unknown allocation units",
+			storage:  []CheckDiskStorageThresholds{{Thresholds: percent, AbsoluteThresholds: absolute}},
+			expected: monitoringplugin.Thresholds{WarningMax: float64(800), CriticalMax: float64(900)},
+		},
+		{
+			name:     "only absolute thresholds with unknown allocation units",
+			storage:  []CheckDiskStorageThresholds{{AbsoluteThresholds: absolute}},
+			expected: monitoringplugin.Thresholds{CriticalMax: float64(950)},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := CheckDiskRequest{DiskThresholds: global, StorageThresholds: test.storage}
+			res, absolute, err := r.getStorageThresholds(context.Background(), "/", "Virtual Memory", 1000, test.bytesKnown)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, res)
+			assert.Equal(t, test.absolute, absolute)
+		})
+	}
+}
+
+func TestCompactDiskUsageSamples(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	sample := func(age time.Duration, used float64) database.DiskUsageSample {
+		return database.DiskUsageSample{Time: now.Add(-age), Used: used}
+	}
+
+	samples := []database.DiskUsageSample{
+		sample(0, 6),
+		sample(8*24*time.Hour, 1),
+		sample(120*time.Minute, 2),
+		sample(110*time.Minute, 3),
+		sample(100*time.Minute, 4),
+		sample(95*time.Minute, 5),
+	}
+	expected := []database.DiskUsageSample{sample(120*time.Minute, 2), sample(95*time.Minute, 5), sample(0, 6)}
+	assert.Equal(t, expected, compactDiskUsageSamples(samples, now))
+
+	// checks every minute for the whole window are bounded by the sample interval
+	samples = nil
+	for age := daysUntilFullSampleWindow; age >= 0; age -= time.Minute {
+		samples = compactDiskUsageSamples(append(samples, sample(age, 1)), now)
+	}
+	assert.LessOrEqual(t, len(samples), int(2*daysUntilFullSampleWindow/daysUntilFullSampleInterval)+1)
+	assert.Equal(t, now, samples[len(samples)-1].Time)
+}
+
+// diskUsageHistoryDB is a database which only stores the disk usage history of a single device.
+type diskUsageHistoryDB struct {
+	database.Database
+	history database.DiskUsageHistory
+}
+
+func (d *diskUsageHistoryDB) GetDiskUsageHistory(_ context.Context, _ string) (database.DiskUsageHistory, error) {
+	if d.history == nil {
+		return nil, tholaerr.NewNotFoundError("no history")
+	}
+	return d.history, nil
+}
+
+func (d *diskUsageHistoryDB) SetDiskUsageHistory(_ context.Context, _ string, history database.DiskUsageHistory) error {
+	d.history = history
+	return nil
+}
+
+func TestStoreDiskUsageSamples(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1600000000, 0)
+	db := &diskUsageHistoryDB{}
+
+	first := database.DiskUsageSample{Time: now.Add(-time.Hour), Used: 10}
+	require.NoError(t, storeDiskUsageSamples(ctx, db, "192.0.2.1", map[string]database.DiskUsageSample{"/": first}, now))
+
+	// a concurrent check stored a sample of another storage in the meantime
+	db.history["/var"] = []database.DiskUsageSample{{Time: now.Add(-time.Hour), Used: 5}}
+	db.history["/old"] = []database.DiskUsageSample{{Time: now.Add(-8 * 24 * time.Hour), Used: 5}}
+
+	second := database.DiskUsageSample{Time: now, Used: 20}
+	require.NoError(t, storeDiskUsageSamples(ctx, db, "192.0.2.1", map[string]database.DiskUsageSample{"/": second}, now))
+	assert.Equal(t, database.DiskUsageHistory{
+		"/":    {first, second},
+		"/var": {{Time: now.Add(-time.Hour), Used: 5}},
+	}, db.history)
+}
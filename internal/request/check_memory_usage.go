@@ -3,6 +3,8 @@ package request
 import (
 	"context"
 	"github.com/inexio/go-monitoringplugin"
+	"github.com/pkg/errors"
+	"regexp"
 )
 
 // CheckMemoryUsageRequest
@@ -13,11 +15,67 @@ import (
 type CheckMemoryUsageRequest struct {
 	CheckDeviceRequest
 	MemoryUsageThresholds monitoringplugin.Thresholds `json:"memoryUsageThresholds" xml:"memoryUsageThresholds"`
+	// MemoryFreeThresholds are applied to the free memory of every pool in bytes.
+	MemoryFreeThresholds monitoringplugin.Thresholds `json:"memoryFreeThresholds" xml:"memoryFreeThresholds"`
+	// PoolThresholds overwrite the thresholds above for all pools with a matching label.
+	// If multiple entries match a pool, the first one is used.
+	PoolThresholds []CheckMemoryPoolThresholds `json:"poolThresholds" xml:"poolThresholds"`
+}
+
+// CheckMemoryPoolThresholds
+//
+// CheckMemoryPoolThresholds contains the thresholds for all memory pools matching the label regex.
+//
+// swagger:model
+type CheckMemoryPoolThresholds struct {
+	LabelRegex            string                      `json:"labelRegex" xml:"labelRegex"`
+	MemoryUsageThresholds monitoringplugin.Thresholds `json:"memoryUsageThresholds" xml:"memoryUsageThresholds"`
+	MemoryFreeThresholds  monitoringplugin.Thresholds `json:"memoryFreeThresholds" xml:"memoryFreeThresholds"`
+
+	labelRegex *regexp.Regexp
 }
 
 func (r *CheckMemoryUsageRequest) validate(ctx context.Context) error {
 	if err := r.MemoryUsageThresholds.Validate(); err != nil {
 		return err
 	}
+	if err := r.MemoryFreeThresholds.Validate(); err != nil {
+		return err
+	}
+	for i := range r.PoolThresholds {
+		if err := r.PoolThresholds[i].validate(); err != nil {
+			return errors.Wrapf(err, "pool thresholds %d are invalid", i)
+		}
+	}
 	return r.CheckDeviceRequest.validate(ctx)
 }
+
+func (p *CheckMemoryPoolThresholds) validate() error {
+	if err := p.MemoryUsageThresholds.Validate(); err != nil {
+		return err
+	}
+	if err := p.MemoryFreeThresholds.Validate(); err != nil {
+		return err
+	}
+	regex, err := regexp.Compile(p.LabelRegex)
+	if err != nil {
+		return errors.Wrap(err, "failed to compile label regex")
+	}
+	p.labelRegex = regex
+	return nil
+}
+
+// getPoolThresholds returns the usage and free thresholds for the memory pool with the given label.
+// Pools with thresholds of the device class, like the lsmpi_io pool of ios which is nearly full by design,
+// only get free thresholds of a matching pool entry, as the global free thresholds do not fit them.
+func (r *CheckMemoryUsageRequest) getPoolThresholds(label string, deviceThresholds bool) (monitoringplugin.Thresholds, monitoringplugin.Thresholds) {
+	for _, p := range r.PoolThresholds {
+		if p.labelRegex != nil && p.labelRegex.MatchString(label) {
+			return p.MemoryUsageThresholds, p.MemoryFreeThresholds
+		}
+	}
+	if deviceThresholds {
+		return r.MemoryUsageThresholds, monitoringplugin.Thresholds{}
+	}
+	return r.MemoryUsageThresholds, r.MemoryFreeThresholds
+}
@@ -22,25 +22,55 @@ func (r *CheckMemoryUsageRequest) process(ctx context.Context) (Response, error)
 	}
 
 	for k, memPool := range memoryPools {
-		if memPool.Usage == nil {
+		if memPool.Usage == nil && memPool.Free == nil {
 			continue
 		}
 
-		point := monitoringplugin.NewPerformanceDataPoint("memory_usage", *memPool.Usage).SetUnit("%").SetThresholds(r.MemoryUsageThresholds)
-
+		var label string
 		if memPool.Label != nil {
-			point.SetLabel(*memPool.Label)
+			label = *memPool.Label
 		} else if len(memoryPools) > 1 {
-			point.SetLabel(strconv.Itoa(k))
+			label = strconv.Itoa(k)
 		}
+		usageThresholds, freeThresholds := r.getPoolThresholds(label, memPool.PerformanceDataPointModifier != nil)
 
-		if memPool.PerformanceDataPointModifier != nil {
-			memPool.PerformanceDataPointModifier(point)
+		var points []*monitoringplugin.PerformanceDataPoint
+		if memPool.Usage != nil {
+			point := monitoringplugin.NewPerformanceDataPoint("memory_usage", *memPool.Usage).SetUnit("%").SetThresholds(usageThresholds)
+			if memPool.PerformanceDataPointModifier != nil {
+				memPool.PerformanceDataPointModifier(point)
+			}
+			points = append(points, point)
+		}
+		if memPool.Free != nil {
+			point := monitoringplugin.NewPerformanceDataPoint("memory_free", *memPool.Free).SetUnit("B").SetThresholds(freeThresholds)
+			if memPool.Total != nil {
+				point.SetMax(float64(*memPool.Total))
+			}
+			points = append(points, point)
+		}
+		if memPool.Used != nil {
+			point := monitoringplugin.NewPerformanceDataPoint("memory_used", *memPool.Used).SetUnit("B")
+			if memPool.Total != nil {
+				point.SetMax(float64(*memPool.Total))
+			}
+			points = append(points, point)
+		}
+		if memPool.Buffers != nil {
+			points = append(points, monitoringplugin.NewPerformanceDataPoint("memory_buffers", *memPool.Buffers).SetUnit("B"))
+		}
+		if memPool.Cached != nil {
+			points = append(points, monitoringplugin.NewPerformanceDataPoint("memory_cached", *memPool.Cached).SetUnit("B"))
 		}
 
-		err = r.mon.AddPerformanceDataPoint(point)
-		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
-			return &CheckResponse{r.mon.GetInfo()}, nil
+		for _, point := range points {
+			if label != "" {
+				point.SetLabel(label)
+			}
+			err = r.mon.AddPerformanceDataPoint(point)
+			if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+				return &CheckResponse{r.mon.GetInfo()}, nil
+			}
 		}
 	}
 
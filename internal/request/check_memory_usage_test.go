@@ -0,0 +1,50 @@
+package request
+
+import (
+	"github.com/inexio/go-monitoringplugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestCheckMemoryUsageRequest_getPoolThresholds(t *testing.T) {
+	defaultUsage := monitoringplugin.Thresholds{WarningMin: 0, WarningMax: 80}
+	defaultFree := monitoringplugin.Thresholds{CriticalMin: 1024}
+	processorUsage := monitoringplugin.Thresholds{CriticalMin: 0, CriticalMax: 95}
+	ioFree := monitoringplugin.Thresholds{WarningMin: 2048}
+
+	r := CheckMemoryUsageRequest{
+		MemoryUsageThresholds: defaultUsage,
+		MemoryFreeThresholds:  defaultFree,
+		PoolThresholds: []CheckMemoryPoolThresholds{
+			{LabelRegex: "^Processor$", MemoryUsageThresholds: processorUsage},
+			{LabelRegex: "^(I/O|Processor)", MemoryFreeThresholds: ioFree},
+		},
+	}
+	for i := range r.PoolThresholds {
+		require.NoError(t, r.PoolThresholds[i].validate())
+	}
+
+	usage, free := r.getPoolThresholds("Processor", false)
+	assert.Equal(t, processorUsage, usage)
+	assert.True(t, free.IsEmpty(), "the first matching pool thresholds are used")
+
+	usage, free = r.getPoolThresholds("I/O", false)
+	assert.True(t, usage.IsEmpty())
+	assert.Equal(t, ioFree, free)
+
+	usage, free = r.getPoolThresholds("", false)
+	assert.Equal(t, defaultUsage, usage)
+	assert.Equal(t, defaultFree, free)
+
+	// pools with thresholds of the device class do not get the global free thresholds
+	usage, free = r.getPoolThresholds("lsmpi_io", true)
+	assert.Equal(t, defaultUsage, usage)
+	assert.True(t, free.IsEmpty())
+
+	usage, free = r.getPoolThresholds("I/O", true)
+	assert.True(t, usage.IsEmpty())
+	assert.Equal(t, ioFree, free, "matching pool thresholds are still used")
+
+	assert.Error(t, (&CheckMemoryPoolThresholds{LabelRegex: "("}).validate())
+}
@@ -14,6 +14,13 @@ type CheckServerRequest struct {
 	CheckDeviceRequest
 	UsersThreshold monitoringplugin.Thresholds `json:"usersThreshold" xml:"usersThreshold"`
 	ProcsThreshold monitoringplugin.Thresholds `json:"procsThreshold" xml:"procsThreshold"`
+
+	// ProcessNames are the names of the processes which should be checked.
+	ProcessNames []string `json:"processNames" xml:"processNames"`
+
+	// ProcessInstancesThresholds are applied to the instance count of every checked process.
+	// If it is empty, a critical min threshold of 1 is used.
+	ProcessInstancesThresholds monitoringplugin.Thresholds `json:"processInstancesThresholds" xml:"processInstancesThresholds"`
 }
 
 func (r *CheckServerRequest) validate(ctx context.Context) error {
@@ -25,5 +32,13 @@ func (r *CheckServerRequest) validate(ctx context.Context) error {
 		return err
 	}
 
+	if r.ProcessInstancesThresholds.IsEmpty() {
+		r.ProcessInstancesThresholds.CriticalMin = 1
+	}
+
+	if err := r.ProcessInstancesThresholds.Validate(); err != nil {
+		return err
+	}
+
 	return r.CheckDeviceRequest.validate(ctx)
 }
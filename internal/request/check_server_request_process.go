@@ -5,6 +5,7 @@ package request
 import (
 	"context"
 	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/device"
 )
 
 func (r *CheckServerRequest) process(ctx context.Context) (Response, error) {
@@ -32,5 +33,83 @@ func (r *CheckServerRequest) process(ctx context.Context) (Response, error) {
 		}
 	}
 
+	if r.checkPerformanceData(server.Server) {
+		r.mon.PrintPerformanceData(false)
+		return &CheckResponse{r.mon.GetInfo()}, nil
+	}
+
+	if r.checkProcesses(server.Server) {
+		r.mon.PrintPerformanceData(false)
+		return &CheckResponse{r.mon.GetInfo()}, nil
+	}
+
 	return &CheckResponse{r.mon.GetInfo()}, nil
 }
+
+// checkPerformanceData adds the load, swap and uptime performance data of the server.
+// It returns true if an error occurred.
+func (r *CheckServerRequest) checkPerformanceData(server device.ServerComponent) bool {
+	if server.Load1 != nil {
+		err := r.mon.AddPerformanceDataPoint(monitoringplugin.NewPerformanceDataPoint("load_1", *server.Load1))
+		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+			return true
+		}
+	}
+	if server.Load5 != nil {
+		err := r.mon.AddPerformanceDataPoint(monitoringplugin.NewPerformanceDataPoint("load_5", *server.Load5))
+		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+			return true
+		}
+	}
+	if server.Load15 != nil {
+		err := r.mon.AddPerformanceDataPoint(monitoringplugin.NewPerformanceDataPoint("load_15", *server.Load15))
+		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+			return true
+		}
+	}
+	if server.SwapTotal != nil && server.SwapFree != nil && *server.SwapTotal >= *server.SwapFree {
+		p := monitoringplugin.NewPerformanceDataPoint("swap_used", *server.SwapTotal-*server.SwapFree).SetUnit("B").SetMin(0).SetMax(float64(*server.SwapTotal))
+		err := r.mon.AddPerformanceDataPoint(p)
+		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+			return true
+		}
+	}
+	if server.Uptime != nil {
+		err := r.mon.AddPerformanceDataPoint(monitoringplugin.NewPerformanceDataPoint("uptime", *server.Uptime).SetUnit("s"))
+		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkProcesses adds the instances and memory of all processes of the request.
+// Processes which are not running have zero instances. It returns true if an error occurred.
+func (r *CheckServerRequest) checkProcesses(server device.ServerComponent) bool {
+	for _, name := range r.ProcessNames {
+		instances := 0
+		var memory *uint64
+		for _, process := range server.Processes {
+			if process.Name != nil && *process.Name == name {
+				if process.Instances != nil {
+					instances = *process.Instances
+				}
+				memory = process.Memory
+				break
+			}
+		}
+
+		err := r.mon.AddPerformanceDataPoint(monitoringplugin.NewPerformanceDataPoint("process_instances", instances).SetLabel(name).SetThresholds(r.ProcessInstancesThresholds))
+		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+			return true
+		}
+
+		if memory != nil {
+			err = r.mon.AddPerformanceDataPoint(monitoringplugin.NewPerformanceDataPoint("process_memory", *memory).SetUnit("B").SetLabel(name))
+			if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+				return true
+			}
+		}
+	}
+	return false
+}
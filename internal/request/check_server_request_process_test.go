@@ -0,0 +1,76 @@
+// +build !client
+
+package request
+
+import (
+	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/device"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCheckServerRequest_checkProcesses(t *testing.T) {
+	name := func(s string) *string { return &s }
+	number := func(i int) *int { return &i }
+	bytes := func(b uint64) *uint64 { return &b }
+	server := device.ServerComponent{
+		Processes: []device.ServerComponentProcess{
+			{Name: name("sshd"), Instances: number(2), Memory: bytes(300)},
+			{Name: name("nginx"), Instances: number(4)},
+		},
+	}
+
+	r := newCheckServerTestRequest([]string{"sshd", "nginx"}, monitoringplugin.Thresholds{CriticalMin: 1})
+	assert.False(t, r.checkProcesses(server))
+	info := r.mon.GetInfo()
+	assert.Equal(t, monitoringplugin.OK, info.StatusCode)
+	assert.Len(t, info.PerformanceData, 3)
+	assert.Equal(t, 2, getPerformanceDataPoint(t, info, "process_instances", "sshd").Value)
+	assert.Equal(t, uint64(300), getPerformanceDataPoint(t, info, "process_memory", "sshd").Value)
+	assert.Equal(t, 4, getPerformanceDataPoint(t, info, "process_instances", "nginx").Value)
+
+	// processes which are not running violate the default threshold
+	r = newCheckServerTestRequest([]string{"sshd", "postfix"}, monitoringplugin.Thresholds{CriticalMin: 1})
+	assert.False(t, r.checkProcesses(server))
+	info = r.mon.GetInfo()
+	assert.Equal(t, monitoringplugin.CRITICAL, info.StatusCode)
+	assert.Equal(t, 0, getPerformanceDataPoint(t, info, "process_instances", "postfix").Value)
+
+	r = newCheckServerTestRequest([]string{"nginx"}, monitoringplugin.Thresholds{WarningMax: 2})
+	assert.False(t, r.checkProcesses(server))
+	assert.Equal(t, monitoringplugin.WARNING, r.mon.GetInfo().StatusCode)
+}
+
+func TestCheckServerRequest_checkPerformanceData(t *testing.T) {
+	load := 0.5
+	swapTotal, swapFree := uint64(1000), uint64(400)
+	uptime := uint64(3600)
+
+	r := CheckServerRequest{}
+	r.init()
+	assert.False(t, r.checkPerformanceData(device.ServerComponent{Load1: &load, SwapTotal: &swapTotal, SwapFree: &swapFree, Uptime: &uptime}))
+	info := r.mon.GetInfo()
+	assert.Equal(t, monitoringplugin.OK, info.StatusCode)
+	assert.Len(t, info.PerformanceData, 3)
+	assert.Equal(t, 0.5, getPerformanceDataPoint(t, info, "load_1", "").Value)
+	swapUsed := getPerformanceDataPoint(t, info, "swap_used", "")
+	assert.Equal(t, uint64(600), swapUsed.Value)
+	assert.Equal(t, float64(1000), swapUsed.Max)
+	assert.Equal(t, uint64(3600), getPerformanceDataPoint(t, info, "uptime", "").Value)
+}
+
+func newCheckServerTestRequest(processNames []string, thresholds monitoringplugin.Thresholds) CheckServerRequest {
+	r := CheckServerRequest{ProcessNames: processNames, ProcessInstancesThresholds: thresholds}
+	r.init()
+	return r
+}
+
+func getPerformanceDataPoint(t *testing.T, info monitoringplugin.ResponseInfo, metric, label string) monitoringplugin.PerformanceDataPoint {
+	for _, point := range info.PerformanceData {
+		if point.Metric == metric && point.Label == label {
+			return point
+		}
+	}
+	t.Fatalf("performance data point %s with label '%s' not found", metric, label)
+	return monitoringplugin.PerformanceDataPoint{}
+}
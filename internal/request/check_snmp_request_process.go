@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/inexio/go-monitoringplugin"
 	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/tholaerr"
 	"github.com/inexio/thola/internal/utility"
 )
 
@@ -15,7 +16,9 @@ func (r *CheckSNMPRequest) process(ctx context.Context) (Response, error) {
 	r.mon.SetOutputDelimiter(" - ")
 	var res CheckSNMPResponse
 	con, err := r.setupSNMPConnection(ctx)
-	if !r.mon.UpdateStatusOnError(err, monitoringplugin.CRITICAL, "failed to create snmp connection", false) {
+	if reason, ok := tholaerr.GetSNMPv3USMReason(err); ok {
+		r.mon.UpdateStatus(monitoringplugin.CRITICAL, fmt.Sprintf("snmpv3 request was rejected by the device: %s (%s)", reason, reason.Hint()))
+	} else if !r.mon.UpdateStatusOnError(err, monitoringplugin.CRITICAL, "failed to create snmp connection", false) {
 		version := con.SnmpClient.GetVersion()
 		if version == "3" {
 			res.SuccessfulSnmpCredentials = &network.SNMPCredentials{
@@ -0,0 +1,30 @@
+package request
+
+import (
+	"context"
+	"github.com/inexio/go-monitoringplugin"
+)
+
+// CheckSNMPValueRequest
+//
+// CheckSNMPValueRequest is the request struct for the check snmp value request.
+//
+// swagger:model
+type CheckSNMPValueRequest struct {
+	CheckDeviceRequest
+	SNMPValueQuery
+	// Name is the name of the performance data. If multiple columns of a table are read, the column is appended.
+	Name       string                      `yaml:"name" json:"name" xml:"name"`
+	Unit       string                      `yaml:"unit" json:"unit" xml:"unit"`
+	Thresholds monitoringplugin.Thresholds `yaml:"thresholds" json:"thresholds" xml:"thresholds"`
+}
+
+func (r *CheckSNMPValueRequest) validate(ctx context.Context) error {
+	if err := r.SNMPValueQuery.validate(); err != nil {
+		return err
+	}
+	if err := r.Thresholds.Validate(); err != nil {
+		return err
+	}
+	return r.CheckDeviceRequest.validate(ctx)
+}
@@ -0,0 +1,53 @@
+// +build !client
+
+package request
+
+import (
+	"context"
+	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/value"
+	"github.com/pkg/errors"
+)
+
+func (r *CheckSNMPValueRequest) process(ctx context.Context) (Response, error) {
+	r.init()
+
+	readRequest := ReadSNMPValueRequest{ReadRequest{r.BaseRequest}, r.SNMPValueQuery}
+	response, err := readRequest.process(ctx)
+	if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while processing read snmp value request", true) {
+		return &CheckResponse{r.mon.GetInfo()}, nil
+	}
+
+	name := r.Name
+	if name == "" {
+		name = "snmp_value"
+	}
+
+	for _, snmpValue := range response.(*ReadSNMPValueResponse).Values {
+		val, err := value.New(snmpValue.Value).Float64()
+		if err != nil {
+			r.mon.UpdateStatusOnError(errors.Wrapf(err, "value '%s' of oid '%s' is not a number", snmpValue.Value, snmpValue.OID), monitoringplugin.UNKNOWN, "error while parsing snmp value", true)
+			r.mon.PrintPerformanceData(false)
+			return &CheckResponse{r.mon.GetInfo()}, nil
+		}
+
+		pointName := name
+		if len(r.Columns) > 1 {
+			pointName += "_" + snmpValue.Column
+		}
+		p := monitoringplugin.NewPerformanceDataPoint(pointName, val).SetLabel(snmpValue.Label)
+		if r.Unit != "" {
+			p.SetUnit(r.Unit)
+		}
+		if !r.Thresholds.IsEmpty() {
+			p.SetThresholds(r.Thresholds)
+		}
+		err = r.mon.AddPerformanceDataPoint(p)
+		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+			r.mon.PrintPerformanceData(false)
+			return &CheckResponse{r.mon.GetInfo()}, nil
+		}
+	}
+
+	return &CheckResponse{r.mon.GetInfo()}, nil
+}
@@ -2,6 +2,7 @@ package request
 
 import (
 	"context"
+	"github.com/inexio/go-monitoringplugin"
 	"github.com/inexio/thola/internal/network"
 )
 
@@ -13,6 +14,10 @@ import (
 type CheckTholaServerRequest struct {
 	CheckRequest
 	Timeout *int `json:"timeout" xml:"timeout"`
+
+	// Thresholds for the p95 response time in seconds and the ratio of failed requests in the last five minutes.
+	P95ResponseTimeThresholds monitoringplugin.Thresholds `json:"p95_response_time_thresholds" xml:"p95_response_time_thresholds"`
+	ErrorRatioThresholds      monitoringplugin.Thresholds `json:"error_ratio_thresholds" xml:"error_ratio_thresholds"`
 }
 
 func (r *CheckTholaServerRequest) setupConnection(_ context.Context) (*network.RequestDeviceConnection, error) {
@@ -24,7 +29,10 @@ func (r *CheckTholaServerRequest) getTimeout() *int {
 }
 
 func (r *CheckTholaServerRequest) validate(_ context.Context) error {
-	return nil
+	if err := r.P95ResponseTimeThresholds.Validate(); err != nil {
+		return err
+	}
+	return r.ErrorRatioThresholds.Validate()
 }
 
 // GetDeviceData returns the device data of the request.
@@ -57,5 +57,22 @@ func (r *CheckTholaServerRequest) process(ctx context.Context) (Response, error)
 		return &CheckResponse{r.mon.GetInfo()}, nil
 	}
 
+	err = r.mon.AddPerformanceDataPoint(monitoringplugin.NewPerformanceDataPoint("p95_response_time", stats.LastFiveMinutes.P95ResponseTime).
+		SetUnit("s").
+		SetThresholds(r.P95ResponseTimeThresholds))
+	if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+		r.mon.PrintPerformanceData(false)
+		return &CheckResponse{r.mon.GetInfo()}, nil
+	}
+
+	err = r.mon.AddPerformanceDataPoint(monitoringplugin.NewPerformanceDataPoint("error_ratio", stats.LastFiveMinutes.ErrorRatio).
+		SetThresholds(r.ErrorRatioThresholds).
+		SetMin(0).
+		SetMax(1))
+	if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+		r.mon.PrintPerformanceData(false)
+		return &CheckResponse{r.mon.GetInfo()}, nil
+	}
+
 	return &CheckResponse{r.mon.GetInfo()}, nil
 }
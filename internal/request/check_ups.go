@@ -17,6 +17,7 @@ type CheckUPSRequest struct {
 	CurrentLoadThresholds        monitoringplugin.Thresholds `json:"currentLoadThresholds" xml:"currentLoadThresholds"`
 	RectifierCurrentThresholds   monitoringplugin.Thresholds `json:"rectifierCurrentThresholds" xml:"rectifierCurrentThresholds"`
 	SystemVoltageThresholds      monitoringplugin.Thresholds `json:"systemVoltageThresholds" xml:"systemVoltageThresholds"`
+	OutputLoadThresholds         monitoringplugin.Thresholds `json:"outputLoadThresholds" xml:"outputLoadThresholds"`
 }
 
 func (r *CheckUPSRequest) validate(ctx context.Context) error {
@@ -40,5 +41,9 @@ func (r *CheckUPSRequest) validate(ctx context.Context) error {
 		return err
 	}
 
+	if err := r.OutputLoadThresholds.Validate(); err != nil {
+		return err
+	}
+
 	return r.CheckDeviceRequest.validate(ctx)
 }
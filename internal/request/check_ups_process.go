@@ -5,7 +5,9 @@ package request
 import (
 	"context"
 	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/device"
 	"github.com/inexio/thola/internal/utility"
+	"strconv"
 )
 
 func (r *CheckUPSRequest) process(ctx context.Context) (Response, error) {
@@ -116,6 +118,11 @@ func (r *CheckUPSRequest) process(ctx context.Context) (Response, error) {
 		}
 	}
 
+	if r.checkACValues(readUPSResponse.UPS) {
+		r.mon.PrintPerformanceData(false)
+		return &CheckResponse{r.mon.GetInfo()}, nil
+	}
+
 	return &CheckResponse{r.mon.GetInfo()}, nil
 }
 
@@ -129,3 +136,86 @@ func (r *CheckUPSRequest) getData(ctx context.Context) (*ReadUPSResponse, error)
 	readUPSResponse := response.(*ReadUPSResponse)
 	return readUPSResponse, nil
 }
+
+// checkACValues adds the input and output values of ac ups devices and checks the output source and the battery.
+// It returns true if an error occurred.
+func (r *CheckUPSRequest) checkACValues(ups device.UPSComponent) bool {
+	if ups.OutputFrequency != nil {
+		err := r.mon.AddPerformanceDataPoint(monitoringplugin.NewPerformanceDataPoint("output_frequency", *ups.OutputFrequency))
+		if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+			return true
+		}
+	}
+
+	for i, input := range ups.Inputs {
+		label := strconv.Itoa(i + 1)
+		points := []struct {
+			name  string
+			value *float64
+		}{
+			{"input_voltage", input.Voltage},
+			{"input_frequency", input.Frequency},
+			{"input_current", input.Current},
+		}
+		for _, point := range points {
+			if point.value == nil {
+				continue
+			}
+			err := r.mon.AddPerformanceDataPoint(monitoringplugin.NewPerformanceDataPoint(point.name, *point.value).SetLabel(label))
+			if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+				return true
+			}
+		}
+	}
+
+	for i, output := range ups.Outputs {
+		label := strconv.Itoa(i + 1)
+		points := []struct {
+			name       string
+			value      *float64
+			thresholds monitoringplugin.Thresholds
+		}{
+			{"output_voltage", output.Voltage, monitoringplugin.Thresholds{}},
+			{"output_current", output.Current, monitoringplugin.Thresholds{}},
+			{"output_power", output.Power, monitoringplugin.Thresholds{}},
+			{"output_load", output.Load, r.OutputLoadThresholds},
+		}
+		for _, point := range points {
+			if point.value == nil {
+				continue
+			}
+			p := monitoringplugin.NewPerformanceDataPoint(point.name, *point.value).SetLabel(label)
+			if !point.thresholds.IsEmpty() {
+				p.SetThresholds(point.thresholds)
+			}
+			err := r.mon.AddPerformanceDataPoint(p)
+			if r.mon.UpdateStatusOnError(err, monitoringplugin.UNKNOWN, "error while adding performance data point", true) {
+				return true
+			}
+		}
+	}
+
+	if ups.OutputSource != nil {
+		switch *ups.OutputSource {
+		case "battery":
+			r.mon.UpdateStatus(monitoringplugin.CRITICAL, "UPS is running on battery")
+		case "bypass":
+			r.mon.UpdateStatus(monitoringplugin.WARNING, "UPS is running on bypass")
+		case "none":
+			r.mon.UpdateStatus(monitoringplugin.CRITICAL, "UPS output is off")
+		}
+	}
+
+	if ups.BatteryStatus != nil {
+		switch *ups.BatteryStatus {
+		case "low", "depleted", "fault":
+			r.mon.UpdateStatus(monitoringplugin.CRITICAL, "Battery status is "+*ups.BatteryStatus)
+		}
+	}
+
+	if ups.BatteryReplacementNeeded != nil {
+		r.mon.UpdateStatusIf(*ups.BatteryReplacementNeeded, monitoringplugin.WARNING, "Battery needs to be replaced")
+	}
+
+	return false
+}
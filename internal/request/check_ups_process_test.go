@@ -0,0 +1,58 @@
+// +build !client
+
+package request
+
+import (
+	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/device"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCheckUPSRequest_checkACValues(t *testing.T) {
+	number := func(f float64) *float64 { return &f }
+	text := func(s string) *string { return &s }
+	boolean := func(b bool) *bool { return &b }
+
+	ups := device.UPSComponent{
+		OutputFrequency: number(50),
+		OutputSource:    text("normal"),
+		BatteryStatus:   text("normal"),
+		Inputs:          []device.UPSComponentInput{{Voltage: number(230), Frequency: number(50)}},
+		Outputs:         []device.UPSComponentOutput{{Voltage: number(230), Load: number(85)}, {Load: number(20)}},
+	}
+	r := CheckUPSRequest{OutputLoadThresholds: monitoringplugin.Thresholds{WarningMax: 80}}
+	r.init()
+	assert.False(t, r.checkACValues(ups))
+	info := r.mon.GetInfo()
+	assert.Equal(t, monitoringplugin.WARNING, info.StatusCode, "output load of the first output exceeds the threshold")
+	assert.Len(t, info.PerformanceData, 6)
+	assert.Equal(t, float64(50), getPerformanceDataPoint(t, info, "output_frequency", "").Value)
+	assert.Equal(t, float64(230), getPerformanceDataPoint(t, info, "input_voltage", "1").Value)
+	assert.Equal(t, float64(50), getPerformanceDataPoint(t, info, "input_frequency", "1").Value)
+	assert.Equal(t, float64(230), getPerformanceDataPoint(t, info, "output_voltage", "1").Value)
+	assert.Equal(t, float64(85), getPerformanceDataPoint(t, info, "output_load", "1").Value)
+	assert.Equal(t, float64(20), getPerformanceDataPoint(t, info, "output_load", "2").Value)
+
+	tests := []struct {
+		name   string
+		ups    device.UPSComponent
+		status int
+	}{
+		{"normal", device.UPSComponent{OutputSource: text("normal"), BatteryStatus: text("normal"), BatteryReplacementNeeded: boolean(false)}, monitoringplugin.OK},
+		{"on battery", device.UPSComponent{OutputSource: text("battery")}, monitoringplugin.CRITICAL},
+		{"on bypass", device.UPSComponent{OutputSource: text("bypass")}, monitoringplugin.WARNING},
+		{"output off", device.UPSComponent{OutputSource: text("none")}, monitoringplugin.CRITICAL},
+		{"battery low", device.UPSComponent{BatteryStatus: text("low")}, monitoringplugin.CRITICAL},
+		{"battery depleted", device.UPSComponent{BatteryStatus: text("depleted")}, monitoringplugin.CRITICAL},
+		{"battery replacement", device.UPSComponent{BatteryReplacementNeeded: boolean(true)}, monitoringplugin.WARNING},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := CheckUPSRequest{}
+			r.init()
+			assert.False(t, r.checkACValues(test.ups))
+			assert.Equal(t, test.status, r.mon.GetInfo().StatusCode)
+		})
+	}
+}
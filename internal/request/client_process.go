@@ -72,6 +72,10 @@ func (r *CheckInterfaceMetricsRequest) process(ctx context.Context) (Response, e
 	return checkProcess(ctx, r, "check/interface-metrics"), nil
 }
 
+func (r *CheckSNMPValueRequest) process(ctx context.Context) (Response, error) {
+	return checkProcess(ctx, r, "check/snmp-value"), nil
+}
+
 func (r *CheckTholaServerRequest) process(ctx context.Context) (Response, error) {
 	var res CheckResponse
 	apiFormat := viper.GetString("target-api-format")
@@ -222,6 +226,20 @@ func (r *ReadServerRequest) process(ctx context.Context) (Response, error) {
 	return &res, nil
 }
 
+func (r *ReadSNMPValueRequest) process(ctx context.Context) (Response, error) {
+	apiFormat := viper.GetString("target-api-format")
+	responseBody, err := sendToAPI(ctx, r, "read/snmp-value", apiFormat)
+	if err != nil {
+		return nil, err
+	}
+	var res ReadSNMPValueResponse
+	err = parser.ToStruct(responseBody, apiFormat, &res)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse api response body to thola response")
+	}
+	return &res, nil
+}
+
 func (r *ReadDiskRequest) process(ctx context.Context) (Response, error) {
 	apiFormat := viper.GetString("target-api-format")
 	responseBody, err := sendToAPI(ctx, r, "read/disk", apiFormat)
@@ -266,6 +284,34 @@ func (r *ReadAvailableComponentsRequest) process(ctx context.Context) (Response,
 	return &res, nil
 }
 
+func (r *SNMPGetRequest) process(ctx context.Context) (Response, error) {
+	apiFormat := viper.GetString("target-api-format")
+	responseBody, err := sendToAPI(ctx, r, "snmp/get", apiFormat)
+	if err != nil {
+		return nil, err
+	}
+	var res SNMPResponse
+	err = parser.ToStruct(responseBody, apiFormat, &res)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse api response body to thola response")
+	}
+	return &res, nil
+}
+
+func (r *SNMPWalkRequest) process(ctx context.Context) (Response, error) {
+	apiFormat := viper.GetString("target-api-format")
+	responseBody, err := sendToAPI(ctx, r, "snmp/walk", apiFormat)
+	if err != nil {
+		return nil, err
+	}
+	var res SNMPResponse
+	err = parser.ToStruct(responseBody, apiFormat, &res)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse api response body to thola response")
+	}
+	return &res, nil
+}
+
 func checkProcess(ctx context.Context, r Request, apiPath string) Response {
 	var res CheckResponse
 	apiFormat := viper.GetString("target-api-format")
@@ -302,6 +348,18 @@ func sendToAPI(ctx context.Context, request Request, path, format string) ([]byt
 	}
 
 	client.InsecureSSLCert(viper.GetBool("insecure-ssl-cert"))
+	if cert := viper.GetString("target-api-cert"); cert != "" {
+		err = client.SetClientCertificate(cert, viper.GetString("target-api-key"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to set client certificate")
+		}
+	}
+	if ca := viper.GetString("target-api-ca"); ca != "" {
+		err = client.SetRootCAs(ca)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to set CA of the target API")
+		}
+	}
 	err = client.SetFormat(viper.GetString("target-api-format"))
 	if err != nil {
 		return nil, errors.Wrap(err, "error during set format of http client")
@@ -313,6 +371,9 @@ func sendToAPI(ctx context.Context, request Request, path, format string) ([]byt
 	}
 
 	header := map[string]string{"User-Agent": "Thola Client " + doc.Version}
+	if token := viper.GetString("target-api-token"); token != "" {
+		header["Authorization"] = "Bearer " + token
+	}
 	rid, ok := RequestIDFromContext(ctx)
 	if ok {
 		header["X-Request-ID"] = rid
@@ -4,13 +4,16 @@ package request
 
 import (
 	"context"
+	"github.com/inexio/thola/api/statistics"
 	"github.com/inexio/thola/internal/communicator"
 	"github.com/inexio/thola/internal/communicator/create"
 	"github.com/inexio/thola/internal/database"
 	"github.com/inexio/thola/internal/device"
+	"github.com/inexio/thola/internal/network"
 	"github.com/inexio/thola/internal/tholaerr"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"time"
 )
 
 // GetCommunicator returns a NetworkDeviceCommunicator for the given device.
@@ -33,9 +36,9 @@ func GetCommunicator(ctx context.Context, baseRequest BaseRequest) (communicator
 		ctx := logger.WithContext(ctx)
 
 		log.Ctx(ctx).Debug().Msg("found device properties in cache, starting to validate")
-		res, err := create.MatchDeviceClass(ctx, deviceProperties.Class)
+		res, err := validateCachedDeviceClass(ctx, db, baseRequest.DeviceData.IPAddress, deviceProperties.Class)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to match device class")
+			return nil, errors.Wrap(err, "failed to validate cached device class")
 		}
 		if invalidCache = !res; invalidCache {
 			log.Ctx(ctx).Debug().Msg("cached device class is invalid")
@@ -51,6 +54,7 @@ func GetCommunicator(ctx context.Context, baseRequest BaseRequest) (communicator
 		}
 		deviceProperties = res.(*IdentifyResponse).Device
 	}
+	statistics.SetDeviceClass(ctx, deviceProperties.Class)
 	ctx = device.NewContextWithDeviceProperties(ctx, deviceProperties)
 
 	com, err := create.GetNetworkDeviceCommunicator(ctx, deviceProperties.Class)
@@ -65,3 +69,92 @@ func GetCommunicator(ctx context.Context, baseRequest BaseRequest) (communicator
 
 	return com, nil
 }
+
+// bootTimeTolerance is the maximum difference of two boot times calculated from the sysUpTime of a device
+// that still belong to the same boot, as the calculation is inaccurate by the latency of the requests.
+const bootTimeTolerance = time.Minute
+
+// validateCachedDeviceClass returns whether the cached device class of the device is still valid.
+// Device classes whose device class data is cached are trusted without matching them again,
+// unless the sysObjectID or the sysUpTime of the device show that it was replaced or rebooted.
+func validateCachedDeviceClass(ctx context.Context, db database.Database, ip, class string) (bool, error) {
+	cached, err := db.GetDeviceClass(ctx, ip)
+	if err != nil && !tholaerr.IsNotFoundError(err) {
+		return false, errors.Wrap(err, "failed to get device class data from cache")
+	}
+
+	if err == nil && cached.Class == class {
+		current, err := getDeviceClassData(ctx, class)
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Msg("failed to read sysObjectID and sysUpTime, matching cached device class")
+		} else {
+			if cached.SysObjectID != current.SysObjectID {
+				log.Ctx(ctx).Debug().Str("cached_sys_object_id", cached.SysObjectID).Str("sys_object_id", current.SysObjectID).
+					Msg("sysObjectID changed, device was replaced")
+				err = db.DeleteEntries(ctx, ip)
+				if err != nil {
+					return false, errors.Wrap(err, "failed to invalidate cache entries of replaced device")
+				}
+				return false, nil
+			}
+			if diff := current.BootTime.Sub(cached.BootTime); diff > bootTimeTolerance || diff < -bootTimeTolerance {
+				log.Ctx(ctx).Debug().Time("cached_boot_time", cached.BootTime).Time("boot_time", current.BootTime).
+					Msg("boot time changed, device was rebooted")
+				return false, nil
+			}
+			log.Ctx(ctx).Debug().Time("identified_at", cached.IdentifiedAt).Msg("trusting cached device class")
+			return true, nil
+		}
+	}
+
+	res, err := create.MatchDeviceClass(ctx, class)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to match device class")
+	}
+	if res {
+		storeDeviceClassData(ctx, db, ip, class)
+	}
+	return res, nil
+}
+
+// storeDeviceClassData caches the device class data of the identified device.
+// Failures are only logged, as the device class is matched again if there is no device class data.
+func storeDeviceClassData(ctx context.Context, db database.Database, ip, class string) {
+	data, err := getDeviceClassData(ctx, class)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to read sysObjectID and sysUpTime, not caching device class")
+		return
+	}
+	err = db.SetDeviceClass(ctx, ip, data)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to cache device class")
+	}
+}
+
+// getDeviceClassData returns the device class data of the device in its current state.
+// Devices without snmp connection have no sysObjectID and boot time.
+func getDeviceClassData(ctx context.Context, class string) (database.DeviceClassData, error) {
+	data := database.DeviceClassData{
+		Class:        class,
+		IdentifiedAt: time.Now().Truncate(time.Second),
+	}
+	con, ok := network.DeviceConnectionFromContext(ctx)
+	if !ok {
+		return database.DeviceClassData{}, errors.New("no connection data found in context")
+	}
+	if con.SNMP == nil {
+		return data, nil
+	}
+
+	var err error
+	data.SysObjectID, err = con.SNMP.GetSysObjectID(ctx)
+	if err != nil {
+		return database.DeviceClassData{}, errors.Wrap(err, "failed to get sysObjectID")
+	}
+	upTime, err := con.SNMP.GetSysUpTime(ctx)
+	if err != nil {
+		return database.DeviceClassData{}, errors.Wrap(err, "failed to get sysUpTime")
+	}
+	data.BootTime = time.Now().Add(-upTime).Truncate(time.Second)
+	return data, nil
+}
@@ -0,0 +1,60 @@
+// +build !client
+
+package request
+
+import (
+	"context"
+	"github.com/inexio/thola/internal/database"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/snmprec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+// deviceClassDB is a database which only contains the device class data of a single device.
+type deviceClassDB struct {
+	database.Database
+	data    database.DeviceClassData
+	deleted bool
+}
+
+func (d *deviceClassDB) GetDeviceClass(_ context.Context, _ string) (database.DeviceClassData, error) {
+	return d.data, nil
+}
+
+func (d *deviceClassDB) DeleteEntries(_ context.Context, _ string) error {
+	d.deleted = true
+	return nil
+}
+
+func TestValidateCachedDeviceClass(t *testing.T) {
+	client, err := network.NewSNMPReplayClient([]snmprec.Record{
+		{OID: "1.3.6.1.2.1.1.2.0", Type: "6", Value: "1.3.6.1.4.1.9.1.222"},
+		{OID: "1.3.6.1.2.1.1.3.0", Type: "67", Value: "360000"},
+	})
+	require.NoError(t, err)
+	ctx := network.NewContextWithDeviceConnection(context.Background(), &network.RequestDeviceConnection{
+		SNMP: &network.RequestDeviceConnectionSNMP{SnmpClient: client},
+	})
+	bootTime := time.Now().Add(-time.Hour)
+
+	// the replay client has not enough data to match the class, so valid device classes are trusted without matching
+	db := &deviceClassDB{data: database.DeviceClassData{Class: "ios", SysObjectID: ".1.3.6.1.4.1.9.1.222", BootTime: bootTime}}
+	valid, err := validateCachedDeviceClass(ctx, db, "192.0.2.1", "ios")
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	db = &deviceClassDB{data: database.DeviceClassData{Class: "ios", SysObjectID: ".1.3.6.1.4.1.9.1.222", BootTime: bootTime.Add(-24 * time.Hour)}}
+	valid, err = validateCachedDeviceClass(ctx, db, "192.0.2.1", "ios")
+	require.NoError(t, err)
+	assert.False(t, valid, "rebooted device")
+	assert.False(t, db.deleted)
+
+	db = &deviceClassDB{data: database.DeviceClassData{Class: "ios", SysObjectID: ".1.3.6.1.4.1.2636.1.1.1.2.29", BootTime: bootTime}}
+	valid, err = validateCachedDeviceClass(ctx, db, "192.0.2.1", "ios")
+	require.NoError(t, err)
+	assert.False(t, valid, "replaced device")
+	assert.True(t, db.deleted)
+}
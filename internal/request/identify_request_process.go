@@ -4,6 +4,7 @@ package request
 
 import (
 	"context"
+	"github.com/inexio/thola/api/statistics"
 	"github.com/inexio/thola/internal/communicator/create"
 	"github.com/inexio/thola/internal/database"
 	"github.com/inexio/thola/internal/network"
@@ -33,6 +34,7 @@ func (r *IdentifyRequest) process(ctx context.Context) (Response, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to save device info to cache")
 	}
+	storeDeviceClassData(ctx, db, r.DeviceData.IPAddress, response.Class)
 
 	err = db.SetConnectionData(ctx, r.DeviceData.IPAddress, con.GetIdealConnectionData())
 	if err != nil {
@@ -50,6 +52,7 @@ func (r *IdentifyRequest) identify(ctx context.Context) (*IdentifyResponse, erro
 
 	var response IdentifyResponse
 	response.Class = com.GetIdentifier()
+	statistics.SetDeviceClass(ctx, response.Class)
 
 	response.Properties, err = com.GetIdentifyProperties(ctx)
 	if err != nil {
@@ -5,8 +5,11 @@ package request
 import (
 	"context"
 	"fmt"
+	"github.com/inexio/thola/internal/database"
 	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/tholaerr"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
 	"strconv"
 	"time"
 )
@@ -66,8 +69,47 @@ func processRequest(ctx context.Context, request Request, responseChan chan resp
 	defer con.CloseConnections()
 	ctx = network.NewContextWithDeviceConnection(ctx, con)
 	res, err := request.process(ctx)
+	if r, ok := request.(interface{ GetDeviceData() *DeviceData }); ok {
+		cacheAdaptiveMaxRepetitions(ctx, r.GetDeviceData(), con)
+	}
 	responseChan <- response{
 		res: res,
 		err: err,
 	}
 }
+
+// cacheAdaptiveMaxRepetitions stores the max repetitions learned by the bulk walks of the request in the connection data cache,
+// so that the next requests to the device start with them.
+func cacheAdaptiveMaxRepetitions(ctx context.Context, deviceData *DeviceData, con *network.RequestDeviceConnection) {
+	if con.SNMP == nil || con.SNMP.SnmpClient == nil {
+		return
+	}
+	maxRepetitions := con.SNMP.SnmpClient.GetAdaptiveMaxRepetitions()
+	if maxRepetitions == 0 {
+		return
+	}
+	if known := deviceData.ConnectionData.SNMP; known != nil && known.AdaptiveMaxRepetitions != nil && *known.AdaptiveMaxRepetitions == maxRepetitions {
+		return
+	}
+
+	db, err := database.GetDB(ctx)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get DB")
+		return
+	}
+	cacheData, err := db.GetConnectionData(ctx, deviceData.IPAddress)
+	if err != nil && !tholaerr.IsNotFoundError(err) {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to get cached connection data")
+		return
+	}
+	if cacheData.SNMP == nil {
+		cacheData.SNMP = &network.SNMPConnectionData{}
+	}
+	cacheData.SNMP.AdaptiveMaxRepetitions = &maxRepetitions
+	err = db.SetConnectionData(ctx, deviceData.IPAddress, cacheData)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("failed to cache learned snmp max repetitions")
+		return
+	}
+	log.Ctx(ctx).Debug().Uint32("max_repetitions", maxRepetitions).Msg("cached learned snmp max repetitions")
+}
@@ -0,0 +1,111 @@
+package request
+
+import (
+	"context"
+	"github.com/inexio/thola/internal/network"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// SNMPValueQuery
+//
+// SNMPValueQuery describes which snmp values are read and how they are processed.
+//
+// swagger:model
+type SNMPValueQuery struct {
+	// OIDs are read with a snmpget each, the oid is used as label.
+	OIDs []network.OID `yaml:"oids" json:"oids" xml:"oids"`
+	// Table is walked, only the given columns of it are read.
+	Table   network.OID `yaml:"table" json:"table" xml:"table"`
+	Columns []string    `yaml:"columns" json:"columns" xml:"columns"`
+	// LabelColumn is the column of the table which is used as label. If it is empty, the index is used.
+	LabelColumn string `yaml:"labelColumn" json:"labelColumn" xml:"labelColumn"`
+	// Operators are applied to every value. They are given as yaml or json in the same format as in device classes.
+	Operators string `yaml:"operators" json:"operators" xml:"operators"`
+}
+
+func (q *SNMPValueQuery) validate() error {
+	if len(q.OIDs) == 0 && q.Table == "" {
+		return errors.New("either oids or a table need to be given")
+	}
+	for _, oid := range q.OIDs {
+		if err := oid.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid oid '%s'", oid)
+		}
+	}
+	if q.Table != "" {
+		if err := q.Table.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid table oid '%s'", q.Table)
+		}
+		if len(q.Columns) == 0 {
+			return errors.New("at least one column of the table needs to be given")
+		}
+		columns := append(append([]string{}, q.Columns...), q.LabelColumn)
+		for _, column := range columns {
+			if column == "" {
+				continue
+			}
+			if err := network.OID(column).Validate(); err != nil {
+				return errors.Wrapf(err, "invalid column '%s'", column)
+			}
+		}
+	} else if len(q.Columns) > 0 || q.LabelColumn != "" {
+		return errors.New("columns can only be given together with a table")
+	}
+	if _, err := q.getOperators(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// getOperators returns the unmarshalled operators of the query.
+func (q *SNMPValueQuery) getOperators() ([]interface{}, error) {
+	if q.Operators == "" {
+		return nil, nil
+	}
+	var operators []interface{}
+	if err := yaml.Unmarshal([]byte(q.Operators), &operators); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal operators")
+	}
+	return operators, nil
+}
+
+// ReadSNMPValueRequest
+//
+// ReadSNMPValueRequest is the request struct for the read snmp value request.
+//
+// swagger:model
+type ReadSNMPValueRequest struct {
+	ReadRequest
+	SNMPValueQuery
+}
+
+func (r *ReadSNMPValueRequest) validate(ctx context.Context) error {
+	if err := r.SNMPValueQuery.validate(); err != nil {
+		return err
+	}
+	return r.ReadRequest.validate(ctx)
+}
+
+// ReadSNMPValueResponse
+//
+// ReadSNMPValueResponse is the response struct for the read snmp value request.
+//
+// swagger:model
+type ReadSNMPValueResponse struct {
+	Values []SNMPValue `yaml:"values" json:"values" xml:"values"`
+	ReadResponse
+}
+
+// SNMPValue
+//
+// SNMPValue is a single value that was read by a snmp value request.
+//
+// swagger:model
+type SNMPValue struct {
+	OID network.OID `yaml:"oid" json:"oid" xml:"oid"`
+	// Column is the column of the table the value was read from, it is empty for single oids.
+	Column string `yaml:"column,omitempty" json:"column,omitempty" xml:"column,omitempty"`
+	Label  string `yaml:"label" json:"label" xml:"label"`
+	Value  string `yaml:"value" json:"value" xml:"value"`
+}
@@ -0,0 +1,147 @@
+// +build !client
+
+package request
+
+import (
+	"context"
+	"github.com/inexio/thola/internal/deviceclass/condition"
+	"github.com/inexio/thola/internal/deviceclass/property"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/inexio/thola/internal/value"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"strings"
+)
+
+func (r *ReadSNMPValueRequest) process(ctx context.Context) (Response, error) {
+	con, ok := network.DeviceConnectionFromContext(ctx)
+	if !ok || con.SNMP == nil {
+		return nil, errors.New("snmp client is empty")
+	}
+
+	operatorsInterface, err := r.getOperators()
+	if err != nil {
+		return nil, err
+	}
+	operators, err := property.InterfaceSlice2Operators(operatorsInterface, condition.PropertyDefault)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse operators")
+	}
+
+	var values []SNMPValue
+
+	if len(r.OIDs) > 0 {
+		responses, err := con.SNMP.SnmpClient.SNMPGet(ctx, r.OIDs...)
+		if err != nil && !tholaerr.IsNotFoundError(err) {
+			return nil, errors.Wrap(err, "snmpget failed")
+		}
+		for _, response := range responses {
+			oid := response.GetOID()
+			val, err := response.GetValue()
+			if err != nil {
+				if tholaerr.IsNotFoundError(err) {
+					log.Ctx(ctx).Debug().Str("oid", oid.String()).Msg("oid is not available, skipping it")
+					continue
+				}
+				return nil, errors.Wrapf(err, "failed to get value of oid '%s'", oid)
+			}
+			val, ok, err := applySNMPValueOperators(ctx, operators, val)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to apply operators to value of oid '%s'", oid)
+			}
+			if !ok {
+				continue
+			}
+			values = append(values, SNMPValue{
+				OID:   oid,
+				Label: strings.TrimPrefix(oid.String(), "."),
+				Value: val.String(),
+			})
+		}
+	}
+
+	if r.Table != "" {
+		labels := make(map[string]string)
+		if r.LabelColumn != "" {
+			labelOID := r.Table.AddIndex(r.LabelColumn)
+			responses, err := con.SNMP.SnmpClient.SNMPWalk(ctx, labelOID)
+			if err != nil && !tholaerr.IsNotFoundError(err) {
+				return nil, errors.Wrapf(err, "failed to walk label column '%s'", labelOID)
+			}
+			for _, response := range responses {
+				index, err := response.GetOID().GetIndexAfterOID(labelOID)
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to get index of label")
+				}
+				label, err := response.GetValue()
+				if err != nil {
+					log.Ctx(ctx).Debug().Err(err).Str("index", index).Msg("failed to get label, using index instead")
+					continue
+				}
+				labels[index] = label.String()
+			}
+		}
+
+		for _, column := range r.Columns {
+			columnOID := r.Table.AddIndex(column)
+			responses, err := con.SNMP.SnmpClient.SNMPWalk(ctx, columnOID)
+			if err != nil {
+				if tholaerr.IsNotFoundError(err) {
+					log.Ctx(ctx).Debug().Str("column", column).Msg("column is not available, skipping it")
+					continue
+				}
+				return nil, errors.Wrapf(err, "failed to walk column '%s'", columnOID)
+			}
+			for _, response := range responses {
+				oid := response.GetOID()
+				index, err := oid.GetIndexAfterOID(columnOID)
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to get index of value")
+				}
+				val, err := response.GetValue()
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to get value of oid '%s'", oid)
+				}
+				val, ok, err := applySNMPValueOperators(ctx, operators, val)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to apply operators to value of oid '%s'", oid)
+				}
+				if !ok {
+					continue
+				}
+				label, ok := labels[index]
+				if !ok {
+					label = index
+				}
+				values = append(values, SNMPValue{
+					OID:    oid,
+					Column: column,
+					Label:  label,
+					Value:  val.String(),
+				})
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, tholaerr.NewNotFoundError("no snmp values available")
+	}
+
+	return &ReadSNMPValueResponse{
+		Values: values,
+	}, nil
+}
+
+// applySNMPValueOperators applies the operators to the value. It returns false if the value was filtered out.
+func applySNMPValueOperators(ctx context.Context, operators property.Operators, val value.Value) (value.Value, bool, error) {
+	res, err := operators.Apply(ctx, val)
+	if err != nil {
+		if tholaerr.IsDidNotMatchError(err) {
+			log.Ctx(ctx).Debug().Str("value", val.String()).Msg("value was filtered out by operators")
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return res, true, nil
+}
@@ -0,0 +1,113 @@
+// +build !client
+
+package request
+
+import (
+	"context"
+	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/replay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestSNMPValueQuery_validate(t *testing.T) {
+	columns := make([]string, 1, 2)
+	columns[0] = "10"
+	q := SNMPValueQuery{Table: ".1.3.6.1.2.1.2.2.1", Columns: columns, LabelColumn: "2"}
+	require.NoError(t, q.validate())
+	assert.Equal(t, []string{"10", ""}, columns[:2], "the columns of the query are not modified")
+
+	assert.Error(t, (&SNMPValueQuery{}).validate())
+	assert.Error(t, (&SNMPValueQuery{Table: ".1.3.6.1.2.1.2.2.1"}).validate())
+	assert.Error(t, (&SNMPValueQuery{OIDs: []network.OID{".1.3.6.1.2.1.1.5.0"}, Columns: []string{"10"}}).validate())
+	assert.Error(t, (&SNMPValueQuery{OIDs: []network.OID{".1.3.6.1.2.1.1.5.0"}, Operators: "{"}).validate())
+}
+
+func TestReadSNMPValueRequest_process(t *testing.T) {
+	ctx, err := replay.NewContext(context.Background(), "testdata/snmp_value.snmprec")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		query    SNMPValueQuery
+		expected []SNMPValue
+	}{
+		{
+			name:  "oids",
+			query: SNMPValueQuery{OIDs: []network.OID{".1.3.6.1.2.1.1.5.0", ".1.3.6.1.2.1.1.6.0"}},
+			expected: []SNMPValue{
+				{OID: ".1.3.6.1.2.1.1.5.0", Label: "1.3.6.1.2.1.1.5.0", Value: "router-1"},
+			},
+		},
+		{
+			name: "table",
+			query: SNMPValueQuery{
+				Table:       ".1.3.6.1.2.1.2.2.1",
+				Columns:     []string{"10", "16"},
+				LabelColumn: "2",
+				Operators:   "[{type: modify, modify_method: multiply, value: {detection: constant, value: 8}}]",
+			},
+			expected: []SNMPValue{
+				{OID: ".1.3.6.1.2.1.2.2.1.10.1", Column: "10", Label: "eth0", Value: "8000"},
+				{OID: ".1.3.6.1.2.1.2.2.1.10.2", Column: "10", Label: "eth1", Value: "2000"},
+			},
+		},
+		{
+			name:  "table without label column",
+			query: SNMPValueQuery{Table: ".1.3.6.1.2.1.2.2.1", Columns: []string{"10"}, LabelColumn: "3"},
+			expected: []SNMPValue{
+				{OID: ".1.3.6.1.2.1.2.2.1.10.1", Column: "10", Label: "1", Value: "1000"},
+				{OID: ".1.3.6.1.2.1.2.2.1.10.2", Column: "10", Label: "2", Value: "250"},
+			},
+		},
+		{
+			name: "filtered values",
+			query: SNMPValueQuery{
+				Table:     ".1.3.6.1.2.1.2.2.1",
+				Columns:   []string{"2"},
+				Operators: "[{type: filter, filter_method: equals, value: eth1}]",
+			},
+			expected: []SNMPValue{
+				{OID: ".1.3.6.1.2.1.2.2.1.2.2", Column: "2", Label: "2", Value: "eth1"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := ReadSNMPValueRequest{SNMPValueQuery: test.query}
+			require.NoError(t, r.SNMPValueQuery.validate())
+			res, err := r.process(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, res.(*ReadSNMPValueResponse).Values)
+		})
+	}
+
+	r := ReadSNMPValueRequest{SNMPValueQuery: SNMPValueQuery{OIDs: []network.OID{".1.3.6.1.2.1.1.6.0"}}}
+	_, err = r.process(ctx)
+	assert.Error(t, err, "no value is available")
+}
+
+func TestCheckSNMPValueRequest_process(t *testing.T) {
+	ctx, err := replay.NewContext(context.Background(), "testdata/snmp_value.snmprec")
+	require.NoError(t, err)
+
+	r := CheckSNMPValueRequest{
+		SNMPValueQuery: SNMPValueQuery{Table: ".1.3.6.1.2.1.2.2.1", Columns: []string{"10"}, LabelColumn: "2"},
+		Name:           "in_octets",
+		Thresholds:     monitoringplugin.Thresholds{WarningMax: 500},
+	}
+	res, err := r.process(ctx)
+	require.NoError(t, err)
+	info := res.(*CheckResponse).ResponseInfo
+	assert.Equal(t, monitoringplugin.WARNING, info.StatusCode)
+	assert.Len(t, info.PerformanceData, 2)
+	assert.Equal(t, float64(1000), getPerformanceDataPoint(t, info, "in_octets", "eth0").Value)
+	assert.Equal(t, float64(250), getPerformanceDataPoint(t, info, "in_octets", "eth1").Value)
+
+	r = CheckSNMPValueRequest{SNMPValueQuery: SNMPValueQuery{OIDs: []network.OID{".1.3.6.1.2.1.1.5.0"}}}
+	res, err = r.process(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, monitoringplugin.UNKNOWN, res.(*CheckResponse).StatusCode, "value is not a number")
+}
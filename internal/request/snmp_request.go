@@ -0,0 +1,103 @@
+package request
+
+import (
+	"context"
+	"github.com/inexio/thola/internal/network"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// SNMPGetRequest
+//
+// SNMPGetRequest is the request struct for the snmp get request.
+//
+// swagger:model
+type SNMPGetRequest struct {
+	BaseRequest
+	OIDs []network.OID `yaml:"oids" json:"oids" xml:"oids"`
+}
+
+func (r *SNMPGetRequest) validate(ctx context.Context) error {
+	if len(r.OIDs) == 0 {
+		return errors.New("no oids given")
+	}
+	for _, oid := range r.OIDs {
+		if err := oid.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid oid '%s'", oid)
+		}
+	}
+	return r.BaseRequest.validate(ctx)
+}
+
+func (r *SNMPGetRequest) setupConnection(ctx context.Context) (*network.RequestDeviceConnection, error) {
+	return setupRawSNMPConnection(ctx, &r.BaseRequest)
+}
+
+// SNMPWalkRequest
+//
+// SNMPWalkRequest is the request struct for the snmp walk request.
+//
+// swagger:model
+type SNMPWalkRequest struct {
+	BaseRequest
+	OID network.OID `yaml:"oid" json:"oid" xml:"oid"`
+	// Bulk defines if GETBULK requests are used instead of GETNEXT requests.
+	Bulk bool `yaml:"bulk" json:"bulk" xml:"bulk"`
+}
+
+func (r *SNMPWalkRequest) validate(ctx context.Context) error {
+	if err := r.OID.Validate(); err != nil {
+		return errors.Wrapf(err, "invalid oid '%s'", r.OID)
+	}
+	return r.BaseRequest.validate(ctx)
+}
+
+func (r *SNMPWalkRequest) setupConnection(ctx context.Context) (*network.RequestDeviceConnection, error) {
+	return setupRawSNMPConnection(ctx, &r.BaseRequest)
+}
+
+// setupRawSNMPConnection sets up only the snmp connection, raw snmp requests do not need any other connection.
+func setupRawSNMPConnection(ctx context.Context, r *BaseRequest) (*network.RequestDeviceConnection, error) {
+	snmpCon, err := r.setupSNMPConnection(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to setup snmp connection")
+	}
+	return &network.RequestDeviceConnection{
+		RawConnectionData: r.DeviceData.ConnectionData,
+		SNMP:              snmpCon,
+	}, nil
+}
+
+// SNMPResponse
+//
+// SNMPResponse is the response struct for raw snmp requests.
+//
+// swagger:model
+type SNMPResponse struct {
+	Records []SNMPRecord `yaml:"records" json:"records" xml:"records"`
+	BaseResponse
+}
+
+// SNMPRecord
+//
+// SNMPRecord is a single snmp value in the snmprec format.
+//
+// swagger:model
+type SNMPRecord struct {
+	OID string `yaml:"oid" json:"oid" xml:"oid"`
+	// Type is the snmprec type tag, e.g. 4 for octet strings or 4x for hex encoded octet strings.
+	Type  string `yaml:"type" json:"type" xml:"type"`
+	Value string `yaml:"value" json:"value" xml:"value"`
+}
+
+// ToSNMPRec returns the records in the snmprec format which is used by snmpsim.
+func (r *SNMPResponse) ToSNMPRec() ([]byte, error) {
+	var b strings.Builder
+	for i, record := range r.Records {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(record.OID + "|" + record.Type + "|" + record.Value)
+	}
+	return []byte(b.String()), nil
+}
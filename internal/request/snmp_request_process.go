@@ -0,0 +1,62 @@
+// +build !client
+
+package request
+
+import (
+	"context"
+	"github.com/inexio/thola/internal/network"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"strings"
+)
+
+func (r *SNMPGetRequest) process(ctx context.Context) (Response, error) {
+	con, ok := network.DeviceConnectionFromContext(ctx)
+	if !ok || con.SNMP == nil {
+		return nil, errors.New("snmp client is empty")
+	}
+
+	responses, err := con.SNMP.SnmpClient.SNMPGet(ctx, r.OIDs...)
+	if err != nil {
+		return nil, errors.Wrap(err, "snmpget failed")
+	}
+
+	return &SNMPResponse{
+		Records: snmpResponses2Records(ctx, responses),
+	}, nil
+}
+
+func (r *SNMPWalkRequest) process(ctx context.Context) (Response, error) {
+	con, ok := network.DeviceConnectionFromContext(ctx)
+	if !ok || con.SNMP == nil {
+		return nil, errors.New("snmp client is empty")
+	}
+
+	responses, err := con.SNMP.SnmpClient.SNMPRawWalk(ctx, r.OID, r.Bulk)
+	if err != nil {
+		return nil, errors.Wrap(err, "snmpwalk failed")
+	}
+
+	return &SNMPResponse{
+		Records: snmpResponses2Records(ctx, responses),
+	}, nil
+}
+
+// snmpResponses2Records converts the snmp responses to snmprec records, unsuccessful responses are skipped.
+func snmpResponses2Records(ctx context.Context, responses []network.SNMPResponse) []SNMPRecord {
+	var records []SNMPRecord
+	for _, response := range responses {
+		oid := strings.TrimPrefix(response.GetOID().String(), ".")
+		tag, val, err := response.GetSNMPRecValue()
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Str("oid", oid).Msg("skipping snmp response")
+			continue
+		}
+		records = append(records, SNMPRecord{
+			OID:   oid,
+			Type:  tag,
+			Value: val,
+		})
+	}
+	return records
+}
@@ -0,0 +1,559 @@
+// Package simulator implements an SNMP agent that serves snmprec files.
+//
+// It can be used instead of snmpsim to run the device tests without any external dependencies.
+// Like in snmpsim, the community of a SNMPv1/v2c request and the context name of a SNMPv3 request
+// select the snmprec file, which is the path of the file relative to the data directory without the file extension.
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"github.com/gosnmp/gosnmp"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/snmprec"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"hash"
+	"io/ioutil"
+	stdlog "log"
+	"net"
+	"sort"
+	"time"
+)
+
+const (
+	// maxMessageSize is the maximum size of a response, which has to fit into a single UDP datagram.
+	maxMessageSize = 60000
+	// defaultMaxRepetitions is used for GETBULK requests which do not contain the max repetitions.
+	defaultMaxRepetitions = 10
+
+	oidUsmStatsUnsupportedSecLevels = ".1.3.6.1.6.3.15.1.1.1.0"
+	oidUsmStatsUnknownUserNames     = ".1.3.6.1.6.3.15.1.1.3.0"
+	oidUsmStatsUnknownEngineIDs     = ".1.3.6.1.6.3.15.1.1.4.0"
+	oidUsmStatsWrongDigests         = ".1.3.6.1.6.3.15.1.1.5.0"
+	oidUsmStatsDecryptionErrors     = ".1.3.6.1.6.3.15.1.1.6.0"
+)
+
+// engineID is the SNMPv3 engine id of the agent in the text format of RFC 3411.
+var engineID = string([]byte{0x80, 0x00, 0x00, 0x00, 0x04}) + "thola-simulator"
+
+// Config is the configuration of an agent.
+type Config struct {
+	// Dir is the directory which contains the snmprec files.
+	Dir string
+	// Address is the UDP address the agent listens on, e.g. "127.0.0.1:161".
+	Address string
+	// V3User is the optional user for SNMPv3 requests.
+	V3User *V3User
+}
+
+// V3User is a SNMPv3 user of the agent.
+//
+// Requests of the user are authenticated and decrypted. Failures are answered with the
+// usmStats report of RFC 3414, like a real agent does. The security level of the user is determined by the configured protocols.
+type V3User struct {
+	Name         string
+	AuthProtocol string
+	AuthKey      string
+	PrivProtocol string
+	PrivKey      string
+}
+
+// Agent is an SNMP agent which answers requests with the data of snmprec files.
+type Agent struct {
+	conn       net.PacketConn
+	recordings map[string]*snmprec.Recording
+	v3User     *gosnmp.UsmSecurityParameters
+	v3Flags    gosnmp.SnmpV3MsgFlags
+	startTime  time.Time
+}
+
+// NewAgent loads the snmprec files and creates a new agent listening on the configured address.
+func NewAgent(config Config) (*Agent, error) {
+	recordings, err := loadRecordings(config.Dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load recordings")
+	}
+	if len(recordings) == 0 {
+		return nil, errors.New("no snmprec files found")
+	}
+
+	agent := Agent{
+		recordings: recordings,
+		startTime:  time.Now(),
+	}
+
+	if config.V3User != nil {
+		err = agent.setV3User(*config.V3User)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid snmp v3 user")
+		}
+	}
+
+	agent.conn, err = net.ListenPacket("udp", config.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen")
+	}
+	return &agent, nil
+}
+
+func (a *Agent) setV3User(user V3User) error {
+	if user.Name == "" {
+		return errors.New("user name is empty")
+	}
+	a.v3User = &gosnmp.UsmSecurityParameters{
+		UserName:               user.Name,
+		AuthenticationProtocol: gosnmp.NoAuth,
+		PrivacyProtocol:        gosnmp.NoPriv,
+		Logger:                 stdlog.New(ioutil.Discard, "", 0),
+	}
+	a.v3Flags = gosnmp.NoAuthNoPriv
+	if user.AuthProtocol != "" {
+		authProtocol, err := network.GetGoSNMPV3AuthProtocol(user.AuthProtocol)
+		if err != nil {
+			return err
+		}
+		a.v3User.AuthenticationProtocol = authProtocol
+		a.v3User.AuthenticationPassphrase = user.AuthKey
+		a.v3Flags = gosnmp.AuthNoPriv
+	}
+	if user.PrivProtocol != "" {
+		if a.v3Flags != gosnmp.AuthNoPriv {
+			return errors.New("privacy protocol requires an authentication protocol")
+		}
+		privProtocol, err := network.GetGoSNMPV3PrivProtocol(user.PrivProtocol)
+		if err != nil {
+			return err
+		}
+		a.v3User.PrivacyProtocol = privProtocol
+		a.v3User.PrivacyPassphrase = user.PrivKey
+		a.v3Flags = gosnmp.AuthPriv
+	}
+	return nil
+}
+
+// Addr returns the address the agent listens on.
+func (a *Agent) Addr() net.Addr {
+	return a.conn.LocalAddr()
+}
+
+// Communities returns the sorted communities of all loaded snmprec files.
+func (a *Agent) Communities() []string {
+	var communities []string
+	for community := range a.recordings {
+		communities = append(communities, community)
+	}
+	sort.Strings(communities)
+	return communities
+}
+
+// Serve answers requests until the context is canceled or the agent is closed.
+func (a *Agent) Serve(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		_ = a.conn.Close()
+	}()
+
+	buf := make([]byte, 65535)
+	for {
+		n, addr, err := a.conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return errors.Wrap(err, "failed to read request")
+		}
+
+		packet := make([]byte, n)
+		copy(packet, buf[:n])
+		go func() {
+			res, err := a.handle(packet)
+			if err != nil {
+				log.Ctx(ctx).Debug().Err(err).Str("client", addr.String()).Msg("failed to handle snmp request")
+				return
+			}
+			if res == nil {
+				return
+			}
+			if _, err = a.conn.WriteTo(res, addr); err != nil {
+				log.Ctx(ctx).Debug().Err(err).Str("client", addr.String()).Msg("failed to send snmp response")
+			}
+		}()
+	}
+}
+
+// Close stops the agent.
+func (a *Agent) Close() error {
+	return a.conn.Close()
+}
+
+// handle returns the encoded response to the request, or nil if the request is dropped.
+func (a *Agent) handle(packet []byte) ([]byte, error) {
+	version, err := getVersion(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	switch version {
+	case gosnmp.Version1, gosnmp.Version2c:
+		decoder := gosnmp.GoSNMP{Version: version}
+		req, err := decoder.SnmpDecodePacket(packet)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode request")
+		}
+		rec, ok := a.recordings[req.Community]
+		if !ok {
+			return nil, fmt.Errorf("unknown community '%s'", req.Community)
+		}
+		return a.marshalResponse(req, rec, func(res *gosnmp.SnmpPacket) ([]byte, error) {
+			res.Community = req.Community
+			return res.MarshalMsg()
+		})
+	case gosnmp.Version3:
+		return a.handleV3(packet)
+	default:
+		return nil, fmt.Errorf("unknown snmp version %d", version)
+	}
+}
+
+func (a *Agent) handleV3(packet []byte) ([]byte, error) {
+	if a.v3User == nil {
+		return nil, errors.New("no snmp v3 user configured")
+	}
+
+	// decoding decrypts the packet in place, the digest has to be checked on the original packet
+	raw := append([]byte(nil), packet...)
+	req, decodeErr := a.decodeV3(packet)
+	if req == nil {
+		return nil, decodeErr
+	}
+	params, ok := req.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	if !ok {
+		return nil, errors.New("unsupported security parameters")
+	}
+
+	switch {
+	case params.AuthoritativeEngineID != engineID:
+		return a.marshalV3Report(req, oidUsmStatsUnknownEngineIDs)
+	case params.UserName != a.v3User.UserName:
+		return a.marshalV3Report(req, oidUsmStatsUnknownUserNames)
+	case req.MsgFlags&gosnmp.AuthPriv != a.v3Flags:
+		return a.marshalV3Report(req, oidUsmStatsUnsupportedSecLevels)
+	case a.v3Flags&gosnmp.AuthNoPriv > 0 && !authentic(raw, a.v3User.AuthenticationProtocol, params):
+		return a.marshalV3Report(req, oidUsmStatsWrongDigests)
+	case decodeErr != nil && a.v3Flags&gosnmp.AuthPriv > gosnmp.AuthNoPriv:
+		return a.marshalV3Report(req, oidUsmStatsDecryptionErrors)
+	case decodeErr != nil:
+		return nil, decodeErr
+	}
+
+	rec, ok := a.recordings[req.ContextName]
+	if !ok {
+		return nil, fmt.Errorf("unknown context '%s'", req.ContextName)
+	}
+	return a.marshalResponse(req, rec, func(res *gosnmp.SnmpPacket) ([]byte, error) {
+		return a.marshalV3(req, res, a.v3Flags, params)
+	})
+}
+
+// decodeV3 decodes the request. If the scoped pdu could not be decrypted,
+// the request with only the header and security parameters is returned together with the error.
+func (a *Agent) decodeV3(packet []byte) (*gosnmp.SnmpPacket, error) {
+	raw := append([]byte(nil), packet...)
+	req, err := a.decodeV3Packet(packet)
+	if err == nil {
+		return req, nil
+	}
+
+	// replace the type of the encrypted scoped pdu so that gosnmp stops decoding after the header
+	idx, ok := scopedPDUIndex(raw)
+	if !ok || raw[idx] != byte(gosnmp.OctetString) {
+		return nil, err
+	}
+	raw[idx] = byte(gosnmp.Null)
+	header, _ := a.decodeV3Packet(raw)
+	if header == nil {
+		return nil, err
+	}
+	if _, ok := header.SecurityParameters.(*gosnmp.UsmSecurityParameters); !ok {
+		return nil, err
+	}
+	return header, err
+}
+
+func (a *Agent) decodeV3Packet(packet []byte) (req *gosnmp.SnmpPacket, err error) {
+	// gosnmp panics while decoding requests that were encrypted with an other privacy protocol or key than the user's
+	defer func() {
+		if r := recover(); r != nil {
+			req, err = nil, fmt.Errorf("failed to decode request: %v", r)
+		}
+	}()
+
+	decoder := gosnmp.GoSNMP{
+		Version:            gosnmp.Version3,
+		SecurityModel:      gosnmp.UserSecurityModel,
+		MsgFlags:           a.v3Flags,
+		SecurityParameters: a.v3User.Copy(),
+	}
+	req, err = decoder.SnmpDecodePacket(packet)
+	if err != nil {
+		return req, errors.Wrap(err, "failed to decode request")
+	}
+	return req, nil
+}
+
+// scopedPDUIndex returns the index of the scoped pdu, which follows the version,
+// the global data and the security parameters in the message sequence.
+func scopedPDUIndex(packet []byte) (int, bool) {
+	idx, _, ok := berHeader(packet, 0)
+	if !ok {
+		return 0, false
+	}
+	for i := 0; i < 3; i++ {
+		header, length, ok := berHeader(packet, idx)
+		if !ok {
+			return 0, false
+		}
+		idx += header + length
+	}
+	return idx, idx < len(packet)
+}
+
+// berHeader returns the length of the tag and length octets and the length of the content of the element at idx.
+func berHeader(packet []byte, idx int) (int, int, bool) {
+	if idx+2 > len(packet) {
+		return 0, 0, false
+	}
+	length := int(packet[idx+1])
+	if length < 0x80 {
+		return 2, length, true
+	}
+	n := length & 0x7f
+	if n == 0 || n > 4 || idx+2+n > len(packet) {
+		return 0, 0, false
+	}
+	length = 0
+	for _, b := range packet[idx+2 : idx+2+n] {
+		length = length<<8 | int(b)
+	}
+	return 2 + n, length, true
+}
+
+// authentic checks the digest of the packet with the localized authentication key of the user.
+func authentic(packet []byte, protocol gosnmp.SnmpV3AuthProtocol, params *gosnmp.UsmSecurityParameters) bool {
+	digest := []byte(params.AuthenticationParameters)
+	idx := bytes.Index(packet, digest)
+	if len(digest) == 0 || idx < 0 || len(params.SecretKey) == 0 {
+		return false
+	}
+
+	var h func() hash.Hash
+	switch protocol {
+	case gosnmp.MD5:
+		h = md5.New
+	case gosnmp.SHA:
+		h = sha1.New
+	case gosnmp.SHA224:
+		h = sha256.New224
+	case gosnmp.SHA256:
+		h = sha256.New
+	case gosnmp.SHA384:
+		h = sha512.New384
+	case gosnmp.SHA512:
+		h = sha512.New
+	default:
+		return false
+	}
+
+	// the digest is calculated over the packet with zeroed authentication parameters
+	copy(packet[idx:idx+len(digest)], make([]byte, len(digest)))
+	mac := hmac.New(h, params.SecretKey)
+	mac.Write(packet)
+	sum := mac.Sum(nil)
+	return len(sum) >= len(digest) && hmac.Equal(sum[:len(digest)], digest)
+}
+
+// marshalV3Report returns a report containing the counter of the usm error.
+func (a *Agent) marshalV3Report(req *gosnmp.SnmpPacket, oid string) ([]byte, error) {
+	res := gosnmp.SnmpPacket{
+		PDUType:   gosnmp.Report,
+		RequestID: req.RequestID,
+		Variables: []gosnmp.SnmpPDU{{Name: oid, Type: gosnmp.Counter32, Value: uint32(1)}},
+	}
+	params, _ := req.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	return a.marshalV3(req, &res, gosnmp.NoAuthNoPriv, params)
+}
+
+func (a *Agent) marshalV3(req, res *gosnmp.SnmpPacket, flags gosnmp.SnmpV3MsgFlags, params *gosnmp.UsmSecurityParameters) ([]byte, error) {
+	securityParameters := &gosnmp.UsmSecurityParameters{
+		AuthoritativeEngineID:    engineID,
+		AuthoritativeEngineBoots: 1,
+		AuthoritativeEngineTime:  uint32(time.Since(a.startTime).Seconds()),
+		Logger:                   a.v3User.Logger,
+	}
+	if params != nil {
+		securityParameters.UserName = params.UserName
+	}
+	if flags&gosnmp.AuthNoPriv > 0 {
+		securityParameters.AuthenticationProtocol = params.AuthenticationProtocol
+		securityParameters.SecretKey = params.SecretKey
+	}
+	if flags&gosnmp.AuthPriv > gosnmp.AuthNoPriv {
+		securityParameters.PrivacyProtocol = params.PrivacyProtocol
+		securityParameters.PrivacyKey = params.PrivacyKey
+		securityParameters.PrivacyParameters = make([]byte, 8)
+		if _, err := rand.Read(securityParameters.PrivacyParameters); err != nil {
+			return nil, errors.Wrap(err, "failed to create salt")
+		}
+	}
+
+	res.Version = gosnmp.Version3
+	res.MsgID = req.MsgID
+	res.MsgFlags = flags
+	res.SecurityModel = gosnmp.UserSecurityModel
+	res.SecurityParameters = securityParameters
+	res.ContextEngineID = engineID
+	res.ContextName = req.ContextName
+	return res.MarshalMsg()
+}
+
+// marshalResponse creates the response and marshals it. The repetitions of GETBULK responses are
+// reduced until the response fits into a single message.
+func (a *Agent) marshalResponse(req *gosnmp.SnmpPacket, rec *snmprec.Recording, marshal func(*gosnmp.SnmpPacket) ([]byte, error)) ([]byte, error) {
+	maxRepetitions := req.MaxRepetitions
+	if maxRepetitions == 0 {
+		// gosnmp does not decode the max repetitions of requests
+		maxRepetitions = defaultMaxRepetitions
+	}
+	for {
+		b, err := marshal(response(req, rec, maxRepetitions))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal response")
+		}
+		if len(b) <= maxMessageSize || req.PDUType != gosnmp.GetBulkRequest || maxRepetitions == 1 {
+			return b, nil
+		}
+		maxRepetitions /= 2
+	}
+}
+
+// response returns the response to the request.
+func response(req *gosnmp.SnmpPacket, rec *snmprec.Recording, maxRepetitions uint32) *gosnmp.SnmpPacket {
+	res := gosnmp.SnmpPacket{
+		PDUType:   gosnmp.GetResponse,
+		RequestID: req.RequestID,
+	}
+	v1 := req.Version == gosnmp.Version1
+
+	setError := func(snmpError gosnmp.SNMPError, index int) *gosnmp.SnmpPacket {
+		res.Error = snmpError
+		res.ErrorIndex = uint8(index + 1)
+		res.Variables = req.Variables
+		return &res
+	}
+
+	switch req.PDUType {
+	case gosnmp.GetRequest:
+		for i, v := range req.Variables {
+			oid, err := snmprec.ParseOID(v.Name)
+			if err != nil {
+				return setError(gosnmp.GenErr, i)
+			}
+			pdu, ok := rec.Get(oid)
+			if !ok || (v1 && pdu.Type == gosnmp.Counter64) {
+				if v1 {
+					return setError(gosnmp.NoSuchName, i)
+				}
+				pdu = gosnmp.SnmpPDU{Name: v.Name, Type: gosnmp.NoSuchObject}
+			}
+			res.Variables = append(res.Variables, pdu)
+		}
+	case gosnmp.GetNextRequest:
+		for i, v := range req.Variables {
+			oid, err := snmprec.ParseOID(v.Name)
+			if err != nil {
+				return setError(gosnmp.GenErr, i)
+			}
+			pdu, _, ok := rec.Next(oid, v1)
+			if !ok {
+				if v1 {
+					return setError(gosnmp.NoSuchName, i)
+				}
+				pdu = gosnmp.SnmpPDU{Name: v.Name, Type: gosnmp.EndOfMibView}
+			}
+			res.Variables = append(res.Variables, pdu)
+		}
+	case gosnmp.GetBulkRequest:
+		nonRepeaters := int(req.NonRepeaters)
+		if nonRepeaters > len(req.Variables) {
+			nonRepeaters = len(req.Variables)
+		}
+		var current [][]uint32
+		var names []string
+		for i, v := range req.Variables {
+			oid, err := snmprec.ParseOID(v.Name)
+			if err != nil {
+				return setError(gosnmp.GenErr, i)
+			}
+			if i >= nonRepeaters {
+				current = append(current, oid)
+				names = append(names, v.Name)
+				continue
+			}
+			pdu, _, ok := rec.Next(oid, false)
+			if !ok {
+				pdu = gosnmp.SnmpPDU{Name: v.Name, Type: gosnmp.EndOfMibView}
+			}
+			res.Variables = append(res.Variables, pdu)
+		}
+		for r := uint32(0); r < maxRepetitions && len(current) > 0; r++ {
+			end := true
+			for i := range current {
+				pdu, oid, ok := rec.Next(current[i], false)
+				if !ok {
+					pdu = gosnmp.SnmpPDU{Name: names[i], Type: gosnmp.EndOfMibView}
+				} else {
+					current[i], names[i], end = oid, pdu.Name, false
+				}
+				res.Variables = append(res.Variables, pdu)
+			}
+			if end {
+				break
+			}
+		}
+	default:
+		if v1 {
+			return setError(gosnmp.ReadOnly, 0)
+		}
+		return setError(gosnmp.NotWritable, 0)
+	}
+	for i := range res.Variables {
+		// gosnmp cannot marshal opaque values, so their raw bytes are served as octet string
+		if res.Variables[i].Type == gosnmp.Opaque {
+			res.Variables[i].Type = gosnmp.OctetString
+		}
+	}
+	return &res
+}
+
+// getVersion returns the version of the encoded message, which is the first element of the message sequence.
+func getVersion(packet []byte) (gosnmp.SnmpVersion, error) {
+	if len(packet) < 2 || packet[0] != byte(gosnmp.Sequence) {
+		return 0, errors.New("invalid message")
+	}
+	i := 2
+	if packet[1]&0x80 != 0 {
+		i += int(packet[1] & 0x7f)
+	}
+	if len(packet) < i+3 || packet[i] != byte(gosnmp.Integer) || packet[i+1] != 1 {
+		return 0, errors.New("invalid message version")
+	}
+	return gosnmp.SnmpVersion(packet[i+2]), nil
+}
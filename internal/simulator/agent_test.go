@@ -0,0 +1,260 @@
+package simulator
+
+import (
+	"context"
+	"encoding/hex"
+	"github.com/gosnmp/gosnmp"
+	"github.com/inexio/thola/internal/network"
+	"github.com/inexio/thola/internal/tholaerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testSNMPRec = `1.3.6.1.2.1.1.1.0|4|Test Device
+1.3.6.1.2.1.1.2.0|6|1.3.6.1.4.1.9.1.1
+1.3.6.1.2.1.1.3.0|67|12345
+1.3.6.1.2.1.2.2.1.2.1|4x|65746830
+1.3.6.1.2.1.2.2.1.2.2|4|eth1
+1.3.6.1.2.1.2.2.1.10.1|65|100
+1.3.6.1.2.1.2.2.1.10.2|65|200
+1.3.6.1.2.1.31.1.1.1.6.1|70|18446744073709551615
+1.3.6.1.2.1.4.20.1.1.192.0.2.1|64|192.0.2.1
+1.3.6.1.2.1.25.3.3.1.2.1|68|load
+`
+
+func startTestAgent(t *testing.T, user *V3User) *Agent {
+	dir, err := ioutil.TempDir("", "simulator")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "vendor"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "vendor", "device.snmprec"), []byte(testSNMPRec), 0644))
+
+	agent, err := NewAgent(Config{
+		Dir:     dir,
+		Address: "127.0.0.1:0",
+		V3User:  user,
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		_ = agent.Serve(ctx)
+	}()
+	t.Cleanup(cancel)
+	return agent
+}
+
+func newTestClient(t *testing.T, agent *Agent, version gosnmp.SnmpVersion, configure ...func(*gosnmp.GoSNMP)) *gosnmp.GoSNMP {
+	client := &gosnmp.GoSNMP{
+		Target:         "127.0.0.1",
+		Port:           uint16(agent.Addr().(*net.UDPAddr).Port),
+		Community:      "vendor/device",
+		Version:        version,
+		Timeout:        time.Second,
+		MaxRepetitions: 3,
+	}
+	for _, c := range configure {
+		c(client)
+	}
+	require.NoError(t, client.Connect())
+	t.Cleanup(func() {
+		_ = client.Conn.Close()
+	})
+	return client
+}
+
+func TestAgent_Communities(t *testing.T) {
+	agent := startTestAgent(t, nil)
+	assert.Equal(t, []string{"vendor/device"}, agent.Communities())
+}
+
+func TestAgent_v2c(t *testing.T) {
+	client := newTestClient(t, startTestAgent(t, nil), gosnmp.Version2c)
+
+	res, err := client.Get([]string{".1.3.6.1.2.1.1.1.0", ".1.3.6.1.2.1.1.2.0", ".1.3.6.1.2.1.1.9.0"})
+	require.NoError(t, err)
+	require.Len(t, res.Variables, 3)
+	assert.Equal(t, []byte("Test Device"), res.Variables[0].Value)
+	assert.Equal(t, ".1.3.6.1.4.1.9.1.1", res.Variables[1].Value)
+	assert.Equal(t, gosnmp.NoSuchObject, res.Variables[2].Type)
+
+	walk, err := client.WalkAll(".1.3.6.1.2.1.2.2.1")
+	require.NoError(t, err)
+	require.Len(t, walk, 4)
+	assert.Equal(t, []byte("eth0"), walk[0].Value)
+	assert.Equal(t, uint(200), walk[3].Value)
+
+	bulkWalk, err := client.BulkWalkAll(".1.3.6.1.2")
+	require.NoError(t, err)
+	require.Len(t, bulkWalk, 10)
+	assert.Equal(t, "192.0.2.1", bulkWalk[7].Value)
+	assert.Equal(t, []byte("load"), bulkWalk[8].Value, "opaque values are served as octet string")
+	assert.Equal(t, uint64(18446744073709551615), bulkWalk[9].Value)
+
+	res, err = client.GetNext([]string{".1.3.6.1.2.1.31.1.1.1.6.1"})
+	require.NoError(t, err)
+	assert.Equal(t, gosnmp.EndOfMibView, res.Variables[0].Type)
+}
+
+func TestAgent_v1(t *testing.T) {
+	client := newTestClient(t, startTestAgent(t, nil), gosnmp.Version1)
+
+	res, err := client.Get([]string{".1.3.6.1.2.1.1.3.0"})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(12345), res.Variables[0].Value)
+
+	res, err = client.Get([]string{".1.3.6.1.2.1.1.3.0", ".1.3.6.1.2.1.1.9.0"})
+	require.NoError(t, err)
+	assert.Equal(t, gosnmp.NoSuchName, res.Error)
+	assert.Equal(t, uint8(2), res.ErrorIndex)
+
+	// counter64 values do not exist in snmp v1
+	res, err = client.GetNext([]string{".1.3.6.1.2.1.2.2.1.10.2"})
+	require.NoError(t, err)
+	assert.Equal(t, ".1.3.6.1.2.1.4.20.1.1.192.0.2.1", res.Variables[0].Name)
+}
+
+func TestAgent_unknownCommunity(t *testing.T) {
+	client := newTestClient(t, startTestAgent(t, nil), gosnmp.Version2c)
+	client.Community = "unknown"
+	client.Retries = 0
+	client.Timeout = 100 * time.Millisecond
+
+	_, err := client.Get([]string{".1.3.6.1.2.1.1.1.0"})
+	assert.Error(t, err)
+}
+
+func TestAgent_v3(t *testing.T) {
+	tests := []struct {
+		name     string
+		user     V3User
+		msgFlags gosnmp.SnmpV3MsgFlags
+		params   *gosnmp.UsmSecurityParameters
+	}{
+		{
+			name:     "noAuthNoPriv",
+			user:     V3User{Name: "user"},
+			msgFlags: gosnmp.NoAuthNoPriv,
+			params:   &gosnmp.UsmSecurityParameters{UserName: "user"},
+		},
+		{
+			name:     "authPriv SHA AES",
+			user:     V3User{Name: "user", AuthProtocol: "SHA", AuthKey: "authpassword", PrivProtocol: "AES", PrivKey: "privpassword"},
+			msgFlags: gosnmp.AuthPriv,
+			params: &gosnmp.UsmSecurityParameters{
+				UserName:                 "user",
+				AuthenticationProtocol:   gosnmp.SHA,
+				AuthenticationPassphrase: "authpassword",
+				PrivacyProtocol:          gosnmp.AES,
+				PrivacyPassphrase:        "privpassword",
+			},
+		},
+		{
+			name:     "authPriv MD5 DES",
+			user:     V3User{Name: "user", AuthProtocol: "MD5", AuthKey: "authpassword", PrivProtocol: "DES", PrivKey: "privpassword"},
+			msgFlags: gosnmp.AuthPriv,
+			params: &gosnmp.UsmSecurityParameters{
+				UserName:                 "user",
+				AuthenticationProtocol:   gosnmp.MD5,
+				AuthenticationPassphrase: "authpassword",
+				PrivacyProtocol:          gosnmp.DES,
+				PrivacyPassphrase:        "privpassword",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			user := test.user
+			client := newTestClient(t, startTestAgent(t, &user), gosnmp.Version3, func(client *gosnmp.GoSNMP) {
+				client.SecurityModel = gosnmp.UserSecurityModel
+				client.MsgFlags = test.msgFlags
+				client.SecurityParameters = test.params
+				client.ContextName = "vendor/device"
+			})
+
+			res, err := client.Get([]string{".1.3.6.1.2.1.1.1.0"})
+			require.NoError(t, err)
+			assert.Equal(t, []byte("Test Device"), res.Variables[0].Value)
+
+			walk, err := client.BulkWalkAll(".1.3.6.1.2.1.2.2.1")
+			require.NoError(t, err)
+			assert.Len(t, walk, 4)
+		})
+	}
+}
+
+func TestAgent_v3UnknownUser(t *testing.T) {
+	client := newTestClient(t, startTestAgent(t, &V3User{Name: "user"}), gosnmp.Version3, func(client *gosnmp.GoSNMP) {
+		client.SecurityModel = gosnmp.UserSecurityModel
+		client.MsgFlags = gosnmp.NoAuthNoPriv
+		client.SecurityParameters = &gosnmp.UsmSecurityParameters{UserName: "other"}
+		client.ContextName = "vendor/device"
+	})
+
+	res, err := client.Get([]string{".1.3.6.1.2.1.1.1.0"})
+	require.NoError(t, err)
+	assert.Equal(t, gosnmp.Report, res.PDUType)
+	assert.Equal(t, oidUsmStatsUnknownUserNames, res.Variables[0].Name)
+}
+
+func TestAgent_v3Errors(t *testing.T) {
+	agent := startTestAgent(t, &V3User{Name: "user", AuthProtocol: "SHA", AuthKey: "authpassword", PrivProtocol: "AES", PrivKey: "privpassword"})
+	port := agent.Addr().(*net.UDPAddr).Port
+	str := func(s string) *string {
+		return &s
+	}
+	v3Data := func(user, authKey, privKey string) network.SNMPv3ConnectionData {
+		return network.SNMPv3ConnectionData{
+			Level:        str("authPriv"),
+			ContextName:  str("vendor/device"),
+			User:         str(user),
+			AuthProtocol: str("sha"),
+			AuthKey:      str(authKey),
+			PrivProtocol: str("aes"),
+			PrivKey:      str(privKey),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		data   network.SNMPv3ConnectionData
+		reason tholaerr.SNMPv3USMReason
+	}{
+		{name: "unknown user", data: v3Data("other", "authpassword", "privpassword"), reason: tholaerr.SNMPv3USMUnknownUserName},
+		{name: "wrong auth key", data: v3Data("user", "wrongpassword", "privpassword"), reason: tholaerr.SNMPv3USMWrongDigest},
+		{name: "wrong priv key", data: v3Data("user", "authpassword", "wrongpassword"), reason: tholaerr.SNMPv3USMDecryptionError},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := network.NewSNMPv3Client(context.Background(), "127.0.0.1", port, 1, 0, test.data, nil)
+			reason, ok := tholaerr.GetSNMPv3USMReason(err)
+			require.True(t, ok, "unexpected error: %v", err)
+			assert.Equal(t, test.reason, reason)
+		})
+	}
+
+	client, err := network.NewSNMPv3Client(context.Background(), "127.0.0.1", port, 1, 0, v3Data("user", "authpassword", "privpassword"), nil)
+	require.NoError(t, err)
+	engine := client.GetV3EngineData()
+	require.NotNil(t, engine)
+	assert.Equal(t, hex.EncodeToString([]byte(engineID)), engine.EngineID)
+	assert.Equal(t, uint32(1), engine.EngineBoots)
+	_ = client.Disconnect()
+
+	// a cached engine skips the discovery, an outdated one is replaced after the discovery
+	for _, cached := range []network.SNMPv3EngineData{*engine, {EngineID: "8000000004", EngineBoots: 1, Time: time.Now()}} {
+		client, err = network.NewSNMPv3Client(context.Background(), "127.0.0.1", port, 1, 0, v3Data("user", "authpassword", "privpassword"), &cached)
+		require.NoError(t, err)
+		assert.Equal(t, engine.EngineID, client.GetV3EngineData().EngineID)
+		_ = client.Disconnect()
+	}
+}
@@ -0,0 +1,51 @@
+package simulator
+
+import (
+	"github.com/inexio/thola/internal/snmprec"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// loadRecordings loads all snmprec files in the directory and its sub directories.
+// The key of a recording is the path of the file relative to the directory without the file extension,
+// which is the community that snmpsim uses for the file.
+func loadRecordings(dir string) (map[string]*snmprec.Recording, error) {
+	recordings := make(map[string]*snmprec.Recording)
+	err := loadRecordingsRecursive(dir, "", recordings)
+	if err != nil {
+		return nil, err
+	}
+	return recordings, nil
+}
+
+func loadRecordingsRecursive(dir, relativePath string, recordings map[string]*snmprec.Recording) error {
+	files, err := ioutil.ReadDir(filepath.Join(dir, relativePath))
+	if err != nil {
+		return errors.Wrap(err, "failed to read directory")
+	}
+	for _, f := range files {
+		if strings.HasPrefix(f.Name(), ".") {
+			continue
+		}
+		if f.IsDir() {
+			err = loadRecordingsRecursive(dir, filepath.Join(relativePath, f.Name()), recordings)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if !strings.HasSuffix(f.Name(), ".snmprec") {
+			continue
+		}
+
+		community := filepath.ToSlash(filepath.Join(relativePath, strings.TrimSuffix(f.Name(), ".snmprec")))
+		rec, err := snmprec.LoadRecording(filepath.Join(dir, relativePath, f.Name()))
+		if err != nil {
+			return errors.Wrapf(err, "failed to load snmprec file '%s'", community)
+		}
+		recordings[community] = rec
+	}
+	return nil
+}
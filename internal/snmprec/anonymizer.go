@@ -0,0 +1,160 @@
+package snmprec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	typeOctetString    = "4"
+	typeOctetStringHex = "4x"
+	typeIPAddress      = "64"
+
+	oidSysContact  = "1.3.6.1.2.1.1.4.0"
+	oidSysName     = "1.3.6.1.2.1.1.5.0"
+	oidSysLocation = "1.3.6.1.2.1.1.6.0"
+)
+
+var ipv4Regex = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+
+// Anonymizer replaces sensitive data in records.
+//
+// IPv4 addresses of IpAddress values or octet strings that only contain an ip address are consistently
+// replaced with addresses of the benchmarking range 198.18.0.0/15. Afterwards all occurrences of these
+// addresses are replaced, also if they are part of a string or used as index of an oid. Other dotted
+// numbers like version strings are not touched. All occurrences of the added sensitive strings are
+// replaced with a placeholder. The sysName is only replaced in values that consist of it entirely,
+// short host names would otherwise also be replaced in unrelated strings.
+type Anonymizer struct {
+	replacements map[string]string
+	values       map[string]string
+	ips          map[string]string
+}
+
+// NewAnonymizer creates a new anonymizer.
+func NewAnonymizer() *Anonymizer {
+	return &Anonymizer{
+		replacements: make(map[string]string),
+		values:       make(map[string]string),
+		ips:          make(map[string]string),
+	}
+}
+
+// AddSensitiveString adds a string, e.g. a serial number or community, that is replaced with the given placeholder.
+func (a *Anonymizer) AddSensitiveString(s, placeholder string) {
+	if strings.TrimSpace(s) == "" {
+		return
+	}
+	a.replacements[s] = placeholder
+}
+
+// Anonymize returns the anonymized records.
+func (a *Anonymizer) Anonymize(records []Record) []Record {
+	for _, record := range records {
+		if record.OID == oidSysName && record.Type == typeOctetString && strings.TrimSpace(record.Value) != "" {
+			a.values[record.Value] = "device"
+		}
+		if record.Type == typeIPAddress || (record.Type == typeOctetString && net.ParseIP(record.Value).To4() != nil) {
+			a.ip(record.Value)
+		}
+	}
+
+	res := make([]Record, 0, len(records))
+	for _, record := range records {
+		switch record.OID {
+		case oidSysContact, oidSysLocation:
+			record.Type, record.Value = typeOctetString, ""
+		}
+
+		switch record.Type {
+		case typeIPAddress:
+			record.Value = a.ip(record.Value)
+		case typeOctetString:
+			record.Value = a.AnonymizeString(record.Value)
+		case typeOctetStringHex:
+			if b, err := hex.DecodeString(record.Value); err == nil {
+				record.Value = hex.EncodeToString([]byte(a.replaceStrings(string(b))))
+			}
+		}
+		record.OID = a.anonymizeOIDIndex(record.OID)
+		res = append(res, record)
+	}
+
+	Sort(res)
+	return res
+}
+
+// AnonymizeString replaces all sensitive strings and known ip addresses in the string.
+func (a *Anonymizer) AnonymizeString(s string) string {
+	s = a.replaceStrings(s)
+	return ipv4Regex.ReplaceAllStringFunc(s, func(ip string) string {
+		if replacement, ok := a.ips[ip]; ok {
+			return replacement
+		}
+		return ip
+	})
+}
+
+func (a *Anonymizer) replaceStrings(s string) string {
+	if replacement, ok := a.values[s]; ok {
+		return replacement
+	}
+	// longer strings are replaced first so that substrings of them do not break their replacement
+	var keys []string
+	for k := range a.replacements {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return len(keys[i]) > len(keys[j])
+	})
+	for _, k := range keys {
+		s = strings.ReplaceAll(s, k, a.replacements[k])
+	}
+	return s
+}
+
+// ip returns the replacement of the ip address.
+func (a *Anonymizer) ip(ip string) string {
+	parsed := net.ParseIP(ip).To4()
+	if parsed == nil || parsed.IsLoopback() || parsed.IsUnspecified() || parsed.Equal(net.IPv4bcast) || parsed[0] == 255 {
+		return ip
+	}
+	if replacement, ok := a.ips[ip]; ok {
+		return replacement
+	}
+	n := len(a.ips) + 1
+	replacement := fmt.Sprintf("198.%d.%d.%d", 18+n/65536%2, n/256%256, n%256)
+	a.ips[ip] = replacement
+	return replacement
+}
+
+// anonymizeOIDIndex replaces ip addresses that are used as index at the end of the oid.
+func (a *Anonymizer) anonymizeOIDIndex(oid string) string {
+	parts := strings.Split(oid, ".")
+	if len(parts) < 5 {
+		return oid
+	}
+	index := strings.Join(parts[len(parts)-4:], ".")
+	replacement, ok := a.ips[index]
+	if !ok {
+		return oid
+	}
+	return strings.Join(parts[:len(parts)-4], ".") + "." + replacement
+}
+
+func compareOIDs(a, b string) int {
+	pa, pb := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(pa) && i < len(pb); i++ {
+		if len(pa[i]) != len(pb[i]) {
+			return len(pa[i]) - len(pb[i])
+		}
+		if c := strings.Compare(pa[i], pb[i]); c != 0 {
+			return c
+		}
+	}
+	return len(pa) - len(pb)
+}
@@ -0,0 +1,50 @@
+package snmprec
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAnonymizer_Anonymize(t *testing.T) {
+	records := []Record{
+		{"1.3.6.1.2.1.1.1.0", "4", "Cisco IOS Software, core"},
+		{"1.3.6.1.2.1.1.4.0", "4", "noc@example.com"},
+		{"1.3.6.1.2.1.1.5.0", "4", "core"},
+		{"1.3.6.1.2.1.4.20.1.1.10.1.2.3", "64", "10.1.2.3"},
+		{"1.3.6.1.2.1.4.20.1.2.10.1.2.3", "2", "1"},
+		{"1.3.6.1.2.1.47.1.1.1.1.11.1", "4", "FOC1234X0AB"},
+		{"1.3.6.1.4.1.9.9.1.0", "4", "peer 10.1.2.3 community secret"},
+		{"1.3.6.1.4.1.9.9.2.0", "4x", "00ff736563726574"},
+		{"1.0.8802.1.1.2.1.3.3.0", "4", "core"},
+	}
+
+	a := NewAnonymizer()
+	a.AddSensitiveString("FOC1234X0AB", "SERIAL")
+	a.AddSensitiveString("secret", "public")
+	res := a.Anonymize(records)
+
+	assert.Equal(t, []Record{
+		{"1.0.8802.1.1.2.1.3.3.0", "4", "device"},
+		{"1.3.6.1.2.1.1.1.0", "4", "Cisco IOS Software, core"},
+		{"1.3.6.1.2.1.1.4.0", "4", ""},
+		{"1.3.6.1.2.1.1.5.0", "4", "device"},
+		{"1.3.6.1.2.1.4.20.1.1.198.18.0.1", "64", "198.18.0.1"},
+		{"1.3.6.1.2.1.4.20.1.2.198.18.0.1", "2", "1"},
+		{"1.3.6.1.2.1.47.1.1.1.1.11.1", "4", "SERIAL"},
+		{"1.3.6.1.4.1.9.9.1.0", "4", "peer 198.18.0.1 community public"},
+		{"1.3.6.1.4.1.9.9.2.0", "4x", "00ff7075626c6963"},
+	}, res)
+}
+
+func TestAnonymizer_AnonymizeString(t *testing.T) {
+	a := NewAnonymizer()
+	a.Anonymize([]Record{
+		{"1.3.6.1.2.1.4.20.1.1.10.0.0.1", "64", "10.0.0.1"},
+		{"1.3.6.1.2.1.4.20.1.1.10.0.0.2", "64", "10.0.0.2"},
+		{"1.3.6.1.2.1.4.20.1.3.10.0.0.1", "64", "255.255.255.0"},
+	})
+	assert.Equal(t, "198.18.0.1", a.AnonymizeString("10.0.0.1"))
+	assert.Equal(t, "via 198.18.0.2", a.AnonymizeString("via 10.0.0.2"))
+	assert.Equal(t, "255.255.255.0", a.AnonymizeString("255.255.255.0"))
+	assert.Equal(t, "Version 16.9.4.1", a.AnonymizeString("Version 16.9.4.1"))
+}
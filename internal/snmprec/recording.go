@@ -0,0 +1,203 @@
+package snmprec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/gosnmp/gosnmp"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var unsignedTypes = map[string]gosnmp.Asn1BER{
+	"65": gosnmp.Counter32,
+	"66": gosnmp.Gauge32,
+	"67": gosnmp.TimeTicks,
+}
+
+// Recording contains the variables of a snmprec file sorted by their oid.
+type Recording struct {
+	variables []variable
+}
+
+type variable struct {
+	oid []uint32
+	pdu gosnmp.SnmpPDU
+}
+
+// LoadRecording reads the snmprec file and returns its recording.
+func LoadRecording(file string) (*Recording, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open file")
+	}
+	defer f.Close()
+
+	records, err := Read(f)
+	if err != nil {
+		return nil, err
+	}
+	return NewRecording(records)
+}
+
+// NewRecording creates a recording of the records. Records with types that cannot be converted to a snmp pdu are skipped.
+func NewRecording(records []Record) (*Recording, error) {
+	var rec Recording
+	for _, record := range records {
+		oid, err := ParseOID(record.OID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid oid '%s'", record.OID)
+		}
+		pdu, err := record.PDU()
+		if err != nil {
+			log.Debug().Err(err).Str("oid", record.OID).Msg("skipping unsupported snmprec record")
+			continue
+		}
+		rec.variables = append(rec.variables, variable{oid: oid, pdu: pdu})
+	}
+	sort.SliceStable(rec.variables, func(i, j int) bool {
+		return compareNumericOIDs(rec.variables[i].oid, rec.variables[j].oid) < 0
+	})
+	return &rec, nil
+}
+
+// Get returns the variable with the given oid.
+func (r *Recording) Get(oid []uint32) (gosnmp.SnmpPDU, bool) {
+	i := r.search(oid)
+	if i < len(r.variables) && compareNumericOIDs(r.variables[i].oid, oid) == 0 {
+		return r.variables[i].pdu, true
+	}
+	return gosnmp.SnmpPDU{}, false
+}
+
+// Next returns the first variable after the given oid.
+// Counter64 values are skipped if noCounter64 is set, as they do not exist in SNMPv1.
+func (r *Recording) Next(oid []uint32, noCounter64 bool) (gosnmp.SnmpPDU, []uint32, bool) {
+	i := r.search(oid)
+	if i < len(r.variables) && compareNumericOIDs(r.variables[i].oid, oid) == 0 {
+		i++
+	}
+	for ; i < len(r.variables); i++ {
+		if noCounter64 && r.variables[i].pdu.Type == gosnmp.Counter64 {
+			continue
+		}
+		return r.variables[i].pdu, r.variables[i].oid, true
+	}
+	return gosnmp.SnmpPDU{}, nil, false
+}
+
+// Walk returns all variables in the subtree of the given oid.
+func (r *Recording) Walk(oid []uint32) []gosnmp.SnmpPDU {
+	var res []gosnmp.SnmpPDU
+	for i := r.search(oid); i < len(r.variables); i++ {
+		if len(r.variables[i].oid) < len(oid) || compareNumericOIDs(r.variables[i].oid[:len(oid)], oid) != 0 {
+			break
+		}
+		res = append(res, r.variables[i].pdu)
+	}
+	return res
+}
+
+// search returns the index of the first variable that is not smaller than the given oid.
+func (r *Recording) search(oid []uint32) int {
+	return sort.Search(len(r.variables), func(i int) bool {
+		return compareNumericOIDs(r.variables[i].oid, oid) >= 0
+	})
+}
+
+// PDU converts the record to a snmp pdu whose value can be encoded by gosnmp.
+// Opaque values are the only exception, their raw bytes cannot be marshalled by gosnmp.
+func (record Record) PDU() (gosnmp.SnmpPDU, error) {
+	pdu := gosnmp.SnmpPDU{
+		Name: "." + record.OID,
+	}
+	switch record.Type {
+	case "2":
+		i, err := strconv.ParseInt(record.Value, 10, 32)
+		if err != nil {
+			return pdu, errors.Wrap(err, "invalid integer")
+		}
+		pdu.Type, pdu.Value = gosnmp.Integer, int(i)
+	case "4":
+		pdu.Type, pdu.Value = gosnmp.OctetString, []byte(record.Value)
+	case "4x":
+		b, err := hex.DecodeString(record.Value)
+		if err != nil {
+			return pdu, errors.Wrap(err, "invalid hex string")
+		}
+		pdu.Type, pdu.Value = gosnmp.OctetString, b
+	case "68":
+		pdu.Type, pdu.Value = gosnmp.Opaque, []byte(record.Value)
+	case "68x":
+		b, err := hex.DecodeString(record.Value)
+		if err != nil {
+			return pdu, errors.Wrap(err, "invalid hex opaque")
+		}
+		pdu.Type, pdu.Value = gosnmp.Opaque, b
+	case "5":
+		pdu.Type, pdu.Value = gosnmp.Null, nil
+	case "6":
+		pdu.Type, pdu.Value = gosnmp.ObjectIdentifier, "."+strings.TrimPrefix(record.Value, ".")
+	case "64":
+		ip := net.ParseIP(record.Value).To4()
+		if ip == nil {
+			return pdu, errors.New("invalid ip address")
+		}
+		pdu.Type, pdu.Value = gosnmp.IPAddress, []byte(ip)
+	case "64x":
+		b, err := hex.DecodeString(record.Value)
+		if err != nil || len(b) != net.IPv4len {
+			return pdu, errors.New("invalid hex ip address")
+		}
+		pdu.Type, pdu.Value = gosnmp.IPAddress, b
+	case "65", "66", "67":
+		i, err := strconv.ParseUint(record.Value, 10, 32)
+		if err != nil {
+			return pdu, errors.Wrap(err, "invalid unsigned integer")
+		}
+		pdu.Type, pdu.Value = unsignedTypes[record.Type], uint32(i)
+	case "70":
+		i, err := strconv.ParseUint(record.Value, 10, 64)
+		if err != nil {
+			return pdu, errors.Wrap(err, "invalid counter64")
+		}
+		pdu.Type, pdu.Value = gosnmp.Counter64, i
+	default:
+		return pdu, fmt.Errorf("unsupported type '%s'", record.Type)
+	}
+	return pdu, nil
+}
+
+// ParseOID parses a numeric oid with or without leading dot.
+func ParseOID(oid string) ([]uint32, error) {
+	oid = strings.TrimPrefix(oid, ".")
+	if oid == "" {
+		return nil, errors.New("empty oid")
+	}
+	parts := strings.Split(oid, ".")
+	res := make([]uint32, len(parts))
+	for i, part := range parts {
+		n, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = uint32(n)
+	}
+	return res, nil
+}
+
+func compareNumericOIDs(a, b []uint32) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
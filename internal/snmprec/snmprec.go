@@ -0,0 +1,63 @@
+// Package snmprec implements the snmprec format of snmpsim which is used for the recorded test devices.
+package snmprec
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/pkg/errors"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Record is a single line of a snmprec file.
+type Record struct {
+	OID string
+	// Type is the snmprec type tag, e.g. 4 for octet strings or 4x for hex encoded octet strings.
+	Type  string
+	Value string
+}
+
+// Write writes the records in the snmprec format.
+func Write(w io.Writer, records []Record) error {
+	bw := bufio.NewWriter(w)
+	for _, record := range records {
+		if _, err := fmt.Fprintf(bw, "%s|%s|%s\n", record.OID, record.Type, record.Value); err != nil {
+			return errors.Wrap(err, "failed to write record")
+		}
+	}
+	return bw.Flush()
+}
+
+// Read reads all records of a snmprec file. Empty lines and comments are skipped.
+func Read(r io.Reader) ([]Record, error) {
+	var records []Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		parts := strings.SplitN(text, "|", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid record in line %d", line)
+		}
+		records = append(records, Record{
+			OID:   strings.TrimPrefix(parts[0], "."),
+			Type:  parts[1],
+			Value: parts[2],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read records")
+	}
+	return records, nil
+}
+
+// Sort sorts the records by their oid.
+func Sort(records []Record) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return compareOIDs(records[i].OID, records[j].OID) < 0
+	})
+}
@@ -0,0 +1,51 @@
+package snmprec
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	input := "# comment\n" +
+		"1.3.6.1.2.1.1.1.0|4|Device|with pipe\n" +
+		"\n" +
+		".1.3.6.1.2.1.1.3.0|67|1234\r\n"
+
+	records, err := Read(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, []Record{
+		{"1.3.6.1.2.1.1.1.0", "4", "Device|with pipe"},
+		{"1.3.6.1.2.1.1.3.0", "67", "1234"},
+	}, records)
+
+	_, err = Read(strings.NewReader("1.3.6.1.2.1.1.1.0|4\n"))
+	assert.Error(t, err)
+}
+
+func TestWriteRead(t *testing.T) {
+	records := []Record{
+		{"1.3.6.1.2.1.1.1.0", "4", "Device"},
+		{"1.3.6.1.2.1.2.2.1.6.1", "4x", "00aabbccddee"},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, records))
+
+	read, err := Read(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, records, read)
+}
+
+func TestSort(t *testing.T) {
+	records := []Record{
+		{"1.3.6.1.2.1.2.2.1.10.1", "65", "1"},
+		{"1.3.6.1.2.1.2.2.1.2.1", "4", "eth0"},
+		{"1.3.6.1.2.1.1.1.0", "4", "Device"},
+	}
+	Sort(records)
+	assert.Equal(t, "1.3.6.1.2.1.1.1.0", records[0].OID)
+	assert.Equal(t, "1.3.6.1.2.1.2.2.1.2.1", records[1].OID)
+	assert.Equal(t, "1.3.6.1.2.1.2.2.1.10.1", records[2].OID)
+}
@@ -40,6 +40,107 @@ func (e HTTPError) networkError() bool {
 	return true
 }
 
+// SSHError is an error returned by ssh functions.
+type SSHError struct {
+	error
+}
+
+// NewSSHError returns an ssh error.
+func NewSSHError(msg string) error {
+	return SSHError{errors.New(msg)}
+}
+
+func (e SSHError) networkError() bool {
+	return true
+}
+
+// GNMIError is an error returned by gnmi functions.
+type GNMIError struct {
+	error
+}
+
+// NewGNMIError returns a gnmi error.
+func NewGNMIError(msg string) error {
+	return GNMIError{errors.New(msg)}
+}
+
+func (e GNMIError) networkError() bool {
+	return true
+}
+
+// NETCONFError is an error returned by netconf functions.
+type NETCONFError struct {
+	error
+}
+
+// NewNETCONFError returns a netconf error.
+func NewNETCONFError(msg string) error {
+	return NETCONFError{errors.New(msg)}
+}
+
+func (e NETCONFError) networkError() bool {
+	return true
+}
+
+// SNMPv3USMReason is the reason why the user-based security model of a device rejected a snmpv3 request.
+type SNMPv3USMReason string
+
+// All reasons that are reported by the usmStats counters of RFC 3414.
+const (
+	SNMPv3USMUnsupportedSecurityLevel SNMPv3USMReason = "unsupported security level"
+	SNMPv3USMNotInTimeWindow          SNMPv3USMReason = "not in time window"
+	SNMPv3USMUnknownUserName          SNMPv3USMReason = "unknown user name"
+	SNMPv3USMUnknownEngineID          SNMPv3USMReason = "unknown engine id"
+	SNMPv3USMWrongDigest              SNMPv3USMReason = "wrong digest"
+	SNMPv3USMDecryptionError          SNMPv3USMReason = "decryption error"
+)
+
+// Hint returns a hint which part of the snmpv3 connection data is probably wrong.
+func (r SNMPv3USMReason) Hint() string {
+	switch r {
+	case SNMPv3USMUnsupportedSecurityLevel:
+		return "the security level is not supported for the user"
+	case SNMPv3USMNotInTimeWindow:
+		return "the engine boots or time of the device are out of sync"
+	case SNMPv3USMUnknownUserName:
+		return "the user does not exist on the device"
+	case SNMPv3USMUnknownEngineID:
+		return "the engine id of the device is unknown"
+	case SNMPv3USMWrongDigest:
+		return "the auth key or auth protocol is wrong"
+	case SNMPv3USMDecryptionError:
+		return "the priv key or priv protocol is wrong"
+	}
+	return "unknown reason"
+}
+
+// SNMPv3USMError occurs when a device rejects a snmpv3 request with a usmStats report.
+// It is no network error, as the device is reachable, but the connection data is wrong.
+type SNMPv3USMError struct {
+	error
+	Reason SNMPv3USMReason
+}
+
+// NewSNMPv3USMError returns a new SNMPv3USMError
+func NewSNMPv3USMError(reason SNMPv3USMReason) error {
+	return SNMPv3USMError{errors.New("snmpv3 request was rejected: " + string(reason)), reason}
+}
+
+// IsSNMPv3USMError returns if the error is a SNMPv3USMError
+func IsSNMPv3USMError(err error) bool {
+	_, ok := errors.Cause(err).(SNMPv3USMError)
+	return ok
+}
+
+// GetSNMPv3USMReason returns the reason of a SNMPv3USMError.
+func GetSNMPv3USMReason(err error) (SNMPv3USMReason, bool) {
+	e, ok := errors.Cause(err).(SNMPv3USMError)
+	if !ok {
+		return "", false
+	}
+	return e.Reason, true
+}
+
 type notFoundError interface {
 	notFoundError() bool
 }
@@ -136,6 +237,30 @@ func IsTooManyRequestsError(err error) bool {
 	return ok && e.tooManyRequestsError()
 }
 
+type forbiddenError interface {
+	forbiddenError() bool
+}
+
+// ForbiddenError occurs when the api token is not allowed to send a request.
+type ForbiddenError struct {
+	error
+}
+
+// NewForbiddenError returns an ForbiddenError
+func NewForbiddenError(msg string) error {
+	return ForbiddenError{errors.New(msg)}
+}
+
+func (p ForbiddenError) forbiddenError() bool {
+	return true
+}
+
+// IsForbiddenError returns if the error is an ForbiddenError
+func IsForbiddenError(err error) bool {
+	e, ok := errors.Cause(err).(forbiddenError)
+	return ok && e.forbiddenError()
+}
+
 type componentNotFound interface {
 	componentNotFoundError() bool
 }
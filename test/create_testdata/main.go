@@ -1,11 +1,12 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"github.com/inexio/thola/internal/devicetest"
 	"github.com/inexio/thola/internal/network"
-	"github.com/inexio/thola/internal/parser"
 	"github.com/inexio/thola/internal/request"
 	"github.com/inexio/thola/test"
 	"github.com/pkg/errors"
@@ -166,7 +167,7 @@ func createTestdata(testDevices []string) error {
 	return nil
 }
 
-func getDeviceTestData(device string) (test.DeviceTestData, error) {
+func getDeviceTestData(device string) (devicetest.Data, error) {
 	connectionData := network.ConnectionData{
 		SNMP: &network.SNMPConnectionData{
 			Communities: []string{device},
@@ -182,233 +183,9 @@ func getDeviceTestData(device string) (test.DeviceTestData, error) {
 		},
 	}
 
-	var identifyResponse *request.IdentifyResponse
-	res, err := test.ProcessRequest(
-		&request.IdentifyRequest{
-			BaseRequest: baseRequest,
-		},
-		port,
-	)
-	if err != nil {
-		log.Info().Err(err).Msg("identify for device " + device + " failed")
-	} else {
-		identifyResponse = res.(*request.IdentifyResponse)
-	}
-
-	var readCountInterfacesResponse *request.ReadCountInterfacesResponse
-	res, err = test.ProcessRequest(
-		&request.ReadCountInterfacesRequest{
-			ReadRequest: request.ReadRequest{
-				BaseRequest: baseRequest,
-			},
-		},
-		port,
-	)
-	if err != nil {
-		log.Info().Err(err).Msg("read count interfaces for device " + device + " failed")
-	} else {
-		readCountInterfacesResponse = res.(*request.ReadCountInterfacesResponse)
-	}
-
-	var checkInterfaceMetricsResponse *request.CheckResponse
-	res, err = test.ProcessRequest(
-		&request.CheckInterfaceMetricsRequest{
-			CheckDeviceRequest: request.CheckDeviceRequest{
-				BaseRequest:  baseRequest,
-				CheckRequest: request.CheckRequest{},
-			},
-			PrintInterfaces: true,
-		},
-		port,
-	)
-	if err != nil {
-		log.Info().Err(err).Msg("check interface metrics for device " + device + " failed")
-	} else if res.GetExitCode() == 3 {
-		errString, err := parser.Parse(res, "")
-		if err != nil {
-			return test.DeviceTestData{}, errors.New("failed to parse error message")
-		}
-		log.Info().Err(errors.New(string(errString))).Msg("check interface metrics for device " + device + " failed")
-	} else {
-		checkInterfaceMetricsResponse = res.(*request.CheckResponse)
-		checkInterfaceMetricsResponse.RawOutput = ""
-	}
-
-	var checkUPSResponse *request.CheckResponse
-	res, err = test.ProcessRequest(
-		&request.CheckUPSRequest{
-			CheckDeviceRequest: request.CheckDeviceRequest{
-				BaseRequest:  baseRequest,
-				CheckRequest: request.CheckRequest{},
-			},
-		},
-		port,
-	)
-	if err != nil {
-		log.Info().Err(err).Msg("check ups for device " + device + " failed")
-	} else if res.GetExitCode() == 3 {
-		errString, err := parser.Parse(res, "")
-		if err != nil {
-			return test.DeviceTestData{}, errors.New("failed to parse error message")
-		}
-		log.Info().Err(errors.New(string(errString))).Msg("check ups for device " + device + " failed")
-	} else {
-		checkUPSResponse = res.(*request.CheckResponse)
-		checkUPSResponse.RawOutput = ""
-	}
-
-	var checkCPULoadResponse *request.CheckResponse
-	res, err = test.ProcessRequest(
-		&request.CheckCPULoadRequest{
-			CheckDeviceRequest: request.CheckDeviceRequest{
-				BaseRequest:  baseRequest,
-				CheckRequest: request.CheckRequest{},
-			},
-		},
-		port,
-	)
-	if err != nil {
-		log.Info().Err(err).Msg("check cpu load for device " + device + " failed")
-	} else if res.GetExitCode() == 3 {
-		errString, err := parser.Parse(res, "")
-		if err != nil {
-			return test.DeviceTestData{}, errors.New("failed to parse error message")
-		}
-		log.Info().Err(errors.New(string(errString))).Msg("check cpu load for device " + device + " failed")
-	} else {
-		checkCPULoadResponse = res.(*request.CheckResponse)
-		checkCPULoadResponse.RawOutput = ""
-	}
-
-	var checkMemoryUsageResponse *request.CheckResponse
-	res, err = test.ProcessRequest(
-		&request.CheckMemoryUsageRequest{
-			CheckDeviceRequest: request.CheckDeviceRequest{
-				BaseRequest:  baseRequest,
-				CheckRequest: request.CheckRequest{},
-			},
-		},
-		port,
-	)
-	if err != nil {
-		log.Info().Err(err).Msg("check memory usage for device " + device + " failed")
-	} else if res.GetExitCode() == 3 {
-		errString, err := parser.Parse(res, "")
-		if err != nil {
-			return test.DeviceTestData{}, errors.New("failed to parse error message")
-		}
-		log.Info().Err(errors.New(string(errString))).Msg("check memory usage for device " + device + " failed")
-	} else {
-		checkMemoryUsageResponse = res.(*request.CheckResponse)
-		checkMemoryUsageResponse.RawOutput = ""
-	}
-
-	var checkDiskResponse *request.CheckResponse
-	res, err = test.ProcessRequest(
-		&request.CheckDiskRequest{
-			CheckDeviceRequest: request.CheckDeviceRequest{
-				BaseRequest:  baseRequest,
-				CheckRequest: request.CheckRequest{},
-			},
-		},
-		port,
-	)
-	if err != nil {
-		log.Info().Err(err).Msg("check disk for device " + device + " failed")
-	} else if res.GetExitCode() == 3 {
-		errString, err := parser.Parse(res, "")
-		if err != nil {
-			return test.DeviceTestData{}, errors.New("failed to parse error message")
-		}
-		log.Info().Err(errors.New(string(errString))).Msg("check disk for device " + device + " failed")
-	} else {
-		checkDiskResponse = res.(*request.CheckResponse)
-		checkDiskResponse.RawOutput = ""
-	}
-
-	var checkServerResponse *request.CheckResponse
-	res, err = test.ProcessRequest(
-		&request.CheckServerRequest{
-			CheckDeviceRequest: request.CheckDeviceRequest{
-				BaseRequest:  baseRequest,
-				CheckRequest: request.CheckRequest{},
-			},
-		},
-		port,
-	)
-	if err != nil {
-		log.Info().Err(err).Msg("check server for device " + device + " failed")
-	} else if res.GetExitCode() == 3 {
-		errString, err := parser.Parse(res, "")
-		if err != nil {
-			return test.DeviceTestData{}, errors.New("failed to parse error message")
-		}
-		log.Info().Err(errors.New(string(errString))).Msg("check server for device " + device + " failed")
-	} else {
-		checkServerResponse = res.(*request.CheckResponse)
-		checkServerResponse.RawOutput = ""
-	}
-
-	var checkSBCResponse *request.CheckResponse
-	res, err = test.ProcessRequest(
-		&request.CheckSBCRequest{
-			CheckDeviceRequest: request.CheckDeviceRequest{
-				BaseRequest:  baseRequest,
-				CheckRequest: request.CheckRequest{},
-			},
-		},
-		port,
-	)
-	if err != nil {
-		log.Info().Err(err).Msg("check sbc for device " + device + " failed")
-	} else if res.GetExitCode() == 3 {
-		errString, err := parser.Parse(res, "")
-		if err != nil {
-			return test.DeviceTestData{}, errors.New("failed to parse error message")
-		}
-		log.Info().Err(errors.New(string(errString))).Msg("check sbc for device " + device + " failed")
-	} else {
-		checkSBCResponse = res.(*request.CheckResponse)
-		checkSBCResponse.RawOutput = ""
-	}
-
-	var checkHardwareHealthResponse *request.CheckResponse
-	res, err = test.ProcessRequest(
-		&request.CheckHardwareHealthRequest{
-			CheckDeviceRequest: request.CheckDeviceRequest{
-				BaseRequest:  baseRequest,
-				CheckRequest: request.CheckRequest{},
-			},
-		},
-		port,
-	)
-	if err != nil {
-		log.Info().Err(err).Msg("check hardware-health for device " + device + " failed")
-	} else if res.GetExitCode() == 3 {
-		errString, err := parser.Parse(res, "")
-		if err != nil {
-			return test.DeviceTestData{}, errors.New("failed to parse error message")
-		}
-		log.Info().Err(errors.New(string(errString))).Msg("check hardware-health for device " + device + " failed")
-	} else {
-		checkHardwareHealthResponse = res.(*request.CheckResponse)
-		checkHardwareHealthResponse.RawOutput = ""
-	}
-
-	return test.DeviceTestData{
-		Type: "snmpsim",
-		Expectations: test.DeviceTestDataExpectations{
-			Identify:              identifyResponse,
-			ReadCountInterfaces:   readCountInterfacesResponse,
-			CheckInterfaceMetrics: checkInterfaceMetricsResponse,
-			CheckUPS:              checkUPSResponse,
-			CheckCPULoad:          checkCPULoadResponse,
-			CheckMemoryUsage:      checkMemoryUsageResponse,
-			CheckDisk:             checkDiskResponse,
-			CheckServer:           checkServerResponse,
-			CheckSBC:              checkSBCResponse,
-			CheckHardwareHealth:   checkHardwareHealthResponse,
-		},
-		Connection: connectionData,
-	}, nil
+	logger := log.With().Str("device", device).Logger()
+	ctx := logger.WithContext(context.Background())
+	return devicetest.Create(ctx, baseRequest, func(r request.Request) (request.Response, error) {
+		return test.ProcessRequest(r, port)
+	})
 }
@@ -13,74 +13,6 @@ import (
 	"time"
 )
 
-// DeviceTestData represents a test_data.yaml file.
-type DeviceTestData struct {
-	Type         string                     `json:"type"`
-	Expectations DeviceTestDataExpectations `json:"expectations"`
-	Connection   network.ConnectionData     `json:"-"`
-}
-
-// DeviceTestDataExpectations represents the expectations part of an test data file.
-type DeviceTestDataExpectations struct {
-	Identify              *request.IdentifyResponse            `json:"identify" mapstructure:"identify"`
-	ReadCountInterfaces   *request.ReadCountInterfacesResponse `json:"readCountInterfaces" mapstructure:"readCountInterfaces"`
-	CheckInterfaceMetrics *request.CheckResponse               `json:"checkInterfaceMetrics" mapstructure:"checkInterfaceMetrics"`
-	CheckUPS              *request.CheckResponse               `json:"checkUPS" mapstructure:"checkUPS"`
-	CheckCPULoad          *request.CheckResponse               `json:"checkCPULoad" mapstructure:"checkCPULoad"`
-	CheckMemoryUsage      *request.CheckResponse               `json:"checkMemoryUsage" mapstructure:"checkMemoryUsage"`
-	CheckDisk             *request.CheckResponse               `json:"checkDisk" mapstructure:"checkDisk"`
-	CheckServer           *request.CheckResponse               `json:"checkServer" mapstructure:"checkServer"`
-	CheckSBC              *request.CheckResponse               `json:"checkSBC" mapstructure:"checkSBC"`
-	CheckHardwareHealth   *request.CheckResponse               `json:"checkHardwareHealth" mapstructure:"checkHardwareHealth"`
-}
-
-// GetAvailableRequestTypes returns all available request types
-func (d *DeviceTestData) GetAvailableRequestTypes() []string {
-	var res []string
-
-	if d.Expectations.Identify != nil {
-		res = append(res, "identify")
-	}
-
-	if d.Expectations.ReadCountInterfaces != nil {
-		res = append(res, "read count-interfaces")
-	}
-
-	if d.Expectations.CheckInterfaceMetrics != nil {
-		res = append(res, "check interface-metrics")
-	}
-
-	if d.Expectations.CheckUPS != nil {
-		res = append(res, "check ups")
-	}
-
-	if d.Expectations.CheckCPULoad != nil {
-		res = append(res, "check cpu-load")
-	}
-
-	if d.Expectations.CheckMemoryUsage != nil {
-		res = append(res, "check memory-usage")
-	}
-
-	if d.Expectations.CheckDisk != nil {
-		res = append(res, "check disk")
-	}
-
-	if d.Expectations.CheckServer != nil {
-		res = append(res, "check server")
-	}
-
-	if d.Expectations.CheckSBC != nil {
-		res = append(res, "check sbc")
-	}
-
-	if d.Expectations.CheckHardwareHealth != nil {
-		res = append(res, "check hardware-health")
-	}
-
-	return res
-}
-
 // BuildupTestEnvironment build up the test environment
 func BuildupTestEnvironment(dockerPath string) {
 	dockerUp := exec.Command("docker-compose", "up", "--detach", "--build")
@@ -1,10 +1,12 @@
 package test
 
 import (
+	"context"
 	"fmt"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/inexio/go-monitoringplugin"
+	"github.com/inexio/thola/internal/devicetest"
 	"github.com/inexio/thola/internal/network"
 	"github.com/inexio/thola/internal/parser"
 	"github.com/inexio/thola/internal/request"
@@ -30,7 +32,7 @@ import (
 
 type testDevice struct {
 	info         testDeviceInfo
-	expectations DeviceTestDataExpectations
+	expectations devicetest.Expectations
 	requestTypes []string
 	retries      int
 }
@@ -51,6 +53,7 @@ type testConfig struct {
 	SNMPRecDir         string `yaml:"snmpRecDir"`
 	APIPort            int    `yaml:"apiPort"`
 	KeepDockerAlive    bool   `yaml:"keepDockerAlive"`
+	Simulator          bool   `yaml:"simulator"`
 }
 
 type statistics struct {
@@ -63,6 +66,8 @@ var (
 	bar            *progressbar.ProgressBar
 	requestCounter int32
 	snmpSimIPs     chan string
+	snmpSimPort    = 161
+	processRequest func(r request.Request) (request.Response, error)
 )
 
 func init() {
@@ -103,9 +108,27 @@ func TestIntegration(t *testing.T) {
 	_, currFilename, _, _ := runtime.Caller(0)
 	testdataDir := filepath.Join(path.Dir(currFilename), "testdata")
 
-	BuildupTestEnvironment(testdataDir)
-	if !testConf.KeepDockerAlive {
-		defer CleanupTestEnvironment(testdataDir)
+	if testConf.Simulator {
+		addr, stop, err := StartSimulator(context.Background(), getSNMPRecDir())
+		if !assert.NoError(t, err, "an error occurred while starting the snmp simulator") {
+			return
+		}
+		defer stop()
+
+		snmpSimIPs = make(chan string, 1)
+		snmpSimIPs <- addr.IP.String()
+		snmpSimPort = addr.Port
+		processRequest = func(r request.Request) (request.Response, error) {
+			return ProcessRequestLocally(context.Background(), r)
+		}
+	} else {
+		BuildupTestEnvironment(testdataDir)
+		if !testConf.KeepDockerAlive {
+			defer CleanupTestEnvironment(testdataDir)
+		}
+		processRequest = func(r request.Request) (request.Response, error) {
+			return ProcessRequest(r, testConf.APIPort)
+		}
 	}
 
 	deviceChannel, err := createTestDevices()
@@ -116,9 +139,11 @@ func TestIntegration(t *testing.T) {
 	deviceAmount := len(deviceChannel)
 	assert.True(t, deviceAmount > 0, "no device data found")
 
-	err = waitForDevices(deviceChannel)
-	if !assert.NoError(t, err, "an error occurred while waiting for the test devices being ready") {
-		return
+	if !testConf.Simulator {
+		err = waitForDevices(deviceChannel)
+		if !assert.NoError(t, err, "an error occurred while waiting for the test devices being ready") {
+			return
+		}
 	}
 
 	bar = progressbar.NewOptions(int(requestCounter),
@@ -195,7 +220,7 @@ func sendRequests(input chan testDevice, output chan statistics) {
 				stats.failed[testDevice.info.getIdentifier()] = fmt.Sprintf("generating new request failed: %s", err.Error())
 				continue
 			}
-			response, err := ProcessRequest(r, testConf.APIPort)
+			response, err := processRequest(r)
 			if err != nil {
 				if testDevice.checkForRetryRequest() {
 					testDevice.retries++
@@ -204,7 +229,7 @@ func sendRequests(input chan testDevice, output chan statistics) {
 					stats.failed[testDevice.info.getIdentifier()] = err.Error()
 				}
 			} else {
-				err := testDevice.expectations.compareExpectations(response, testDevice.requestTypes[0])
+				err := compareExpectations(testDevice.expectations, response, testDevice.requestTypes[0])
 				if err == nil {
 					// EXPECTATIONS MATCH
 					if len(testDevice.requestTypes) > 1 {
@@ -290,7 +315,7 @@ func (t *testDevice) checkForRetryRequest() bool {
 	return t.retries < testConf.Retries
 }
 
-func (e *DeviceTestDataExpectations) compareExpectations(response request.Response, requestType string) error {
+func compareExpectations(e devicetest.Expectations, response request.Response, requestType string) error {
 	switch requestType {
 	case "identify":
 		if !cmp.Equal(e.Identify, response) {
@@ -346,19 +371,16 @@ func waitForDevices(deviceChannel chan testDevice) error {
 	return err
 }
 
-func createTestDevices() (chan testDevice, error) {
-	res, err := regexp.MatchString("^/.*", testConf.SNMPRecDir)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to match regex")
+func getSNMPRecDir() string {
+	if filepath.IsAbs(testConf.SNMPRecDir) {
+		return testConf.SNMPRecDir
 	}
-	var recDir string
+	_, currFilename, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Join(path.Dir(currFilename), "testdata"), testConf.SNMPRecDir)
+}
 
-	if res {
-		recDir = testConf.SNMPRecDir
-	} else {
-		_, currFilename, _, _ := runtime.Caller(0)
-		recDir = filepath.Join(filepath.Join(path.Dir(currFilename), "testdata"), testConf.SNMPRecDir)
-	}
+func createTestDevices() (chan testDevice, error) {
+	recDir := getSNMPRecDir()
 
 	fileInfo, err := os.Stat(recDir)
 	if err != nil {
@@ -435,7 +457,7 @@ func buildTestDevicesRecursive(dataPath, relativePath string) ([]testDevice, err
 }
 
 func buildTestDeviceByFile(snmpRecFile, testDataFile, relativePath, dataPath string) (testDevice, error) {
-	var testData DeviceTestData
+	var testData devicetest.Data
 	contents, err := ioutil.ReadFile(filepath.Join(dataPath, relativePath, testDataFile))
 	if err != nil {
 		return testDevice{}, errors.Wrap(err, "error during read file")
@@ -478,7 +500,7 @@ func buildTestDeviceSNMPSim(snmpCommunity string) (*testDeviceInfoSNMPSim, error
 				SNMP: &network.SNMPConnectionData{
 					Communities:              []string{snmpCommunity},
 					Versions:                 []string{"2c"},
-					Ports:                    []int{161},
+					Ports:                    []int{snmpSimPort},
 					DiscoverParallelRequests: nil,
 					DiscoverTimeout:          nil,
 					DiscoverRetries:          nil,
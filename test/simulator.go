@@ -0,0 +1,57 @@
+package test
+
+import (
+	"context"
+	"github.com/inexio/thola/internal/parser"
+	"github.com/inexio/thola/internal/request"
+	"github.com/inexio/thola/internal/simulator"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"net"
+	"reflect"
+)
+
+// StartSimulator starts the built-in SNMP simulator for the snmprec files in the directory on a random local port.
+// The simulator runs until the returned stop function is called.
+func StartSimulator(ctx context.Context, snmpRecDir string) (*net.UDPAddr, func(), error) {
+	agent, err := simulator.NewAgent(simulator.Config{
+		Dir:     snmpRecDir,
+		Address: "127.0.0.1:0",
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create simulator")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		_ = agent.Serve(ctx)
+	}()
+
+	return agent.Addr().(*net.UDPAddr), cancel, nil
+}
+
+// ProcessRequestLocally processes the request in this process instead of sending it to a thola api.
+// The database cache is not used. The response is encoded and decoded like a response of the api,
+// so that it can be compared with the expectations of the test data.
+func ProcessRequestLocally(ctx context.Context, r request.Request) (request.Response, error) {
+	viper.Set("db.no-cache", true)
+	viper.SetDefault("device.snmp-discover-par-requests", 5)
+	viper.SetDefault("device.snmp-discover-timeout", 2)
+	viper.SetDefault("device.snmp-discover-retries", 0)
+
+	res, err := request.ProcessRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := parser.Parse(res, "json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode response")
+	}
+	decoded := reflect.New(reflect.TypeOf(res).Elem()).Interface().(request.Response)
+	err = parser.ToStruct(b, "json", decoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+	return decoded, nil
+}